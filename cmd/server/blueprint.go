@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"operators-mcp/internal/application/blueprint"
+)
+
+// runExportBlueprint implements `server export-blueprint -project <id> [-format yaml|json] [-o file]`.
+func runExportBlueprint(args []string) error {
+	fs := flag.NewFlagSet("export-blueprint", flag.ExitOnError)
+	store := registerStoreFlags(fs)
+	projectID := fs.String("project", "", "project ID to export (required)")
+	format := fs.String("format", blueprint.FormatYAML, "document format: yaml or json")
+	out := fs.String("o", "", "output file (default: stdout)")
+	_ = fs.Parse(args)
+	if *projectID == "" {
+		return errors.New("-project is required")
+	}
+
+	svc, err := newService(store)
+	if err != nil {
+		return err
+	}
+	doc, err := svc.ExportBlueprint(*projectID)
+	if err != nil {
+		return err
+	}
+	b, err := blueprint.MarshalDocument(doc, *format)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(*out, b, 0644)
+}
+
+// runImportBlueprint implements `server import-blueprint -f file [-project id | -root dir] [-mode update] [-dry-run]`.
+func runImportBlueprint(args []string) error {
+	fs := flag.NewFlagSet("import-blueprint", flag.ExitOnError)
+	store := registerStoreFlags(fs)
+	file := fs.String("f", "", "blueprint document to import (required)")
+	format := fs.String("format", "", "document format: yaml or json (default: detect)")
+	projectID := fs.String("project", "", "target project ID")
+	rootDir := fs.String("root", "", "target project root directory (project is created if missing)")
+	mode := fs.String("mode", blueprint.ImportUpdate, "import mode: create, update or prune")
+	dryRun := fs.Bool("dry-run", false, "print the diff without applying it")
+	_ = fs.Parse(args)
+	if *file == "" {
+		return errors.New("-f is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	doc, err := blueprint.UnmarshalDocument(data, *format)
+	if err != nil {
+		return err
+	}
+	svc, err := newService(store)
+	if err != nil {
+		return err
+	}
+	res, err := svc.ImportBlueprint(doc, blueprint.ImportOptions{
+		ProjectID: *projectID,
+		RootDir:   *rootDir,
+		Mode:      *mode,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range res.Changes {
+		fmt.Println(c.String())
+	}
+	switch {
+	case len(res.Changes) == 0:
+		fmt.Println("no changes")
+	case !res.Applied:
+		fmt.Printf("%d change(s) not applied (dry run)\n", len(res.Changes))
+	default:
+		fmt.Printf("%d change(s) applied to project %s\n", len(res.Changes), res.ProjectID)
+	}
+	return nil
+}
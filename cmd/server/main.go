@@ -8,37 +8,48 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"time"
 
 	"operators-mcp/internal/adapter/in/httpapi"
 	"operators-mcp/internal/adapter/in/mcp"
 	"operators-mcp/internal/adapter/in/ui"
-	"operators-mcp/internal/adapter/out/filesystem"
-	"operators-mcp/internal/adapter/out/persistence/sqlite"
 	"operators-mcp/internal/application/blueprint"
 
 	mcplib "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// commands are the CLI subcommands; running the binary without one starts the servers.
+var commands = map[string]func(args []string) error{
+	"export-blueprint": runExportBlueprint,
+	"import-blueprint": runImportBlueprint,
+	"migrate":          runMigrate,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				log.Fatalf("%s: %v", os.Args[1], err)
+			}
+			return
+		}
+	}
+
 	devMode := flag.Bool("dev", false, "proxy ui://designer to Vite dev server (run 'make web-dev' separately)")
 	mcpAddr := flag.String("mcp.addr", ":8081", "MCP server listen address (IDE connects here)")
 	httpAddr := flag.String("http.addr", ":8080", "HTTP server listen address (UI and API)")
-	dbPath := flag.String("db", "data.db", "SQLite database path (e.g. data.db or :memory:)")
+	store := registerStoreFlags(flag.CommandLine)
+	syncInterval := flag.Duration("sync.interval", 5*time.Second, "how often bound blueprint files are checked for edits (0 disables)")
+	requireIdentity := flag.Bool("require-identity", false, "refuse anonymous MCP sessions acting for an agent once any agent token has been issued")
+	anonymousAgents := flag.Bool("agents.anonymous", false, "serve /agents/{id}/mcp without a bearer token (anyone reaching the port can act as any agent)")
 	flag.Parse()
 
-	db, err := sqlite.Open(*dbPath)
+	svc, err := newService(store)
 	if err != nil {
-		log.Fatalf("database: %v", err)
+		log.Fatalf("store: %v", err)
 	}
-
-	root, _ := os.Getwd()
-	projectStore := sqlite.NewProjectRepository(db)
-	zoneStore := sqlite.NewZoneRepository(db)
-	agentStore := sqlite.NewAgentRepository(db)
-	pathMatcher := filesystem.NewMatcher()
-	treeLister := filesystem.NewLister()
-	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister, root)
+	svc.RequireIdentity = *requireIdentity
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -49,14 +60,55 @@ func main() {
 		cancel()
 	}()
 
-	go runMCPServer(ctx, *mcpAddr, svc, *devMode)
+	go runBlueprintSync(ctx, svc, *syncInterval)
+	go runMCPServer(ctx, *mcpAddr, svc, *devMode, *anonymousAgents)
 	runHTTPServer(ctx, *httpAddr, svc)
 }
 
+// runBlueprintSync periodically reconciles projects bound to a blueprint file so that edits
+// to the file (e.g. after a git pull) are applied to the store. Conflicts are logged.
+func runBlueprintSync(ctx context.Context, svc *blueprint.Service, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			results, err := svc.SyncBlueprints()
+			if err != nil {
+				log.Printf("blueprint sync: %v", err)
+			}
+			for _, res := range results {
+				switch res.Status {
+				case blueprint.SyncImported:
+					log.Printf("blueprint sync: applied %s to project %s (%d change(s))", res.File, res.ProjectID, len(res.Changes))
+				case blueprint.SyncConflict:
+					log.Printf("blueprint sync: conflict in project %s: both %s and the store changed; run sync_blueprint with resolve=file or resolve=store", res.ProjectID, res.File)
+				}
+			}
+		}
+	}
+}
+
 // runMCPServer runs the MCP server on its own port using mcp-go streamable HTTP transport.
-func runMCPServer(ctx context.Context, addr string, svc *blueprint.Service, devMode bool) {
-	s := server.NewMCPServer("operators-mcp", "0.0.1", server.WithToolCapabilities(true))
+// anonymousAgents serves the per-agent endpoints without bearer tokens.
+func runMCPServer(ctx context.Context, addr string, svc *blueprint.Service, devMode, anonymousAgents bool) {
+	ids := mcp.NewIdentities(svc)
+	projects := mcp.NewActiveProjects(svc)
+	hooks := &server.Hooks{}
+	ids.AddHooks(hooks)
+	projects.AddHooks(hooks)
+	s := server.NewMCPServer("operators-mcp", "0.0.1", server.WithToolCapabilities(true), server.WithPromptCapabilities(true),
+		server.WithResourceCapabilities(true, false), server.WithLogging(), server.WithHooks(hooks), server.WithToolHandlerMiddleware(projects.ToolMiddleware))
 	mcp.RegisterTools(s, svc)
+	mcp.RegisterPrompts(s, svc)
+	subs := mcp.RegisterResources(s, svc)
+	projects.RegisterTools(s)
+	ids.DeliverMessages(s)
 
 	designerResource := mcplib.NewResource(ui.DesignerURI, "Designer",
 		mcplib.WithResourceDescription("Architecture Designer UI"),
@@ -65,9 +117,16 @@ func runMCPServer(ctx context.Context, addr string, svc *blueprint.Service, devM
 	s.AddResource(designerResource, designerResourceHandler(devMode))
 
 	httpServer := server.NewStreamableHTTPServer(s)
-	srv := &http.Server{Addr: addr, Handler: httpServer}
+	mux := http.NewServeMux()
+	global := ids.Handler(projects.Handler(subs.Handler(httpServer)))
+	mux.Handle("/", global)
+	mux.Handle(mcp.ProjectEndpointPattern, projects.ProjectEndpoint(global))
+	agents := mcp.NewAgentServers(svc)
+	agents.AllowAnonymous = anonymousAgents
+	mux.Handle(mcp.AgentEndpointPattern, agents)
+	srv := &http.Server{Addr: addr, Handler: mux}
 	go func() {
-		log.Printf("MCP server listening on %s (use this URL in your IDE)", addr)
+		log.Printf("MCP server listening on %s (use this URL in your IDE; per-project and per-agent endpoints at /projects/{id}/mcp and /agents/{id}/mcp)", addr)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Printf("MCP server: %v", err)
 		}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"operators-mcp/internal/adapter/out/persistence/sqlite"
+)
+
+// runMigrate implements `server migrate status|up [-db path] [-to version]`.
+func runMigrate(args []string) error {
+	if len(args) == 0 || (args[0] != "status" && args[0] != "up") {
+		return errors.New("usage: migrate status|up [-db path] [-to version]")
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	dbPath := fs.String("db", "data.db", "SQLite database path")
+	to := fs.Int("to", 0, "apply migrations up to this version (default: all)")
+	_ = fs.Parse(args[1:])
+
+	db, err := sqlite.Connect(*dbPath)
+	if err != nil {
+		return err
+	}
+	if action == "up" {
+		applied, err := sqlite.Migrate(db, *to)
+		for _, m := range applied {
+			fmt.Printf("applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return nil
+	}
+	status, err := sqlite.Status(db)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, s := range status {
+		applied := "pending"
+		if s.Applied() {
+			applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/adapter/out/persistence/file"
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/adapter/out/persistence/sqlite"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/application/ports"
+)
+
+// Store backends selectable with -store.
+const (
+	storeMemory = "memory"
+	storeSQLite = "sqlite"
+	storeFile   = "file"
+)
+
+// storeConfig selects and locates the persistence backend.
+type storeConfig struct {
+	kind    string
+	dbPath  string
+	dataDir string
+}
+
+// registerStoreFlags adds the persistence flags to fs so the server and every subcommand accept them.
+func registerStoreFlags(fs *flag.FlagSet) *storeConfig {
+	cfg := &storeConfig{}
+	fs.StringVar(&cfg.kind, "store", storeSQLite, "persistence backend: memory, sqlite or file")
+	fs.StringVar(&cfg.dbPath, "db", "data.db", "SQLite database path (e.g. data.db or :memory:); used with -store sqlite")
+	fs.StringVar(&cfg.dataDir, "data.dir", "data", "directory of JSON records; used with -store file")
+	return cfg
+}
+
+// newService opens the configured store and wires the blueprint service.
+func newService(cfg *storeConfig) (*blueprint.Service, error) {
+	var (
+		projectStore ports.ProjectRepository
+		zoneStore    ports.ZoneRepository
+		agentStore   ports.AgentRepository
+		changeLog    ports.ChangeLog
+		tokens       ports.AgentTokenStore
+		tasks        ports.TaskRepository
+		leases       ports.LeaseStore
+		runs         ports.RunRepository
+		messages     ports.MessageRepository
+		decisions    ports.DecisionRepository
+		notes        ports.NoteRepository
+	)
+	switch cfg.kind {
+	case storeMemory:
+		projectStore = memory.NewProjectStore()
+		zoneStore = memory.NewStore()
+		agentStore = memory.NewAgentStore()
+		changeLog = memory.NewChangeLog()
+		tokens = memory.NewAgentTokens()
+		tasks = memory.NewTaskStore()
+		leases = memory.NewLeaseStore()
+		runs = memory.NewRunStore()
+		messages = memory.NewMessageStore()
+		decisions = memory.NewDecisionStore()
+		notes = memory.NewNoteStore()
+	case storeSQLite, "":
+		db, err := sqlite.Open(cfg.dbPath)
+		if err != nil {
+			return nil, err
+		}
+		projectStore = sqlite.NewProjectRepository(db)
+		zoneStore = sqlite.NewZoneRepository(db)
+		agentStore = sqlite.NewAgentRepository(db)
+		changeLog = sqlite.NewChangeLog(db)
+		tokens = sqlite.NewAgentTokens(db)
+		tasks = sqlite.NewTaskRepository(db)
+		leases = sqlite.NewLeaseStore(db)
+		runs = sqlite.NewRunRepository(db)
+		messages = sqlite.NewMessageRepository(db)
+		decisions = sqlite.NewDecisionRepository(db)
+		notes = sqlite.NewNoteRepository(db)
+	case storeFile:
+		dir, err := file.Open(cfg.dataDir)
+		if err != nil {
+			return nil, err
+		}
+		projectStore = file.NewProjectRepository(dir)
+		zoneStore = file.NewZoneRepository(dir)
+		agentStore = file.NewAgentRepository(dir)
+		changeLog = file.NewChangeLog(dir)
+		tokens = file.NewAgentTokens(dir)
+		tasks = file.NewTaskRepository(dir)
+		leases = file.NewLeaseStore(dir)
+		runs = file.NewRunRepository(dir)
+		messages = file.NewMessageRepository(dir)
+		decisions = file.NewDecisionRepository(dir)
+		notes = file.NewNoteRepository(dir)
+	default:
+		return nil, fmt.Errorf("unknown store %q (want memory, sqlite or file)", cfg.kind)
+	}
+	pathMatcher := filesystem.NewMatcher()
+	treeLister := filesystem.NewLister()
+	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister)
+	svc.Dependencies = filesystem.NewImportAnalyzer()
+	svc.Files = filesystem.NewFiles()
+	svc.Changes = changeLog
+	svc.Tokens = tokens
+	svc.Tasks = tasks
+	svc.Leases = leases
+	svc.Runs = runs
+	svc.Messages = messages
+	svc.Decisions = decisions
+	svc.Notes = notes
+	return svc, nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"operators-mcp/internal/adapter/in/mcp"
 	"operators-mcp/internal/application/blueprint"
@@ -42,6 +44,51 @@ func (h *Handler) Mount(mux *http.ServeMux, prefix string) {
 	mux.HandleFunc(prefix+"/create_agent", h.handleCreateAgent)
 	mux.HandleFunc(prefix+"/update_agent", h.handleUpdateAgent)
 	mux.HandleFunc(prefix+"/delete_agent", h.handleDeleteAgent)
+	mux.HandleFunc(prefix+"/render_agent_prompt", h.handleRenderAgentPrompt)
+	mux.HandleFunc(prefix+"/export_diagram", h.handleExportDiagram)
+	mux.HandleFunc(prefix+"/export_blueprint", h.handleExportBlueprint)
+	mux.HandleFunc(prefix+"/import_blueprint", h.handleImportBlueprint)
+	mux.HandleFunc(prefix+"/bind_blueprint_file", h.handleBindBlueprintFile)
+	mux.HandleFunc(prefix+"/unbind_blueprint_file", h.handleUnbindBlueprintFile)
+	mux.HandleFunc(prefix+"/sync_blueprint", h.handleSyncBlueprint)
+	mux.HandleFunc(prefix+"/read_file", h.handleReadFile)
+	mux.HandleFunc(prefix+"/read_zone_files", h.handleReadZoneFiles)
+	mux.HandleFunc(prefix+"/build_context", h.handleBuildContext)
+	mux.HandleFunc(prefix+"/write_file", h.handleWriteFile)
+	mux.HandleFunc(prefix+"/apply_patch", h.handleApplyPatch)
+	mux.HandleFunc(prefix+"/list_changes", h.handleListChanges)
+	mux.HandleFunc(prefix+"/issue_agent_token", h.handleIssueAgentToken)
+	mux.HandleFunc(prefix+"/revoke_agent_token", h.handleRevokeAgentToken)
+	mux.HandleFunc(prefix+"/create_task", h.handleCreateTask)
+	mux.HandleFunc(prefix+"/list_tasks", h.handleListTasks)
+	mux.HandleFunc(prefix+"/get_task", h.handleGetTask)
+	mux.HandleFunc(prefix+"/claim_task", h.handleClaimTask)
+	mux.HandleFunc(prefix+"/update_task_status", h.handleUpdateTaskStatus)
+	mux.HandleFunc(prefix+"/complete_task", h.handleCompleteTask)
+	mux.HandleFunc(prefix+"/route_task", h.handleRouteTask)
+	mux.HandleFunc(prefix+"/claim_zone", h.handleClaimZone)
+	mux.HandleFunc(prefix+"/renew_lease", h.handleRenewLease)
+	mux.HandleFunc(prefix+"/release_zone", h.handleReleaseZone)
+	mux.HandleFunc(prefix+"/list_leases", h.handleListLeases)
+	mux.HandleFunc(prefix+"/create_run", h.handleCreateRun)
+	mux.HandleFunc(prefix+"/set_run_task", h.handleSetRunTask)
+	mux.HandleFunc(prefix+"/get_run", h.handleGetRun)
+	mux.HandleFunc(prefix+"/list_runs", h.handleListRuns)
+	mux.HandleFunc(prefix+"/delete_run", h.handleDeleteRun)
+	mux.HandleFunc(prefix+"/create_decision", h.handleCreateDecision)
+	mux.HandleFunc(prefix+"/set_decision_status", h.handleSetDecisionStatus)
+	mux.HandleFunc(prefix+"/get_decision", h.handleGetDecision)
+	mux.HandleFunc(prefix+"/list_decisions", h.handleListDecisions)
+	mux.HandleFunc(prefix+"/export_decisions", h.handleExportDecisions)
+	mux.HandleFunc(prefix+"/add_zone_note", h.handleAddZoneNote)
+	mux.HandleFunc(prefix+"/list_zone_notes", h.handleListZoneNotes)
+	mux.HandleFunc(prefix+"/search_zone_notes", h.handleSearchZoneNotes)
+	mux.HandleFunc(prefix+"/delete_zone_note", h.handleDeleteZoneNote)
+	mux.HandleFunc(prefix+"/send_message", h.handleSendMessage)
+	mux.HandleFunc(prefix+"/list_inbox", h.handleListInbox)
+	mux.HandleFunc(prefix+"/acknowledge_message", h.handleAcknowledgeMessage)
+	mux.HandleFunc(prefix+"/get_thread", h.handleGetThread)
+	mux.HandleFunc(prefix+"/get_briefing", h.handleGetBriefing)
 }
 
 func (h *Handler) handleListTools(w http.ResponseWriter, r *http.Request) {
@@ -185,15 +232,43 @@ func (h *Handler) handleListTree(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	} else {
-		in.Root = r.URL.Query().Get("root")
-		in.ProjectID = r.URL.Query().Get("project_id")
+		q := r.URL.Query()
+		in.Root = q.Get("root")
+		in.ProjectID = q.Get("project_id")
+		in.Format = q.Get("format")
+		in.Depth, _ = strconv.Atoi(q.Get("depth"))
+		in.MaxEntries, _ = strconv.Atoi(q.Get("max_entries"))
+	}
+	format, err := blueprint.ParseTreeFormat(in.Format)
+	if err != nil {
+		writeDomainError(w, err)
+		return
 	}
 	tree, err := h.svc.ListTree(in.Root, in.ProjectID)
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
-	writeJSON(w, mcp.ListTreeOut{Tree: mcp.TreeNodeToDTO(tree)})
+	if format == blueprint.TreeFormatJSON {
+		writeJSON(w, mcp.ListTreeOut{Tree: mcp.TreeNodeToDTO(tree)})
+		return
+	}
+	h.writeTreeText(w, format, tree, in.Root, in.ProjectID, in.Depth, in.MaxEntries)
+}
+
+// writeTreeText writes tree as plain text in format, annotated with the project's zones unless an
+// explicit root was listed.
+func (h *Handler) writeTreeText(w http.ResponseWriter, format string, tree *domain.TreeNode, root, projectID string, depth, maxEntries int) {
+	var zones []*domain.Zone
+	if root == "" && projectID != "" {
+		zones = h.svc.ListZones(projectID)
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte(blueprint.RenderTree(tree, zones, blueprint.TreeTextOptions{
+		Collapse:   format == blueprint.TreeFormatCollapsed,
+		MaxEntries: maxEntries,
+		Depth:      depth,
+	})))
 }
 
 func (h *Handler) handleListZones(w http.ResponseWriter, r *http.Request) {
@@ -230,16 +305,28 @@ func (h *Handler) handleListMatchingPaths(w http.ResponseWriter, r *http.Request
 			return
 		}
 	} else {
-		in.Pattern = r.URL.Query().Get("pattern")
-		in.Root = r.URL.Query().Get("root")
-		in.ProjectID = r.URL.Query().Get("project_id")
+		q := r.URL.Query()
+		in.Pattern = q.Get("pattern")
+		in.Root = q.Get("root")
+		in.ProjectID = q.Get("project_id")
+		in.Format = q.Get("format")
+		in.MaxEntries, _ = strconv.Atoi(q.Get("max_entries"))
+	}
+	format, err := blueprint.ParseTreeFormat(in.Format)
+	if err != nil {
+		writeDomainError(w, err)
+		return
 	}
 	paths, err := h.svc.ListMatchingPaths(in.Root, in.ProjectID, in.Pattern)
 	if err != nil {
 		writeDomainError(w, err)
 		return
 	}
-	writeJSON(w, mcp.ListMatchingPathsOut{Paths: paths})
+	if format == blueprint.TreeFormatJSON {
+		writeJSON(w, mcp.ListMatchingPathsOut{Paths: paths})
+		return
+	}
+	h.writeTreeText(w, format, blueprint.PathsTree(paths), in.Root, in.ProjectID, 0, in.MaxEntries)
 }
 
 func (h *Handler) handleGetZone(w http.ResponseWriter, r *http.Request) {
@@ -274,7 +361,7 @@ func (h *Handler) handleCreateZone(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, "invalid body", http.StatusBadRequest)
 		return
 	}
-	z, err := h.svc.CreateZone(in.ProjectID, in.Name, in.Pattern, in.Purpose, in.Constraints, mcp.DTOToAgents(in.AssignedAgents))
+	z, err := h.svc.CreateZone(in.ProjectID, in.Name, in.Pattern, in.Purpose, in.Constraints, mcp.DTOToAgentIDs(in.AssignedAgents))
 	if err != nil {
 		writeDomainError(w, err)
 		return
@@ -292,7 +379,7 @@ func (h *Handler) handleUpdateZone(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, "invalid body", http.StatusBadRequest)
 		return
 	}
-	z, err := h.svc.UpdateZone(in.ZoneID, in.Name, in.Pattern, in.Purpose, in.Constraints, mcp.DTOToAgents(in.AssignedAgents))
+	z, err := h.svc.UpdateZone(in.ZoneID, in.Name, in.Pattern, in.Purpose, in.Constraints, mcp.DTOToAgentIDs(in.AssignedAgents))
 	if err != nil {
 		writeDomainError(w, err)
 		return
@@ -363,7 +450,7 @@ func (h *Handler) handleCreateAgent(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, "invalid body", http.StatusBadRequest)
 		return
 	}
-	a, err := h.svc.CreateAgent(in.Name, in.Description, in.Prompt)
+	a, err := h.svc.CreateAgent(in.Name, in.Description, in.Prompt, mcp.DTOToVariables(in.Variables))
 	if err != nil {
 		writeDomainError(w, err)
 		return
@@ -381,7 +468,7 @@ func (h *Handler) handleUpdateAgent(w http.ResponseWriter, r *http.Request) {
 		writeJSONError(w, "invalid body", http.StatusBadRequest)
 		return
 	}
-	a, err := h.svc.UpdateAgent(in.AgentID, in.Name, in.Description, in.Prompt)
+	a, err := h.svc.UpdateAgent(in.AgentID, in.Name, in.Description, in.Prompt, mcp.DTOToVariables(in.Variables))
 	if err != nil {
 		writeDomainError(w, err)
 		return
@@ -406,26 +493,1003 @@ func (h *Handler) handleDeleteAgent(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func writeJSON(w http.ResponseWriter, v any) {
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(v)
+func (h *Handler) handleIssueAgentToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.AgentTokenIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	token, err := h.svc.IssueAgentToken(in.AgentID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.IssueAgentTokenOut{AgentID: in.AgentID, Token: token})
 }
 
-func writeJSONError(w http.ResponseWriter, message string, status int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+func (h *Handler) handleRevokeAgentToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.AgentTokenIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.RevokeAgentToken(in.AgentID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func writeDomainError(w http.ResponseWriter, err error) {
-	var se *domain.StructuredError
-	if errors.As(err, &se) {
-		switch se.Code {
-		case "ZONE_NOT_FOUND", "PROJECT_NOT_FOUND", "AGENT_NOT_FOUND":
-			writeJSONError(w, se.Message, http.StatusNotFound)
+func (h *Handler) handleRenderAgentPrompt(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.RenderAgentPromptIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	p, err := h.svc.RenderAgentPrompt(in.AgentID, in.ZoneID, in.Task, in.Variables)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.RenderAgentPromptOut{Text: p.Text})
+}
+
+func (h *Handler) handleExportDiagram(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ExportDiagramIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
 			return
-		case "INVALID_PATTERN", "INVALID_NAME", "INVALID_ROOT", "INVALID_PATH":
-			writeJSONError(w, se.Message, http.StatusBadRequest)
+		}
+	} else {
+		q := r.URL.Query()
+		in.ProjectID = q.Get("project_id")
+		in.Format = q.Get("format")
+		in.GroupNested = q.Get("group_nested") == "true"
+		in.ZoneIDs = q["zone_id"]
+	}
+	if in.Format == "" {
+		in.Format = blueprint.DiagramMermaid
+	}
+	diagram, err := h.svc.ExportDiagram(in.ProjectID, blueprint.DiagramOptions{
+		Format:      in.Format,
+		GroupNested: in.GroupNested,
+		ZoneIDs:     in.ZoneIDs,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ExportDiagramOut{Format: in.Format, Diagram: diagram})
+}
+
+func (h *Handler) handleExportBlueprint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ExportBlueprintIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.ProjectID = r.URL.Query().Get("project_id")
+		in.Format = r.URL.Query().Get("format")
+	}
+	if in.Format == "" {
+		in.Format = blueprint.FormatYAML
+	}
+	doc, err := h.svc.ExportBlueprint(in.ProjectID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	b, err := blueprint.MarshalDocument(doc, in.Format)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ExportBlueprintOut{Format: in.Format, Document: string(b)})
+}
+
+func (h *Handler) handleImportBlueprint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ImportBlueprintIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	doc, err := blueprint.UnmarshalDocument([]byte(in.Document), in.Format)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	res, err := h.svc.ImportBlueprint(doc, blueprint.ImportOptions{
+		ProjectID: in.ProjectID,
+		RootDir:   in.RootDir,
+		Mode:      in.Mode,
+		DryRun:    in.DryRun,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (h *Handler) handleBindBlueprintFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.BindBlueprintFileIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.BindBlueprintFile(in.ProjectID, in.File, in.Initial)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (h *Handler) handleUnbindBlueprintFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.UnbindBlueprintFileIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	p, err := h.svc.UnbindBlueprintFile(in.ProjectID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.UnbindBlueprintFileOut{Project: mcp.ProjectToDTO(p)})
+}
+
+func (h *Handler) handleSyncBlueprint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.SyncBlueprintIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.SyncBlueprint(in.ProjectID, in.Resolve)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (h *Handler) handleReadFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ReadFileIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.ReadFile(in.ProjectID, in.Path, blueprint.ReadOptions{
+		AgentID:   in.AgentID,
+		StartLine: in.StartLine,
+		EndLine:   in.EndLine,
+		MaxBytes:  in.MaxBytes,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (h *Handler) handleReadZoneFiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ReadZoneFilesIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.ReadZoneFiles(in.ZoneID, blueprint.ReadOptions{AgentID: in.AgentID, MaxBytes: in.MaxBytes})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (h *Handler) handleBuildContext(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.BuildContextIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	res, err := h.svc.BuildContext(in.ProjectID, blueprint.ContextOptions{ZoneID: in.ZoneID, Paths: in.Paths, MaxTokens: in.MaxTokens, AgentID: in.AgentID})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, res)
+}
+
+func (h *Handler) handleWriteFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.WriteFileIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	c, err := h.svc.WriteFile(in.ProjectID, in.AgentID, in.Path, in.Content)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.WriteFileOut{Change: mcp.FileChangesToDTO([]*domain.FileChange{c})[0]})
+}
+
+func (h *Handler) handleApplyPatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ApplyPatchIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	changes, err := h.svc.ApplyPatch(in.ProjectID, in.AgentID, in.Patch)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ApplyPatchOut{Changes: mcp.FileChangesToDTO(changes)})
+}
+
+func (h *Handler) handleListChanges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ListChangesIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.ProjectID = r.URL.Query().Get("project_id")
+		in.Limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+	}
+	changes, err := h.svc.ListChanges(in.ProjectID, in.Limit)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ListChangesOut{Changes: mcp.FileChangesToDTO(changes)})
+}
+
+func (h *Handler) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.CreateTaskIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	t, err := h.svc.CreateTask(r.Context(), in.ProjectID, in.Title, in.Description, in.ZoneIDs, in.AgentID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.TaskOut{Task: mcp.TaskToDTO(t)})
+}
+
+func (h *Handler) handleListTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ListTasksIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		in.ProjectID = q.Get("project_id")
+		in.Status = q.Get("status")
+		in.AgentID = q.Get("agent_id")
+		in.ZoneID = q.Get("zone_id")
+	}
+	tasks, err := h.svc.ListTasks(in.ProjectID, blueprint.TaskFilter{Status: in.Status, AgentID: in.AgentID, ZoneID: in.ZoneID})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ListTasksOut{Tasks: mcp.TasksToDTO(tasks)})
+}
+
+func (h *Handler) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.GetTaskIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.TaskID = r.URL.Query().Get("task_id")
+	}
+	t, err := h.svc.GetTask(in.TaskID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.TaskOut{Task: mcp.TaskToDTO(t)})
+}
+
+func (h *Handler) handleClaimTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ClaimTaskIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	t, err := h.svc.ClaimTask(r.Context(), in.TaskID, in.AgentID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.TaskOut{Task: mcp.TaskToDTO(t)})
+}
+
+func (h *Handler) handleUpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.UpdateTaskStatusIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	t, err := h.svc.UpdateTaskStatus(r.Context(), in.TaskID, in.Status)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.TaskOut{Task: mcp.TaskToDTO(t)})
+}
+
+func (h *Handler) handleCompleteTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.CompleteTaskIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	t, err := h.svc.CompleteTask(r.Context(), in.TaskID, in.Result)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.TaskOut{Task: mcp.TaskToDTO(t)})
+}
+
+func (h *Handler) handleRouteTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.RouteTaskIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	var route *blueprint.TaskRoute
+	var err error
+	if in.TaskID != "" {
+		route, err = h.svc.RouteExistingTask(in.TaskID, in.Files)
+	} else {
+		route, err = h.svc.RouteTask(in.ProjectID, in.Title, in.Title+"\n"+in.Description, in.Files)
+	}
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.TaskRouteToDTO(route))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, message string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+func (h *Handler) handleClaimZone(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ClaimZoneIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	l, err := h.svc.ClaimZone(r.Context(), in.ZoneID, in.AgentID, time.Duration(in.TTLSeconds)*time.Second)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.LeaseOut{Lease: mcp.LeaseToDTO(l)})
+}
+
+func (h *Handler) handleRenewLease(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ClaimZoneIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	l, err := h.svc.RenewLease(r.Context(), in.ZoneID, in.AgentID, time.Duration(in.TTLSeconds)*time.Second)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.LeaseOut{Lease: mcp.LeaseToDTO(l)})
+}
+
+func (h *Handler) handleReleaseZone(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ReleaseZoneIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.ReleaseZone(r.Context(), in.ZoneID, in.AgentID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleListLeases(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ListLeasesIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.ProjectID = r.URL.Query().Get("project_id")
+	}
+	leases, err := h.svc.ListLeases(in.ProjectID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ListLeasesOut{Leases: mcp.LeasesToDTO(leases)})
+}
+
+func (h *Handler) handleCreateRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.CreateRunIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	tasks := make([]domain.RunTask, len(in.Tasks))
+	for i, t := range in.Tasks {
+		tasks[i] = domain.RunTask{TaskID: t.TaskID, DependsOn: t.DependsOn}
+	}
+	run, err := h.svc.CreateRun(r.Context(), in.ProjectID, in.Title, in.Description, tasks)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.RunOut{Run: mcp.RunToDTO(run, h.svc.RunProgress(run))})
+}
+
+func (h *Handler) handleSetRunTask(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.SetRunTaskIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	run, err := h.svc.SetRunTask(in.RunID, in.TaskID, in.DependsOn)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.RunOut{Run: mcp.RunToDTO(run, h.svc.RunProgress(run))})
+}
+
+func (h *Handler) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.RunIDIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.RunID = r.URL.Query().Get("run_id")
+	}
+	run, err := h.svc.GetRun(in.RunID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.RunOut{Run: mcp.RunToDTO(run, h.svc.RunProgress(run))})
+}
+
+func (h *Handler) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ListRunsIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.ProjectID = r.URL.Query().Get("project_id")
+	}
+	runs, err := h.svc.ListRuns(in.ProjectID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ListRunsOut{Runs: mcp.RunsToDTO(h.svc, runs)})
+}
+
+func (h *Handler) handleDeleteRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.RunIDIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.DeleteRun(in.RunID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleCreateDecision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.CreateDecisionIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	d, err := h.svc.CreateDecision(r.Context(), in.ProjectID, blueprint.DecisionDraft{
+		Title:        in.Title,
+		Context:      in.Context,
+		Decision:     in.Decision,
+		Consequences: in.Consequences,
+		Status:       in.Status,
+		ZoneIDs:      in.ZoneIDs,
+		Supersedes:   in.Supersedes,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.DecisionOut{Decision: mcp.DecisionToDTO(d)})
+}
+
+func (h *Handler) handleSetDecisionStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.SetDecisionStatusIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	d, err := h.svc.SetDecisionStatus(in.DecisionID, in.Status)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.DecisionOut{Decision: mcp.DecisionToDTO(d)})
+}
+
+func (h *Handler) handleGetDecision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.GetDecisionIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.DecisionID = r.URL.Query().Get("decision_id")
+	}
+	d, err := h.svc.GetDecision(in.DecisionID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.DecisionOut{Decision: mcp.DecisionToDTO(d)})
+}
+
+func (h *Handler) handleListDecisions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ListDecisionsIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		in.ProjectID = q.Get("project_id")
+		in.ZoneID = q.Get("zone_id")
+		in.Status = q.Get("status")
+		in.Query = q.Get("query")
+	}
+	decisions, err := h.svc.ListDecisions(in.ProjectID, blueprint.DecisionFilter{ZoneID: in.ZoneID, Status: in.Status, Query: in.Query})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ListDecisionsOut{Decisions: mcp.DecisionsToDTO(decisions)})
+}
+
+func (h *Handler) handleExportDecisions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ExportDecisionsIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	paths, err := h.svc.ExportDecisions(r.Context(), in.ProjectID, in.AgentID, in.Dir)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.ExportDecisionsOut{Paths: paths})
+}
+
+func (h *Handler) handleAddZoneNote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.AddZoneNoteIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	n, err := h.svc.AddZoneNote(r.Context(), in.ZoneID, in.AgentID, blueprint.NoteDraft{
+		Text:   in.Text,
+		Tags:   in.Tags,
+		Pinned: in.Pinned,
+		TTL:    time.Duration(in.TTLDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.NoteOut{Note: mcp.NoteToDTO(n)})
+}
+
+func (h *Handler) handleListZoneNotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ListZoneNotesIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.ZoneID = r.URL.Query().Get("zone_id")
+		in.Tag = r.URL.Query().Get("tag")
+	}
+	notes, err := h.svc.ListZoneNotes(in.ZoneID, in.Tag)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.NotesOut{Notes: mcp.NotesToDTO(notes)})
+}
+
+func (h *Handler) handleSearchZoneNotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.SearchZoneNotesIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		in.ProjectID = q.Get("project_id")
+		in.Query = q.Get("query")
+		in.Tags = q["tag"]
+		in.ZoneID = q.Get("zone_id")
+		in.Limit, _ = strconv.Atoi(q.Get("limit"))
+	}
+	notes, err := h.svc.SearchZoneNotes(in.ProjectID, blueprint.NoteQuery{ZoneID: in.ZoneID, Query: in.Query, Tags: in.Tags, Limit: in.Limit})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.NotesOut{Notes: mcp.NotesToDTO(notes)})
+}
+
+func (h *Handler) handleDeleteZoneNote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.DeleteZoneNoteIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.DeleteZoneNote(r.Context(), in.NoteID); err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleSendMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.SendMessageIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	messages, err := h.svc.SendMessage(r.Context(), in.AgentID, blueprint.MessageDraft{
+		ProjectID: in.ProjectID,
+		ToAgentID: in.ToAgentID,
+		ZoneID:    in.ZoneID,
+		ReplyTo:   in.ReplyTo,
+		Subject:   in.Subject,
+		Body:      in.Body,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.MessagesOut{Messages: mcp.MessagesToDTO(messages)})
+}
+
+func (h *Handler) handleListInbox(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.ListInboxIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		in.AgentID = q.Get("agent_id")
+		in.ProjectID = q.Get("project_id")
+		in.UnreadOnly = q.Get("unread_only") == "true"
+	}
+	messages, err := h.svc.ListInbox(r.Context(), in.AgentID, in.ProjectID, in.UnreadOnly)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.MessagesOut{Messages: mcp.MessagesToDTO(messages)})
+}
+
+func (h *Handler) handleAcknowledgeMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.MessageIDIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	m, err := h.svc.AcknowledgeMessage(r.Context(), in.MessageID, in.AgentID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.MessageOut{Message: mcp.MessageToDTO(m)})
+}
+
+func (h *Handler) handleGetThread(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.MessageIDIn
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			writeJSONError(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+	} else {
+		in.MessageID = r.URL.Query().Get("message_id")
+		in.AgentID = r.URL.Query().Get("agent_id")
+	}
+	messages, err := h.svc.MessageThread(r.Context(), in.MessageID, in.AgentID)
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.MessagesOut{Messages: mcp.MessagesToDTO(messages)})
+}
+
+func (h *Handler) handleGetBriefing(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var in mcp.GetBriefingIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSONError(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	b, err := h.svc.GetBriefing(r.Context(), in.AgentID, in.ZoneID, blueprint.BriefingOptions{
+		ProjectID: in.ProjectID,
+		Task:      in.Task,
+		Variables: in.Variables,
+		MaxChars:  in.MaxChars,
+		MaxTokens: in.MaxTokens,
+	})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, mcp.BriefingToDTO(b))
+}
+
+func writeDomainError(w http.ResponseWriter, err error) {
+	var oz *domain.OutOfZoneError
+	if errors.As(err, &oz) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(mcp.OutOfZoneToDTO(oz))
+		return
+	}
+	var se *domain.StructuredError
+	if errors.As(err, &se) {
+		switch se.Code {
+		case "ZONE_NOT_FOUND", "PROJECT_NOT_FOUND", "AGENT_NOT_FOUND", "FILE_NOT_FOUND", "TASK_NOT_FOUND", "RUN_NOT_FOUND",
+			"MESSAGE_NOT_FOUND", "DECISION_NOT_FOUND", "NOTE_NOT_FOUND":
+			writeJSONError(w, se.Message, http.StatusNotFound)
+			return
+		case "INVALID_PATTERN", "INVALID_NAME", "INVALID_ROOT", "INVALID_PATH", "INVALID_FORMAT",
+			"INVALID_DOCUMENT", "INVALID_MODE", "BLUEPRINT_NOT_BOUND", "INVALID_PROMPT", "INVALID_VARIABLE",
+			"MISSING_VARIABLE", "UNKNOWN_VARIABLE", "PATH_IGNORED", "INVALID_RANGE", "NOT_A_FILE", "BINARY_FILE",
+			"INVALID_PATCH", "AGENT_REQUIRED", "PROJECT_REQUIRED", "TITLE_REQUIRED", "INVALID_STATUS", "INVALID_TTL", "INVALID_DEPENDENCY", "DEPENDENCY_CYCLE",
+			"BODY_REQUIRED", "INVALID_RECIPIENT", "TEXT_REQUIRED", "NOTE_TOO_LONG", "TOO_MANY_TAGS", "INVALID_BUDGET", "PATHS_REQUIRED":
+			writeJSONError(w, se.Message, http.StatusBadRequest)
+			return
+		case "OUT_OF_ZONE", "IDENTITY_MISMATCH", "NOT_RECIPIENT", "NOT_AUTHOR", "NOT_PARTICIPANT":
+			writeJSONError(w, se.Message, http.StatusForbidden)
+			return
+		case "INVALID_TOKEN", "UNAUTHENTICATED":
+			writeJSONError(w, se.Message, http.StatusUnauthorized)
+			return
+		case "PATCH_CONFLICT", "TASK_ASSIGNED", "TASK_CLOSED", "TASK_NOT_CLAIMED", "ZONE_LEASED", "LEASE_NOT_HELD",
+			"TASK_IN_RUN", "TASK_NOT_READY", "TOO_MANY_PINNED", "UNNAMED_AGENT":
+			writeJSONError(w, se.Message, http.StatusConflict)
+			return
+		case "FILE_TOO_LARGE":
+			writeJSONError(w, se.Message, http.StatusRequestEntityTooLarge)
 			return
 		}
 	}
@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"operators-mcp/internal/application/blueprint"
+)
+
+// ProjectEndpointPattern is the mux pattern of the per-project MCP endpoint.
+const ProjectEndpointPattern = "/projects/{id}/mcp"
+
+type endpointProjectKey struct{}
+
+// ActiveProjects maps MCP sessions to the project their tools default to. A session binds one with
+// set_active_project on the global endpoint; on the per-project endpoint the project is fixed by
+// the path. Tools then take project_id as optional (see blueprint.ActiveProject).
+type ActiveProjects struct {
+	svc *blueprint.Service
+
+	mu       sync.RWMutex
+	sessions map[string]string // session id -> project id
+}
+
+// NewActiveProjects returns an empty session-to-project mapping. Bindings to deleted projects are
+// dropped. Add its hooks with AddHooks and its ToolMiddleware to the server, register its tool
+// with RegisterTools and wrap the HTTP transport with Handler.
+func NewActiveProjects(svc *blueprint.Service) *ActiveProjects {
+	a := &ActiveProjects{svc: svc, sessions: make(map[string]string)}
+	svc.Subscribe(func(e blueprint.Event) {
+		if e.Kind != blueprint.EventProject || !e.Deleted {
+			return
+		}
+		a.mu.Lock()
+		for sessionID, projectID := range a.sessions {
+			if projectID == e.ProjectID {
+				delete(a.sessions, sessionID)
+			}
+		}
+		a.mu.Unlock()
+	})
+	return a
+}
+
+// AddHooks registers the hook that answers initialize with server instructions generated from the
+// blueprint for the request's project and caller (see blueprint.Service.ServerInstructions).
+func (a *ActiveProjects) AddHooks(h *server.Hooks) {
+	h.AddAfterInitialize(func(ctx context.Context, id any, req *mcp.InitializeRequest, res *mcp.InitializeResult) {
+		res.Instructions = a.svc.ServerInstructions(blueprint.ActiveProject(ctx), blueprint.CallerID(ctx))
+	})
+}
+
+// endpointRefs maps the tool arguments that name an entity by id to the entity's event kind.
+var endpointRefs = map[string]string{
+	"zone_id":     blueprint.EventZone,
+	"zone_ids":    blueprint.EventZone,
+	"task_id":     blueprint.EventTask,
+	"run_id":      blueprint.EventRun,
+	"decision_id": blueprint.EventDecision,
+	"message_id":  blueprint.EventMessage,
+	"reply_to":    blueprint.EventMessage,
+	"supersedes":  blueprint.EventDecision,
+	"note_id":     blueprint.EventNote,
+}
+
+// ToolMiddleware refuses, on the per-project endpoint, tool calls naming a zone, task, run,
+// decision, message or note of another project. Unknown ids are left to the tool to report.
+func (a *ActiveProjects) ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		fixed := endpointProject(ctx)
+		if fixed == "" {
+			return next(ctx, req)
+		}
+		for name, value := range req.GetArguments() {
+			kind, ok := endpointRefs[name]
+			if !ok {
+				continue
+			}
+			ids, _ := value.([]any)
+			if id, ok := value.(string); ok {
+				ids = []any{id}
+			}
+			for _, v := range ids {
+				id, _ := v.(string)
+				if p := a.svc.EntityProject(kind, id); p != "" && p != fixed {
+					return mcp.NewToolResultError(name + " " + id + " is not in this endpoint's project " + fixed), nil
+				}
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+// RegisterTools adds set_active_project.
+func (a *ActiveProjects) RegisterTools(s *server.MCPServer) {
+	s.AddTool(mcp.NewTool("set_active_project",
+		mcp.WithDescription(setActiveProjectDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (empty clears the binding)")),
+	), a.toolSetActiveProject)
+}
+
+const setActiveProjectDescription = "Bind a project to this MCP session so that tools default to it when project_id is omitted, and return the server instructions refreshed for it. An empty project_id clears the binding. Not available on /projects/{id}/mcp, where the project is fixed."
+
+func (a *ActiveProjects) toolSetActiveProject(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if endpointProject(ctx) != "" {
+		return mcp.NewToolResultError("the project is fixed by this endpoint"), nil
+	}
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" {
+		return mcp.NewToolResultError("set_active_project requires a session"), nil
+	}
+	projectID := req.GetString("project_id", "")
+	out := SetActiveProjectOut{}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if projectID == "" {
+		delete(a.sessions, session.SessionID())
+	} else {
+		p := a.svc.GetProject(projectID)
+		if p == nil {
+			return mcp.NewToolResultError("project not found"), nil
+		}
+		a.sessions[session.SessionID()] = p.ID
+		out.Project = ProjectToDTO(p)
+	}
+	out.Instructions = a.svc.ServerInstructions(projectID, blueprint.CallerID(ctx))
+	return jsonResult(out)
+}
+
+// ProjectID returns the project bound to the session, or "".
+func (a *ActiveProjects) ProjectID(sessionID string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.sessions[sessionID]
+}
+
+// Handler passes the session's active project to next in the request context unless the request
+// already has one (from ProjectEndpoint). Deleting a session drops its binding.
+func (a *ActiveProjects) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if r.Method == http.MethodDelete && sessionID != "" {
+			a.mu.Lock()
+			delete(a.sessions, sessionID)
+			a.mu.Unlock()
+		}
+		if blueprint.ActiveProject(r.Context()) == "" {
+			r = r.WithContext(blueprint.WithActiveProject(r.Context(), a.ProjectID(sessionID)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ProjectEndpoint serves ProjectEndpointPattern with next, the global MCP handler, with the
+// project of the path as every request's active project.
+func (a *ActiveProjects) ProjectEndpoint(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		projectID := r.PathValue("id")
+		if a.svc.GetProject(projectID) == nil {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		ctx := context.WithValue(r.Context(), endpointProjectKey{}, projectID)
+		next.ServeHTTP(w, r.WithContext(blueprint.WithActiveProject(ctx, projectID)))
+	})
+}
+
+// endpointProject returns the project fixed by ProjectEndpoint, or "" on other endpoints.
+func endpointProject(ctx context.Context) string {
+	id, _ := ctx.Value(endpointProjectKey{}).(string)
+	return id
+}
+
+// checkEndpointProject refuses a projectID other than the one fixed by the endpoint.
+func checkEndpointProject(ctx context.Context, projectID string) error {
+	if fixed := endpointProject(ctx); fixed != "" && projectID != "" && projectID != fixed {
+		return errors.New("project_id " + projectID + " is not this endpoint's project " + fixed)
+	}
+	return nil
+}
@@ -0,0 +1,399 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"operators-mcp/internal/application/blueprint"
+)
+
+// AgentEndpointPattern is the mux pattern of the per-agent MCP endpoint.
+const AgentEndpointPattern = "/agents/{id}/mcp"
+
+// AgentServers serves AgentEndpointPattern with one MCP server per agent, created on first use.
+// Each server only offers the tools an agent needs to work in its zones; listings are restricted
+// to those zones and every call acts as the agent. Server instructions are the agent's prompt.
+type AgentServers struct {
+	svc *blueprint.Service
+
+	// AllowAnonymous serves requests without a bearer token. By default every request must carry
+	// a token of the endpoint's agent, since the endpoint acts for it.
+	AllowAnonymous bool
+
+	mu       sync.Mutex
+	handlers map[string]http.Handler      // agent id -> streamable HTTP server
+	servers  map[string]*server.MCPServer // agent id -> MCP server behind the handler
+}
+
+// NewAgentServers returns the per-agent endpoint handler. Servers of deleted agents are dropped,
+// and new messages are delivered to the sessions of their recipient's server (see
+// Identities.DeliverMessages).
+func NewAgentServers(svc *blueprint.Service) *AgentServers {
+	a := &AgentServers{svc: svc, handlers: make(map[string]http.Handler), servers: make(map[string]*server.MCPServer)}
+	svc.Subscribe(func(e blueprint.Event) {
+		if e.Kind == blueprint.EventAgent && e.Deleted {
+			a.mu.Lock()
+			delete(a.handlers, e.ID)
+			delete(a.servers, e.ID)
+			a.mu.Unlock()
+		}
+		if m := newMessage(svc, e); m != nil {
+			a.mu.Lock()
+			s := a.servers[m.ToAgentID]
+			a.mu.Unlock()
+			if s != nil {
+				s.SendNotificationToAllClients(methodNotificationMessage, inboxNotification(m))
+			}
+		}
+	})
+	return a
+}
+
+// ServeHTTP routes the request to the agent's server. The request must carry a bearer token of
+// the agent of the path, unless AllowAnonymous is set; a token that is sent must belong to it.
+func (a *AgentServers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	agentID := r.PathValue("id")
+	if token, ok := bearerToken(r); ok {
+		ag, err := a.svc.AgentForToken(token)
+		if err != nil {
+			unauthorized(w, err.Error())
+			return
+		}
+		if ag.ID != agentID {
+			unauthorized(w, "token belongs to another agent")
+			return
+		}
+	} else if !a.AllowAnonymous {
+		unauthorized(w, "a bearer token of the endpoint's agent is required")
+		return
+	}
+	if a.svc.GetAgent(agentID) == nil {
+		http.Error(w, "agent not found", http.StatusNotFound)
+		return
+	}
+	a.mu.Lock()
+	h, ok := a.handlers[agentID]
+	if !ok {
+		s := NewAgentServer(a.svc, agentID)
+		h = server.NewStreamableHTTPServer(s)
+		a.handlers[agentID] = h
+		a.servers[agentID] = s
+	}
+	a.mu.Unlock()
+	h.ServeHTTP(w, r.WithContext(blueprint.WithCaller(r.Context(), agentID)))
+}
+
+// NewAgentServer returns an MCP server scoped to the agent. Its instructions are computed when a
+// session initializes, so they follow edits to the agent and its zones.
+func NewAgentServer(svc *blueprint.Service, agentID string) *server.MCPServer {
+	hooks := &server.Hooks{}
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, req *mcp.InitializeRequest, res *mcp.InitializeResult) {
+		res.Instructions = svc.AgentInstructions(agentID)
+	})
+	s := server.NewMCPServer("operators-mcp", "0.0.1", server.WithToolCapabilities(true), server.WithLogging(), server.WithHooks(hooks))
+	RegisterAgentTools(s, svc, agentID)
+	return s
+}
+
+// RegisterAgentTools registers the tools of an agent-scoped server. The file tools are the
+// global ones without agent_id, since the caller is always the agent.
+func RegisterAgentTools(s *server.MCPServer, svc *blueprint.Service, agentID string) {
+	s.AddTool(mcp.NewTool("whoami",
+		mcp.WithDescription("Return the agent this endpoint is scoped to."),
+	), toolWhoAmI(svc))
+
+	s.AddTool(mcp.NewTool("list_projects",
+		mcp.WithDescription("Return the projects in which you are assigned zones."),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return jsonResult(ListProjectsOut{Projects: ProjectsToDTO(svc.AgentProjects(agentID))})
+	})
+
+	s.AddTool(mcp.NewTool("list_zones",
+		mcp.WithDescription("Return the zones assigned to you, in one project or in all of them."),
+		mcp.WithString("project_id", mcp.Description("Project ID (default all projects)")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zones, err := svc.AgentZones(agentID, req.GetString("project_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ListZonesOut{Zones: ZonesToDTO(zones)})
+	})
+
+	s.AddTool(mcp.NewTool("get_zone",
+		mcp.WithDescription("Return one of your zones by ID."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zoneID, err := req.RequireString("zone_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		z, err := svc.GetAgentZone(agentID, zoneID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(GetZoneOut{Zone: ZoneToDTO(z)})
+	})
+
+	s.AddTool(mcp.NewTool("list_tree",
+		mcp.WithDescription("Return the project's directory tree restricted to your zones."),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional when your zones are in one project)")),
+		mcp.WithNumber("depth", mcp.Description("Text formats: summarize directories at this depth")),
+		mcp.WithString("format", mcp.Description(treeFormatDescription), mcp.Enum("json", "text", "collapsed")),
+		mcp.WithNumber("max_entries", mcp.Description(maxEntriesDescription)),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := blueprint.ParseTreeFormat(req.GetString("format", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		tree, err := svc.ListAgentTree(agentID, req.GetString("project_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		if format == blueprint.TreeFormatJSON {
+			return jsonResult(ListTreeOut{Tree: TreeNodeToDTO(tree)})
+		}
+		zones, _ := svc.AgentZones(agentID, req.GetString("project_id", ""))
+		return treeTextResult(req, format, tree, zones), nil
+	})
+
+	s.AddTool(mcp.NewTool("list_matching_paths",
+		mcp.WithDescription("Return the paths in your zones that match a regex pattern."),
+		mcp.WithString("pattern", mcp.Required(), mcp.Description("Regex pattern")),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional when your zones are in one project)")),
+		mcp.WithString("format", mcp.Description(treeFormatDescription), mcp.Enum("json", "text", "collapsed")),
+		mcp.WithNumber("max_entries", mcp.Description(maxEntriesDescription)),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		pattern, err := req.RequireString("pattern")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		format, err := blueprint.ParseTreeFormat(req.GetString("format", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		paths, err := svc.ListAgentMatchingPaths(agentID, req.GetString("project_id", ""), pattern)
+		if err != nil {
+			return toolError(err)
+		}
+		if format == blueprint.TreeFormatJSON {
+			return jsonResult(ListMatchingPathsOut{Paths: paths})
+		}
+		zones, _ := svc.AgentZones(agentID, req.GetString("project_id", ""))
+		return treeTextResult(req, format, blueprint.PathsTree(paths), zones), nil
+	})
+
+	s.AddTool(mcp.NewTool("render_agent_prompt",
+		mcp.WithDescription("Render your prompt, optionally for one of your zones and a task."),
+		mcp.WithString("zone_id", mcp.Description("Zone ID")),
+		mcp.WithString("task", mcp.Description("Task description")),
+		mcp.WithObject("variables", mcp.Description("Values for the prompt's declared variables")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if zoneID := req.GetString("zone_id", ""); zoneID != "" {
+			if _, err := svc.GetAgentZone(agentID, zoneID); err != nil {
+				return toolError(err)
+			}
+		}
+		args := map[string]any{"agent_id": agentID}
+		for k, v := range req.GetArguments() {
+			if k != "agent_id" {
+				args[k] = v
+			}
+		}
+		req.Params.Arguments = args
+		return toolRenderAgentPrompt(svc)(ctx, req)
+	})
+
+	s.AddTool(mcp.NewTool("read_file",
+		mcp.WithDescription("Read a text file in one of your zones, optionally a line range."),
+		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("path", mcp.Required(), mcp.Description("File path relative to the project root")),
+		mcp.WithNumber("start_line", mcp.Description("First line to return (1-based)")),
+		mcp.WithNumber("end_line", mcp.Description("Last line to return (inclusive)")),
+		mcp.WithNumber("max_bytes", mcp.Description("Maximum bytes of content to return")),
+	), toolReadFile(svc))
+
+	s.AddTool(mcp.NewTool("read_zone_files",
+		mcp.WithDescription("Read the text files of one of your zones, up to max_bytes in total."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithNumber("max_bytes", mcp.Description("Maximum total bytes of content to return")),
+	), toolReadZoneFiles(svc))
+
+	s.AddTool(mcp.NewTool("build_context",
+		mcp.WithDescription("Bundle the files of one of your zones, or files and directories in your zones, into one text within a token budget: entry points first, then small files, then recently changed ones. Files left out are listed in omitted."),
+		mcp.WithString("project_id", mcp.Description("Project ID (required with paths)")),
+		mcp.WithString("zone_id", mcp.Description("Zone ID")),
+		mcp.WithArray("paths", mcp.Description("Files or directories relative to the project root"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithNumber("max_tokens", mcp.Description("Token budget (default 16000)")),
+	), toolBuildContext(svc))
+
+	s.AddTool(mcp.NewTool("write_file",
+		mcp.WithDescription("Create or replace a file in one of your zones. The change is recorded."),
+		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("path", mcp.Required(), mcp.Description("File path relative to the project root")),
+		mcp.WithString("content", mcp.Description("New file content")),
+	), toolWriteFile(svc))
+
+	s.AddTool(mcp.NewTool("apply_patch",
+		mcp.WithDescription("Apply a unified diff touching only your zones. Nothing is written unless every path is yours and every hunk applies."),
+		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("patch", mcp.Required(), mcp.Description("Unified diff with paths relative to the project root (git a/ b/ prefixes allowed)")),
+	), toolApplyPatch(svc))
+
+	s.AddTool(mcp.NewTool("claim_zone",
+		mcp.WithDescription("Take an exclusive, expiring write lease on one of your zones so other agents cannot write there. Claiming a zone you hold extends the lease."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithNumber("ttl_seconds", mcp.Description("Lease duration in seconds (default 600, at most one day)")),
+	), toolClaimZone(svc))
+
+	s.AddTool(mcp.NewTool("renew_lease",
+		mcp.WithDescription("Extend your active lease on a zone to ttl_seconds from now."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithNumber("ttl_seconds", mcp.Description("New lease duration from now in seconds (default 600, at most one day)")),
+	), toolRenewLease(svc))
+
+	s.AddTool(mcp.NewTool("release_zone",
+		mcp.WithDescription("Release your lease on a zone."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+	), toolReleaseZone(svc))
+
+	s.AddTool(mcp.NewTool("list_leases",
+		mcp.WithDescription("List a project's active zone leases: zone, holder agent and expiry."),
+		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := svc.CheckAgentProject(agentID, req.GetString("project_id", "")); err != nil {
+			return toolError(err)
+		}
+		return toolListLeases(svc)(ctx, req)
+	})
+
+	s.AddTool(mcp.NewTool("list_decisions",
+		mcp.WithDescription("List a project's architecture decision records, optionally filtered by zone, status or a text query. Read them before changing how a zone is shaped."),
+		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("zone_id", mcp.Description("Zone the decisions concern")),
+		mcp.WithString("status", mcp.Description("proposed, accepted or superseded")),
+		mcp.WithString("query", mcp.Description("Text to search for in title, context, decision and consequences")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := svc.CheckAgentProject(agentID, req.GetString("project_id", "")); err != nil {
+			return toolError(err)
+		}
+		return toolListDecisions(svc)(ctx, req)
+	})
+
+	s.AddTool(mcp.NewTool("get_decision",
+		mcp.WithDescription("Return one architecture decision record of your projects by id."),
+		mcp.WithString("decision_id", mcp.Required(), mcp.Description("Decision ID")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		decisionID, err := req.RequireString("decision_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		d, err := svc.GetAgentDecision(agentID, decisionID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(DecisionOut{Decision: DecisionToDTO(d)})
+	})
+
+	s.AddTool(mcp.NewTool("add_zone_note",
+		mcp.WithDescription("Add a short note to one of your zones: a gotcha, an entry point, a command that works. Notes expire after ttl_days (default 30) unless pinned."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithString("text", mcp.Required(), mcp.Description("Note text (at most 2000 bytes)")),
+		mcp.WithArray("tags", mcp.Description("Tags, e.g. gotcha, entry-point, command"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithBoolean("pinned", mcp.Description("Keep the note until it is deleted")),
+		mcp.WithNumber("ttl_days", mcp.Description("Days until the note expires (default 30, at most 365; ignored when pinned)")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := svc.GetAgentZone(agentID, req.GetString("zone_id", "")); err != nil {
+			return toolError(err)
+		}
+		return toolAddZoneNote(svc)(ctx, req)
+	})
+
+	s.AddTool(mcp.NewTool("list_zone_notes",
+		mcp.WithDescription("List the current notes of one of your zones, pinned first and then newest first."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithString("tag", mcp.Description("Only notes with this tag")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := svc.GetAgentZone(agentID, req.GetString("zone_id", "")); err != nil {
+			return toolError(err)
+		}
+		return toolListZoneNotes(svc)(ctx, req)
+	})
+
+	s.AddTool(mcp.NewTool("search_zone_notes",
+		mcp.WithDescription("Search the current notes of your zones by words and tags."),
+		mcp.WithString("project_id", mcp.Description("Project ID (required when your zones span projects)")),
+		mcp.WithString("query", mcp.Description("Words that must all appear in the note text or tags")),
+		mcp.WithArray("tags", mcp.Description("Tags the notes must all carry"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of notes (default 50)")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		notes, err := svc.SearchAgentNotes(agentID, req.GetString("project_id", ""), blueprint.NoteQuery{
+			Query: req.GetString("query", ""),
+			Tags:  req.GetStringSlice("tags", nil),
+			Limit: req.GetInt("limit", 0),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(NotesOut{Notes: NotesToDTO(notes)})
+	})
+
+	s.AddTool(mcp.NewTool("delete_zone_note",
+		mcp.WithDescription("Delete a note you wrote."),
+		mcp.WithString("note_id", mcp.Required(), mcp.Description("Note ID")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		noteID, err := req.RequireString("note_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := svc.DeleteAgentZoneNote(ctx, agentID, noteID); err != nil {
+			return toolError(err)
+		}
+		return jsonResult(map[string]string{"deleted": noteID})
+	})
+
+	s.AddTool(mcp.NewTool("send_message",
+		mcp.WithDescription("Send a message to another agent, or to every agent of a zone (e.g. to ask its owner to change an interface). Pass reply_to to answer a message in its thread."),
+		mcp.WithString("project_id", mcp.Description("Project ID (required unless reply_to is given)")),
+		mcp.WithString("to_agent_id", mcp.Description("Recipient agent")),
+		mcp.WithString("zone_id", mcp.Description("Recipient zone: every agent assigned to it")),
+		mcp.WithString("reply_to", mcp.Description("Message ID being answered")),
+		mcp.WithString("subject", mcp.Description("Subject")),
+		mcp.WithString("body", mcp.Required(), mcp.Description("Message text")),
+	), toolSendMessage(svc))
+
+	s.AddTool(mcp.NewTool("list_inbox",
+		mcp.WithDescription("List the messages sent to you, oldest first."),
+		mcp.WithString("project_id", mcp.Description("Project ID (default all projects)")),
+		mcp.WithBoolean("unread_only", mcp.Description("Leave out acknowledged messages")),
+	), toolListInbox(svc))
+
+	s.AddTool(mcp.NewTool("acknowledge_message",
+		mcp.WithDescription("Mark a message sent to you as read."),
+		mcp.WithString("message_id", mcp.Required(), mcp.Description("Message ID")),
+	), toolAcknowledgeMessage(svc))
+
+	s.AddTool(mcp.NewTool("get_thread",
+		mcp.WithDescription("Return every message of the thread containing a message, oldest first."),
+		mcp.WithString("message_id", mcp.Required(), mcp.Description("Message ID")),
+	), toolGetThread(svc))
+
+	s.AddTool(mcp.NewTool("get_briefing",
+		mcp.WithDescription("Get your briefing before starting work: your prompt, your zones (purpose, constraints, decisions, notes, owned paths) and the neighbouring zones with their owners, fitted to max_chars or max_tokens."),
+		mcp.WithString("zone_id", mcp.Description("One of your zones (default all of them)")),
+		mcp.WithString("project_id", mcp.Description("Only your zones in this project (ignored with zone_id)")),
+		mcp.WithString("task", mcp.Description("Task description")),
+		mcp.WithObject("variables", mcp.Description("Values for the prompt's declared variables")),
+		mcp.WithNumber("max_chars", mcp.Description("Maximum length of the briefing text in characters")),
+		mcp.WithNumber("max_tokens", mcp.Description("Maximum estimated tokens of the briefing text")),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if zoneID := req.GetString("zone_id", ""); zoneID != "" {
+			if _, err := svc.GetAgentZone(agentID, zoneID); err != nil {
+				return toolError(err)
+			}
+		}
+		return toolGetBriefing(svc)(ctx, req)
+	})
+}
@@ -1,13 +1,28 @@
 package mcp
 
-import "operators-mcp/internal/domain"
+import (
+	"time"
+	"unicode/utf8"
+
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+)
 
 // AgentDTO is the MCP/JSON representation of an agent.
 type AgentDTO struct {
-	ID          string `json:"id"`
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Prompt      string        `json:"prompt,omitempty"`
+	Variables   []VariableDTO `json:"variables,omitempty"`
+}
+
+// VariableDTO is the MCP/JSON representation of a prompt variable declared by an agent.
+type VariableDTO struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
-	Prompt      string `json:"prompt,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"`
 }
 
 // AgentToDTO converts a domain Agent to API DTO.
@@ -20,15 +35,41 @@ func AgentToDTO(a *domain.Agent) *AgentDTO {
 		Name:        a.Name,
 		Description: a.Description,
 		Prompt:      a.Prompt,
+		Variables:   VariablesToDTO(a.Variables),
 	}
 }
 
+// VariablesToDTO converts declared prompt variables to DTOs.
+func VariablesToDTO(v []domain.PromptVariable) []VariableDTO {
+	if len(v) == 0 {
+		return nil
+	}
+	out := make([]VariableDTO, len(v))
+	for i, x := range v {
+		out[i] = VariableDTO{Name: x.Name, Description: x.Description, Required: x.Required, Default: x.Default}
+	}
+	return out
+}
+
+// DTOToVariables converts variable DTOs to domain prompt variables (exported for HTTP adapter).
+func DTOToVariables(v []VariableDTO) []domain.PromptVariable {
+	if len(v) == 0 {
+		return nil
+	}
+	out := make([]domain.PromptVariable, len(v))
+	for i, x := range v {
+		out[i] = domain.PromptVariable{Name: x.Name, Description: x.Description, Required: x.Required, Default: x.Default}
+	}
+	return out
+}
+
 // ProjectDTO is the MCP/JSON representation of a project (snake_case for API contract).
 type ProjectDTO struct {
-	ID           string   `json:"id"`
-	Name         string   `json:"name"`
-	RootDir      string   `json:"root_dir"`
-	IgnoredPaths []string `json:"ignored_paths,omitempty"`
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	RootDir       string   `json:"root_dir"`
+	IgnoredPaths  []string `json:"ignored_paths,omitempty"`
+	BlueprintFile string   `json:"blueprint_file,omitempty"`
 }
 
 // ZoneDTO is the MCP/JSON representation of a zone (snake_case for API contract).
@@ -61,10 +102,11 @@ func ProjectToDTO(p *domain.Project) *ProjectDTO {
 		ignored = nil
 	}
 	return &ProjectDTO{
-		ID:           p.ID,
-		Name:         p.Name,
-		RootDir:      p.RootDir,
-		IgnoredPaths: ignored,
+		ID:            p.ID,
+		Name:          p.Name,
+		RootDir:       p.RootDir,
+		IgnoredPaths:  ignored,
+		BlueprintFile: p.BlueprintFile,
 	}
 }
 
@@ -106,14 +148,14 @@ func AgentsToDTO(a []domain.Agent) []AgentDTO {
 	return out
 }
 
-// DTOToAgents converts AgentDTO slice to domain agents (exported for HTTP adapter).
-func DTOToAgents(a []AgentDTO) []domain.Agent {
+// DTOToAgentIDs returns the agent ids referenced by an AgentDTO slice (exported for HTTP adapter).
+func DTOToAgentIDs(a []AgentDTO) []string {
 	if len(a) == 0 {
 		return nil
 	}
-	out := make([]domain.Agent, len(a))
+	out := make([]string, len(a))
 	for i := range a {
-		out[i] = domain.Agent{ID: a[i].ID, Name: a[i].Name}
+		out[i] = a[i].ID
 	}
 	return out
 }
@@ -143,3 +185,461 @@ func TreeNodeToDTO(n *domain.TreeNode) *TreeNodeDTO {
 		Children: children,
 	}
 }
+
+// FileChangeDTO is the MCP/JSON representation of a recorded file change.
+type FileChangeDTO struct {
+	ID           string    `json:"id"`
+	ProjectID    string    `json:"project_id"`
+	AgentID      string    `json:"agent_id,omitempty"`
+	Path         string    `json:"path"`
+	Operation    string    `json:"operation"`
+	Source       string    `json:"source,omitempty"`
+	OldHash      string    `json:"old_hash,omitempty"`
+	NewHash      string    `json:"new_hash,omitempty"`
+	LinesAdded   int       `json:"lines_added"`
+	LinesRemoved int       `json:"lines_removed"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// FileChangesToDTO converts recorded file changes to DTOs.
+func FileChangesToDTO(changes []*domain.FileChange) []*FileChangeDTO {
+	out := make([]*FileChangeDTO, len(changes))
+	for i, c := range changes {
+		d := FileChangeDTO(*c)
+		out[i] = &d
+	}
+	return out
+}
+
+// ZoneViolationDTO is a path refused with OUT_OF_ZONE and the zones that own it.
+type ZoneViolationDTO struct {
+	Path        string   `json:"path"`
+	OwningZones []string `json:"owning_zones"`
+}
+
+// OutOfZoneDTO is the error body returned for OUT_OF_ZONE.
+type OutOfZoneDTO struct {
+	Code       string             `json:"code"`
+	Error      string             `json:"error"`
+	Violations []ZoneViolationDTO `json:"violations"`
+}
+
+// OutOfZoneToDTO converts an OUT_OF_ZONE error to its JSON body (exported for HTTP adapter).
+func OutOfZoneToDTO(e *domain.OutOfZoneError) *OutOfZoneDTO {
+	out := &OutOfZoneDTO{Code: e.Code, Error: e.Message, Violations: make([]ZoneViolationDTO, len(e.Violations))}
+	for i, v := range e.Violations {
+		zones := v.OwningZones
+		if zones == nil {
+			zones = []string{}
+		}
+		out.Violations[i] = ZoneViolationDTO{Path: v.Path, OwningZones: zones}
+	}
+	return out
+}
+
+// TaskDTO is the MCP/JSON representation of a task.
+type TaskDTO struct {
+	ID          string    `json:"id"`
+	ProjectID   string    `json:"project_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status"`
+	ZoneIDs     []string  `json:"zone_ids"`
+	AgentID     string    `json:"agent_id,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	Result      string    `json:"result,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TaskToDTO converts a task to its DTO.
+func TaskToDTO(t *domain.Task) *TaskDTO {
+	if t == nil {
+		return nil
+	}
+	d := TaskDTO(*t)
+	if d.ZoneIDs == nil {
+		d.ZoneIDs = []string{}
+	}
+	return &d
+}
+
+// TasksToDTO converts tasks to DTOs.
+func TasksToDTO(tasks []*domain.Task) []*TaskDTO {
+	out := make([]*TaskDTO, len(tasks))
+	for i, t := range tasks {
+		out[i] = TaskToDTO(t)
+	}
+	return out
+}
+
+// LeaseDTO is the MCP/JSON representation of a zone lease.
+type LeaseDTO struct {
+	ZoneID     string    `json:"zone_id"`
+	ProjectID  string    `json:"project_id"`
+	AgentID    string    `json:"agent_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// LeaseToDTO converts a lease to its DTO.
+func LeaseToDTO(l *domain.ZoneLease) *LeaseDTO {
+	if l == nil {
+		return nil
+	}
+	d := LeaseDTO(*l)
+	return &d
+}
+
+// LeasesToDTO converts leases to DTOs.
+func LeasesToDTO(leases []*domain.ZoneLease) []*LeaseDTO {
+	out := make([]*LeaseDTO, len(leases))
+	for i, l := range leases {
+		out[i] = LeaseToDTO(l)
+	}
+	return out
+}
+
+// DecisionDTO is the MCP/JSON representation of an architecture decision record.
+type DecisionDTO struct {
+	ID           string    `json:"id"`
+	ProjectID    string    `json:"project_id"`
+	Number       int       `json:"number"`
+	Label        string    `json:"label"`
+	Title        string    `json:"title"`
+	Context      string    `json:"context,omitempty"`
+	Decision     string    `json:"decision,omitempty"`
+	Consequences string    `json:"consequences,omitempty"`
+	Status       string    `json:"status"`
+	ZoneIDs      []string  `json:"zone_ids"`
+	Supersedes   string    `json:"supersedes,omitempty"`
+	CreatedBy    string    `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DecisionToDTO converts a decision to its DTO.
+func DecisionToDTO(d *domain.Decision) *DecisionDTO {
+	if d == nil {
+		return nil
+	}
+	return &DecisionDTO{
+		ID:           d.ID,
+		ProjectID:    d.ProjectID,
+		Number:       d.Number,
+		Label:        d.Label(),
+		Title:        d.Title,
+		Context:      d.Context,
+		Decision:     d.Decision,
+		Consequences: d.Consequences,
+		Status:       d.Status,
+		ZoneIDs:      append([]string{}, d.ZoneIDs...),
+		Supersedes:   d.Supersedes,
+		CreatedBy:    d.CreatedBy,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.UpdatedAt,
+	}
+}
+
+// DecisionsToDTO converts decisions to DTOs.
+func DecisionsToDTO(decisions []*domain.Decision) []*DecisionDTO {
+	out := make([]*DecisionDTO, len(decisions))
+	for i, d := range decisions {
+		out[i] = DecisionToDTO(d)
+	}
+	return out
+}
+
+// NoteDTO is the MCP/JSON representation of a zone note. ExpiresAt is omitted for pinned notes.
+type NoteDTO struct {
+	ID        string     `json:"id"`
+	ProjectID string     `json:"project_id"`
+	ZoneID    string     `json:"zone_id"`
+	AgentID   string     `json:"agent_id,omitempty"`
+	Text      string     `json:"text"`
+	Tags      []string   `json:"tags"`
+	Pinned    bool       `json:"pinned"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// NoteToDTO converts a zone note to its DTO.
+func NoteToDTO(n *domain.ZoneNote) *NoteDTO {
+	if n == nil {
+		return nil
+	}
+	d := &NoteDTO{
+		ID:        n.ID,
+		ProjectID: n.ProjectID,
+		ZoneID:    n.ZoneID,
+		AgentID:   n.AgentID,
+		Text:      n.Text,
+		Tags:      append([]string{}, n.Tags...),
+		Pinned:    n.Pinned,
+		CreatedAt: n.CreatedAt,
+	}
+	if !n.ExpiresAt.IsZero() {
+		expires := n.ExpiresAt
+		d.ExpiresAt = &expires
+	}
+	return d
+}
+
+// NotesToDTO converts zone notes to DTOs.
+func NotesToDTO(notes []*domain.ZoneNote) []*NoteDTO {
+	out := make([]*NoteDTO, len(notes))
+	for i, n := range notes {
+		out[i] = NoteToDTO(n)
+	}
+	return out
+}
+
+// MessageDTO is the MCP/JSON representation of a message. ThreadID is always set: the first
+// message of a thread carries its own id.
+type MessageDTO struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	ThreadID    string     `json:"thread_id"`
+	ReplyTo     string     `json:"reply_to,omitempty"`
+	FromAgentID string     `json:"from_agent_id,omitempty"`
+	ToAgentID   string     `json:"to_agent_id"`
+	ZoneID      string     `json:"zone_id,omitempty"`
+	Subject     string     `json:"subject,omitempty"`
+	Body        string     `json:"body"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AckedAt     *time.Time `json:"acked_at,omitempty"`
+}
+
+// MessageToDTO converts a message to its DTO.
+func MessageToDTO(m *domain.Message) *MessageDTO {
+	if m == nil {
+		return nil
+	}
+	d := &MessageDTO{
+		ID:          m.ID,
+		ProjectID:   m.ProjectID,
+		ThreadID:    m.Thread(),
+		ReplyTo:     m.ReplyTo,
+		FromAgentID: m.FromAgentID,
+		ToAgentID:   m.ToAgentID,
+		ZoneID:      m.ZoneID,
+		Subject:     m.Subject,
+		Body:        m.Body,
+		CreatedAt:   m.CreatedAt,
+	}
+	if m.Acked() {
+		ackedAt := m.AckedAt
+		d.AckedAt = &ackedAt
+	}
+	return d
+}
+
+// MessagesToDTO converts messages to DTOs.
+func MessagesToDTO(messages []*domain.Message) []*MessageDTO {
+	out := make([]*MessageDTO, len(messages))
+	for i, m := range messages {
+		out[i] = MessageToDTO(m)
+	}
+	return out
+}
+
+// RunTaskDTO is a task of a run and the tasks of the run it depends on.
+type RunTaskDTO struct {
+	TaskID    string   `json:"task_id"`
+	DependsOn []string `json:"depends_on"`
+}
+
+// RunDTO is the MCP/JSON representation of a run with its progress.
+type RunDTO struct {
+	ID          string          `json:"id"`
+	ProjectID   string          `json:"project_id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Tasks       []RunTaskDTO    `json:"tasks"`
+	CreatedBy   string          `json:"created_by,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	Progress    *RunProgressDTO `json:"progress"`
+}
+
+// RunProgressDTO is the progress of a run: its status, task counts, the tasks ready to be
+// claimed, the tasks waiting on prerequisites and per-zone counts.
+type RunProgressDTO struct {
+	Status   string            `json:"status"`
+	Total    int               `json:"total"`
+	Done     int               `json:"done"`
+	Percent  int               `json:"percent"`
+	ByStatus map[string]int    `json:"by_status"`
+	Ready    []string          `json:"ready"`
+	Waiting  []WaitingTaskDTO  `json:"waiting"`
+	Zones    []ZoneProgressDTO `json:"zones"`
+}
+
+// WaitingTaskDTO is a task of a run and the unfinished prerequisites it waits on.
+type WaitingTaskDTO struct {
+	TaskID    string   `json:"task_id"`
+	WaitingOn []string `json:"waiting_on"`
+}
+
+// ZoneProgressDTO counts the tasks of a run routed to one zone.
+type ZoneProgressDTO struct {
+	ZoneID string `json:"zone_id"`
+	Total  int    `json:"total"`
+	Done   int    `json:"done"`
+	Ready  int    `json:"ready"`
+}
+
+// RunToDTO converts a run and its progress to a DTO.
+func RunToDTO(run *domain.Run, p *blueprint.RunProgress) *RunDTO {
+	if run == nil {
+		return nil
+	}
+	d := &RunDTO{
+		ID:          run.ID,
+		ProjectID:   run.ProjectID,
+		Title:       run.Title,
+		Description: run.Description,
+		Tasks:       make([]RunTaskDTO, len(run.Tasks)),
+		CreatedBy:   run.CreatedBy,
+		CreatedAt:   run.CreatedAt,
+		UpdatedAt:   run.UpdatedAt,
+	}
+	for i, t := range run.Tasks {
+		d.Tasks[i] = RunTaskDTO{TaskID: t.TaskID, DependsOn: append([]string{}, t.DependsOn...)}
+	}
+	if p != nil {
+		d.Progress = &RunProgressDTO{
+			Status:   p.Status,
+			Total:    p.Total,
+			Done:     p.Done,
+			Percent:  p.Percent,
+			ByStatus: p.ByStatus,
+			Ready:    p.Ready,
+			Waiting:  make([]WaitingTaskDTO, len(p.Waiting)),
+			Zones:    make([]ZoneProgressDTO, len(p.Zones)),
+		}
+		for i, w := range p.Waiting {
+			d.Progress.Waiting[i] = WaitingTaskDTO(w)
+		}
+		for i, z := range p.Zones {
+			d.Progress.Zones[i] = ZoneProgressDTO(z)
+		}
+	}
+	return d
+}
+
+// RunsToDTO converts runs to DTOs with the progress computed by svc.
+func RunsToDTO(svc *blueprint.Service, runs []*domain.Run) []*RunDTO {
+	out := make([]*RunDTO, len(runs))
+	for i, run := range runs {
+		out[i] = RunToDTO(run, svc.RunProgress(run))
+	}
+	return out
+}
+
+// RoutedPathDTO is a path referenced by a task and the zones containing it.
+type RoutedPathDTO struct {
+	Path    string   `json:"path"`
+	ZoneIDs []string `json:"zone_ids"`
+}
+
+// ZoneRouteDTO is a zone involved in a task and the referenced paths it owns.
+type ZoneRouteDTO struct {
+	Zone  *ZoneDTO `json:"zone"`
+	Paths []string `json:"paths"`
+}
+
+// TaskSplitDTO is a suggested per-zone subtask.
+type TaskSplitDTO struct {
+	Title   string   `json:"title"`
+	ZoneID  string   `json:"zone_id"`
+	AgentID string   `json:"agent_id,omitempty"`
+	Paths   []string `json:"paths"`
+}
+
+// RouteTaskOut is the output for route_task.
+type RouteTaskOut struct {
+	Paths        []RoutedPathDTO `json:"paths"`
+	Zones        []ZoneRouteDTO  `json:"zones"`
+	AgentIDs     []string        `json:"agent_ids"`
+	UnownedPaths []string        `json:"unowned_paths"`
+	Split        []TaskSplitDTO  `json:"split"`
+}
+
+// TaskRouteToDTO converts a routing proposal to the route_task output.
+func TaskRouteToDTO(r *blueprint.TaskRoute) *RouteTaskOut {
+	out := &RouteTaskOut{
+		Paths:        make([]RoutedPathDTO, len(r.Paths)),
+		Zones:        make([]ZoneRouteDTO, len(r.Zones)),
+		AgentIDs:     r.AgentIDs,
+		UnownedPaths: r.Unowned,
+		Split:        make([]TaskSplitDTO, len(r.Split)),
+	}
+	for i, p := range r.Paths {
+		out.Paths[i] = RoutedPathDTO(p)
+	}
+	for i, z := range r.Zones {
+		out.Zones[i] = ZoneRouteDTO{Zone: ZoneToDTO(z.Zone), Paths: z.Paths}
+	}
+	for i, sp := range r.Split {
+		out.Split[i] = TaskSplitDTO(sp)
+	}
+	return out
+}
+
+// ZoneBriefingDTO is a briefed zone with its project name, accepted decisions, current notes and
+// owned paths. PathCount includes the paths left out to fit the budget.
+type ZoneBriefingDTO struct {
+	Zone        *ZoneDTO   `json:"zone"`
+	ProjectName string     `json:"project_name"`
+	Decisions   []string   `json:"decisions"`
+	Notes       []*NoteDTO `json:"notes"`
+	Paths       []string   `json:"paths"`
+	PathCount   int        `json:"path_count"`
+}
+
+// NeighbourZoneDTO is a zone next to the briefed zones; its owners are the zone's assigned agents.
+type NeighbourZoneDTO struct {
+	Zone      *ZoneDTO `json:"zone"`
+	Relations []string `json:"relations"`
+}
+
+// GetBriefingOut is the output for get_briefing. Text is the whole briefing as markdown.
+type GetBriefingOut struct {
+	AgentID         string             `json:"agent_id"`
+	Prompt          string             `json:"prompt"`
+	Zones           []ZoneBriefingDTO  `json:"zones"`
+	Neighbours      []NeighbourZoneDTO `json:"neighbours"`
+	Text            string             `json:"text"`
+	Chars           int                `json:"chars"`
+	EstimatedTokens int                `json:"estimated_tokens"`
+	Truncated       bool               `json:"truncated"`
+}
+
+// BriefingToDTO converts a briefing to the get_briefing output.
+func BriefingToDTO(b *blueprint.Briefing) *GetBriefingOut {
+	out := &GetBriefingOut{
+		AgentID:         b.Agent.ID,
+		Prompt:          b.Prompt,
+		Zones:           make([]ZoneBriefingDTO, len(b.Zones)),
+		Neighbours:      make([]NeighbourZoneDTO, len(b.Neighbours)),
+		Text:            b.Text,
+		Chars:           utf8.RuneCountInString(b.Text),
+		EstimatedTokens: b.Tokens,
+		Truncated:       b.Truncated,
+	}
+	for i, zb := range b.Zones {
+		out.Zones[i] = ZoneBriefingDTO{
+			Zone:        ZoneToDTO(zb.Zone),
+			ProjectName: zb.Project.Name,
+			Decisions:   append([]string{}, zb.Decisions...),
+			Notes:       NotesToDTO(zb.Notes),
+			Paths:       append([]string{}, zb.Paths...),
+			PathCount:   zb.PathCount,
+		}
+	}
+	for i, n := range b.Neighbours {
+		out.Neighbours[i] = NeighbourZoneDTO{Zone: ZoneToDTO(n.Zone), Relations: n.Relations}
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"operators-mcp/internal/application/blueprint"
+)
+
+// Identities maps MCP sessions to the agents they identified as. A client identifies by sending
+// "Authorization: Bearer <token>" with a token from issue_agent_token; the session created by its
+// initialize request is then bound to that agent, so later requests of the session act as the
+// agent even without the header. Sessions without a token stay anonymous.
+type Identities struct {
+	svc *blueprint.Service
+
+	mu       sync.RWMutex
+	sessions map[string]string // session id -> agent id
+}
+
+// NewIdentities returns an empty session-to-agent mapping. Add its hooks to the MCP server with
+// AddHooks and wrap the HTTP transport with Handler.
+func NewIdentities(svc *blueprint.Service) *Identities {
+	return &Identities{svc: svc, sessions: make(map[string]string)}
+}
+
+// AddHooks registers the hook that binds a session to its agent once initialize succeeds.
+func (i *Identities) AddHooks(h *server.Hooks) {
+	h.AddAfterInitialize(func(ctx context.Context, id any, req *mcp.InitializeRequest, res *mcp.InitializeResult) {
+		agentID := blueprint.CallerID(ctx)
+		session := server.ClientSessionFromContext(ctx)
+		if agentID == "" || session == nil {
+			return
+		}
+		i.mu.Lock()
+		i.sessions[session.SessionID()] = agentID
+		i.mu.Unlock()
+	})
+}
+
+// AgentID returns the agent the session is bound to, or "".
+func (i *Identities) AgentID(sessionID string) string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.sessions[sessionID]
+}
+
+// Sessions returns the ids of the sessions bound to agentID.
+func (i *Identities) Sessions(agentID string) []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	var out []string
+	for sessionID, a := range i.sessions {
+		if a == agentID {
+			out = append(out, sessionID)
+		}
+	}
+	return out
+}
+
+// Handler resolves the caller of each request and passes it to next in the request context
+// (see blueprint.CallerID). Unknown tokens are refused with 401, as are requests whose token names
+// a different agent than the one their session is bound to. Deleting a session drops its binding.
+func (i *Identities) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		bound := i.AgentID(sessionID)
+		agentID := bound
+		if token, ok := bearerToken(r); ok {
+			a, err := i.svc.AgentForToken(token)
+			if err != nil {
+				unauthorized(w, err.Error())
+				return
+			}
+			if bound != "" && a.ID != bound {
+				unauthorized(w, "session is bound to another agent")
+				return
+			}
+			agentID = a.ID
+		} else if bound != "" && i.svc.GetAgent(bound) == nil {
+			unauthorized(w, "the session's agent no longer exists")
+			return
+		}
+		if r.Method == http.MethodDelete && sessionID != "" {
+			i.mu.Lock()
+			delete(i.sessions, sessionID)
+			i.mu.Unlock()
+		}
+		next.ServeHTTP(w, r.WithContext(blueprint.WithCaller(r.Context(), agentID)))
+	})
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func unauthorized(w http.ResponseWriter, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="operators-mcp"`)
+	http.Error(w, msg, http.StatusUnauthorized)
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"errors"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+)
+
+// InboxLogger is the logger of the notifications/message entries that deliver agent messages.
+const InboxLogger = "inbox"
+
+const methodNotificationMessage = "notifications/message"
+
+// DeliverMessages sends every new message to the sessions of s bound to its recipient, as a
+// notifications/message log entry (level notice, logger InboxLogger) whose data is the message.
+// Messages stay in the inbox either way. The server should be created with server.WithLogging().
+func (i *Identities) DeliverMessages(s *server.MCPServer) {
+	i.svc.Subscribe(func(e blueprint.Event) {
+		m := newMessage(i.svc, e)
+		if m == nil {
+			return
+		}
+		for _, sessionID := range i.Sessions(m.ToAgentID) {
+			err := s.SendNotificationToSpecificClient(sessionID, methodNotificationMessage, inboxNotification(m))
+			if errors.Is(err, server.ErrSessionNotFound) {
+				i.mu.Lock()
+				delete(i.sessions, sessionID)
+				i.mu.Unlock()
+			}
+		}
+	})
+}
+
+// newMessage returns the message of a message event that still needs delivering, or nil.
+func newMessage(svc *blueprint.Service, e blueprint.Event) *domain.Message {
+	if e.Kind != blueprint.EventMessage || e.Deleted {
+		return nil
+	}
+	m, err := svc.GetMessage(e.ID)
+	if err != nil || m.Acked() {
+		return nil
+	}
+	return m
+}
+
+func inboxNotification(m *domain.Message) map[string]any {
+	return map[string]any{"level": mcp.LoggingLevelNotice, "logger": InboxLogger, "data": MessageToDTO(m)}
+}
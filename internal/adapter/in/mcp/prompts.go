@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+)
+
+// RegisterPrompts exposes every agent as an MCP prompt, with one argument per declared
+// variable besides zone_id and task, and keeps the list current:
+// agent changes made through the service replace the prompt set and notify clients.
+// The server should be created with server.WithPromptCapabilities(true).
+// A service without an agent repository exposes no prompts.
+func RegisterPrompts(s *server.MCPServer, svc *blueprint.Service) {
+	if svc.Agents == nil {
+		return
+	}
+	s.SetPrompts(agentPrompts(svc)...)
+	svc.Subscribe(func(e blueprint.Event) {
+		if e.Kind == blueprint.EventAgent {
+			s.SetPrompts(agentPrompts(svc)...)
+		}
+	})
+}
+
+// agentPrompts builds one prompt per agent, ordered by name.
+func agentPrompts(svc *blueprint.Service) []server.ServerPrompt {
+	agents := svc.ListAgents()
+	sort.Slice(agents, func(i, j int) bool {
+		if agents[i].Name != agents[j].Name {
+			return agents[i].Name < agents[j].Name
+		}
+		return agents[i].ID < agents[j].ID
+	})
+	names := promptNames(agents)
+	out := make([]server.ServerPrompt, 0, len(agents))
+	for _, a := range agents {
+		desc := a.Description
+		if desc == "" {
+			desc = "Act as the " + a.Name + " agent."
+		}
+		opts := []mcp.PromptOption{
+			mcp.WithPromptDescription(desc),
+			mcp.WithArgument("zone_id", mcp.ArgumentDescription("Zone to work in; adds its name, purpose, constraints and matched paths")),
+			mcp.WithArgument("task", mcp.ArgumentDescription("What the agent should do")),
+		}
+		for _, v := range a.Variables {
+			argOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(v.Description)}
+			if v.Required {
+				argOpts = append(argOpts, mcp.RequiredArgument())
+			}
+			opts = append(opts, mcp.WithArgument(v.Name, argOpts...))
+		}
+		out = append(out, server.ServerPrompt{
+			Prompt:  mcp.NewPrompt(names[a.ID], opts...),
+			Handler: promptAgent(svc, a.ID),
+		})
+	}
+	return out
+}
+
+var promptNameUnsafe = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// promptNames returns the MCP prompt name for each agent, keyed by agent id: the agent name
+// lowercased with other characters replaced by "-", suffixed with the id when names collide.
+func promptNames(agents []*domain.Agent) map[string]string {
+	base := make(map[string]string, len(agents))
+	count := make(map[string]int)
+	for _, a := range agents {
+		n := strings.Trim(promptNameUnsafe.ReplaceAllString(strings.ToLower(a.Name), "-"), "-")
+		if n == "" {
+			n = "agent"
+		}
+		base[a.ID] = n
+		count[n]++
+	}
+	out := make(map[string]string, len(agents))
+	for id, n := range base {
+		if count[n] > 1 {
+			n += "-" + id
+		}
+		out[id] = n
+	}
+	return out
+}
+
+func promptAgent(svc *blueprint.Service, agentID string) server.PromptHandlerFunc {
+	return func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := req.Params.Arguments
+		vars := make(map[string]string, len(args))
+		for k, v := range args {
+			if k != "zone_id" && k != "task" {
+				vars[k] = v
+			}
+		}
+		p, err := svc.RenderAgentPrompt(agentID, args["zone_id"], args["task"], vars)
+		if err != nil {
+			return nil, err
+		}
+		desc := p.Agent.Name
+		if p.Zone != nil {
+			desc += " in zone " + p.Zone.Name
+		}
+		return mcp.NewGetPromptResult(desc, []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(p.Text)),
+		}), nil
+	}
+}
@@ -0,0 +1,273 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"operators-mcp/internal/application/blueprint"
+)
+
+// URI templates of the read-only blueprint resources.
+const (
+	ProjectResourceTemplate = "blueprint://projects/{id}"
+	ZoneResourceTemplate    = "blueprint://zones/{id}"
+	AgentResourceTemplate   = "blueprint://agents/{id}"
+)
+
+const (
+	methodResourcesSubscribe   = "resources/subscribe"
+	methodResourcesUnsubscribe = "resources/unsubscribe"
+)
+
+// ProjectResource is the content of a blueprint://projects/{id} resource.
+type ProjectResource struct {
+	Project *ProjectDTO `json:"project"`
+	Zones   []*ZoneDTO  `json:"zones"`
+}
+
+// ResourceSubscriptions tracks which sessions subscribed to which blueprint resources and sends
+// notifications/resources/updated when the underlying entity changes through the service.
+// mcp-go does not route resources/subscribe itself, so Handler answers those requests in front
+// of the transport.
+type ResourceSubscriptions struct {
+	srv *server.MCPServer
+	svc *blueprint.Service
+
+	mu   sync.Mutex
+	subs map[string]map[string]bool // session id -> subscribed URIs
+}
+
+// RegisterResources adds the project, zone and agent resource templates and returns the
+// subscription registry. The server should be created with server.WithResourceCapabilities(true, false)
+// and its HTTP transport wrapped with the registry's Handler.
+func RegisterResources(s *server.MCPServer, svc *blueprint.Service) *ResourceSubscriptions {
+	s.AddResourceTemplate(mcp.NewResourceTemplate(ProjectResourceTemplate, "Project",
+		mcp.WithTemplateDescription("A project with its zones"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), readResource(svc))
+	s.AddResourceTemplate(mcp.NewResourceTemplate(ZoneResourceTemplate, "Zone",
+		mcp.WithTemplateDescription("A zone with its pattern, purpose, constraints, paths and assigned agents"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), readResource(svc))
+	s.AddResourceTemplate(mcp.NewResourceTemplate(AgentResourceTemplate, "Agent",
+		mcp.WithTemplateDescription("An agent with its prompt template and variables"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), readResource(svc))
+
+	r := &ResourceSubscriptions{srv: s, svc: svc, subs: make(map[string]map[string]bool)}
+	svc.Subscribe(r.changed)
+	return r
+}
+
+// ProjectURI returns the resource URI of a project.
+func ProjectURI(id string) string { return "blueprint://projects/" + id }
+
+// ZoneURI returns the resource URI of a zone.
+func ZoneURI(id string) string { return "blueprint://zones/" + id }
+
+// AgentURI returns the resource URI of an agent.
+func AgentURI(id string) string { return "blueprint://agents/" + id }
+
+// parseResourceURI splits a blueprint resource URI into its kind (an event kind) and id.
+func parseResourceURI(uri string) (kind, id string, ok bool) {
+	for _, k := range []struct{ kind, prefix string }{
+		{blueprint.EventProject, ProjectURI("")},
+		{blueprint.EventZone, ZoneURI("")},
+		{blueprint.EventAgent, AgentURI("")},
+	} {
+		if rest, found := strings.CutPrefix(uri, k.prefix); found && rest != "" && !strings.Contains(rest, "/") {
+			return k.kind, rest, true
+		}
+	}
+	return "", "", false
+}
+
+func readResource(svc *blueprint.Service) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		uri := req.Params.URI
+		v, err := resourceContent(svc, uri)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(b)},
+		}, nil
+	}
+}
+
+var errResourceNotFound = errors.New("resource not found")
+
+// resourceContent returns the DTO served for uri.
+func resourceContent(svc *blueprint.Service, uri string) (any, error) {
+	kind, id, ok := parseResourceURI(uri)
+	if !ok {
+		return nil, errResourceNotFound
+	}
+	switch kind {
+	case blueprint.EventProject:
+		if p := svc.GetProject(id); p != nil {
+			return ProjectResource{Project: ProjectToDTO(p), Zones: ZonesToDTO(svc.ListZones(id))}, nil
+		}
+	case blueprint.EventZone:
+		if z := svc.GetZone(id); z != nil {
+			return ZoneToDTO(z), nil
+		}
+	case blueprint.EventAgent:
+		if a := svc.GetAgent(id); a != nil {
+			return AgentToDTO(a), nil
+		}
+	}
+	return nil, errResourceNotFound
+}
+
+// maxSubscribeBody bounds the bytes Handler reads to recognize a subscription request. Larger
+// bodies cannot be one and are passed on unread.
+const maxSubscribeBody = 64 << 10
+
+// Handler answers resources/subscribe and resources/unsubscribe requests for the session named
+// in the Mcp-Session-Id header and passes every other request to next. mcp-go does not route
+// these methods, so they are recognized here from at most maxSubscribeBody bytes of the body.
+func (r *ResourceSubscriptions) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			next.ServeHTTP(w, req)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxSubscribeBody+1))
+		if err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		req.Body = readCloser{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		if len(body) > maxSubscribeBody {
+			next.ServeHTTP(w, req)
+			return
+		}
+		var msg struct {
+			ID     mcp.RequestId `json:"id"`
+			Method string        `json:"method"`
+			Params struct {
+				URI string `json:"uri"`
+			} `json:"params"`
+		}
+		if json.Unmarshal(body, &msg) != nil || msg.ID.IsNil() ||
+			(msg.Method != methodResourcesSubscribe && msg.Method != methodResourcesUnsubscribe) {
+			next.ServeHTTP(w, req)
+			return
+		}
+		sessionID := req.Header.Get(server.HeaderKeySessionID)
+		var resp any
+		switch {
+		case sessionID == "":
+			resp = mcp.NewJSONRPCError(msg.ID, mcp.INVALID_REQUEST, msg.Method+" requires a session", nil)
+		case msg.Method == methodResourcesUnsubscribe:
+			r.unsubscribe(sessionID, msg.Params.URI)
+			resp = mcp.NewJSONRPCResultResponse(msg.ID, mcp.EmptyResult{})
+		default:
+			if _, err := resourceContent(r.svc, msg.Params.URI); err != nil {
+				resp = mcp.NewJSONRPCError(msg.ID, mcp.RESOURCE_NOT_FOUND, err.Error()+": "+msg.Params.URI, nil)
+				break
+			}
+			r.subscribe(sessionID, msg.Params.URI)
+			resp = mcp.NewJSONRPCResultResponse(msg.ID, mcp.EmptyResult{})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// readCloser reads from Reader and closes Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (r *ResourceSubscriptions) subscribe(sessionID, uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subs[sessionID] == nil {
+		r.subs[sessionID] = make(map[string]bool)
+	}
+	r.subs[sessionID][uri] = true
+}
+
+func (r *ResourceSubscriptions) unsubscribe(sessionID, uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs[sessionID], uri)
+	if len(r.subs[sessionID]) == 0 {
+		delete(r.subs, sessionID)
+	}
+}
+
+// changed notifies every session subscribed to a resource affected by e. Subscriptions of
+// sessions that no longer exist are dropped.
+func (r *ResourceSubscriptions) changed(e blueprint.Event) {
+	r.mu.Lock()
+	pending := make(map[string][]string)
+	for sessionID, uris := range r.subs {
+		for uri := range uris {
+			if r.affected(uri, e) {
+				pending[sessionID] = append(pending[sessionID], uri)
+			}
+		}
+	}
+	r.mu.Unlock()
+
+	for sessionID, uris := range pending {
+		for _, uri := range uris {
+			err := r.srv.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+			if errors.Is(err, server.ErrSessionNotFound) {
+				r.mu.Lock()
+				delete(r.subs, sessionID)
+				r.mu.Unlock()
+				break
+			}
+		}
+	}
+}
+
+// affected reports whether the resource at uri may have changed because of e. Projects embed
+// their zones and zones embed their assigned agents, so those changes propagate upward; a project
+// event (e.g. a blueprint import or deletion) also touches every zone of the project.
+func (r *ResourceSubscriptions) affected(uri string, e blueprint.Event) bool {
+	kind, id, ok := parseResourceURI(uri)
+	if !ok {
+		return false
+	}
+	switch kind {
+	case blueprint.EventProject:
+		return (e.Kind == blueprint.EventProject || e.Kind == blueprint.EventZone) && e.ProjectID == id
+	case blueprint.EventZone:
+		if e.Kind == blueprint.EventZone {
+			return e.ID == id
+		}
+		z := r.svc.GetZone(id)
+		if e.Kind == blueprint.EventProject {
+			return z == nil || z.ProjectID == e.ProjectID
+		}
+		if e.Kind == blueprint.EventAgent && z != nil {
+			for _, a := range z.AgentIDs {
+				if a == e.ID {
+					return true
+				}
+			}
+			return e.Deleted
+		}
+	case blueprint.EventAgent:
+		return e.Kind == blueprint.EventAgent && e.ID == id
+	}
+	return false
+}
@@ -4,11 +4,14 @@ import (
 	"github.com/google/jsonschema-go/jsonschema"
 )
 
-// ListMatchingPathsIn is the input for list_matching_paths.
+// ListMatchingPathsIn is the input for list_matching_paths. Format is json (default), text or
+// collapsed.
 type ListMatchingPathsIn struct {
-	Pattern   string `json:"pattern" jsonschema:"required"`
-	Root      string `json:"root,omitempty"`
-	ProjectID string `json:"project_id,omitempty"`
+	Pattern    string `json:"pattern" jsonschema:"required"`
+	Root       string `json:"root,omitempty"`
+	ProjectID  string `json:"project_id,omitempty"`
+	Format     string `json:"format,omitempty"`
+	MaxEntries int    `json:"max_entries,omitempty"`
 }
 
 // ListMatchingPathsOut is the output for list_matching_paths.
@@ -16,11 +19,13 @@ type ListMatchingPathsOut struct {
 	Paths []string `json:"paths"`
 }
 
-// ListTreeIn is the input for list_tree.
+// ListTreeIn is the input for list_tree. Format is json (default), text or collapsed.
 type ListTreeIn struct {
-	Root      string `json:"root,omitempty"`
-	ProjectID string `json:"project_id,omitempty"`
-	Depth     int    `json:"depth,omitempty"`
+	Root       string `json:"root,omitempty"`
+	ProjectID  string `json:"project_id,omitempty"`
+	Depth      int    `json:"depth,omitempty"`
+	Format     string `json:"format,omitempty"`
+	MaxEntries int    `json:"max_entries,omitempty"`
 }
 
 // ListTreeOut is the output for list_tree.
@@ -30,7 +35,7 @@ type ListTreeOut struct {
 
 // ListZonesIn is the input for list_zones.
 type ListZonesIn struct {
-	ProjectID string `json:"project_id" jsonschema:"required"`
+	ProjectID string `json:"project_id,omitempty"`
 }
 
 // ListZonesOut is the output for list_zones.
@@ -45,7 +50,7 @@ type ListProjectsOut struct {
 
 // GetProjectIn is the input for get_project.
 type GetProjectIn struct {
-	ProjectID string `json:"project_id" jsonschema:"required"`
+	ProjectID string `json:"project_id,omitempty"`
 }
 
 // GetProjectOut is the output for get_project.
@@ -83,7 +88,7 @@ type DeleteProjectIn struct {
 
 // AddIgnoredPathIn is the input for add_ignored_path.
 type AddIgnoredPathIn struct {
-	ProjectID string `json:"project_id" jsonschema:"required"`
+	ProjectID string `json:"project_id,omitempty"`
 	Path      string `json:"path" jsonschema:"required"`
 }
 
@@ -94,7 +99,7 @@ type AddIgnoredPathOut struct {
 
 // RemoveIgnoredPathIn is the input for remove_ignored_path.
 type RemoveIgnoredPathIn struct {
-	ProjectID string `json:"project_id" jsonschema:"required"`
+	ProjectID string `json:"project_id,omitempty"`
 	Path      string `json:"path" jsonschema:"required"`
 }
 
@@ -115,7 +120,7 @@ type GetZoneOut struct {
 
 // CreateZoneIn is the input for create_zone.
 type CreateZoneIn struct {
-	ProjectID      string     `json:"project_id" jsonschema:"required"`
+	ProjectID      string     `json:"project_id,omitempty"`
 	Name           string     `json:"name" jsonschema:"required"`
 	Pattern        string     `json:"pattern,omitempty"`
 	Purpose        string     `json:"purpose,omitempty"`
@@ -171,9 +176,10 @@ type GetAgentOut struct {
 
 // CreateAgentIn is the input for create_agent.
 type CreateAgentIn struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	Prompt      string `json:"prompt,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Prompt      string        `json:"prompt,omitempty"`
+	Variables   []VariableDTO `json:"variables,omitempty"`
 }
 
 // CreateAgentOut is the output for create_agent.
@@ -183,10 +189,11 @@ type CreateAgentOut struct {
 
 // UpdateAgentIn is the input for update_agent.
 type UpdateAgentIn struct {
-	AgentID     string `json:"agent_id" jsonschema:"required"`
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	Prompt      string `json:"prompt,omitempty"`
+	AgentID     string        `json:"agent_id" jsonschema:"required"`
+	Name        string        `json:"name,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Prompt      string        `json:"prompt,omitempty"`
+	Variables   []VariableDTO `json:"variables,omitempty"`
 }
 
 // UpdateAgentOut is the output for update_agent.
@@ -199,6 +206,430 @@ type DeleteAgentIn struct {
 	AgentID string `json:"agent_id" jsonschema:"required"`
 }
 
+// RenderAgentPromptIn is the input for render_agent_prompt.
+type RenderAgentPromptIn struct {
+	AgentID   string            `json:"agent_id" jsonschema:"required"`
+	ZoneID    string            `json:"zone_id,omitempty"`
+	Task      string            `json:"task,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// RenderAgentPromptOut is the output for render_agent_prompt.
+type RenderAgentPromptOut struct {
+	Text string `json:"text"`
+}
+
+// ExportDiagramIn is the input for export_diagram.
+type ExportDiagramIn struct {
+	ProjectID   string   `json:"project_id,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	GroupNested bool     `json:"group_nested,omitempty"`
+	ZoneIDs     []string `json:"zone_ids,omitempty"`
+}
+
+// ExportDiagramOut is the output for export_diagram.
+type ExportDiagramOut struct {
+	Format  string `json:"format"`
+	Diagram string `json:"diagram"`
+}
+
+// ExportBlueprintIn is the input for export_blueprint.
+type ExportBlueprintIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	Format    string `json:"format,omitempty"`
+}
+
+// ExportBlueprintOut is the output for export_blueprint.
+type ExportBlueprintOut struct {
+	Format   string `json:"format"`
+	Document string `json:"document"`
+}
+
+// ImportBlueprintIn is the input for import_blueprint.
+type ImportBlueprintIn struct {
+	Document  string `json:"document" jsonschema:"required"`
+	Format    string `json:"format,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+	RootDir   string `json:"root_dir,omitempty"`
+	Mode      string `json:"mode,omitempty"`
+	DryRun    bool   `json:"dry_run,omitempty"`
+}
+
+// BindBlueprintFileIn is the input for bind_blueprint_file.
+type BindBlueprintFileIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	File      string `json:"file,omitempty"`
+	Initial   string `json:"initial,omitempty"`
+}
+
+// UnbindBlueprintFileIn is the input for unbind_blueprint_file.
+type UnbindBlueprintFileIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// UnbindBlueprintFileOut is the output for unbind_blueprint_file.
+type UnbindBlueprintFileOut struct {
+	Project *ProjectDTO `json:"project"`
+}
+
+// SyncBlueprintIn is the input for sync_blueprint.
+type SyncBlueprintIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	Resolve   string `json:"resolve,omitempty"`
+}
+
+// ReadFileIn is the input for read_file.
+type ReadFileIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	Path      string `json:"path" jsonschema:"required"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+	MaxBytes  int    `json:"max_bytes,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+}
+
+// WriteFileIn is the input for write_file.
+type WriteFileIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+	Path      string `json:"path" jsonschema:"required"`
+	Content   string `json:"content"`
+}
+
+// WriteFileOut is the output for write_file.
+type WriteFileOut struct {
+	Change *FileChangeDTO `json:"change"`
+}
+
+// ApplyPatchIn is the input for apply_patch.
+type ApplyPatchIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+	Patch     string `json:"patch" jsonschema:"required"`
+}
+
+// ApplyPatchOut is the output for apply_patch.
+type ApplyPatchOut struct {
+	Changes []*FileChangeDTO `json:"changes"`
+}
+
+// SetActiveProjectIn is the input for set_active_project.
+type SetActiveProjectIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// SetActiveProjectOut is the output for set_active_project. Project is nil when the binding was cleared.
+// Instructions are the server instructions refreshed for the new project.
+type SetActiveProjectOut struct {
+	Project      *ProjectDTO `json:"project"`
+	Instructions string      `json:"instructions"`
+}
+
+// WhoAmIOut is the output for whoami. Agent is nil for anonymous sessions.
+type WhoAmIOut struct {
+	Agent *AgentDTO `json:"agent"`
+}
+
+// AgentTokenIn is the input for issue_agent_token and revoke_agent_token.
+type AgentTokenIn struct {
+	AgentID string `json:"agent_id" jsonschema:"required"`
+}
+
+// IssueAgentTokenOut is the output for issue_agent_token.
+type IssueAgentTokenOut struct {
+	AgentID string `json:"agent_id"`
+	Token   string `json:"token"`
+}
+
+// ListChangesIn is the input for list_changes.
+type ListChangesIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+// ListChangesOut is the output for list_changes.
+type ListChangesOut struct {
+	Changes []*FileChangeDTO `json:"changes"`
+}
+
+// ReadZoneFilesIn is the input for read_zone_files.
+type ReadZoneFilesIn struct {
+	ZoneID   string `json:"zone_id" jsonschema:"required"`
+	MaxBytes int    `json:"max_bytes,omitempty"`
+	AgentID  string `json:"agent_id,omitempty"`
+}
+
+// BuildContextIn is the input for build_context. Either zone_id or paths is required.
+type BuildContextIn struct {
+	ProjectID string   `json:"project_id,omitempty"`
+	ZoneID    string   `json:"zone_id,omitempty"`
+	Paths     []string `json:"paths,omitempty"`
+	MaxTokens int      `json:"max_tokens,omitempty"`
+	AgentID   string   `json:"agent_id,omitempty"`
+}
+
+// CreateTaskIn is the input for create_task.
+type CreateTaskIn struct {
+	ProjectID   string   `json:"project_id,omitempty"`
+	Title       string   `json:"title" jsonschema:"required"`
+	Description string   `json:"description,omitempty"`
+	ZoneIDs     []string `json:"zone_ids,omitempty"`
+	AgentID     string   `json:"agent_id,omitempty"`
+}
+
+// ListTasksIn is the input for list_tasks.
+type ListTasksIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	Status    string `json:"status,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+	ZoneID    string `json:"zone_id,omitempty"`
+}
+
+// ListTasksOut is the output for list_tasks.
+type ListTasksOut struct {
+	Tasks []*TaskDTO `json:"tasks"`
+}
+
+// GetTaskIn is the input for get_task.
+type GetTaskIn struct {
+	TaskID string `json:"task_id" jsonschema:"required"`
+}
+
+// ClaimTaskIn is the input for claim_task.
+type ClaimTaskIn struct {
+	TaskID  string `json:"task_id" jsonschema:"required"`
+	AgentID string `json:"agent_id,omitempty"`
+}
+
+// UpdateTaskStatusIn is the input for update_task_status.
+type UpdateTaskStatusIn struct {
+	TaskID string `json:"task_id" jsonschema:"required"`
+	Status string `json:"status" jsonschema:"required"`
+}
+
+// CompleteTaskIn is the input for complete_task.
+type CompleteTaskIn struct {
+	TaskID string `json:"task_id" jsonschema:"required"`
+	Result string `json:"result,omitempty"`
+}
+
+// RouteTaskIn is the input for route_task. With task_id the stored task's title and description
+// are used and project_id is ignored.
+type RouteTaskIn struct {
+	ProjectID   string   `json:"project_id,omitempty"`
+	TaskID      string   `json:"task_id,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Files       []string `json:"files,omitempty"`
+}
+
+// CreateRunIn is the input for create_run.
+type CreateRunIn struct {
+	ProjectID   string       `json:"project_id,omitempty"`
+	Title       string       `json:"title" jsonschema:"required"`
+	Description string       `json:"description,omitempty"`
+	Tasks       []RunTaskDTO `json:"tasks,omitempty"`
+}
+
+// SetRunTaskIn is the input for set_run_task.
+type SetRunTaskIn struct {
+	RunID     string   `json:"run_id" jsonschema:"required"`
+	TaskID    string   `json:"task_id" jsonschema:"required"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// RunIDIn is the input for get_run and delete_run.
+type RunIDIn struct {
+	RunID string `json:"run_id" jsonschema:"required"`
+}
+
+// ListRunsIn is the input for list_runs.
+type ListRunsIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// RunOut is the output for the tools returning one run.
+type RunOut struct {
+	Run *RunDTO `json:"run"`
+}
+
+// ListRunsOut is the output for list_runs.
+type ListRunsOut struct {
+	Runs []*RunDTO `json:"runs"`
+}
+
+// ClaimZoneIn is the input for claim_zone and renew_lease. TTLSeconds defaults to 600.
+type ClaimZoneIn struct {
+	ZoneID     string `json:"zone_id" jsonschema:"required"`
+	AgentID    string `json:"agent_id,omitempty"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+}
+
+// ReleaseZoneIn is the input for release_zone.
+type ReleaseZoneIn struct {
+	ZoneID  string `json:"zone_id" jsonschema:"required"`
+	AgentID string `json:"agent_id,omitempty"`
+}
+
+// ListLeasesIn is the input for list_leases.
+type ListLeasesIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+}
+
+// LeaseOut is the output for claim_zone and renew_lease.
+type LeaseOut struct {
+	Lease *LeaseDTO `json:"lease"`
+}
+
+// ListLeasesOut is the output for list_leases.
+type ListLeasesOut struct {
+	Leases []*LeaseDTO `json:"leases"`
+}
+
+// CreateDecisionIn is the input for create_decision.
+type CreateDecisionIn struct {
+	ProjectID    string   `json:"project_id,omitempty"`
+	Title        string   `json:"title" jsonschema:"required"`
+	Context      string   `json:"context,omitempty"`
+	Decision     string   `json:"decision,omitempty"`
+	Consequences string   `json:"consequences,omitempty"`
+	Status       string   `json:"status,omitempty"`
+	ZoneIDs      []string `json:"zone_ids,omitempty"`
+	Supersedes   string   `json:"supersedes,omitempty"`
+}
+
+// SetDecisionStatusIn is the input for set_decision_status.
+type SetDecisionStatusIn struct {
+	DecisionID string `json:"decision_id" jsonschema:"required"`
+	Status     string `json:"status" jsonschema:"required"`
+}
+
+// GetDecisionIn is the input for get_decision.
+type GetDecisionIn struct {
+	DecisionID string `json:"decision_id" jsonschema:"required"`
+}
+
+// ListDecisionsIn is the input for list_decisions.
+type ListDecisionsIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	ZoneID    string `json:"zone_id,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Query     string `json:"query,omitempty"`
+}
+
+// ExportDecisionsIn is the input for export_decisions. Dir defaults to docs/adr.
+type ExportDecisionsIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+	Dir       string `json:"dir,omitempty"`
+}
+
+// DecisionOut is the output for the tools returning one decision.
+type DecisionOut struct {
+	Decision *DecisionDTO `json:"decision"`
+}
+
+// ListDecisionsOut is the output for list_decisions.
+type ListDecisionsOut struct {
+	Decisions []*DecisionDTO `json:"decisions"`
+}
+
+// ExportDecisionsOut is the output for export_decisions.
+type ExportDecisionsOut struct {
+	Paths []string `json:"paths"`
+}
+
+// AddZoneNoteIn is the input for add_zone_note. TTLDays defaults to 30 and is ignored for pinned notes.
+type AddZoneNoteIn struct {
+	ZoneID  string   `json:"zone_id" jsonschema:"required"`
+	AgentID string   `json:"agent_id,omitempty"`
+	Text    string   `json:"text" jsonschema:"required"`
+	Tags    []string `json:"tags,omitempty"`
+	Pinned  bool     `json:"pinned,omitempty"`
+	TTLDays int      `json:"ttl_days,omitempty"`
+}
+
+// ListZoneNotesIn is the input for list_zone_notes.
+type ListZoneNotesIn struct {
+	ZoneID string `json:"zone_id" jsonschema:"required"`
+	Tag    string `json:"tag,omitempty"`
+}
+
+// SearchZoneNotesIn is the input for search_zone_notes.
+type SearchZoneNotesIn struct {
+	ProjectID string   `json:"project_id,omitempty"`
+	Query     string   `json:"query,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	ZoneID    string   `json:"zone_id,omitempty"`
+	Limit     int      `json:"limit,omitempty"`
+}
+
+// DeleteZoneNoteIn is the input for delete_zone_note.
+type DeleteZoneNoteIn struct {
+	NoteID string `json:"note_id" jsonschema:"required"`
+}
+
+// NoteOut is the output for add_zone_note.
+type NoteOut struct {
+	Note *NoteDTO `json:"note"`
+}
+
+// NotesOut is the output for list_zone_notes and search_zone_notes.
+type NotesOut struct {
+	Notes []*NoteDTO `json:"notes"`
+}
+
+// GetBriefingIn is the input for get_briefing. Without zone_id the agent's zones (in project_id,
+// when set) are briefed.
+type GetBriefingIn struct {
+	AgentID   string            `json:"agent_id,omitempty"`
+	ZoneID    string            `json:"zone_id,omitempty"`
+	ProjectID string            `json:"project_id,omitempty"`
+	Task      string            `json:"task,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+	MaxChars  int               `json:"max_chars,omitempty"`
+	MaxTokens int               `json:"max_tokens,omitempty"`
+}
+
+// SendMessageIn is the input for send_message. AgentID is the sender.
+type SendMessageIn struct {
+	ProjectID string `json:"project_id,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+	ToAgentID string `json:"to_agent_id,omitempty"`
+	ZoneID    string `json:"zone_id,omitempty"`
+	ReplyTo   string `json:"reply_to,omitempty"`
+	Subject   string `json:"subject,omitempty"`
+	Body      string `json:"body" jsonschema:"required"`
+}
+
+// ListInboxIn is the input for list_inbox.
+type ListInboxIn struct {
+	AgentID    string `json:"agent_id,omitempty"`
+	ProjectID  string `json:"project_id,omitempty"`
+	UnreadOnly bool   `json:"unread_only,omitempty"`
+}
+
+// MessageIDIn is the input for acknowledge_message and get_thread.
+type MessageIDIn struct {
+	MessageID string `json:"message_id" jsonschema:"required"`
+	AgentID   string `json:"agent_id,omitempty"`
+}
+
+// MessageOut is the output for acknowledge_message.
+type MessageOut struct {
+	Message *MessageDTO `json:"message"`
+}
+
+// MessagesOut is the output for send_message (one copy per recipient), list_inbox and get_thread.
+type MessagesOut struct {
+	Messages []*MessageDTO `json:"messages"`
+}
+
+// TaskOut is the output for the tools returning one task.
+type TaskOut struct {
+	Task *TaskDTO `json:"task"`
+}
+
 // emptyIn is used for ListTools schema (HTTP /api/tools).
 type emptyIn struct{}
 
@@ -229,6 +660,48 @@ func ListTools() []ToolDescriptor {
 	schemaCreateAgent, _ := jsonschema.For[CreateAgentIn](nil)
 	schemaUpdateAgent, _ := jsonschema.For[UpdateAgentIn](nil)
 	schemaDeleteAgent, _ := jsonschema.For[DeleteAgentIn](nil)
+	schemaRenderAgentPrompt, _ := jsonschema.For[RenderAgentPromptIn](nil)
+	schemaExportDiagram, _ := jsonschema.For[ExportDiagramIn](nil)
+	schemaExportBlueprint, _ := jsonschema.For[ExportBlueprintIn](nil)
+	schemaImportBlueprint, _ := jsonschema.For[ImportBlueprintIn](nil)
+	schemaBindBlueprintFile, _ := jsonschema.For[BindBlueprintFileIn](nil)
+	schemaUnbindBlueprintFile, _ := jsonschema.For[UnbindBlueprintFileIn](nil)
+	schemaSyncBlueprint, _ := jsonschema.For[SyncBlueprintIn](nil)
+	schemaReadFile, _ := jsonschema.For[ReadFileIn](nil)
+	schemaReadZoneFiles, _ := jsonschema.For[ReadZoneFilesIn](nil)
+	schemaBuildContext, _ := jsonschema.For[BuildContextIn](nil)
+	schemaWriteFile, _ := jsonschema.For[WriteFileIn](nil)
+	schemaApplyPatch, _ := jsonschema.For[ApplyPatchIn](nil)
+	schemaListChanges, _ := jsonschema.For[ListChangesIn](nil)
+	schemaAgentToken, _ := jsonschema.For[AgentTokenIn](nil)
+	schemaSetActiveProject, _ := jsonschema.For[SetActiveProjectIn](nil)
+	schemaCreateTask, _ := jsonschema.For[CreateTaskIn](nil)
+	schemaListTasks, _ := jsonschema.For[ListTasksIn](nil)
+	schemaGetTask, _ := jsonschema.For[GetTaskIn](nil)
+	schemaClaimTask, _ := jsonschema.For[ClaimTaskIn](nil)
+	schemaUpdateTaskStatus, _ := jsonschema.For[UpdateTaskStatusIn](nil)
+	schemaCompleteTask, _ := jsonschema.For[CompleteTaskIn](nil)
+	schemaRouteTask, _ := jsonschema.For[RouteTaskIn](nil)
+	schemaClaimZone, _ := jsonschema.For[ClaimZoneIn](nil)
+	schemaReleaseZone, _ := jsonschema.For[ReleaseZoneIn](nil)
+	schemaListLeases, _ := jsonschema.For[ListLeasesIn](nil)
+	schemaCreateRun, _ := jsonschema.For[CreateRunIn](nil)
+	schemaSetRunTask, _ := jsonschema.For[SetRunTaskIn](nil)
+	schemaRunID, _ := jsonschema.For[RunIDIn](nil)
+	schemaListRuns, _ := jsonschema.For[ListRunsIn](nil)
+	schemaCreateDecision, _ := jsonschema.For[CreateDecisionIn](nil)
+	schemaSetDecisionStatus, _ := jsonschema.For[SetDecisionStatusIn](nil)
+	schemaGetDecision, _ := jsonschema.For[GetDecisionIn](nil)
+	schemaListDecisions, _ := jsonschema.For[ListDecisionsIn](nil)
+	schemaExportDecisions, _ := jsonschema.For[ExportDecisionsIn](nil)
+	schemaAddZoneNote, _ := jsonschema.For[AddZoneNoteIn](nil)
+	schemaListZoneNotes, _ := jsonschema.For[ListZoneNotesIn](nil)
+	schemaSearchZoneNotes, _ := jsonschema.For[SearchZoneNotesIn](nil)
+	schemaDeleteZoneNote, _ := jsonschema.For[DeleteZoneNoteIn](nil)
+	schemaSendMessage, _ := jsonschema.For[SendMessageIn](nil)
+	schemaListInbox, _ := jsonschema.For[ListInboxIn](nil)
+	schemaMessageID, _ := jsonschema.For[MessageIDIn](nil)
+	schemaGetBriefing, _ := jsonschema.For[GetBriefingIn](nil)
 
 	return []ToolDescriptor{
 		{"list_projects", "Return all projects. A project defines the directory root that everything (tree, zones, paths) is based on.", schemaEmpty},
@@ -238,8 +711,8 @@ func ListTools() []ToolDescriptor {
 		{"delete_project", "Delete a project by id. All zones belonging to the project are also deleted.", schemaDeleteProject},
 		{"add_ignored_path", "Add a file or directory path to the project's ignore list. Ignored paths are hidden from the tree view.", schemaAddIgnoredPath},
 		{"remove_ignored_path", "Remove a path from the project's ignore list so it is shown again in the tree view.", schemaRemoveIgnoredPath},
-		{"list_matching_paths", "Return paths under project root that match the given regex pattern. Use project_id or root to specify the base directory.", schemaListMatchingPaths},
-		{"list_tree", "Return the project's folder structure as a hierarchical tree. Use project_id or root to specify the base directory.", schemaListTree},
+		{"list_matching_paths", "Return paths under project root that match the given regex pattern. Use project_id or root to specify the base directory. format text or collapsed returns the matches as an indented tree with zone annotations.", schemaListMatchingPaths},
+		{"list_tree", "Return the project's folder structure as a hierarchical tree. Use project_id or root to specify the base directory. format text or collapsed returns an indented tree with zone annotations instead of JSON.", schemaListTree},
 		{"list_zones", "Return all zones for the given project.", schemaListZones},
 		{"get_zone", "Return one zone by id.", schemaGetZone},
 		{"create_zone", "Create a zone in the given project with optional metadata and pattern.", schemaCreateZone},
@@ -247,8 +720,55 @@ func ListTools() []ToolDescriptor {
 		{"assign_path_to_zone", "Add a path to a zone's explicit path set.", schemaAssignPathToZone},
 		{"list_agents", "Return all agents. Agents can be assigned to zones.", schemaEmpty},
 		{"get_agent", "Return one agent by id.", schemaGetAgent},
-		{"create_agent", "Create an agent with an optional name.", schemaCreateAgent},
-		{"update_agent", "Update an agent's name.", schemaUpdateAgent},
+		{"create_agent", "Create an agent with an optional name, description, prompt template and declared variables.", schemaCreateAgent},
+		{"update_agent", "Update an agent's name, description, prompt template and declared variables.", schemaUpdateAgent},
 		{"delete_agent", "Delete an agent by id. The agent is removed from all zones that reference it.", schemaDeleteAgent},
+		{"render_agent_prompt", "Render an agent's prompt template for a zone, task and variable values.", schemaRenderAgentPrompt},
+		{"export_diagram", "Render a project's zones (purpose, assigned agents, dependencies) as a Mermaid, Graphviz DOT, or PlantUML diagram.", schemaExportDiagram},
+		{"export_blueprint", "Serialize a project (zones, patterns, constraints, explicit paths, agent references, ignored paths) as a YAML or JSON blueprint document.", schemaExportBlueprint},
+		{"import_blueprint", "Apply a YAML or JSON blueprint document to a project. Modes: create, update (default), prune. Use dry_run to get the diff without writing.", schemaImportBlueprint},
+		{"bind_blueprint_file", "Bind a project to a blueprint file inside its root (default .operators/blueprint.yaml). Changes are written back to the file and file edits are applied to the store.", schemaBindBlueprintFile},
+		{"unbind_blueprint_file", "Remove a project's blueprint file binding. The file is kept.", schemaUnbindBlueprintFile},
+		{"sync_blueprint", "Reconcile a project with its bound blueprint file. Reports a conflict when both changed; use resolve=file or resolve=store to pick a side.", schemaSyncBlueprint},
+		{"read_file", "Read a text file of the project, optionally a line range. Paths outside the root or in ignored paths are refused; with agent_id the file must be in a zone assigned to that agent.", schemaReadFile},
+		{"read_zone_files", "Read the text files that belong to a zone, up to max_bytes in total. With agent_id the zone must be assigned to that agent.", schemaReadZoneFiles},
+		{"build_context", buildContextDescription, schemaBuildContext},
+		{"write_file", writeFileDescription, schemaWriteFile},
+		{"apply_patch", applyPatchDescription, schemaApplyPatch},
+		{"list_changes", "List the file changes made through write_file and apply_patch in a project, newest first.", schemaListChanges},
+		{"set_active_project", setActiveProjectDescription, schemaSetActiveProject},
+		{"whoami", "Return the agent this session is identified as (via a bearer token), or anonymous.", schemaEmpty},
+		{"issue_agent_token", issueAgentTokenDescription, schemaAgentToken},
+		{"revoke_agent_token", "Revoke an agent's bearer token. Identified sessions may only revoke their own token.", schemaAgentToken},
+		{"create_task", createTaskDescription, schemaCreateTask},
+		{"list_tasks", "List a project's tasks oldest first, optionally filtered by status, assigned agent or zone.", schemaListTasks},
+		{"get_task", "Return one task by id.", schemaGetTask},
+		{"claim_task", claimTaskDescription, schemaClaimTask},
+		{"update_task_status", updateTaskStatusDescription, schemaUpdateTaskStatus},
+		{"complete_task", "Mark a claimed task done with a summary of the result.", schemaCompleteTask},
+		{"route_task", routeTaskDescription, schemaRouteTask},
+		{"claim_zone", claimZoneDescription, schemaClaimZone},
+		{"renew_lease", renewLeaseDescription, schemaClaimZone},
+		{"release_zone", releaseZoneDescription, schemaReleaseZone},
+		{"list_leases", "List a project's active zone leases: zone, holder agent and expiry.", schemaListLeases},
+		{"create_run", createRunDescription, schemaCreateRun},
+		{"set_run_task", setRunTaskDescription, schemaSetRunTask},
+		{"get_run", getRunDescription, schemaRunID},
+		{"list_runs", "List a project's runs oldest first, each with its progress.", schemaListRuns},
+		{"delete_run", "Delete a run. Its tasks are kept and no longer wait on each other.", schemaRunID},
+		{"create_decision", createDecisionDescription, schemaCreateDecision},
+		{"set_decision_status", setDecisionStatusDescription, schemaSetDecisionStatus},
+		{"get_decision", "Return one architecture decision record by id.", schemaGetDecision},
+		{"list_decisions", listDecisionsDescription, schemaListDecisions},
+		{"export_decisions", exportDecisionsDescription, schemaExportDecisions},
+		{"add_zone_note", addZoneNoteDescription, schemaAddZoneNote},
+		{"list_zone_notes", "List a zone's current notes, pinned first and then newest first, optionally only those with a tag.", schemaListZoneNotes},
+		{"search_zone_notes", searchZoneNotesDescription, schemaSearchZoneNotes},
+		{"delete_zone_note", "Delete a zone note. Identified sessions may only delete their own notes.", schemaDeleteZoneNote},
+		{"send_message", sendMessageDescription, schemaSendMessage},
+		{"list_inbox", listInboxDescription, schemaListInbox},
+		{"acknowledge_message", "Mark a message read. Identified sessions may only acknowledge messages sent to them.", schemaMessageID},
+		{"get_thread", getThreadDescription, schemaMessageID},
+		{"get_briefing", getBriefingDescription, schemaGetBriefing},
 	}
 }
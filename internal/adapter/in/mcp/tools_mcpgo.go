@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -21,7 +22,7 @@ func RegisterTools(s *server.MCPServer, svc *blueprint.Service) {
 	// get_project
 	s.AddTool(mcp.NewTool("get_project",
 		mcp.WithDescription("Return one project by id."),
-		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
 	), toolGetProject(svc))
 
 	// create_project
@@ -48,14 +49,14 @@ func RegisterTools(s *server.MCPServer, svc *blueprint.Service) {
 	// add_ignored_path
 	s.AddTool(mcp.NewTool("add_ignored_path",
 		mcp.WithDescription("Add a file or directory path to the project's ignore list. Ignored paths are hidden from the tree view."),
-		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to ignore")),
 	), toolAddIgnoredPath(svc))
 
 	// remove_ignored_path
 	s.AddTool(mcp.NewTool("remove_ignored_path",
 		mcp.WithDescription("Remove a path from the project's ignore list so it is shown again in the tree view."),
-		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
 		mcp.WithString("path", mcp.Required(), mcp.Description("Path to remove from ignore list")),
 	), toolRemoveIgnoredPath(svc))
 
@@ -64,21 +65,25 @@ func RegisterTools(s *server.MCPServer, svc *blueprint.Service) {
 		mcp.WithDescription("Return paths under project root that match the given regex pattern. Use project_id or root to specify the base directory."),
 		mcp.WithString("pattern", mcp.Required(), mcp.Description("Regex pattern")),
 		mcp.WithString("root", mcp.Description("Root path (optional)")),
-		mcp.WithString("project_id", mcp.Description("Project ID (optional)")),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("format", mcp.Description(treeFormatDescription), mcp.Enum("json", "text", "collapsed")),
+		mcp.WithNumber("max_entries", mcp.Description(maxEntriesDescription)),
 	), toolListMatchingPaths(svc))
 
 	// list_tree
 	s.AddTool(mcp.NewTool("list_tree",
 		mcp.WithDescription("Return the project's folder structure as a hierarchical tree. Use project_id or root to specify the base directory."),
 		mcp.WithString("root", mcp.Description("Root path (optional)")),
-		mcp.WithString("project_id", mcp.Description("Project ID (optional)")),
-		mcp.WithNumber("depth", mcp.Description("Max depth (optional)")),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithNumber("depth", mcp.Description("Max depth (optional; text formats summarize deeper directories)")),
+		mcp.WithString("format", mcp.Description(treeFormatDescription), mcp.Enum("json", "text", "collapsed")),
+		mcp.WithNumber("max_entries", mcp.Description(maxEntriesDescription)),
 	), toolListTree(svc))
 
 	// list_zones
 	s.AddTool(mcp.NewTool("list_zones",
 		mcp.WithDescription("Return all zones for the given project."),
-		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
 	), toolListZones(svc))
 
 	// get_zone
@@ -90,12 +95,12 @@ func RegisterTools(s *server.MCPServer, svc *blueprint.Service) {
 	// create_zone
 	s.AddTool(mcp.NewTool("create_zone",
 		mcp.WithDescription("Create a zone in the given project with optional metadata and pattern."),
-		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Zone name")),
 		mcp.WithString("pattern", mcp.Description("Regex pattern")),
 		mcp.WithString("purpose", mcp.Description("Purpose")),
 		mcp.WithArray("constraints", mcp.Description("Constraints"), mcp.Items(map[string]any{"type": "string"})),
-		mcp.WithAny("assigned_agents", mcp.Description("Assigned agents (array of {id, name})")),
+		mcp.WithAny("assigned_agents", mcp.Description("Assigned agents: array of agent ids or {id, name} objects; ids must exist")),
 	), toolCreateZone(svc))
 
 	// update_zone
@@ -106,7 +111,7 @@ func RegisterTools(s *server.MCPServer, svc *blueprint.Service) {
 		mcp.WithString("pattern", mcp.Description("Regex pattern")),
 		mcp.WithString("purpose", mcp.Description("Purpose")),
 		mcp.WithArray("constraints", mcp.Description("Constraints"), mcp.Items(map[string]any{"type": "string"})),
-		mcp.WithAny("assigned_agents", mcp.Description("Assigned agents (array of {id, name})")),
+		mcp.WithAny("assigned_agents", mcp.Description("Assigned agents: array of agent ids or {id, name} objects; ids must exist")),
 	), toolUpdateZone(svc))
 
 	// assign_path_to_zone
@@ -129,19 +134,21 @@ func RegisterTools(s *server.MCPServer, svc *blueprint.Service) {
 
 	// create_agent
 	s.AddTool(mcp.NewTool("create_agent",
-		mcp.WithDescription("Create an agent with optional name, description, and prompt."),
+		mcp.WithDescription("Create an agent with optional name, description, prompt template, and declared variables."),
 		mcp.WithString("name", mcp.Description("Agent name")),
 		mcp.WithString("description", mcp.Description("Agent description")),
-		mcp.WithString("prompt", mcp.Description("Agent prompt (instructions for the agent)")),
+		mcp.WithString("prompt", mcp.Description(promptParamDescription)),
+		mcp.WithArray("variables", mcp.Description(variablesParamDescription), mcp.Items(variableSchema)),
 	), toolCreateAgent(svc))
 
 	// update_agent
 	s.AddTool(mcp.NewTool("update_agent",
-		mcp.WithDescription("Update an agent's name, description, and/or prompt."),
+		mcp.WithDescription("Update an agent's name, description, prompt template, and/or declared variables."),
 		mcp.WithString("agent_id", mcp.Required(), mcp.Description("Agent ID")),
 		mcp.WithString("name", mcp.Description("Agent name")),
 		mcp.WithString("description", mcp.Description("Agent description")),
-		mcp.WithString("prompt", mcp.Description("Agent prompt (instructions for the agent)")),
+		mcp.WithString("prompt", mcp.Description(promptParamDescription)),
+		mcp.WithArray("variables", mcp.Description(variablesParamDescription), mcp.Items(variableSchema)),
 	), toolUpdateAgent(svc))
 
 	// delete_agent
@@ -149,8 +156,407 @@ func RegisterTools(s *server.MCPServer, svc *blueprint.Service) {
 		mcp.WithDescription("Delete an agent by id. The agent is removed from all zones that reference it."),
 		mcp.WithString("agent_id", mcp.Required(), mcp.Description("Agent ID")),
 	), toolDeleteAgent(svc))
+
+	// render_agent_prompt
+	s.AddTool(mcp.NewTool("render_agent_prompt",
+		mcp.WithDescription("Render an agent's prompt template for a zone, task and variable values."),
+		mcp.WithString("agent_id", mcp.Required(), mcp.Description("Agent ID")),
+		mcp.WithString("zone_id", mcp.Description("Zone to render for; provides .Zone and .Project")),
+		mcp.WithString("task", mcp.Description("Task text, available as .Task")),
+		mcp.WithObject("variables", mcp.Description("Values for the agent's declared variables, keyed by name"), mcp.AdditionalProperties(map[string]any{"type": "string"})),
+	), toolRenderAgentPrompt(svc))
+
+	// export_diagram
+	s.AddTool(mcp.NewTool("export_diagram",
+		mcp.WithDescription("Render a project's zones (purpose, assigned agents, dependencies) as a Mermaid, Graphviz DOT, or PlantUML diagram."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("format", mcp.Description("Diagram format: mermaid (default), dot, or plantuml"), mcp.Enum("mermaid", "dot", "plantuml")),
+		mcp.WithBoolean("group_nested", mcp.Description("Group zones nested inside other zones")),
+		mcp.WithArray("zone_ids", mcp.Description("Only include these zones (optional)"), mcp.Items(map[string]any{"type": "string"})),
+	), toolExportDiagram(svc))
+
+	// export_blueprint
+	s.AddTool(mcp.NewTool("export_blueprint",
+		mcp.WithDescription("Serialize a project (zones, patterns, constraints, explicit paths, agent references, ignored paths) as a YAML or JSON blueprint document."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("format", mcp.Description("Document format: yaml (default) or json"), mcp.Enum("yaml", "json")),
+	), toolExportBlueprint(svc))
+
+	// import_blueprint
+	s.AddTool(mcp.NewTool("import_blueprint",
+		mcp.WithDescription("Apply a YAML or JSON blueprint document to a project. Modes: create, update (default), prune. Use dry_run to get the diff without writing."),
+		mcp.WithString("document", mcp.Required(), mcp.Description("Blueprint document content")),
+		mcp.WithString("format", mcp.Description("Document format: yaml or json (detected when omitted)"), mcp.Enum("yaml", "json")),
+		mcp.WithString("project_id", mcp.Description("Target project ID (optional)")),
+		mcp.WithString("root_dir", mcp.Description("Root directory of the target project; a project is created there if none exists (optional)")),
+		mcp.WithString("mode", mcp.Description("create, update (default), or prune"), mcp.Enum("create", "update", "prune")),
+		mcp.WithBoolean("dry_run", mcp.Description("Return the diff without applying it")),
+	), toolImportBlueprint(svc))
+
+	// bind_blueprint_file
+	s.AddTool(mcp.NewTool("bind_blueprint_file",
+		mcp.WithDescription("Bind a project to a blueprint file inside its root (default .operators/blueprint.yaml). Changes are written back to the file and file edits are applied to the store."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("file", mcp.Description("Blueprint file path relative to the project root (optional)")),
+		mcp.WithString("initial", mcp.Description("Side that wins the first sync: file or store (default: file if it exists)"), mcp.Enum("file", "store")),
+	), toolBindBlueprintFile(svc))
+
+	// unbind_blueprint_file
+	s.AddTool(mcp.NewTool("unbind_blueprint_file",
+		mcp.WithDescription("Remove a project's blueprint file binding. The file is kept."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+	), toolUnbindBlueprintFile(svc))
+
+	// sync_blueprint
+	s.AddTool(mcp.NewTool("sync_blueprint",
+		mcp.WithDescription("Reconcile a project with its bound blueprint file. Reports a conflict when both changed; use resolve=file or resolve=store to pick a side."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("resolve", mcp.Description("Force a side: file or store (optional)"), mcp.Enum("file", "store")),
+	), toolSyncBlueprint(svc))
+
+	// read_file
+	s.AddTool(mcp.NewTool("read_file",
+		mcp.WithDescription("Read a text file of the project, optionally a line range. Paths outside the root or in ignored paths are refused; with agent_id the file must be in a zone assigned to that agent."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("path", mcp.Required(), mcp.Description("File path relative to the project root")),
+		mcp.WithNumber("start_line", mcp.Description("First line to return, 1-based (default 1)")),
+		mcp.WithNumber("end_line", mcp.Description("Last line to return, inclusive (default last line)")),
+		mcp.WithNumber("max_bytes", mcp.Description("Maximum bytes of content to return (default 262144)")),
+		mcp.WithString("agent_id", mcp.Description("Require the file to be in a zone assigned to this agent (defaults to the session's agent)")),
+	), toolReadFile(svc))
+
+	// read_zone_files
+	s.AddTool(mcp.NewTool("read_zone_files",
+		mcp.WithDescription("Read the text files that belong to a zone, up to max_bytes in total. With agent_id the zone must be assigned to that agent."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithNumber("max_bytes", mcp.Description("Maximum bytes of content to return in total (default 524288)")),
+		mcp.WithString("agent_id", mcp.Description("Require the zone to be assigned to this agent (defaults to the session's agent)")),
+	), toolReadZoneFiles(svc))
+
+	// build_context
+	s.AddTool(mcp.NewTool("build_context",
+		mcp.WithDescription(buildContextDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID for paths (defaults to the session's active project)")),
+		mcp.WithString("zone_id", mcp.Description("Zone whose files to bundle")),
+		mcp.WithArray("paths", mcp.Description("Files or directories to bundle, relative to the project root (instead of zone_id)"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithNumber("max_tokens", mcp.Description("Token budget (default 16000, at most 250000; 4 characters per token)")),
+		mcp.WithString("agent_id", mcp.Description("Require the files to be in zones assigned to this agent (defaults to the session's agent)")),
+	), toolBuildContext(svc))
+
+	// write_file
+	s.AddTool(mcp.NewTool("write_file",
+		mcp.WithDescription(writeFileDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("agent_id", mcp.Description("Agent making the change (defaults to the session's agent)")),
+		mcp.WithString("path", mcp.Required(), mcp.Description("File path relative to the project root")),
+		mcp.WithString("content", mcp.Description("New file content")),
+	), toolWriteFile(svc))
+
+	// apply_patch
+	s.AddTool(mcp.NewTool("apply_patch",
+		mcp.WithDescription(applyPatchDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("agent_id", mcp.Description("Agent making the change (defaults to the session's agent)")),
+		mcp.WithString("patch", mcp.Required(), mcp.Description("Unified diff with paths relative to the project root (git a/ b/ prefixes allowed)")),
+	), toolApplyPatch(svc))
+
+	// list_changes
+	s.AddTool(mcp.NewTool("list_changes",
+		mcp.WithDescription("List the file changes made through write_file and apply_patch in a project, newest first."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of changes (default all)")),
+	), toolListChanges(svc))
+
+	// whoami
+	s.AddTool(mcp.NewTool("whoami",
+		mcp.WithDescription("Return the agent this session is identified as (via a bearer token), or anonymous."),
+	), toolWhoAmI(svc))
+
+	// issue_agent_token
+	s.AddTool(mcp.NewTool("issue_agent_token",
+		mcp.WithDescription(issueAgentTokenDescription),
+		mcp.WithString("agent_id", mcp.Required(), mcp.Description("Agent ID")),
+	), toolIssueAgentToken(svc))
+
+	// revoke_agent_token
+	s.AddTool(mcp.NewTool("revoke_agent_token",
+		mcp.WithDescription("Revoke an agent's bearer token. Identified sessions may only revoke their own token."),
+		mcp.WithString("agent_id", mcp.Required(), mcp.Description("Agent ID")),
+	), toolRevokeAgentToken(svc))
+
+	// create_task
+	s.AddTool(mcp.NewTool("create_task",
+		mcp.WithDescription(createTaskDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("title", mcp.Required(), mcp.Description("Task title")),
+		mcp.WithString("description", mcp.Description("What needs to be done")),
+		mcp.WithArray("zone_ids", mcp.Description("Zones the task touches"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithString("agent_id", mcp.Description("Agent to hand the task to")),
+	), toolCreateTask(svc))
+
+	// list_tasks
+	s.AddTool(mcp.NewTool("list_tasks",
+		mcp.WithDescription("List a project's tasks oldest first, optionally filtered by status, assigned agent or zone."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("status", mcp.Description("open, claimed, in_progress, blocked, done or cancelled")),
+		mcp.WithString("agent_id", mcp.Description("Assigned agent")),
+		mcp.WithString("zone_id", mcp.Description("Zone the tasks touch")),
+	), toolListTasks(svc))
+
+	// get_task
+	s.AddTool(mcp.NewTool("get_task",
+		mcp.WithDescription("Return one task by id."),
+		mcp.WithString("task_id", mcp.Required(), mcp.Description("Task ID")),
+	), toolGetTask(svc))
+
+	// claim_task
+	s.AddTool(mcp.NewTool("claim_task",
+		mcp.WithDescription(claimTaskDescription),
+		mcp.WithString("task_id", mcp.Required(), mcp.Description("Task ID")),
+		mcp.WithString("agent_id", mcp.Description("Agent claiming the task (defaults to the session's agent)")),
+	), toolClaimTask(svc))
+
+	// update_task_status
+	s.AddTool(mcp.NewTool("update_task_status",
+		mcp.WithDescription(updateTaskStatusDescription),
+		mcp.WithString("task_id", mcp.Required(), mcp.Description("Task ID")),
+		mcp.WithString("status", mcp.Required(), mcp.Description("open, in_progress, blocked or cancelled")),
+	), toolUpdateTaskStatus(svc))
+
+	// complete_task
+	s.AddTool(mcp.NewTool("complete_task",
+		mcp.WithDescription("Mark a claimed task done with a summary of the result."),
+		mcp.WithString("task_id", mcp.Required(), mcp.Description("Task ID")),
+		mcp.WithString("result", mcp.Description("Summary of the work done")),
+	), toolCompleteTask(svc))
+
+	// route_task
+	s.AddTool(mcp.NewTool("route_task",
+		mcp.WithDescription(routeTaskDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project; ignored with task_id)")),
+		mcp.WithString("task_id", mcp.Description("Route a stored task using its title and description")),
+		mcp.WithString("title", mcp.Description("Task title, used to name suggested subtasks")),
+		mcp.WithString("description", mcp.Description("Task description to extract path references from")),
+		mcp.WithArray("files", mcp.Description("Paths the task touches, relative to the project root"), mcp.Items(map[string]any{"type": "string"})),
+	), toolRouteTask(svc))
+
+	// claim_zone
+	s.AddTool(mcp.NewTool("claim_zone",
+		mcp.WithDescription(claimZoneDescription),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithString("agent_id", mcp.Description("Agent taking the lease (defaults to the session's agent)")),
+		mcp.WithNumber("ttl_seconds", mcp.Description("Lease duration in seconds (default 600, at most one day)")),
+	), toolClaimZone(svc))
+
+	// renew_lease
+	s.AddTool(mcp.NewTool("renew_lease",
+		mcp.WithDescription(renewLeaseDescription),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithString("agent_id", mcp.Description("Agent holding the lease (defaults to the session's agent)")),
+		mcp.WithNumber("ttl_seconds", mcp.Description("New lease duration from now in seconds (default 600, at most one day)")),
+	), toolRenewLease(svc))
+
+	// release_zone
+	s.AddTool(mcp.NewTool("release_zone",
+		mcp.WithDescription(releaseZoneDescription),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithString("agent_id", mcp.Description("Agent holding the lease (defaults to the session's agent)")),
+	), toolReleaseZone(svc))
+
+	// list_leases
+	s.AddTool(mcp.NewTool("list_leases",
+		mcp.WithDescription("List a project's active zone leases: zone, holder agent and expiry."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+	), toolListLeases(svc))
+
+	// create_run
+	s.AddTool(mcp.NewTool("create_run",
+		mcp.WithDescription(createRunDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("title", mcp.Required(), mcp.Description("Run title")),
+		mcp.WithString("description", mcp.Description("What the run changes")),
+		mcp.WithArray("tasks", mcp.Description("Tasks of the run, each with the task_ids of the run it depends_on"), mcp.Items(runTaskSchema)),
+	), toolCreateRun(svc))
+
+	// set_run_task
+	s.AddTool(mcp.NewTool("set_run_task",
+		mcp.WithDescription(setRunTaskDescription),
+		mcp.WithString("run_id", mcp.Required(), mcp.Description("Run ID")),
+		mcp.WithString("task_id", mcp.Required(), mcp.Description("Task ID")),
+		mcp.WithArray("depends_on", mcp.Description("Tasks of the run that must be done first"), mcp.Items(map[string]any{"type": "string"})),
+	), toolSetRunTask(svc))
+
+	// get_run
+	s.AddTool(mcp.NewTool("get_run",
+		mcp.WithDescription(getRunDescription),
+		mcp.WithString("run_id", mcp.Required(), mcp.Description("Run ID")),
+	), toolGetRun(svc))
+
+	// list_runs
+	s.AddTool(mcp.NewTool("list_runs",
+		mcp.WithDescription("List a project's runs oldest first, each with its progress."),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+	), toolListRuns(svc))
+
+	// delete_run
+	s.AddTool(mcp.NewTool("delete_run",
+		mcp.WithDescription("Delete a run. Its tasks are kept and no longer wait on each other."),
+		mcp.WithString("run_id", mcp.Required(), mcp.Description("Run ID")),
+	), toolDeleteRun(svc))
+
+	// create_decision
+	s.AddTool(mcp.NewTool("create_decision",
+		mcp.WithDescription(createDecisionDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("title", mcp.Required(), mcp.Description("Decision title")),
+		mcp.WithString("context", mcp.Description("The forces and constraints that led to the decision")),
+		mcp.WithString("decision", mcp.Description("What was decided")),
+		mcp.WithString("consequences", mcp.Description("What becomes easier or harder")),
+		mcp.WithString("status", mcp.Description("proposed (default) or accepted")),
+		mcp.WithArray("zone_ids", mcp.Description("Zones the decision concerns"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithString("supersedes", mcp.Description("ID of the decision this one replaces")),
+	), toolCreateDecision(svc))
+
+	// set_decision_status
+	s.AddTool(mcp.NewTool("set_decision_status",
+		mcp.WithDescription(setDecisionStatusDescription),
+		mcp.WithString("decision_id", mcp.Required(), mcp.Description("Decision ID")),
+		mcp.WithString("status", mcp.Required(), mcp.Description("accepted or superseded")),
+	), toolSetDecisionStatus(svc))
+
+	// get_decision
+	s.AddTool(mcp.NewTool("get_decision",
+		mcp.WithDescription("Return one architecture decision record by id."),
+		mcp.WithString("decision_id", mcp.Required(), mcp.Description("Decision ID")),
+	), toolGetDecision(svc))
+
+	// list_decisions
+	s.AddTool(mcp.NewTool("list_decisions",
+		mcp.WithDescription(listDecisionsDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("zone_id", mcp.Description("Zone the decisions concern")),
+		mcp.WithString("status", mcp.Description("proposed, accepted or superseded")),
+		mcp.WithString("query", mcp.Description("Text to search for in title, context, decision and consequences")),
+	), toolListDecisions(svc))
+
+	// export_decisions
+	s.AddTool(mcp.NewTool("export_decisions",
+		mcp.WithDescription(exportDecisionsDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("agent_id", mcp.Description("Agent writing the files (defaults to the session's agent)")),
+		mcp.WithString("dir", mcp.Description("Directory relative to the project root (default docs/adr)")),
+	), toolExportDecisions(svc))
+
+	// add_zone_note
+	s.AddTool(mcp.NewTool("add_zone_note",
+		mcp.WithDescription(addZoneNoteDescription),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithString("agent_id", mcp.Description("Author (defaults to the session's agent)")),
+		mcp.WithString("text", mcp.Required(), mcp.Description("Note text (at most 2000 bytes)")),
+		mcp.WithArray("tags", mcp.Description("Tags, e.g. gotcha, entry-point, command"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithBoolean("pinned", mcp.Description("Keep the note until it is deleted")),
+		mcp.WithNumber("ttl_days", mcp.Description("Days until the note expires (default 30, at most 365; ignored when pinned)")),
+	), toolAddZoneNote(svc))
+
+	// list_zone_notes
+	s.AddTool(mcp.NewTool("list_zone_notes",
+		mcp.WithDescription("List a zone's current notes, pinned first and then newest first, optionally only those with a tag."),
+		mcp.WithString("zone_id", mcp.Required(), mcp.Description("Zone ID")),
+		mcp.WithString("tag", mcp.Description("Only notes with this tag")),
+	), toolListZoneNotes(svc))
+
+	// search_zone_notes
+	s.AddTool(mcp.NewTool("search_zone_notes",
+		mcp.WithDescription(searchZoneNotesDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project)")),
+		mcp.WithString("query", mcp.Description("Words that must all appear in the note text or tags")),
+		mcp.WithArray("tags", mcp.Description("Tags the notes must all carry"), mcp.Items(map[string]any{"type": "string"})),
+		mcp.WithString("zone_id", mcp.Description("Only notes of this zone")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of notes (default 50)")),
+	), toolSearchZoneNotes(svc))
+
+	// delete_zone_note
+	s.AddTool(mcp.NewTool("delete_zone_note",
+		mcp.WithDescription("Delete a zone note. Identified sessions may only delete their own notes."),
+		mcp.WithString("note_id", mcp.Required(), mcp.Description("Note ID")),
+	), toolDeleteZoneNote(svc))
+
+	// send_message
+	s.AddTool(mcp.NewTool("send_message",
+		mcp.WithDescription(sendMessageDescription),
+		mcp.WithString("project_id", mcp.Description("Project ID (defaults to the session's active project; ignored with reply_to)")),
+		mcp.WithString("agent_id", mcp.Description("Sending agent (defaults to the session's agent)")),
+		mcp.WithString("to_agent_id", mcp.Description("Recipient agent")),
+		mcp.WithString("zone_id", mcp.Description("Recipient zone: every agent assigned to it")),
+		mcp.WithString("reply_to", mcp.Description("Message ID being answered")),
+		mcp.WithString("subject", mcp.Description("Subject")),
+		mcp.WithString("body", mcp.Required(), mcp.Description("Message text")),
+	), toolSendMessage(svc))
+
+	// list_inbox
+	s.AddTool(mcp.NewTool("list_inbox",
+		mcp.WithDescription(listInboxDescription),
+		mcp.WithString("agent_id", mcp.Description("Recipient agent (defaults to the session's agent)")),
+		mcp.WithString("project_id", mcp.Description("Project ID (default all projects)")),
+		mcp.WithBoolean("unread_only", mcp.Description("Leave out acknowledged messages")),
+	), toolListInbox(svc))
+
+	// acknowledge_message
+	s.AddTool(mcp.NewTool("acknowledge_message",
+		mcp.WithDescription("Mark a message read. Identified sessions may only acknowledge messages sent to them."),
+		mcp.WithString("message_id", mcp.Required(), mcp.Description("Message ID")),
+		mcp.WithString("agent_id", mcp.Description("Recipient agent (defaults to the session's agent)")),
+	), toolAcknowledgeMessage(svc))
+
+	// get_thread
+	s.AddTool(mcp.NewTool("get_thread",
+		mcp.WithDescription(getThreadDescription),
+		mcp.WithString("message_id", mcp.Required(), mcp.Description("Message ID")),
+		mcp.WithString("agent_id", mcp.Description("Agent of the thread (defaults to the session's agent)")),
+	), toolGetThread(svc))
+
+	// get_briefing
+	s.AddTool(mcp.NewTool("get_briefing",
+		mcp.WithDescription(getBriefingDescription),
+		mcp.WithString("agent_id", mcp.Description("Agent to brief (defaults to the session's agent)")),
+		mcp.WithString("zone_id", mcp.Description("Zone to brief for (default all of the agent's zones)")),
+		mcp.WithString("project_id", mcp.Description("Only the agent's zones in this project (ignored with zone_id)")),
+		mcp.WithString("task", mcp.Description("Task text, available to the prompt as .Task")),
+		mcp.WithObject("variables", mcp.Description("Values for the agent's declared variables, keyed by name"), mcp.AdditionalProperties(map[string]any{"type": "string"})),
+		mcp.WithNumber("max_chars", mcp.Description("Maximum length of the briefing text in characters")),
+		mcp.WithNumber("max_tokens", mcp.Description("Maximum estimated tokens of the briefing text (4 characters per token)")),
+	), toolGetBriefing(svc))
 }
 
+const (
+	issueAgentTokenDescription   = "Issue a new bearer token for an agent, replacing its previous one. Clients send it as 'Authorization: Bearer <token>' to identify their MCP session as the agent. The token is shown only once; identified sessions may only issue their own."
+	writeFileDescription         = "Create or replace a file on behalf of an agent (the session's agent unless agent_id is given). The path must be in a zone assigned to the agent; otherwise OUT_OF_ZONE lists the owning zones. Zones leased by another agent are refused with ZONE_LEASED. The change is recorded."
+	createTaskDescription        = "Create an open task in a project, routed to the zones it touches and optionally handed to an agent. The session's agent is recorded as its creator."
+	claimTaskDescription         = "Claim an open task for an agent (the session's agent unless agent_id is given). Tasks routed to zones can only be claimed by an agent assigned to one of them, and tasks of a run only once their prerequisites are done."
+	routeTaskDescription         = "Propose which zones and agents should do a task: path references in its description and the listed files are resolved against zone patterns and explicit paths. Work spanning several zones gets one suggested subtask per zone. Nothing is changed."
+	updateTaskStatusDescription  = "Move a task to open (releasing its agent), in_progress, blocked or cancelled. Identified sessions may only update their own tasks; use complete_task to finish one."
+	applyPatchDescription        = "Apply a unified diff on behalf of an agent (the session's agent unless agent_id is given). Every touched path must be in a zone assigned to the agent (OUT_OF_ZONE lists all offending paths) and not in a zone leased by another agent (ZONE_LEASED); nothing is written unless every hunk applies. Changes are recorded."
+	claimZoneDescription         = "Take an exclusive, expiring write lease on one of an agent's zones (the session's agent unless agent_id is given). While it is active, write_file and apply_patch refuse other agents' changes there with ZONE_LEASED. Claiming a zone you hold extends the lease; a zone leased by another agent is refused until released or expired."
+	renewLeaseDescription        = "Extend an agent's active lease on a zone to ttl_seconds from now. Fails with LEASE_NOT_HELD when the agent holds no active lease on the zone."
+	createRunDescription         = "Group existing tasks of a project into a run: a dependency graph an orchestrator drives to completion. Each task lists the tasks of the run it depends_on; a task is ready once they are all done, and claim_task refuses it before (TASK_NOT_READY). Cycles are refused (DEPENDENCY_CYCLE) and a task belongs to one run only."
+	setRunTaskDescription        = "Add a task to a run with its prerequisites, or replace the prerequisites of a task already in it. Cycles are refused (DEPENDENCY_CYCLE)."
+	getRunDescription            = "Return a run with its progress: status (pending, active, stalled, complete), counts by task status, percent closed, the tasks ready to be claimed, the tasks waiting on prerequisites and per-zone counts."
+	createDecisionDescription    = "Record an architecture decision (ADR) about zones of a project: title, context, decision, consequences and status (proposed or accepted). Decisions are numbered per project (ADR-0001). Accepted decisions are included in the briefing of agents working in their zones. With supersedes, accepting the new decision marks the old one superseded."
+	setDecisionStatusDescription = "Accept a proposed decision, or mark a decision superseded. Superseded decisions are final. Accepting a decision that supersedes another marks that one superseded."
+	listDecisionsDescription     = "List a project's architecture decision records oldest first, optionally filtered by zone, status or a text query."
+	exportDecisionsDescription   = "Write a project's decision records as markdown into the project (default docs/adr) on behalf of an agent (the session's agent unless agent_id is given): one NNNN-title.md file per decision and a README.md index. Existing files are replaced and files an earlier export wrote for a decision under a previous title are removed. The files are recorded in the change log; the export is refused outside the agent's zones and while another agent leases their zone."
+	addZoneNoteDescription       = "Add a short note to a zone's shared scratchpad (a gotcha, an entry point, a command that works), written by an agent (the session's agent unless agent_id is given). Notes expire after ttl_days (default 30) unless pinned. A zone keeps at most 100 unpinned notes, evicting the oldest, and 20 pinned ones."
+	searchZoneNotesDescription   = "Search the current notes of a project's zones by words and tags, pinned first and then newest first."
+	sendMessageDescription       = "Send a message from an agent (the session's agent unless agent_id is given) to another agent (to_agent_id) or to every other agent of a zone (zone_id), e.g. to ask a zone's owner for an interface change. Pass reply_to to answer a message in a thread the agent takes part in; replies go back to its sender by default. An agent cannot message itself. Recipients connected with their token are notified with a notifications/message log entry (logger inbox)."
+	listInboxDescription         = "List the messages sent to an agent (the session's agent unless agent_id is given), oldest first, in one project or all. With unread_only, acknowledged messages are left out."
+	buildContextDescription      = "Bundle the text files of a zone, or of files and directories, into one text within a token budget, so an agent can load its working set in one call. Files are taken in priority order: entry points (main, index, README, ...), small files, files recently changed through the server, then the rest. A file that does not fit is cut at a line boundary when enough budget is left; every file left out is reported in omitted with why (budget, binary, too_large, not_found)."
+	treeFormatDescription        = "Output format: json (default), text (indented tree with zone annotations) or collapsed (text with large directories summarized as N files)"
+	maxEntriesDescription        = "collapsed format: summarize directories with more entries than this (default 20)"
+	getBriefingDescription       = "Assemble everything an agent (the session's agent unless agent_id is given) needs before starting work, for one zone or all its zones: the rendered prompt, each zone's purpose, constraints, accepted decisions, notes and owned paths, and the neighbouring zones with their owners and dependency relations. The result is structured and also rendered as markdown text; with max_chars or max_tokens, paths are dropped first, then notes, neighbours and decisions, until the text fits."
+	getThreadDescription         = "Return every message of the thread containing a message, oldest first. The agent (the session's agent unless agent_id is given) must have sent or received a message of the thread."
+	releaseZoneDescription       = "Release the lease on a zone. Identified sessions and calls with agent_id may only release their own lease; anonymous calls without agent_id release any lease."
+)
+
 func toolListProjects(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		projects := svc.ListProjects()
@@ -161,7 +567,7 @@ func toolListProjects(svc *blueprint.Service) func(context.Context, mcp.CallTool
 
 func toolGetProject(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		projectID, err := req.RequireString("project_id")
+		projectID, err := projectArg(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -190,6 +596,9 @@ func toolCreateProject(svc *blueprint.Service) func(context.Context, mcp.CallToo
 
 func toolUpdateProject(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := checkEndpointProject(ctx, req.GetString("project_id", "")); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		projectID, err := req.RequireString("project_id")
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -206,6 +615,9 @@ func toolUpdateProject(svc *blueprint.Service) func(context.Context, mcp.CallToo
 
 func toolDeleteProject(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := checkEndpointProject(ctx, req.GetString("project_id", "")); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		projectID, err := req.RequireString("project_id")
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -219,7 +631,7 @@ func toolDeleteProject(svc *blueprint.Service) func(context.Context, mcp.CallToo
 
 func toolAddIgnoredPath(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		projectID, err := req.RequireString("project_id")
+		projectID, err := projectArg(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -237,7 +649,7 @@ func toolAddIgnoredPath(svc *blueprint.Service) func(context.Context, mcp.CallTo
 
 func toolRemoveIgnoredPath(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		projectID, err := req.RequireString("project_id")
+		projectID, err := projectArg(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -259,31 +671,76 @@ func toolListMatchingPaths(svc *blueprint.Service) func(context.Context, mcp.Cal
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
-		root := req.GetString("root", "")
-		projectID := req.GetString("project_id", "")
+		format, err := blueprint.ParseTreeFormat(req.GetString("format", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		root, err := rootArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		projectID, err := sessionProject(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		paths, err := svc.ListMatchingPaths(root, projectID, pattern)
 		if err != nil {
 			return toolError(err)
 		}
-		return jsonResult(ListMatchingPathsOut{Paths: paths})
+		if format == blueprint.TreeFormatJSON {
+			return jsonResult(ListMatchingPathsOut{Paths: paths})
+		}
+		return treeTextResult(req, format, blueprint.PathsTree(paths), projectZones(svc, root, projectID)), nil
 	}
 }
 
 func toolListTree(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		root := req.GetString("root", "")
-		projectID := req.GetString("project_id", "")
+		format, err := blueprint.ParseTreeFormat(req.GetString("format", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		root, err := rootArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		projectID, err := sessionProject(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		tree, err := svc.ListTree(root, projectID)
 		if err != nil {
 			return toolError(err)
 		}
-		return jsonResult(ListTreeOut{Tree: TreeNodeToDTO(tree)})
+		if format == blueprint.TreeFormatJSON {
+			return jsonResult(ListTreeOut{Tree: TreeNodeToDTO(tree)})
+		}
+		return treeTextResult(req, format, tree, projectZones(svc, root, projectID)), nil
+	}
+}
+
+// treeTextResult renders tree in a text format (see blueprint.RenderTree) with the zones
+// annotated, using the max_entries and depth arguments.
+func treeTextResult(req mcp.CallToolRequest, format string, tree *domain.TreeNode, zones []*domain.Zone) *mcp.CallToolResult {
+	return mcp.NewToolResultText(blueprint.RenderTree(tree, zones, blueprint.TreeTextOptions{
+		Collapse:   format == blueprint.TreeFormatCollapsed,
+		MaxEntries: req.GetInt("max_entries", 0),
+		Depth:      req.GetInt("depth", 0),
+	}))
+}
+
+// projectZones returns the zones to annotate a listing with: the project's, unless an explicit
+// root was listed instead.
+func projectZones(svc *blueprint.Service, root, projectID string) []*domain.Zone {
+	if root != "" || projectID == "" {
+		return nil
 	}
+	return svc.ListZones(projectID)
 }
 
 func toolListZones(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		projectID, err := req.RequireString("project_id")
+		projectID, err := projectArg(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -308,7 +765,7 @@ func toolGetZone(svc *blueprint.Service) func(context.Context, mcp.CallToolReque
 
 func toolCreateZone(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		projectID, err := req.RequireString("project_id")
+		projectID, err := projectArg(ctx, req)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -319,21 +776,7 @@ func toolCreateZone(svc *blueprint.Service) func(context.Context, mcp.CallToolRe
 		pattern := req.GetString("pattern", "")
 		purpose := req.GetString("purpose", "")
 		constraints := req.GetStringSlice("constraints", []string{})
-		args := req.GetArguments()
-		agentsRaw := args["assigned_agents"]
-		var agents []AgentDTO
-		if agentsRaw != nil {
-			if slice, ok := agentsRaw.([]any); ok {
-				for _, v := range slice {
-					if m, ok := v.(map[string]any); ok {
-						id, _ := m["id"].(string)
-						n, _ := m["name"].(string)
-						agents = append(agents, AgentDTO{ID: id, Name: n})
-					}
-				}
-			}
-		}
-		z, err := svc.CreateZone(projectID, name, pattern, purpose, constraints, DTOToAgents(agents))
+		z, err := svc.CreateZone(projectID, name, pattern, purpose, constraints, agentIDsArg(req))
 		if err != nil {
 			return toolError(err)
 		}
@@ -341,6 +784,23 @@ func toolCreateZone(svc *blueprint.Service) func(context.Context, mcp.CallToolRe
 	}
 }
 
+// agentIDsArg reads assigned_agents as a list of agent ids; items may be id strings or {id, name} objects.
+func agentIDsArg(req mcp.CallToolRequest) []string {
+	slice, _ := req.GetArguments()["assigned_agents"].([]any)
+	var ids []string
+	for _, v := range slice {
+		switch v := v.(type) {
+		case string:
+			ids = append(ids, v)
+		case map[string]any:
+			if id, _ := v["id"].(string); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
 func toolUpdateZone(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		zoneID, err := req.RequireString("zone_id")
@@ -351,21 +811,7 @@ func toolUpdateZone(svc *blueprint.Service) func(context.Context, mcp.CallToolRe
 		pattern := req.GetString("pattern", "")
 		purpose := req.GetString("purpose", "")
 		constraints := req.GetStringSlice("constraints", []string{})
-		args := req.GetArguments()
-		agentsRaw := args["assigned_agents"]
-		var agents []AgentDTO
-		if agentsRaw != nil {
-			if slice, ok := agentsRaw.([]any); ok {
-				for _, v := range slice {
-					if m, ok := v.(map[string]any); ok {
-						id, _ := m["id"].(string)
-						n, _ := m["name"].(string)
-						agents = append(agents, AgentDTO{ID: id, Name: n})
-					}
-				}
-			}
-		}
-		z, err := svc.UpdateZone(zoneID, name, pattern, purpose, constraints, DTOToAgents(agents))
+		z, err := svc.UpdateZone(zoneID, name, pattern, purpose, constraints, agentIDsArg(req))
 		if err != nil {
 			return toolError(err)
 		}
@@ -421,7 +867,7 @@ func toolCreateAgent(svc *blueprint.Service) func(context.Context, mcp.CallToolR
 		name := req.GetString("name", "")
 		description := req.GetString("description", "")
 		prompt := req.GetString("prompt", "")
-		a, err := svc.CreateAgent(name, description, prompt)
+		a, err := svc.CreateAgent(name, description, prompt, variablesArg(req))
 		if err != nil {
 			return toolError(err)
 		}
@@ -438,7 +884,7 @@ func toolUpdateAgent(svc *blueprint.Service) func(context.Context, mcp.CallToolR
 		name := req.GetString("name", "")
 		description := req.GetString("description", "")
 		prompt := req.GetString("prompt", "")
-		a, err := svc.UpdateAgent(agentID, name, description, prompt)
+		a, err := svc.UpdateAgent(agentID, name, description, prompt, variablesArg(req))
 		if err != nil {
 			return toolError(err)
 		}
@@ -446,6 +892,73 @@ func toolUpdateAgent(svc *blueprint.Service) func(context.Context, mcp.CallToolR
 	}
 }
 
+const (
+	promptParamDescription    = "Agent prompt as a Go text/template; may use .Agent, .Project, .Zone, .Task and .Vars.<name> for declared variables"
+	variablesParamDescription = "Variables the prompt template may reference as .Vars.<name>"
+)
+
+var variableSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"name":        map[string]any{"type": "string"},
+		"description": map[string]any{"type": "string"},
+		"required":    map[string]any{"type": "boolean"},
+		"default":     map[string]any{"type": "string"},
+	},
+	"required": []string{"name"},
+}
+
+// variablesArg reads the declared prompt variables from the variables argument.
+func variablesArg(req mcp.CallToolRequest) []domain.PromptVariable {
+	slice, _ := req.GetArguments()["variables"].([]any)
+	var out []domain.PromptVariable
+	for _, v := range slice {
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		pv := domain.PromptVariable{}
+		pv.Name, _ = m["name"].(string)
+		pv.Description, _ = m["description"].(string)
+		pv.Required, _ = m["required"].(bool)
+		pv.Default, _ = m["default"].(string)
+		out = append(out, pv)
+	}
+	return out
+}
+
+func toolRenderAgentPrompt(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		agentID, err := req.RequireString("agent_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		vars, err := variableValuesArg(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		p, err := svc.RenderAgentPrompt(agentID, req.GetString("zone_id", ""), req.GetString("task", ""), vars)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(RenderAgentPromptOut{Text: p.Text})
+	}
+}
+
+// variableValuesArg reads the values of the agent's declared variables from the variables argument.
+func variableValuesArg(req mcp.CallToolRequest) (map[string]string, error) {
+	raw, _ := req.GetArguments()["variables"].(map[string]any)
+	vars := make(map[string]string, len(raw))
+	for k, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("variable " + k + " must be a string")
+		}
+		vars[k] = s
+	}
+	return vars, nil
+}
+
 func toolDeleteAgent(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		agentID, err := req.RequireString("agent_id")
@@ -459,18 +972,864 @@ func toolDeleteAgent(svc *blueprint.Service) func(context.Context, mcp.CallToolR
 	}
 }
 
-func jsonResult(v any) (*mcp.CallToolResult, error) {
-	b, err := json.Marshal(v)
-	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+func toolExportDiagram(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		opts := blueprint.DiagramOptions{
+			Format:      req.GetString("format", blueprint.DiagramMermaid),
+			GroupNested: req.GetBool("group_nested", false),
+			ZoneIDs:     req.GetStringSlice("zone_ids", nil),
+		}
+		diagram, err := svc.ExportDiagram(projectID, opts)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ExportDiagramOut{Format: opts.Format, Diagram: diagram})
 	}
-	return mcp.NewToolResultText(string(b)), nil
 }
 
-func toolError(err error) (*mcp.CallToolResult, error) {
-	var se *domain.StructuredError
-	if errors.As(err, &se) {
-		return mcp.NewToolResultError(se.Message), nil
+func toolExportBlueprint(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		format := req.GetString("format", blueprint.FormatYAML)
+		doc, err := svc.ExportBlueprint(projectID)
+		if err != nil {
+			return toolError(err)
+		}
+		b, err := blueprint.MarshalDocument(doc, format)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ExportBlueprintOut{Format: format, Document: string(b)})
+	}
+}
+
+func toolImportBlueprint(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := checkEndpointProject(ctx, req.GetString("project_id", "")); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		content, err := req.RequireString("document")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		doc, err := blueprint.UnmarshalDocument([]byte(content), req.GetString("format", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		res, err := svc.ImportBlueprint(doc, blueprint.ImportOptions{
+			ProjectID: req.GetString("project_id", ""),
+			RootDir:   req.GetString("root_dir", ""),
+			Mode:      req.GetString("mode", blueprint.ImportUpdate),
+			DryRun:    req.GetBool("dry_run", false),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(res)
+	}
+}
+
+func toolBindBlueprintFile(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		res, err := svc.BindBlueprintFile(projectID, req.GetString("file", ""), req.GetString("initial", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(res)
+	}
+}
+
+func toolUnbindBlueprintFile(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		p, err := svc.UnbindBlueprintFile(projectID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(UnbindBlueprintFileOut{Project: ProjectToDTO(p)})
+	}
+}
+
+func toolSyncBlueprint(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		res, err := svc.SyncBlueprint(projectID, req.GetString("resolve", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(res)
+	}
+}
+
+func toolReadFile(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := req.RequireString("path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		agentID, err := svc.ActingAgent(ctx, req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		res, err := svc.ReadFile(projectID, path, blueprint.ReadOptions{
+			AgentID:   agentID,
+			StartLine: req.GetInt("start_line", 0),
+			EndLine:   req.GetInt("end_line", 0),
+			MaxBytes:  req.GetInt("max_bytes", 0),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(res)
+	}
+}
+
+func toolReadZoneFiles(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zoneID, err := req.RequireString("zone_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		agentID, err := svc.ActingAgent(ctx, req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		res, err := svc.ReadZoneFiles(zoneID, blueprint.ReadOptions{
+			AgentID:  agentID,
+			MaxBytes: req.GetInt("max_bytes", 0),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(res)
+	}
+}
+
+func toolBuildContext(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := sessionProject(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		agentID, err := svc.ActingAgent(ctx, req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		res, err := svc.BuildContext(projectID, blueprint.ContextOptions{
+			ZoneID:    req.GetString("zone_id", ""),
+			Paths:     req.GetStringSlice("paths", nil),
+			MaxTokens: req.GetInt("max_tokens", 0),
+			AgentID:   agentID,
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(res)
+	}
+}
+
+func toolWriteFile(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		path, err := req.RequireString("path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		agentID, err := svc.ActingAgent(ctx, req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		c, err := svc.WriteFile(projectID, agentID, path, req.GetString("content", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(WriteFileOut{Change: FileChangesToDTO([]*domain.FileChange{c})[0]})
+	}
+}
+
+func toolApplyPatch(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		patch, err := req.RequireString("patch")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		agentID, err := svc.ActingAgent(ctx, req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		changes, err := svc.ApplyPatch(projectID, agentID, patch)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ApplyPatchOut{Changes: FileChangesToDTO(changes)})
+	}
+}
+
+func toolListChanges(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		changes, err := svc.ListChanges(projectID, req.GetInt("limit", 0))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ListChangesOut{Changes: FileChangesToDTO(changes)})
+	}
+}
+
+func toolWhoAmI(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		out := WhoAmIOut{}
+		if a := svc.Caller(ctx); a != nil {
+			out.Agent = AgentToDTO(a)
+		}
+		return jsonResult(out)
+	}
+}
+
+func toolIssueAgentToken(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		agentID, err := req.RequireString("agent_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if _, err := svc.ActingAgent(ctx, agentID); err != nil {
+			return toolError(err)
+		}
+		token, err := svc.IssueAgentToken(agentID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(IssueAgentTokenOut{AgentID: agentID, Token: token})
+	}
+}
+
+func toolRevokeAgentToken(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		agentID, err := req.RequireString("agent_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if _, err := svc.ActingAgent(ctx, agentID); err != nil {
+			return toolError(err)
+		}
+		if err := svc.RevokeAgentToken(agentID); err != nil {
+			return toolError(err)
+		}
+		return jsonResult(struct{}{})
+	}
+}
+
+func toolCreateTask(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		title, err := req.RequireString("title")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		t, err := svc.CreateTask(ctx, projectID, title, req.GetString("description", ""), req.GetStringSlice("zone_ids", nil), req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(TaskOut{Task: TaskToDTO(t)})
+	}
+}
+
+func toolListTasks(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		tasks, err := svc.ListTasks(projectID, blueprint.TaskFilter{
+			Status:  req.GetString("status", ""),
+			AgentID: req.GetString("agent_id", ""),
+			ZoneID:  req.GetString("zone_id", ""),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ListTasksOut{Tasks: TasksToDTO(tasks)})
+	}
+}
+
+func toolGetTask(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		taskID, err := req.RequireString("task_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		t, err := svc.GetTask(taskID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(TaskOut{Task: TaskToDTO(t)})
+	}
+}
+
+func toolClaimTask(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		taskID, err := req.RequireString("task_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		t, err := svc.ClaimTask(ctx, taskID, req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(TaskOut{Task: TaskToDTO(t)})
+	}
+}
+
+func toolUpdateTaskStatus(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		taskID, err := req.RequireString("task_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		status, err := req.RequireString("status")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		t, err := svc.UpdateTaskStatus(ctx, taskID, status)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(TaskOut{Task: TaskToDTO(t)})
+	}
+}
+
+func toolCompleteTask(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		taskID, err := req.RequireString("task_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		t, err := svc.CompleteTask(ctx, taskID, req.GetString("result", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(TaskOut{Task: TaskToDTO(t)})
+	}
+}
+
+func toolRouteTask(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		files := req.GetStringSlice("files", nil)
+		var route *blueprint.TaskRoute
+		var err error
+		if taskID := req.GetString("task_id", ""); taskID != "" {
+			route, err = svc.RouteExistingTask(taskID, files)
+		} else {
+			projectID, perr := projectArg(ctx, req)
+			if perr != nil {
+				return mcp.NewToolResultError(perr.Error()), nil
+			}
+			title := req.GetString("title", "")
+			route, err = svc.RouteTask(projectID, title, title+"\n"+req.GetString("description", ""), files)
+		}
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(TaskRouteToDTO(route))
+	}
+}
+
+func toolClaimZone(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zoneID, err := req.RequireString("zone_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		l, err := svc.ClaimZone(ctx, zoneID, req.GetString("agent_id", ""), time.Duration(req.GetInt("ttl_seconds", 0))*time.Second)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(LeaseOut{Lease: LeaseToDTO(l)})
+	}
+}
+
+func toolRenewLease(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zoneID, err := req.RequireString("zone_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		l, err := svc.RenewLease(ctx, zoneID, req.GetString("agent_id", ""), time.Duration(req.GetInt("ttl_seconds", 0))*time.Second)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(LeaseOut{Lease: LeaseToDTO(l)})
+	}
+}
+
+func toolReleaseZone(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zoneID, err := req.RequireString("zone_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := svc.ReleaseZone(ctx, zoneID, req.GetString("agent_id", "")); err != nil {
+			return toolError(err)
+		}
+		return jsonResult(map[string]string{"released": zoneID})
+	}
+}
+
+func toolListLeases(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		leases, err := svc.ListLeases(projectID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ListLeasesOut{Leases: LeasesToDTO(leases)})
+	}
+}
+
+func toolCreateDecision(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		title, err := req.RequireString("title")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		d, err := svc.CreateDecision(ctx, projectID, blueprint.DecisionDraft{
+			Title:        title,
+			Context:      req.GetString("context", ""),
+			Decision:     req.GetString("decision", ""),
+			Consequences: req.GetString("consequences", ""),
+			Status:       req.GetString("status", ""),
+			ZoneIDs:      req.GetStringSlice("zone_ids", nil),
+			Supersedes:   req.GetString("supersedes", ""),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(DecisionOut{Decision: DecisionToDTO(d)})
+	}
+}
+
+func toolSetDecisionStatus(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		decisionID, err := req.RequireString("decision_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		status, err := req.RequireString("status")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		d, err := svc.SetDecisionStatus(decisionID, status)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(DecisionOut{Decision: DecisionToDTO(d)})
+	}
+}
+
+func toolGetDecision(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		decisionID, err := req.RequireString("decision_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		d, err := svc.GetDecision(decisionID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(DecisionOut{Decision: DecisionToDTO(d)})
+	}
+}
+
+func toolListDecisions(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		decisions, err := svc.ListDecisions(projectID, blueprint.DecisionFilter{
+			ZoneID: req.GetString("zone_id", ""),
+			Status: req.GetString("status", ""),
+			Query:  req.GetString("query", ""),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ListDecisionsOut{Decisions: DecisionsToDTO(decisions)})
+	}
+}
+
+func toolExportDecisions(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		paths, err := svc.ExportDecisions(ctx, projectID, req.GetString("agent_id", ""), req.GetString("dir", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ExportDecisionsOut{Paths: paths})
+	}
+}
+
+func toolAddZoneNote(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zoneID, err := req.RequireString("zone_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		text, err := req.RequireString("text")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		n, err := svc.AddZoneNote(ctx, zoneID, req.GetString("agent_id", ""), blueprint.NoteDraft{
+			Text:   text,
+			Tags:   req.GetStringSlice("tags", nil),
+			Pinned: req.GetBool("pinned", false),
+			TTL:    time.Duration(req.GetInt("ttl_days", 0)) * 24 * time.Hour,
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(NoteOut{Note: NoteToDTO(n)})
+	}
+}
+
+func toolListZoneNotes(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		zoneID, err := req.RequireString("zone_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		notes, err := svc.ListZoneNotes(zoneID, req.GetString("tag", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(NotesOut{Notes: NotesToDTO(notes)})
+	}
+}
+
+func toolSearchZoneNotes(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		notes, err := svc.SearchZoneNotes(projectID, blueprint.NoteQuery{
+			ZoneID: req.GetString("zone_id", ""),
+			Query:  req.GetString("query", ""),
+			Tags:   req.GetStringSlice("tags", nil),
+			Limit:  req.GetInt("limit", 0),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(NotesOut{Notes: NotesToDTO(notes)})
+	}
+}
+
+func toolDeleteZoneNote(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		noteID, err := req.RequireString("note_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := svc.DeleteZoneNote(ctx, noteID); err != nil {
+			return toolError(err)
+		}
+		return jsonResult(map[string]string{"deleted": noteID})
+	}
+}
+
+func toolSendMessage(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		body, err := req.RequireString("body")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		projectID, err := sessionProject(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		messages, err := svc.SendMessage(ctx, req.GetString("agent_id", ""), blueprint.MessageDraft{
+			ProjectID: projectID,
+			ToAgentID: req.GetString("to_agent_id", ""),
+			ZoneID:    req.GetString("zone_id", ""),
+			ReplyTo:   req.GetString("reply_to", ""),
+			Subject:   req.GetString("subject", ""),
+			Body:      body,
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(MessagesOut{Messages: MessagesToDTO(messages)})
+	}
+}
+
+func toolListInbox(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := checkEndpointProject(ctx, req.GetString("project_id", "")); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		messages, err := svc.ListInbox(ctx, req.GetString("agent_id", ""), req.GetString("project_id", ""), req.GetBool("unread_only", false))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(MessagesOut{Messages: MessagesToDTO(messages)})
+	}
+}
+
+func toolAcknowledgeMessage(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		messageID, err := req.RequireString("message_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		m, err := svc.AcknowledgeMessage(ctx, messageID, req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(MessageOut{Message: MessageToDTO(m)})
+	}
+}
+
+func toolGetThread(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		messageID, err := req.RequireString("message_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		messages, err := svc.MessageThread(ctx, messageID, req.GetString("agent_id", ""))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(MessagesOut{Messages: MessagesToDTO(messages)})
+	}
+}
+
+var runTaskSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"task_id":    map[string]any{"type": "string"},
+		"depends_on": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+	},
+	"required": []string{"task_id"},
+}
+
+// runTasksArg reads the tasks of a run from the tasks argument.
+func runTasksArg(req mcp.CallToolRequest) []domain.RunTask {
+	slice, _ := req.GetArguments()["tasks"].([]any)
+	var out []domain.RunTask
+	for _, v := range slice {
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		rt := domain.RunTask{}
+		rt.TaskID, _ = m["task_id"].(string)
+		deps, _ := m["depends_on"].([]any)
+		for _, d := range deps {
+			if id, ok := d.(string); ok {
+				rt.DependsOn = append(rt.DependsOn, id)
+			}
+		}
+		out = append(out, rt)
+	}
+	return out
+}
+
+func toolCreateRun(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		title, err := req.RequireString("title")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		run, err := svc.CreateRun(ctx, projectID, title, req.GetString("description", ""), runTasksArg(req))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(RunOut{Run: RunToDTO(run, svc.RunProgress(run))})
+	}
+}
+
+func toolSetRunTask(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		runID, err := req.RequireString("run_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		taskID, err := req.RequireString("task_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		run, err := svc.SetRunTask(runID, taskID, req.GetStringSlice("depends_on", nil))
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(RunOut{Run: RunToDTO(run, svc.RunProgress(run))})
+	}
+}
+
+func toolGetRun(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		runID, err := req.RequireString("run_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		run, err := svc.GetRun(runID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(RunOut{Run: RunToDTO(run, svc.RunProgress(run))})
+	}
+}
+
+func toolListRuns(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := projectArg(ctx, req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		runs, err := svc.ListRuns(projectID)
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(ListRunsOut{Runs: RunsToDTO(svc, runs)})
+	}
+}
+
+func toolDeleteRun(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		runID, err := req.RequireString("run_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := svc.DeleteRun(runID); err != nil {
+			return toolError(err)
+		}
+		return jsonResult(map[string]string{"deleted": runID})
+	}
+}
+
+// sessionProject returns the project_id argument or, when it is omitted, the session's active project.
+// On a project endpoint, a project_id other than the endpoint's is refused.
+func sessionProject(ctx context.Context, req mcp.CallToolRequest) (string, error) {
+	id := req.GetString("project_id", "")
+	if err := checkEndpointProject(ctx, id); err != nil {
+		return "", err
+	}
+	if id != "" {
+		return id, nil
+	}
+	return blueprint.ActiveProject(ctx), nil
+}
+
+// projectArg is sessionProject for tools that need a project.
+func projectArg(ctx context.Context, req mcp.CallToolRequest) (string, error) {
+	id, err := sessionProject(ctx, req)
+	if err != nil || id != "" {
+		return id, err
+	}
+	return "", errors.New("project_id is required (or bind one to the session with set_active_project)")
+}
+
+// rootArg returns the root argument, which is refused on a project endpoint: paths there are
+// always under the endpoint's project.
+func rootArg(ctx context.Context, req mcp.CallToolRequest) (string, error) {
+	root := req.GetString("root", "")
+	if root != "" && endpointProject(ctx) != "" {
+		return "", errors.New("root is not accepted on a project endpoint")
+	}
+	return root, nil
+}
+
+func jsonResult(v any) (*mcp.CallToolResult, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultText(string(b)), nil
+}
+
+// toolError reports err as a tool error. OUT_OF_ZONE errors are returned as JSON so clients
+// can see every refused path and its owning zones.
+func toolError(err error) (*mcp.CallToolResult, error) {
+	var oz *domain.OutOfZoneError
+	if errors.As(err, &oz) {
+		b, _ := json.Marshal(OutOfZoneToDTO(oz))
+		return mcp.NewToolResultError(string(b)), nil
+	}
+	var se *domain.StructuredError
+	if errors.As(err, &se) {
+		return mcp.NewToolResultError(se.Message), nil
+	}
+	return mcp.NewToolResultError(err.Error()), nil
+}
+
+func toolGetBriefing(svc *blueprint.Service) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := checkEndpointProject(ctx, req.GetString("project_id", "")); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		vars, err := variableValuesArg(req)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		b, err := svc.GetBriefing(ctx, req.GetString("agent_id", ""), req.GetString("zone_id", ""), blueprint.BriefingOptions{
+			ProjectID: req.GetString("project_id", ""),
+			Task:      req.GetString("task", ""),
+			Variables: vars,
+			MaxChars:  req.GetInt("max_chars", 0),
+			MaxTokens: req.GetInt("max_tokens", 0),
+		})
+		if err != nil {
+			return toolError(err)
+		}
+		return jsonResult(BriefingToDTO(b))
 	}
-	return mcp.NewToolResultError(err.Error()), nil
 }
@@ -0,0 +1,123 @@
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"operators-mcp/internal/adapter/out/atomicfile"
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure Files implements ports.FileStore at compile time.
+var _ ports.FileStore = (*Files)(nil)
+
+// Files implements FileStore using the OS filesystem.
+type Files struct{}
+
+// NewFiles returns a new filesystem file store.
+func NewFiles() *Files {
+	return &Files{}
+}
+
+// FileSize returns the size in bytes of the regular file at path under root.
+func (f *Files) FileSize(root, path string) (int64, error) {
+	full, err := resolveUnder(root, path)
+	if err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(full)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, &domain.StructuredError{Code: "FILE_NOT_FOUND", Message: "file not found: " + path}
+		}
+		return 0, &domain.StructuredError{Code: "FILE_UNREADABLE", Message: err.Error()}
+	}
+	if !info.Mode().IsRegular() {
+		return 0, &domain.StructuredError{Code: "NOT_A_FILE", Message: "not a regular file: " + path}
+	}
+	return info.Size(), nil
+}
+
+// ReadFile returns the content of path under root.
+func (f *Files) ReadFile(root, path string) ([]byte, error) {
+	full, err := resolveUnder(root, path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(full)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &domain.StructuredError{Code: "FILE_NOT_FOUND", Message: "file not found: " + path}
+		}
+		return nil, &domain.StructuredError{Code: "FILE_UNREADABLE", Message: err.Error()}
+	}
+	return data, nil
+}
+
+// WriteFile writes data to path under root, creating parent directories. The content is
+// written to a temporary file in the same directory and renamed into place.
+func (f *Files) WriteFile(root, path string, data []byte) error {
+	full, err := resolveUnder(root, path)
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(full, data); err != nil {
+		return &domain.StructuredError{Code: "FILE_UNWRITABLE", Message: err.Error()}
+	}
+	return nil
+}
+
+// RemoveFile deletes the regular file at path under root.
+func (f *Files) RemoveFile(root, path string) error {
+	if _, err := f.FileSize(root, path); err != nil {
+		return err
+	}
+	full, err := resolveUnder(root, path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(full); err != nil {
+		return &domain.StructuredError{Code: "FILE_UNWRITABLE", Message: err.Error()}
+	}
+	return nil
+}
+
+// resolveUnder joins root and the project-relative path, rejecting paths that leave root,
+// either lexically or because an existing part of the path is a symlink pointing outside it.
+func resolveUnder(root, path string) (string, error) {
+	rel := domain.NormalizePath(path)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", &domain.StructuredError{Code: "INVALID_PATH", Message: "path must be inside the project root: " + path}
+	}
+	full := filepath.Join(root, filepath.FromSlash(rel))
+	if !resolvesUnder(root, full) {
+		return "", &domain.StructuredError{Code: "INVALID_PATH", Message: "path resolves outside the project root: " + path}
+	}
+	return full, nil
+}
+
+// resolvesUnder reports whether the longest existing prefix of full, with symlinks evaluated,
+// is still inside root (also with symlinks evaluated).
+func resolvesUnder(root, full string) bool {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return true // a missing root fails later with a clearer error
+	}
+	existing := full
+	for {
+		real, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			rel, err := filepath.Rel(realRoot, real)
+			return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return true
+		}
+		existing = parent
+	}
+}
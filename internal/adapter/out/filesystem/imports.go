@@ -0,0 +1,105 @@
+package filesystem
+
+import (
+	"bufio"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure ImportAnalyzer implements ports.DependencyAnalyzer at compile time.
+var _ ports.DependencyAnalyzer = (*ImportAnalyzer)(nil)
+
+// ImportAnalyzer implements DependencyAnalyzer for Go modules by reading import declarations.
+type ImportAnalyzer struct{}
+
+// NewImportAnalyzer returns a new Go import analyzer.
+func NewImportAnalyzer() *ImportAnalyzer {
+	return &ImportAnalyzer{}
+}
+
+// ListImports reads go.mod under root and returns, for every .go file, the project-relative
+// directories of the module-local packages it imports. Roots without go.mod yield an empty map.
+// Hidden directories, vendor and node_modules are skipped; files that fail to parse are ignored.
+func (a *ImportAnalyzer) ListImports(root string) (map[string][]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, &domain.StructuredError{Code: "ROOT_UNREADABLE", Message: err.Error()}
+	}
+	if !info.IsDir() {
+		return nil, &domain.StructuredError{Code: "ROOT_UNREADABLE", Message: "root is not a directory"}
+	}
+	out := make(map[string][]string)
+	module := readModulePath(filepath.Join(root, "go.mod"))
+	if module == "" {
+		return out, nil
+	}
+	fset := token.NewFileSet()
+	err = filepath.WalkDir(root, func(p string, d os.DirEntry, errWalk error) error {
+		if errWalk != nil {
+			return errWalk
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, p, nil, parser.ImportsOnly)
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool)
+		var deps []string
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil || !strings.HasPrefix(path, module+"/") {
+				continue
+			}
+			dir := strings.TrimPrefix(path, module+"/")
+			if !seen[dir] {
+				seen[dir] = true
+				deps = append(deps, dir)
+			}
+		}
+		sort.Strings(deps)
+		out[filepath.ToSlash(rel)] = deps
+		return nil
+	})
+	if err != nil {
+		return nil, &domain.StructuredError{Code: "ROOT_UNREADABLE", Message: err.Error()}
+	}
+	return out, nil
+}
+
+// readModulePath returns the module path declared in the go.mod at path, or "" if none.
+func readModulePath(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package file
+
+import (
+	"slices"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure AgentRepository implements ports.AgentRepository at compile time.
+var _ ports.AgentRepository = (*AgentRepository)(nil)
+
+// AgentRepository persists agents as JSON files.
+type AgentRepository struct {
+	dir *Dir
+}
+
+// NewAgentRepository returns a new agent repository.
+func NewAgentRepository(dir *Dir) *AgentRepository {
+	return &AgentRepository{dir: dir}
+}
+
+// Get returns the agent by id, or nil if not found.
+func (r *AgentRepository) Get(id string) *domain.Agent {
+	var rec agentRecord
+	var found bool
+	err := r.dir.read(func() (err error) {
+		found, err = r.dir.get(kindAgents, id, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// List returns all agents.
+func (r *AgentRepository) List() []*domain.Agent {
+	var recs []*agentRecord
+	err := r.dir.read(func() (err error) {
+		recs, err = list[agentRecord](r.dir, kindAgents)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	out := make([]*domain.Agent, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, rec.toDomain())
+	}
+	return out
+}
+
+// Create creates an agent with generated id. Name, description, and prompt can be empty.
+func (r *AgentRepository) Create(name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := &agentRecord{ID: id, Name: name, Description: description, Prompt: prompt, Variables: variableRecords(variables)}
+	if err := r.dir.write(func() error { return r.dir.put(kindAgents, id, rec) }); err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Update updates an agent by id.
+func (r *AgentRepository) Update(id, name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error) {
+	var rec agentRecord
+	err := r.dir.write(func() error {
+		found, err := r.dir.get(kindAgents, id, &rec)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+		}
+		rec.Name = name
+		rec.Description = description
+		rec.Prompt = prompt
+		rec.Variables = variableRecords(variables)
+		return r.dir.put(kindAgents, id, &rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Delete removes an agent by id and drops it from every zone that references it, under one lock.
+func (r *AgentRepository) Delete(id string) error {
+	return r.dir.write(func() error {
+		var rec agentRecord
+		found, err := r.dir.get(kindAgents, id, &rec)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+		}
+		zones, err := list[zoneRecord](r.dir, kindZones)
+		if err != nil {
+			return err
+		}
+		for _, z := range zones {
+			if !slices.Contains(z.AgentIDs, id) {
+				continue
+			}
+			z.AgentIDs = slices.DeleteFunc(z.AgentIDs, func(a string) bool { return a == id })
+			if err := r.dir.put(kindZones, z.ID, z); err != nil {
+				return err
+			}
+		}
+		_, err = r.dir.remove(kindAgents, id)
+		return err
+	})
+}
@@ -0,0 +1,62 @@
+package file
+
+import (
+	"time"
+
+	"operators-mcp/internal/application/ports"
+)
+
+// Ensure AgentTokens implements ports.AgentTokenStore at compile time.
+var _ ports.AgentTokenStore = (*AgentTokens)(nil)
+
+// AgentTokens persists agent token hashes as JSON files, one per agent.
+type AgentTokens struct {
+	dir *Dir
+}
+
+// NewAgentTokens returns a new token store.
+func NewAgentTokens(dir *Dir) *AgentTokens {
+	return &AgentTokens{dir: dir}
+}
+
+// Set stores tokenHash as the agent's token, replacing any previous one.
+func (t *AgentTokens) Set(agentID, tokenHash string) error {
+	rec := &tokenRecord{AgentID: agentID, TokenHash: tokenHash, CreatedAt: time.Now().UTC()}
+	return t.dir.write(func() error { return t.dir.put(kindTokens, agentID, rec) })
+}
+
+// AgentID returns the agent holding tokenHash, or "".
+func (t *AgentTokens) AgentID(tokenHash string) string {
+	var recs []*tokenRecord
+	err := t.dir.read(func() (err error) {
+		recs, err = list[tokenRecord](t.dir, kindTokens)
+		return err
+	})
+	if err != nil {
+		return ""
+	}
+	for _, rec := range recs {
+		if rec.TokenHash == tokenHash {
+			return rec.AgentID
+		}
+	}
+	return ""
+}
+
+// Delete removes the agent's token.
+func (t *AgentTokens) Delete(agentID string) error {
+	return t.dir.write(func() error {
+		_, err := t.dir.remove(kindTokens, agentID)
+		return err
+	})
+}
+
+// Any reports whether any agent holds a token.
+func (t *AgentTokens) Any() bool {
+	var recs []*tokenRecord
+	err := t.dir.read(func() (err error) {
+		recs, err = list[tokenRecord](t.dir, kindTokens)
+		return err
+	})
+	return err == nil && len(recs) > 0
+}
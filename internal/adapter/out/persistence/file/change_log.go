@@ -0,0 +1,81 @@
+package file
+
+import (
+	"sort"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure ChangeLog implements ports.ChangeLog at compile time.
+var _ ports.ChangeLog = (*ChangeLog)(nil)
+
+// ChangeLog persists recorded file changes as JSON files, one per change.
+type ChangeLog struct {
+	dir *Dir
+}
+
+// NewChangeLog returns a new change log.
+func NewChangeLog(dir *Dir) *ChangeLog {
+	return &ChangeLog{dir: dir}
+}
+
+// Record stores c with a generated id.
+func (l *ChangeLog) Record(c *domain.FileChange) (*domain.FileChange, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := changeRecord{
+		ID:           id,
+		ProjectID:    c.ProjectID,
+		AgentID:      c.AgentID,
+		Path:         c.Path,
+		Operation:    c.Operation,
+		Source:       c.Source,
+		OldHash:      c.OldHash,
+		NewHash:      c.NewHash,
+		LinesAdded:   c.LinesAdded,
+		LinesRemoved: c.LinesRemoved,
+		CreatedAt:    c.CreatedAt.UTC(),
+	}
+	err = l.dir.write(func() error {
+		existing, err := list[changeRecord](l.dir, kindChanges)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			rec.Seq = max(rec.Seq, e.Seq)
+		}
+		rec.Seq++
+		return l.dir.put(kindChanges, id, &rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// List returns the project's changes newest first, at most limit (0 means all).
+func (l *ChangeLog) List(projectID string, limit int) []*domain.FileChange {
+	var recs []*changeRecord
+	err := l.dir.read(func() (err error) {
+		recs, err = list[changeRecord](l.dir, kindChanges)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Seq > recs[j].Seq })
+	var out []*domain.FileChange
+	for _, rec := range recs {
+		if rec.ProjectID != projectID {
+			continue
+		}
+		out = append(out, rec.toDomain())
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package file
+
+import (
+	"sort"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure DecisionRepository implements ports.DecisionRepository at compile time.
+var _ ports.DecisionRepository = (*DecisionRepository)(nil)
+
+// DecisionRepository persists decision records as JSON files, one per decision.
+type DecisionRepository struct {
+	dir *Dir
+}
+
+// NewDecisionRepository returns a new decision repository.
+func NewDecisionRepository(dir *Dir) *DecisionRepository {
+	return &DecisionRepository{dir: dir}
+}
+
+// Get returns the decision by id, or nil if not found.
+func (r *DecisionRepository) Get(id string) *domain.Decision {
+	var rec decisionRecord
+	var found bool
+	err := r.dir.read(func() (err error) {
+		found, err = r.dir.get(kindDecisions, id, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// List returns the project's decisions oldest first.
+func (r *DecisionRepository) List(projectID string) []*domain.Decision {
+	var recs []*decisionRecord
+	err := r.dir.read(func() (err error) {
+		recs, err = list[decisionRecord](r.dir, kindDecisions)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Seq < recs[j].Seq })
+	var out []*domain.Decision
+	for _, rec := range recs {
+		if rec.ProjectID == projectID {
+			out = append(out, rec.toDomain())
+		}
+	}
+	return out
+}
+
+// Create stores d with a generated id.
+func (r *DecisionRepository) Create(d *domain.Decision) (*domain.Decision, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := newDecisionRecord(d)
+	rec.ID = id
+	err = r.dir.write(func() error {
+		existing, err := list[decisionRecord](r.dir, kindDecisions)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			rec.Seq = max(rec.Seq, e.Seq)
+		}
+		rec.Seq++
+		return r.dir.put(kindDecisions, id, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Update replaces every field of an existing decision.
+func (r *DecisionRepository) Update(d *domain.Decision) (*domain.Decision, error) {
+	rec := newDecisionRecord(d)
+	err := r.dir.write(func() error {
+		var old decisionRecord
+		found, err := r.dir.get(kindDecisions, d.ID, &old)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "DECISION_NOT_FOUND", Message: "decision not found"}
+		}
+		rec.Seq = old.Seq
+		return r.dir.put(kindDecisions, d.ID, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// DeleteByProject removes all decisions of a project.
+func (r *DecisionRepository) DeleteByProject(projectID string) error {
+	return r.dir.write(func() error {
+		recs, err := list[decisionRecord](r.dir, kindDecisions)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if rec.ProjectID != projectID {
+				continue
+			}
+			if _, err := r.dir.remove(kindDecisions, rec.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,161 @@
+package file
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"operators-mcp/internal/adapter/out/atomicfile"
+)
+
+// Dir is a directory of JSON files, one file per entity under a subdirectory per kind
+// (e.g. projects/<id>.json). Writes are atomic (temporary file + rename) and every operation
+// holds an advisory lock on <dir>/.lock so several processes can share the directory.
+type Dir struct {
+	path string
+	mu   sync.Mutex
+	lock *os.File
+}
+
+// Open opens (creating if needed) a file store rooted at path.
+func Open(path string) (*Dir, error) {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("file store: %w", err)
+	}
+	lock, err := os.OpenFile(filepath.Join(path, ".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("file store lock: %w", err)
+	}
+	return &Dir{path: path, lock: lock}, nil
+}
+
+// Close releases the lock file.
+func (d *Dir) Close() error {
+	return d.lock.Close()
+}
+
+// read runs fn holding a shared lock.
+func (d *Dir) read(fn func() error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := lockFile(d.lock, false); err != nil {
+		return err
+	}
+	defer unlockFile(d.lock)
+	return fn()
+}
+
+// write runs fn holding an exclusive lock.
+func (d *Dir) write(fn func() error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := lockFile(d.lock, true); err != nil {
+		return err
+	}
+	defer unlockFile(d.lock)
+	return fn()
+}
+
+// validID guards against ids that would escape the kind directory.
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (d *Dir) recordPath(kind, id string) (string, bool) {
+	if !validID.MatchString(id) {
+		return "", false
+	}
+	return filepath.Join(d.path, kind, id+".json"), true
+}
+
+// get decodes the record kind/id into v. It returns false if the record does not exist.
+func (d *Dir) get(kind, id string, v any) (bool, error) {
+	p, ok := d.recordPath(kind, id)
+	if !ok {
+		return false, nil
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return false, fmt.Errorf("%s: %w", p, err)
+	}
+	return true, nil
+}
+
+// put atomically writes v as the record kind/id.
+func (d *Dir) put(kind, id string, v any) error {
+	p, ok := d.recordPath(kind, id)
+	if !ok {
+		return fmt.Errorf("invalid %s id %q", kind, id)
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(p, append(b, '\n'))
+}
+
+// remove deletes the record kind/id. It returns false if the record did not exist.
+func (d *Dir) remove(kind, id string) (bool, error) {
+	p, ok := d.recordPath(kind, id)
+	if !ok {
+		return false, nil
+	}
+	if err := os.Remove(p); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// list decodes every record of a kind, ordered by id.
+func list[T any](d *Dir, kind string) ([]*T, error) {
+	entries, err := os.ReadDir(filepath.Join(d.path, kind))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+	sort.Strings(names)
+	out := make([]*T, 0, len(names))
+	for _, id := range names {
+		v := new(T)
+		ok, err := d.get(kind, id, v)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func genID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
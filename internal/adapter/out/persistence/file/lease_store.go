@@ -0,0 +1,75 @@
+package file
+
+import (
+	"sort"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure LeaseStore implements ports.LeaseStore at compile time.
+var _ ports.LeaseStore = (*LeaseStore)(nil)
+
+// LeaseStore persists zone leases as JSON files, one per zone.
+type LeaseStore struct {
+	dir *Dir
+}
+
+// NewLeaseStore returns a new lease store.
+func NewLeaseStore(dir *Dir) *LeaseStore {
+	return &LeaseStore{dir: dir}
+}
+
+// Get returns the zone's lease, or nil if it has none.
+func (s *LeaseStore) Get(zoneID string) *domain.ZoneLease {
+	var rec leaseRecord
+	var found bool
+	err := s.dir.read(func() (err error) {
+		found, err = s.dir.get(kindLeases, zoneID, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// List returns the project's leases ordered by zone id.
+func (s *LeaseStore) List(projectID string) []*domain.ZoneLease {
+	var recs []*leaseRecord
+	err := s.dir.read(func() (err error) {
+		recs, err = list[leaseRecord](s.dir, kindLeases)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].ZoneID < recs[j].ZoneID })
+	var out []*domain.ZoneLease
+	for _, rec := range recs {
+		if rec.ProjectID == projectID {
+			out = append(out, rec.toDomain())
+		}
+	}
+	return out
+}
+
+// Put stores l as its zone's lease, replacing any previous one.
+func (s *LeaseStore) Put(l *domain.ZoneLease) error {
+	rec := &leaseRecord{
+		ZoneID:     l.ZoneID,
+		ProjectID:  l.ProjectID,
+		AgentID:    l.AgentID,
+		AcquiredAt: l.AcquiredAt.UTC(),
+		ExpiresAt:  l.ExpiresAt.UTC(),
+	}
+	return s.dir.write(func() error { return s.dir.put(kindLeases, l.ZoneID, rec) })
+}
+
+// Delete removes the zone's lease, if any.
+func (s *LeaseStore) Delete(zoneID string) error {
+	return s.dir.write(func() error {
+		_, err := s.dir.remove(kindLeases, zoneID)
+		return err
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package file
+
+import "os"
+
+// Advisory locking is only implemented on Unix; elsewhere the in-process mutex is the only guard.
+
+func lockFile(f *os.File, exclusive bool) error { return nil }
+
+func unlockFile(f *os.File) error { return nil }
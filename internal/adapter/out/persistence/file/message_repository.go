@@ -0,0 +1,120 @@
+package file
+
+import (
+	"sort"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure MessageRepository implements ports.MessageRepository at compile time.
+var _ ports.MessageRepository = (*MessageRepository)(nil)
+
+// MessageRepository persists messages as JSON files, one per message.
+type MessageRepository struct {
+	dir *Dir
+}
+
+// NewMessageRepository returns a new message repository.
+func NewMessageRepository(dir *Dir) *MessageRepository {
+	return &MessageRepository{dir: dir}
+}
+
+// Get returns the message by id, or nil if not found.
+func (r *MessageRepository) Get(id string) *domain.Message {
+	var rec messageRecord
+	var found bool
+	err := r.dir.read(func() (err error) {
+		found, err = r.dir.get(kindMessages, id, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// List returns the project's messages oldest first.
+func (r *MessageRepository) List(projectID string) []*domain.Message {
+	var recs []*messageRecord
+	err := r.dir.read(func() (err error) {
+		recs, err = list[messageRecord](r.dir, kindMessages)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Seq < recs[j].Seq })
+	var out []*domain.Message
+	for _, rec := range recs {
+		if rec.ProjectID == projectID {
+			out = append(out, rec.toDomain())
+		}
+	}
+	return out
+}
+
+// Create stores m with a generated id.
+func (r *MessageRepository) Create(m *domain.Message) (*domain.Message, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := newMessageRecord(m)
+	rec.ID = id
+	err = r.dir.write(func() error {
+		existing, err := list[messageRecord](r.dir, kindMessages)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			rec.Seq = max(rec.Seq, e.Seq)
+		}
+		rec.Seq++
+		return r.dir.put(kindMessages, id, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Update replaces every field of an existing message.
+func (r *MessageRepository) Update(m *domain.Message) (*domain.Message, error) {
+	rec := newMessageRecord(m)
+	err := r.dir.write(func() error {
+		var old messageRecord
+		found, err := r.dir.get(kindMessages, m.ID, &old)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "MESSAGE_NOT_FOUND", Message: "message not found"}
+		}
+		rec.Seq = old.Seq
+		return r.dir.put(kindMessages, m.ID, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// DeleteByProject removes all messages of a project.
+func (r *MessageRepository) DeleteByProject(projectID string) error {
+	return r.dir.write(func() error {
+		recs, err := list[messageRecord](r.dir, kindMessages)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if rec.ProjectID != projectID {
+				continue
+			}
+			if _, err := r.dir.remove(kindMessages, rec.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,426 @@
+package file
+
+import (
+	"time"
+
+	"operators-mcp/internal/domain"
+)
+
+// Record kinds (subdirectory names).
+const (
+	kindProjects  = "projects"
+	kindZones     = "zones"
+	kindAgents    = "agents"
+	kindChanges   = "changes"
+	kindTokens    = "tokens"
+	kindTasks     = "tasks"
+	kindLeases    = "leases"
+	kindRuns      = "runs"
+	kindMessages  = "messages"
+	kindDecisions = "decisions"
+	kindNotes     = "notes"
+)
+
+// projectRecord is the on-disk form of domain.Project.
+type projectRecord struct {
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	RootDir            string   `json:"root_dir"`
+	IgnoredPaths       []string `json:"ignored_paths"`
+	BlueprintFile      string   `json:"blueprint_file,omitempty"`
+	BlueprintFileHash  string   `json:"blueprint_file_hash,omitempty"`
+	BlueprintStoreHash string   `json:"blueprint_store_hash,omitempty"`
+}
+
+func (r *projectRecord) toDomain() *domain.Project {
+	paths := append([]string(nil), r.IgnoredPaths...)
+	if paths == nil {
+		paths = []string{}
+	}
+	return &domain.Project{
+		ID:                 r.ID,
+		Name:               r.Name,
+		RootDir:            r.RootDir,
+		IgnoredPaths:       paths,
+		BlueprintFile:      r.BlueprintFile,
+		BlueprintFileHash:  r.BlueprintFileHash,
+		BlueprintStoreHash: r.BlueprintStoreHash,
+	}
+}
+
+// zoneRecord is the on-disk form of domain.Zone.
+type zoneRecord struct {
+	ID            string   `json:"id"`
+	ProjectID     string   `json:"project_id"`
+	Name          string   `json:"name"`
+	Pattern       string   `json:"pattern"`
+	Purpose       string   `json:"purpose"`
+	Constraints   []string `json:"constraints"`
+	AgentIDs      []string `json:"agent_ids"`
+	ExplicitPaths []string `json:"explicit_paths"`
+}
+
+func (r *zoneRecord) toDomain() *domain.Zone {
+	return &domain.Zone{
+		ID:            r.ID,
+		ProjectID:     r.ProjectID,
+		Name:          r.Name,
+		Pattern:       r.Pattern,
+		Purpose:       r.Purpose,
+		Constraints:   append([]string(nil), r.Constraints...),
+		AgentIDs:      append([]string(nil), r.AgentIDs...),
+		ExplicitPaths: append([]string(nil), r.ExplicitPaths...),
+	}
+}
+
+// agentRecord is the on-disk form of domain.Agent.
+type agentRecord struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Prompt      string           `json:"prompt,omitempty"`
+	Variables   []variableRecord `json:"variables,omitempty"`
+}
+
+// variableRecord is the on-disk form of domain.PromptVariable.
+type variableRecord struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Default     string `json:"default,omitempty"`
+}
+
+func (r *agentRecord) toDomain() *domain.Agent {
+	a := &domain.Agent{ID: r.ID, Name: r.Name, Description: r.Description, Prompt: r.Prompt}
+	for _, v := range r.Variables {
+		a.Variables = append(a.Variables, domain.PromptVariable{Name: v.Name, Description: v.Description, Required: v.Required, Default: v.Default})
+	}
+	return a
+}
+
+func variableRecords(vars []domain.PromptVariable) []variableRecord {
+	var out []variableRecord
+	for _, v := range vars {
+		out = append(out, variableRecord{Name: v.Name, Description: v.Description, Required: v.Required, Default: v.Default})
+	}
+	return out
+}
+
+// changeRecord is the on-disk form of domain.FileChange. Seq orders changes recorded within
+// the same clock tick, since ids are random.
+type changeRecord struct {
+	Seq          int64     `json:"seq"`
+	ID           string    `json:"id"`
+	ProjectID    string    `json:"project_id"`
+	AgentID      string    `json:"agent_id,omitempty"`
+	Path         string    `json:"path"`
+	Operation    string    `json:"operation"`
+	Source       string    `json:"source,omitempty"`
+	OldHash      string    `json:"old_hash,omitempty"`
+	NewHash      string    `json:"new_hash,omitempty"`
+	LinesAdded   int       `json:"lines_added"`
+	LinesRemoved int       `json:"lines_removed"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (r *changeRecord) toDomain() *domain.FileChange {
+	return &domain.FileChange{
+		ID:           r.ID,
+		ProjectID:    r.ProjectID,
+		AgentID:      r.AgentID,
+		Path:         r.Path,
+		Operation:    r.Operation,
+		Source:       r.Source,
+		OldHash:      r.OldHash,
+		NewHash:      r.NewHash,
+		LinesAdded:   r.LinesAdded,
+		LinesRemoved: r.LinesRemoved,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// tokenRecord is the on-disk form of an agent's token hash, stored under the agent id.
+type tokenRecord struct {
+	AgentID   string    `json:"agent_id"`
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// taskRecord is the on-disk form of domain.Task. Seq keeps creation order, since ids are random.
+type taskRecord struct {
+	Seq         int64     `json:"seq"`
+	ID          string    `json:"id"`
+	ProjectID   string    `json:"project_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Status      string    `json:"status"`
+	ZoneIDs     []string  `json:"zone_ids,omitempty"`
+	AgentID     string    `json:"agent_id,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	Result      string    `json:"result,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func newTaskRecord(t *domain.Task) *taskRecord {
+	return &taskRecord{
+		ID:          t.ID,
+		ProjectID:   t.ProjectID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		ZoneIDs:     append([]string(nil), t.ZoneIDs...),
+		AgentID:     t.AgentID,
+		CreatedBy:   t.CreatedBy,
+		Result:      t.Result,
+		CreatedAt:   t.CreatedAt.UTC(),
+		UpdatedAt:   t.UpdatedAt.UTC(),
+	}
+}
+
+func (r *taskRecord) toDomain() *domain.Task {
+	return &domain.Task{
+		ID:          r.ID,
+		ProjectID:   r.ProjectID,
+		Title:       r.Title,
+		Description: r.Description,
+		Status:      r.Status,
+		ZoneIDs:     append([]string{}, r.ZoneIDs...),
+		AgentID:     r.AgentID,
+		CreatedBy:   r.CreatedBy,
+		Result:      r.Result,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// leaseRecord is the on-disk form of domain.ZoneLease, stored under the zone id.
+type leaseRecord struct {
+	ZoneID     string    `json:"zone_id"`
+	ProjectID  string    `json:"project_id"`
+	AgentID    string    `json:"agent_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func (r *leaseRecord) toDomain() *domain.ZoneLease {
+	return &domain.ZoneLease{
+		ZoneID:     r.ZoneID,
+		ProjectID:  r.ProjectID,
+		AgentID:    r.AgentID,
+		AcquiredAt: r.AcquiredAt,
+		ExpiresAt:  r.ExpiresAt,
+	}
+}
+
+// runRecord is the on-disk form of domain.Run. Seq keeps creation order, since ids are random.
+type runRecord struct {
+	Seq         int64           `json:"seq"`
+	ID          string          `json:"id"`
+	ProjectID   string          `json:"project_id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Tasks       []runTaskRecord `json:"tasks"`
+	CreatedBy   string          `json:"created_by,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// runTaskRecord is a task of a run and its prerequisites.
+type runTaskRecord struct {
+	TaskID    string   `json:"task_id"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+func newRunRecord(r *domain.Run) *runRecord {
+	tasks := make([]runTaskRecord, len(r.Tasks))
+	for i, t := range r.Tasks {
+		tasks[i] = runTaskRecord{TaskID: t.TaskID, DependsOn: append([]string(nil), t.DependsOn...)}
+	}
+	return &runRecord{
+		ID:          r.ID,
+		ProjectID:   r.ProjectID,
+		Title:       r.Title,
+		Description: r.Description,
+		Tasks:       tasks,
+		CreatedBy:   r.CreatedBy,
+		CreatedAt:   r.CreatedAt.UTC(),
+		UpdatedAt:   r.UpdatedAt.UTC(),
+	}
+}
+
+func (r *runRecord) toDomain() *domain.Run {
+	tasks := make([]domain.RunTask, len(r.Tasks))
+	for i, t := range r.Tasks {
+		tasks[i] = domain.RunTask{TaskID: t.TaskID, DependsOn: append([]string{}, t.DependsOn...)}
+	}
+	return &domain.Run{
+		ID:          r.ID,
+		ProjectID:   r.ProjectID,
+		Title:       r.Title,
+		Description: r.Description,
+		Tasks:       tasks,
+		CreatedBy:   r.CreatedBy,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// messageRecord is the on-disk form of domain.Message. Seq keeps creation order, since ids are
+// random.
+type messageRecord struct {
+	Seq         int64      `json:"seq"`
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	ThreadID    string     `json:"thread_id,omitempty"`
+	ReplyTo     string     `json:"reply_to,omitempty"`
+	FromAgentID string     `json:"from_agent_id,omitempty"`
+	ToAgentID   string     `json:"to_agent_id"`
+	ZoneID      string     `json:"zone_id,omitempty"`
+	Subject     string     `json:"subject,omitempty"`
+	Body        string     `json:"body"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AckedAt     *time.Time `json:"acked_at,omitempty"`
+}
+
+func newMessageRecord(m *domain.Message) *messageRecord {
+	rec := &messageRecord{
+		ID:          m.ID,
+		ProjectID:   m.ProjectID,
+		ThreadID:    m.ThreadID,
+		ReplyTo:     m.ReplyTo,
+		FromAgentID: m.FromAgentID,
+		ToAgentID:   m.ToAgentID,
+		ZoneID:      m.ZoneID,
+		Subject:     m.Subject,
+		Body:        m.Body,
+		CreatedAt:   m.CreatedAt.UTC(),
+	}
+	if m.Acked() {
+		acked := m.AckedAt.UTC()
+		rec.AckedAt = &acked
+	}
+	return rec
+}
+
+func (r *messageRecord) toDomain() *domain.Message {
+	m := &domain.Message{
+		ID:          r.ID,
+		ProjectID:   r.ProjectID,
+		ThreadID:    r.ThreadID,
+		ReplyTo:     r.ReplyTo,
+		FromAgentID: r.FromAgentID,
+		ToAgentID:   r.ToAgentID,
+		ZoneID:      r.ZoneID,
+		Subject:     r.Subject,
+		Body:        r.Body,
+		CreatedAt:   r.CreatedAt,
+	}
+	if r.AckedAt != nil {
+		m.AckedAt = *r.AckedAt
+	}
+	return m
+}
+
+// decisionRecord is the on-disk form of domain.Decision. Seq keeps creation order, since ids are random.
+type decisionRecord struct {
+	Seq          int64     `json:"seq"`
+	ID           string    `json:"id"`
+	ProjectID    string    `json:"project_id"`
+	Number       int       `json:"number"`
+	Title        string    `json:"title"`
+	Context      string    `json:"context,omitempty"`
+	Decision     string    `json:"decision,omitempty"`
+	Consequences string    `json:"consequences,omitempty"`
+	Status       string    `json:"status"`
+	ZoneIDs      []string  `json:"zone_ids,omitempty"`
+	Supersedes   string    `json:"supersedes,omitempty"`
+	CreatedBy    string    `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func newDecisionRecord(d *domain.Decision) *decisionRecord {
+	return &decisionRecord{
+		ID:           d.ID,
+		ProjectID:    d.ProjectID,
+		Number:       d.Number,
+		Title:        d.Title,
+		Context:      d.Context,
+		Decision:     d.Decision,
+		Consequences: d.Consequences,
+		Status:       d.Status,
+		ZoneIDs:      append([]string(nil), d.ZoneIDs...),
+		Supersedes:   d.Supersedes,
+		CreatedBy:    d.CreatedBy,
+		CreatedAt:    d.CreatedAt.UTC(),
+		UpdatedAt:    d.UpdatedAt.UTC(),
+	}
+}
+
+func (r *decisionRecord) toDomain() *domain.Decision {
+	return &domain.Decision{
+		ID:           r.ID,
+		ProjectID:    r.ProjectID,
+		Number:       r.Number,
+		Title:        r.Title,
+		Context:      r.Context,
+		Decision:     r.Decision,
+		Consequences: r.Consequences,
+		Status:       r.Status,
+		ZoneIDs:      append([]string{}, r.ZoneIDs...),
+		Supersedes:   r.Supersedes,
+		CreatedBy:    r.CreatedBy,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
+
+// noteRecord is the on-disk form of domain.ZoneNote. Seq keeps creation order, since ids are random.
+type noteRecord struct {
+	Seq       int64      `json:"seq"`
+	ID        string     `json:"id"`
+	ProjectID string     `json:"project_id"`
+	ZoneID    string     `json:"zone_id"`
+	AgentID   string     `json:"agent_id,omitempty"`
+	Text      string     `json:"text"`
+	Tags      []string   `json:"tags,omitempty"`
+	Pinned    bool       `json:"pinned,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func newNoteRecord(n *domain.ZoneNote) *noteRecord {
+	rec := &noteRecord{
+		ID:        n.ID,
+		ProjectID: n.ProjectID,
+		ZoneID:    n.ZoneID,
+		AgentID:   n.AgentID,
+		Text:      n.Text,
+		Tags:      append([]string(nil), n.Tags...),
+		Pinned:    n.Pinned,
+		CreatedAt: n.CreatedAt.UTC(),
+	}
+	if !n.ExpiresAt.IsZero() {
+		expires := n.ExpiresAt.UTC()
+		rec.ExpiresAt = &expires
+	}
+	return rec
+}
+
+func (r *noteRecord) toDomain() *domain.ZoneNote {
+	n := &domain.ZoneNote{
+		ID:        r.ID,
+		ProjectID: r.ProjectID,
+		ZoneID:    r.ZoneID,
+		AgentID:   r.AgentID,
+		Text:      r.Text,
+		Tags:      append([]string{}, r.Tags...),
+		Pinned:    r.Pinned,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.ExpiresAt != nil {
+		n.ExpiresAt = *r.ExpiresAt
+	}
+	return n
+}
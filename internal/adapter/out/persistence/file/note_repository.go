@@ -0,0 +1,113 @@
+package file
+
+import (
+	"sort"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure NoteRepository implements ports.NoteRepository at compile time.
+var _ ports.NoteRepository = (*NoteRepository)(nil)
+
+// NoteRepository persists zone notes as JSON files, one per note.
+type NoteRepository struct {
+	dir *Dir
+}
+
+// NewNoteRepository returns a new note repository.
+func NewNoteRepository(dir *Dir) *NoteRepository {
+	return &NoteRepository{dir: dir}
+}
+
+// Get returns the note by id, or nil if not found.
+func (r *NoteRepository) Get(id string) *domain.ZoneNote {
+	var rec noteRecord
+	var found bool
+	err := r.dir.read(func() (err error) {
+		found, err = r.dir.get(kindNotes, id, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// List returns the project's notes oldest first.
+func (r *NoteRepository) List(projectID string) []*domain.ZoneNote {
+	var recs []*noteRecord
+	err := r.dir.read(func() (err error) {
+		recs, err = list[noteRecord](r.dir, kindNotes)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Seq < recs[j].Seq })
+	var out []*domain.ZoneNote
+	for _, rec := range recs {
+		if rec.ProjectID == projectID {
+			out = append(out, rec.toDomain())
+		}
+	}
+	return out
+}
+
+// Create stores n with a generated id.
+func (r *NoteRepository) Create(n *domain.ZoneNote) (*domain.ZoneNote, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := newNoteRecord(n)
+	rec.ID = id
+	err = r.dir.write(func() error {
+		existing, err := list[noteRecord](r.dir, kindNotes)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			rec.Seq = max(rec.Seq, e.Seq)
+		}
+		rec.Seq++
+		return r.dir.put(kindNotes, id, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Delete removes a note by id.
+func (r *NoteRepository) Delete(id string) error {
+	return r.dir.write(func() error {
+		found, err := r.dir.remove(kindNotes, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "NOTE_NOT_FOUND", Message: "note not found"}
+		}
+		return nil
+	})
+}
+
+// DeleteByProject removes all notes of a project.
+func (r *NoteRepository) DeleteByProject(projectID string) error {
+	return r.dir.write(func() error {
+		recs, err := list[noteRecord](r.dir, kindNotes)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if rec.ProjectID != projectID {
+				continue
+			}
+			if _, err := r.dir.remove(kindNotes, rec.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
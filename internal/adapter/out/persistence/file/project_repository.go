@@ -0,0 +1,158 @@
+package file
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure ProjectRepository implements ports.ProjectRepository at compile time.
+var _ ports.ProjectRepository = (*ProjectRepository)(nil)
+
+// ProjectRepository persists projects as JSON files.
+type ProjectRepository struct {
+	dir *Dir
+}
+
+// NewProjectRepository returns a new project repository.
+func NewProjectRepository(dir *Dir) *ProjectRepository {
+	return &ProjectRepository{dir: dir}
+}
+
+// Get returns the project by id, or nil if not found.
+func (r *ProjectRepository) Get(id string) *domain.Project {
+	var rec projectRecord
+	var found bool
+	err := r.dir.read(func() (err error) {
+		found, err = r.dir.get(kindProjects, id, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// List returns all projects.
+func (r *ProjectRepository) List() []*domain.Project {
+	var recs []*projectRecord
+	err := r.dir.read(func() (err error) {
+		recs, err = list[projectRecord](r.dir, kindProjects)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	out := make([]*domain.Project, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, rec.toDomain())
+	}
+	return out
+}
+
+// Create creates a project with generated id. RootDir is required.
+func (r *ProjectRepository) Create(name, rootDir string) (*domain.Project, error) {
+	if rootDir == "" {
+		return nil, &domain.StructuredError{Code: "INVALID_ROOT", Message: "project root directory is required"}
+	}
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := &projectRecord{ID: id, Name: name, RootDir: rootDir, IgnoredPaths: []string{}}
+	if err := r.dir.write(func() error { return r.dir.put(kindProjects, id, rec) }); err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Update updates a project by id.
+func (r *ProjectRepository) Update(id, name, rootDir string) (*domain.Project, error) {
+	return r.modify(id, func(rec *projectRecord) error {
+		if name != "" {
+			rec.Name = name
+		}
+		if rootDir != "" {
+			rec.RootDir = rootDir
+		}
+		return nil
+	})
+}
+
+// Delete removes a project by id. Caller should delete zones for the project first (e.g. via ZoneRepository.DeleteByProject).
+func (r *ProjectRepository) Delete(projectID string) error {
+	return r.dir.write(func() error {
+		found, err := r.dir.remove(kindProjects, projectID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		return nil
+	})
+}
+
+// AddIgnoredPath adds path to the project's ignored list (no-op if already present).
+func (r *ProjectRepository) AddIgnoredPath(projectID, path string) (*domain.Project, error) {
+	path = domain.NormalizePath(path)
+	if path == "" {
+		return nil, &domain.StructuredError{Code: "INVALID_PATH", Message: "path is required"}
+	}
+	return r.modify(projectID, func(rec *projectRecord) error {
+		for _, ig := range rec.IgnoredPaths {
+			if ig == path {
+				return nil
+			}
+		}
+		rec.IgnoredPaths = append(rec.IgnoredPaths, path)
+		return nil
+	})
+}
+
+// RemoveIgnoredPath removes path from the project's ignored list.
+func (r *ProjectRepository) RemoveIgnoredPath(projectID, path string) (*domain.Project, error) {
+	path = domain.NormalizePath(path)
+	return r.modify(projectID, func(rec *projectRecord) error {
+		filtered := []string{}
+		for _, ig := range rec.IgnoredPaths {
+			if ig != path {
+				filtered = append(filtered, ig)
+			}
+		}
+		rec.IgnoredPaths = filtered
+		return nil
+	})
+}
+
+// SetBlueprintSync sets the project's blueprint file binding and last-sync hashes.
+// An empty file removes the binding.
+func (r *ProjectRepository) SetBlueprintSync(projectID, file, fileHash, storeHash string) (*domain.Project, error) {
+	return r.modify(projectID, func(rec *projectRecord) error {
+		rec.BlueprintFile = file
+		rec.BlueprintFileHash = fileHash
+		rec.BlueprintStoreHash = storeHash
+		return nil
+	})
+}
+
+// modify loads a project, applies fn and writes it back under one exclusive lock.
+func (r *ProjectRepository) modify(id string, fn func(rec *projectRecord) error) (*domain.Project, error) {
+	var rec projectRecord
+	err := r.dir.write(func() error {
+		found, err := r.dir.get(kindProjects, id, &rec)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		if err := fn(&rec); err != nil {
+			return err
+		}
+		return r.dir.put(kindProjects, id, &rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
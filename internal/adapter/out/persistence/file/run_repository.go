@@ -0,0 +1,134 @@
+package file
+
+import (
+	"sort"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure RunRepository implements ports.RunRepository at compile time.
+var _ ports.RunRepository = (*RunRepository)(nil)
+
+// RunRepository persists runs as JSON files, one per run.
+type RunRepository struct {
+	dir *Dir
+}
+
+// NewRunRepository returns a new run repository.
+func NewRunRepository(dir *Dir) *RunRepository {
+	return &RunRepository{dir: dir}
+}
+
+// Get returns the run by id, or nil if not found.
+func (r *RunRepository) Get(id string) *domain.Run {
+	var rec runRecord
+	var found bool
+	err := r.dir.read(func() (err error) {
+		found, err = r.dir.get(kindRuns, id, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// List returns the project's runs oldest first.
+func (r *RunRepository) List(projectID string) []*domain.Run {
+	var recs []*runRecord
+	err := r.dir.read(func() (err error) {
+		recs, err = list[runRecord](r.dir, kindRuns)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Seq < recs[j].Seq })
+	var out []*domain.Run
+	for _, rec := range recs {
+		if rec.ProjectID == projectID {
+			out = append(out, rec.toDomain())
+		}
+	}
+	return out
+}
+
+// Create stores run with a generated id.
+func (r *RunRepository) Create(run *domain.Run) (*domain.Run, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := newRunRecord(run)
+	rec.ID = id
+	err = r.dir.write(func() error {
+		existing, err := list[runRecord](r.dir, kindRuns)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			rec.Seq = max(rec.Seq, e.Seq)
+		}
+		rec.Seq++
+		return r.dir.put(kindRuns, id, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Update replaces every field of an existing run.
+func (r *RunRepository) Update(run *domain.Run) (*domain.Run, error) {
+	rec := newRunRecord(run)
+	err := r.dir.write(func() error {
+		var old runRecord
+		found, err := r.dir.get(kindRuns, run.ID, &old)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "RUN_NOT_FOUND", Message: "run not found"}
+		}
+		rec.Seq = old.Seq
+		return r.dir.put(kindRuns, run.ID, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Delete removes a run by id.
+func (r *RunRepository) Delete(id string) error {
+	return r.dir.write(func() error {
+		found, err := r.dir.remove(kindRuns, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "RUN_NOT_FOUND", Message: "run not found"}
+		}
+		return nil
+	})
+}
+
+// DeleteByProject removes all runs of a project.
+func (r *RunRepository) DeleteByProject(projectID string) error {
+	return r.dir.write(func() error {
+		recs, err := list[runRecord](r.dir, kindRuns)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if rec.ProjectID != projectID {
+				continue
+			}
+			if _, err := r.dir.remove(kindRuns, rec.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,134 @@
+package file
+
+import (
+	"sort"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure TaskRepository implements ports.TaskRepository at compile time.
+var _ ports.TaskRepository = (*TaskRepository)(nil)
+
+// TaskRepository persists tasks as JSON files, one per task.
+type TaskRepository struct {
+	dir *Dir
+}
+
+// NewTaskRepository returns a new task repository.
+func NewTaskRepository(dir *Dir) *TaskRepository {
+	return &TaskRepository{dir: dir}
+}
+
+// Get returns the task by id, or nil if not found.
+func (r *TaskRepository) Get(id string) *domain.Task {
+	var rec taskRecord
+	var found bool
+	err := r.dir.read(func() (err error) {
+		found, err = r.dir.get(kindTasks, id, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// List returns the project's tasks oldest first.
+func (r *TaskRepository) List(projectID string) []*domain.Task {
+	var recs []*taskRecord
+	err := r.dir.read(func() (err error) {
+		recs, err = list[taskRecord](r.dir, kindTasks)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Seq < recs[j].Seq })
+	var out []*domain.Task
+	for _, rec := range recs {
+		if rec.ProjectID == projectID {
+			out = append(out, rec.toDomain())
+		}
+	}
+	return out
+}
+
+// Create stores t with a generated id.
+func (r *TaskRepository) Create(t *domain.Task) (*domain.Task, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := newTaskRecord(t)
+	rec.ID = id
+	err = r.dir.write(func() error {
+		existing, err := list[taskRecord](r.dir, kindTasks)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			rec.Seq = max(rec.Seq, e.Seq)
+		}
+		rec.Seq++
+		return r.dir.put(kindTasks, id, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Update replaces every field of an existing task.
+func (r *TaskRepository) Update(t *domain.Task) (*domain.Task, error) {
+	rec := newTaskRecord(t)
+	err := r.dir.write(func() error {
+		var old taskRecord
+		found, err := r.dir.get(kindTasks, t.ID, &old)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "TASK_NOT_FOUND", Message: "task not found"}
+		}
+		rec.Seq = old.Seq
+		return r.dir.put(kindTasks, t.ID, rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Delete removes a task by id.
+func (r *TaskRepository) Delete(id string) error {
+	return r.dir.write(func() error {
+		found, err := r.dir.remove(kindTasks, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "TASK_NOT_FOUND", Message: "task not found"}
+		}
+		return nil
+	})
+}
+
+// DeleteByProject removes all tasks of a project.
+func (r *TaskRepository) DeleteByProject(projectID string) error {
+	return r.dir.write(func() error {
+		recs, err := list[taskRecord](r.dir, kindTasks)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if rec.ProjectID != projectID {
+				continue
+			}
+			if _, err := r.dir.remove(kindTasks, rec.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
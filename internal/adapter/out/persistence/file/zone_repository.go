@@ -0,0 +1,167 @@
+package file
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure ZoneRepository implements ports.ZoneRepository at compile time.
+var _ ports.ZoneRepository = (*ZoneRepository)(nil)
+
+// ZoneRepository persists zones as JSON files.
+type ZoneRepository struct {
+	dir *Dir
+}
+
+// NewZoneRepository returns a new zone repository.
+func NewZoneRepository(dir *Dir) *ZoneRepository {
+	return &ZoneRepository{dir: dir}
+}
+
+// Get returns the zone by id, or nil if not found.
+func (r *ZoneRepository) Get(id string) *domain.Zone {
+	var rec zoneRecord
+	var found bool
+	err := r.dir.read(func() (err error) {
+		found, err = r.dir.get(kindZones, id, &rec)
+		return err
+	})
+	if err != nil || !found {
+		return nil
+	}
+	return rec.toDomain()
+}
+
+// ListByProject returns all zones for the given project.
+func (r *ZoneRepository) ListByProject(projectID string) []*domain.Zone {
+	var recs []*zoneRecord
+	err := r.dir.read(func() (err error) {
+		recs, err = list[zoneRecord](r.dir, kindZones)
+		return err
+	})
+	if err != nil {
+		return nil
+	}
+	out := make([]*domain.Zone, 0, len(recs))
+	for _, rec := range recs {
+		if rec.ProjectID == projectID {
+			out = append(out, rec.toDomain())
+		}
+	}
+	return out
+}
+
+// Create creates a zone in the given project and returns it with generated id.
+func (r *ZoneRepository) Create(projectID, name, pattern, purpose string, constraints []string, agentIDs []string) (*domain.Zone, error) {
+	if name == "" {
+		return nil, &domain.StructuredError{Code: "INVALID_NAME", Message: "zone name is required"}
+	}
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := &zoneRecord{
+		ID:          id,
+		ProjectID:   projectID,
+		Name:        name,
+		Pattern:     pattern,
+		Purpose:     purpose,
+		Constraints: append([]string(nil), constraints...),
+		AgentIDs:    append([]string(nil), agentIDs...),
+	}
+	if err := r.dir.write(func() error { return r.dir.put(kindZones, id, rec) }); err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
+
+// Update updates a zone by id.
+func (r *ZoneRepository) Update(id, name, pattern, purpose string, constraints []string, agentIDs []string) (*domain.Zone, error) {
+	return r.modify(id, func(rec *zoneRecord) {
+		if name != "" {
+			rec.Name = name
+		}
+		rec.Pattern = pattern
+		rec.Purpose = purpose
+		rec.Constraints = append([]string(nil), constraints...)
+		rec.AgentIDs = append([]string(nil), agentIDs...)
+	})
+}
+
+// AssignPath adds path to zone's explicit paths.
+func (r *ZoneRepository) AssignPath(zoneID, path string) (*domain.Zone, error) {
+	return r.modify(zoneID, func(rec *zoneRecord) {
+		for _, p := range rec.ExplicitPaths {
+			if p == path {
+				return
+			}
+		}
+		rec.ExplicitPaths = append(rec.ExplicitPaths, path)
+	})
+}
+
+// UnassignPath removes path from zone's explicit paths (no-op if absent).
+func (r *ZoneRepository) UnassignPath(zoneID, path string) (*domain.Zone, error) {
+	return r.modify(zoneID, func(rec *zoneRecord) {
+		var filtered []string
+		for _, p := range rec.ExplicitPaths {
+			if p != path {
+				filtered = append(filtered, p)
+			}
+		}
+		rec.ExplicitPaths = filtered
+	})
+}
+
+// Delete removes a zone by id.
+func (r *ZoneRepository) Delete(id string) error {
+	return r.dir.write(func() error {
+		found, err := r.dir.remove(kindZones, id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+		}
+		return nil
+	})
+}
+
+// DeleteByProject deletes all zones for the given project.
+func (r *ZoneRepository) DeleteByProject(projectID string) error {
+	return r.dir.write(func() error {
+		recs, err := list[zoneRecord](r.dir, kindZones)
+		if err != nil {
+			return err
+		}
+		for _, rec := range recs {
+			if rec.ProjectID != projectID {
+				continue
+			}
+			if _, err := r.dir.remove(kindZones, rec.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// modify loads a zone, applies fn and writes it back under one exclusive lock.
+func (r *ZoneRepository) modify(id string, fn func(rec *zoneRecord)) (*domain.Zone, error) {
+	var rec zoneRecord
+	err := r.dir.write(func() error {
+		found, err := r.dir.get(kindZones, id, &rec)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+		}
+		fn(&rec)
+		return r.dir.put(kindZones, id, &rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec.toDomain(), nil
+}
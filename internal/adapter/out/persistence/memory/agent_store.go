@@ -44,12 +44,12 @@ func (s *AgentStore) List() []*domain.Agent {
 }
 
 // Create creates an agent with generated id.
-func (s *AgentStore) Create(name, description, prompt string) (*domain.Agent, error) {
+func (s *AgentStore) Create(name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error) {
 	id, err := genID()
 	if err != nil {
 		return nil, err
 	}
-	a := &domain.Agent{ID: id, Name: name, Description: description, Prompt: prompt}
+	a := &domain.Agent{ID: id, Name: name, Description: description, Prompt: prompt, Variables: cloneVariables(variables)}
 	s.mu.Lock()
 	s.agents[id] = a
 	s.mu.Unlock()
@@ -57,7 +57,7 @@ func (s *AgentStore) Create(name, description, prompt string) (*domain.Agent, er
 }
 
 // Update updates an agent by id.
-func (s *AgentStore) Update(id, name, description, prompt string) (*domain.Agent, error) {
+func (s *AgentStore) Update(id, name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	a, ok := s.agents[id]
@@ -67,6 +67,7 @@ func (s *AgentStore) Update(id, name, description, prompt string) (*domain.Agent
 	a.Name = name
 	a.Description = description
 	a.Prompt = prompt
+	a.Variables = cloneVariables(variables)
 	return cloneAgent(a), nil
 }
 
@@ -86,5 +87,13 @@ func cloneAgent(a *domain.Agent) *domain.Agent {
 		return nil
 	}
 	c := *a
+	c.Variables = cloneVariables(a.Variables)
 	return &c
 }
+
+func cloneVariables(v []domain.PromptVariable) []domain.PromptVariable {
+	if len(v) == 0 {
+		return nil
+	}
+	return append([]domain.PromptVariable(nil), v...)
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"sync"
+
+	"operators-mcp/internal/application/ports"
+)
+
+// Ensure AgentTokens implements ports.AgentTokenStore at compile time.
+var _ ports.AgentTokenStore = (*AgentTokens)(nil)
+
+// AgentTokens holds agent token hashes in memory.
+type AgentTokens struct {
+	mu      sync.RWMutex
+	byAgent map[string]string
+	byHash  map[string]string
+}
+
+// NewAgentTokens returns a new in-memory token store.
+func NewAgentTokens() *AgentTokens {
+	return &AgentTokens{byAgent: make(map[string]string), byHash: make(map[string]string)}
+}
+
+// Set stores tokenHash as the agent's token, replacing any previous one.
+func (t *AgentTokens) Set(agentID, tokenHash string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.byHash, t.byAgent[agentID])
+	t.byAgent[agentID] = tokenHash
+	t.byHash[tokenHash] = agentID
+	return nil
+}
+
+// AgentID returns the agent holding tokenHash, or "".
+func (t *AgentTokens) AgentID(tokenHash string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.byHash[tokenHash]
+}
+
+// Delete removes the agent's token.
+func (t *AgentTokens) Delete(agentID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.byHash, t.byAgent[agentID])
+	delete(t.byAgent, agentID)
+	return nil
+}
+
+// Any reports whether any agent holds a token.
+func (t *AgentTokens) Any() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.byAgent) > 0
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"sync"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure ChangeLog implements ports.ChangeLog at compile time.
+var _ ports.ChangeLog = (*ChangeLog)(nil)
+
+// ChangeLog holds recorded file changes in memory, oldest first.
+type ChangeLog struct {
+	mu      sync.RWMutex
+	changes []domain.FileChange
+}
+
+// NewChangeLog returns a new in-memory change log.
+func NewChangeLog() *ChangeLog {
+	return &ChangeLog{}
+}
+
+// Record stores a copy of c with a generated id.
+func (l *ChangeLog) Record(c *domain.FileChange) (*domain.FileChange, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := *c
+	rec.ID = id
+	l.mu.Lock()
+	l.changes = append(l.changes, rec)
+	l.mu.Unlock()
+	return &rec, nil
+}
+
+// List returns the project's changes newest first, at most limit (0 means all).
+func (l *ChangeLog) List(projectID string, limit int) []*domain.FileChange {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var out []*domain.FileChange
+	for i := len(l.changes) - 1; i >= 0; i-- {
+		if l.changes[i].ProjectID != projectID {
+			continue
+		}
+		c := l.changes[i]
+		out = append(out, &c)
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
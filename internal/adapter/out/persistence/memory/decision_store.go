@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"slices"
+	"sync"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure DecisionStore implements ports.DecisionRepository at compile time.
+var _ ports.DecisionRepository = (*DecisionStore)(nil)
+
+// DecisionStore holds in-memory decision records in creation order.
+type DecisionStore struct {
+	mu        sync.RWMutex
+	decisions []*domain.Decision
+}
+
+// NewDecisionStore returns a new in-memory decision store.
+func NewDecisionStore() *DecisionStore {
+	return &DecisionStore{}
+}
+
+// Get returns the decision by id, or nil if not found.
+func (s *DecisionStore) Get(id string) *domain.Decision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.index(id); i >= 0 {
+		return cloneDecision(s.decisions[i])
+	}
+	return nil
+}
+
+// List returns the project's decisions oldest first.
+func (s *DecisionStore) List(projectID string) []*domain.Decision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*domain.Decision
+	for _, d := range s.decisions {
+		if d.ProjectID == projectID {
+			out = append(out, cloneDecision(d))
+		}
+	}
+	return out
+}
+
+// Create stores a copy of d with a generated id.
+func (s *DecisionStore) Create(d *domain.Decision) (*domain.Decision, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := cloneDecision(d)
+	rec.ID = id
+	s.mu.Lock()
+	s.decisions = append(s.decisions, rec)
+	s.mu.Unlock()
+	return cloneDecision(rec), nil
+}
+
+// Update replaces the stored decision with a copy of d.
+func (s *DecisionStore) Update(d *domain.Decision) (*domain.Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(d.ID)
+	if i < 0 {
+		return nil, &domain.StructuredError{Code: "DECISION_NOT_FOUND", Message: "decision not found"}
+	}
+	s.decisions[i] = cloneDecision(d)
+	return cloneDecision(d), nil
+}
+
+// DeleteByProject removes all decisions of a project.
+func (s *DecisionStore) DeleteByProject(projectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decisions = slices.DeleteFunc(s.decisions, func(d *domain.Decision) bool { return d.ProjectID == projectID })
+	return nil
+}
+
+func (s *DecisionStore) index(id string) int {
+	return slices.IndexFunc(s.decisions, func(d *domain.Decision) bool { return d.ID == id })
+}
+
+func cloneDecision(d *domain.Decision) *domain.Decision {
+	c := *d
+	c.ZoneIDs = slices.Clone(d.ZoneIDs)
+	return &c
+}
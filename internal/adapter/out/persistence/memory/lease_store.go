@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure LeaseStore implements ports.LeaseStore at compile time.
+var _ ports.LeaseStore = (*LeaseStore)(nil)
+
+// LeaseStore holds zone leases in memory keyed by zone id.
+type LeaseStore struct {
+	mu     sync.RWMutex
+	leases map[string]domain.ZoneLease
+}
+
+// NewLeaseStore returns a new in-memory lease store.
+func NewLeaseStore() *LeaseStore {
+	return &LeaseStore{leases: make(map[string]domain.ZoneLease)}
+}
+
+// Get returns the zone's lease, or nil if it has none.
+func (s *LeaseStore) Get(zoneID string) *domain.ZoneLease {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	l, ok := s.leases[zoneID]
+	if !ok {
+		return nil
+	}
+	return &l
+}
+
+// List returns the project's leases ordered by zone id.
+func (s *LeaseStore) List(projectID string) []*domain.ZoneLease {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*domain.ZoneLease
+	for _, l := range s.leases {
+		if l.ProjectID == projectID {
+			out = append(out, &l)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ZoneID < out[j].ZoneID })
+	return out
+}
+
+// Put stores a copy of l as its zone's lease.
+func (s *LeaseStore) Put(l *domain.ZoneLease) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leases[l.ZoneID] = *l
+	return nil
+}
+
+// Delete removes the zone's lease, if any.
+func (s *LeaseStore) Delete(zoneID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.leases, zoneID)
+	return nil
+}
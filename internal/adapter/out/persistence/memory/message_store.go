@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"slices"
+	"sync"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure MessageStore implements ports.MessageRepository at compile time.
+var _ ports.MessageRepository = (*MessageStore)(nil)
+
+// MessageStore holds in-memory messages in creation order.
+type MessageStore struct {
+	mu       sync.RWMutex
+	messages []domain.Message
+}
+
+// NewMessageStore returns a new in-memory message store.
+func NewMessageStore() *MessageStore {
+	return &MessageStore{}
+}
+
+// Get returns the message by id, or nil if not found.
+func (s *MessageStore) Get(id string) *domain.Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.index(id); i >= 0 {
+		m := s.messages[i]
+		return &m
+	}
+	return nil
+}
+
+// List returns the project's messages oldest first.
+func (s *MessageStore) List(projectID string) []*domain.Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*domain.Message
+	for _, m := range s.messages {
+		if m.ProjectID == projectID {
+			out = append(out, &m)
+		}
+	}
+	return out
+}
+
+// Create stores a copy of m with a generated id.
+func (s *MessageStore) Create(m *domain.Message) (*domain.Message, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := *m
+	rec.ID = id
+	s.mu.Lock()
+	s.messages = append(s.messages, rec)
+	s.mu.Unlock()
+	return &rec, nil
+}
+
+// Update replaces the stored message with a copy of m.
+func (s *MessageStore) Update(m *domain.Message) (*domain.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(m.ID)
+	if i < 0 {
+		return nil, &domain.StructuredError{Code: "MESSAGE_NOT_FOUND", Message: "message not found"}
+	}
+	s.messages[i] = *m
+	out := *m
+	return &out, nil
+}
+
+// DeleteByProject removes all messages of a project.
+func (s *MessageStore) DeleteByProject(projectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = slices.DeleteFunc(s.messages, func(m domain.Message) bool { return m.ProjectID == projectID })
+	return nil
+}
+
+func (s *MessageStore) index(id string) int {
+	return slices.IndexFunc(s.messages, func(m domain.Message) bool { return m.ID == id })
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"slices"
+	"sync"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure NoteStore implements ports.NoteRepository at compile time.
+var _ ports.NoteRepository = (*NoteStore)(nil)
+
+// NoteStore holds in-memory zone notes in creation order.
+type NoteStore struct {
+	mu    sync.RWMutex
+	notes []*domain.ZoneNote
+}
+
+// NewNoteStore returns a new in-memory note store.
+func NewNoteStore() *NoteStore {
+	return &NoteStore{}
+}
+
+// Get returns the note by id, or nil if not found.
+func (s *NoteStore) Get(id string) *domain.ZoneNote {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.index(id); i >= 0 {
+		return cloneNote(s.notes[i])
+	}
+	return nil
+}
+
+// List returns the project's notes oldest first.
+func (s *NoteStore) List(projectID string) []*domain.ZoneNote {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*domain.ZoneNote
+	for _, n := range s.notes {
+		if n.ProjectID == projectID {
+			out = append(out, cloneNote(n))
+		}
+	}
+	return out
+}
+
+// Create stores a copy of n with a generated id.
+func (s *NoteStore) Create(n *domain.ZoneNote) (*domain.ZoneNote, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := cloneNote(n)
+	rec.ID = id
+	s.mu.Lock()
+	s.notes = append(s.notes, rec)
+	s.mu.Unlock()
+	return cloneNote(rec), nil
+}
+
+// Delete removes a note by id.
+func (s *NoteStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 {
+		return &domain.StructuredError{Code: "NOTE_NOT_FOUND", Message: "note not found"}
+	}
+	s.notes = slices.Delete(s.notes, i, i+1)
+	return nil
+}
+
+// DeleteByProject removes all notes of a project.
+func (s *NoteStore) DeleteByProject(projectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notes = slices.DeleteFunc(s.notes, func(n *domain.ZoneNote) bool { return n.ProjectID == projectID })
+	return nil
+}
+
+func (s *NoteStore) index(id string) int {
+	return slices.IndexFunc(s.notes, func(n *domain.ZoneNote) bool { return n.ID == id })
+}
+
+func cloneNote(n *domain.ZoneNote) *domain.ZoneNote {
+	c := *n
+	c.Tags = slices.Clone(n.Tags)
+	return &c
+}
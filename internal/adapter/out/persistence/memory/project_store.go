@@ -140,3 +140,18 @@ func (s *ProjectStore) RemoveIgnoredPath(projectID, path string) (*domain.Projec
 	p.IgnoredPaths = filtered
 	return cloneProject(p), nil
 }
+
+// SetBlueprintSync sets the project's blueprint file binding and last-sync hashes.
+// An empty file removes the binding.
+func (s *ProjectStore) SetBlueprintSync(projectID, file, fileHash, storeHash string) (*domain.Project, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.projects[projectID]
+	if !ok {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	p.BlueprintFile = file
+	p.BlueprintFileHash = fileHash
+	p.BlueprintStoreHash = storeHash
+	return cloneProject(p), nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"slices"
+	"sync"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure RunStore implements ports.RunRepository at compile time.
+var _ ports.RunRepository = (*RunStore)(nil)
+
+// RunStore holds in-memory runs in creation order.
+type RunStore struct {
+	mu   sync.RWMutex
+	runs []*domain.Run
+}
+
+// NewRunStore returns a new in-memory run store.
+func NewRunStore() *RunStore {
+	return &RunStore{}
+}
+
+// Get returns the run by id, or nil if not found.
+func (s *RunStore) Get(id string) *domain.Run {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.index(id); i >= 0 {
+		return cloneRun(s.runs[i])
+	}
+	return nil
+}
+
+// List returns the project's runs oldest first.
+func (s *RunStore) List(projectID string) []*domain.Run {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*domain.Run
+	for _, r := range s.runs {
+		if r.ProjectID == projectID {
+			out = append(out, cloneRun(r))
+		}
+	}
+	return out
+}
+
+// Create stores a copy of r with a generated id.
+func (s *RunStore) Create(r *domain.Run) (*domain.Run, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := cloneRun(r)
+	rec.ID = id
+	s.mu.Lock()
+	s.runs = append(s.runs, rec)
+	s.mu.Unlock()
+	return cloneRun(rec), nil
+}
+
+// Update replaces the stored run with a copy of r.
+func (s *RunStore) Update(r *domain.Run) (*domain.Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(r.ID)
+	if i < 0 {
+		return nil, &domain.StructuredError{Code: "RUN_NOT_FOUND", Message: "run not found"}
+	}
+	s.runs[i] = cloneRun(r)
+	return cloneRun(r), nil
+}
+
+// Delete removes a run by id.
+func (s *RunStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 {
+		return &domain.StructuredError{Code: "RUN_NOT_FOUND", Message: "run not found"}
+	}
+	s.runs = slices.Delete(s.runs, i, i+1)
+	return nil
+}
+
+// DeleteByProject removes all runs of a project.
+func (s *RunStore) DeleteByProject(projectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs = slices.DeleteFunc(s.runs, func(r *domain.Run) bool { return r.ProjectID == projectID })
+	return nil
+}
+
+func (s *RunStore) index(id string) int {
+	return slices.IndexFunc(s.runs, func(r *domain.Run) bool { return r.ID == id })
+}
+
+func cloneRun(r *domain.Run) *domain.Run {
+	c := *r
+	c.Tasks = make([]domain.RunTask, len(r.Tasks))
+	for i, t := range r.Tasks {
+		c.Tasks[i] = domain.RunTask{TaskID: t.TaskID, DependsOn: slices.Clone(t.DependsOn)}
+	}
+	return &c
+}
@@ -48,7 +48,7 @@ func (s *Store) ListByProject(projectID string) []*domain.Zone {
 }
 
 // Create creates a zone in the given project and returns it with generated id. Name must be non-empty.
-func (s *Store) Create(projectID, name, pattern, purpose string, constraints []string, agents []domain.Agent) (*domain.Zone, error) {
+func (s *Store) Create(projectID, name, pattern, purpose string, constraints []string, agentIDs []string) (*domain.Zone, error) {
 	if name == "" {
 		return nil, &domain.StructuredError{Code: "INVALID_NAME", Message: "zone name is required"}
 	}
@@ -57,14 +57,14 @@ func (s *Store) Create(projectID, name, pattern, purpose string, constraints []s
 		return nil, err
 	}
 	z := &domain.Zone{
-		ID:             id,
-		ProjectID:      projectID,
-		Name:           name,
-		Pattern:        pattern,
-		Purpose:        purpose,
-		Constraints:    append([]string(nil), constraints...),
-		AssignedAgents: cloneAgents(agents),
-		ExplicitPaths:  nil,
+		ID:            id,
+		ProjectID:     projectID,
+		Name:          name,
+		Pattern:       pattern,
+		Purpose:       purpose,
+		Constraints:   append([]string(nil), constraints...),
+		AgentIDs:      append([]string(nil), agentIDs...),
+		ExplicitPaths: nil,
 	}
 	s.mu.Lock()
 	s.zones[id] = z
@@ -73,7 +73,7 @@ func (s *Store) Create(projectID, name, pattern, purpose string, constraints []s
 }
 
 // Update updates a zone by id. Returns StructuredError if not found or invalid.
-func (s *Store) Update(id, name, pattern, purpose string, constraints []string, agents []domain.Agent) (*domain.Zone, error) {
+func (s *Store) Update(id, name, pattern, purpose string, constraints []string, agentIDs []string) (*domain.Zone, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	z, ok := s.zones[id]
@@ -86,7 +86,7 @@ func (s *Store) Update(id, name, pattern, purpose string, constraints []string,
 	z.Pattern = pattern
 	z.Purpose = purpose
 	z.Constraints = append([]string(nil), constraints...)
-	z.AssignedAgents = cloneAgents(agents)
+	z.AgentIDs = append([]string(nil), agentIDs...)
 	return cloneZone(z), nil
 }
 
@@ -107,6 +107,35 @@ func (s *Store) AssignPath(zoneID, path string) (*domain.Zone, error) {
 	return cloneZone(z), nil
 }
 
+// UnassignPath removes path from zone's explicit paths (no-op if absent).
+func (s *Store) UnassignPath(zoneID, path string) (*domain.Zone, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	z, ok := s.zones[zoneID]
+	if !ok {
+		return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	filtered := z.ExplicitPaths[:0]
+	for _, p := range z.ExplicitPaths {
+		if p != path {
+			filtered = append(filtered, p)
+		}
+	}
+	z.ExplicitPaths = filtered
+	return cloneZone(z), nil
+}
+
+// Delete removes a zone by id. Returns ZONE_NOT_FOUND if it does not exist.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.zones[id]; !ok {
+		return &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	delete(s.zones, id)
+	return nil
+}
+
 // DeleteByProject removes all zones for the given project.
 func (s *Store) DeleteByProject(projectID string) error {
 	s.mu.Lock()
@@ -123,17 +152,11 @@ func cloneZone(z *domain.Zone) *domain.Zone {
 	c := *z
 	c.Constraints = append([]string(nil), z.Constraints...)
 	c.ExplicitPaths = append([]string(nil), z.ExplicitPaths...)
-	c.AssignedAgents = cloneAgents(z.AssignedAgents)
+	c.AgentIDs = append([]string(nil), z.AgentIDs...)
+	c.AssignedAgents = nil
 	return &c
 }
 
-func cloneAgents(a []domain.Agent) []domain.Agent {
-	if a == nil {
-		return nil
-	}
-	return append([]domain.Agent(nil), a...)
-}
-
 func genID() (string, error) {
 	var b [8]byte
 	if _, err := rand.Read(b[:]); err != nil {
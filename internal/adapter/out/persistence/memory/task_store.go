@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"slices"
+	"sync"
+
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+// Ensure TaskStore implements ports.TaskRepository at compile time.
+var _ ports.TaskRepository = (*TaskStore)(nil)
+
+// TaskStore holds in-memory tasks in creation order.
+type TaskStore struct {
+	mu    sync.RWMutex
+	tasks []*domain.Task
+}
+
+// NewTaskStore returns a new in-memory task store.
+func NewTaskStore() *TaskStore {
+	return &TaskStore{}
+}
+
+// Get returns the task by id, or nil if not found.
+func (s *TaskStore) Get(id string) *domain.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if i := s.index(id); i >= 0 {
+		return cloneTask(s.tasks[i])
+	}
+	return nil
+}
+
+// List returns the project's tasks oldest first.
+func (s *TaskStore) List(projectID string) []*domain.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*domain.Task
+	for _, t := range s.tasks {
+		if t.ProjectID == projectID {
+			out = append(out, cloneTask(t))
+		}
+	}
+	return out
+}
+
+// Create stores a copy of t with a generated id.
+func (s *TaskStore) Create(t *domain.Task) (*domain.Task, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	rec := cloneTask(t)
+	rec.ID = id
+	s.mu.Lock()
+	s.tasks = append(s.tasks, rec)
+	s.mu.Unlock()
+	return cloneTask(rec), nil
+}
+
+// Update replaces the stored task with a copy of t.
+func (s *TaskStore) Update(t *domain.Task) (*domain.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(t.ID)
+	if i < 0 {
+		return nil, &domain.StructuredError{Code: "TASK_NOT_FOUND", Message: "task not found"}
+	}
+	s.tasks[i] = cloneTask(t)
+	return cloneTask(t), nil
+}
+
+// Delete removes a task by id.
+func (s *TaskStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.index(id)
+	if i < 0 {
+		return &domain.StructuredError{Code: "TASK_NOT_FOUND", Message: "task not found"}
+	}
+	s.tasks = slices.Delete(s.tasks, i, i+1)
+	return nil
+}
+
+// DeleteByProject removes all tasks of a project.
+func (s *TaskStore) DeleteByProject(projectID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = slices.DeleteFunc(s.tasks, func(t *domain.Task) bool { return t.ProjectID == projectID })
+	return nil
+}
+
+func (s *TaskStore) index(id string) int {
+	return slices.IndexFunc(s.tasks, func(t *domain.Task) bool { return t.ID == id })
+}
+
+func cloneTask(t *domain.Task) *domain.Task {
+	c := *t
+	c.ZoneIDs = slices.Clone(t.ZoneIDs)
+	return &c
+}
@@ -22,14 +22,11 @@ func NewAgentRepository(db *gorm.DB) *AgentRepository {
 
 // Get returns the agent by id, or nil if not found.
 func (r *AgentRepository) Get(id string) *domain.Agent {
-	var m AgentModel
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil
-		}
+	a, err := r.load(r.db, id)
+	if err != nil {
 		return nil
 	}
-	return m.ToDomain()
+	return a
 }
 
 // List returns all agents.
@@ -38,46 +35,70 @@ func (r *AgentRepository) List() []*domain.Agent {
 	if err := r.db.Find(&models).Error; err != nil {
 		return nil
 	}
+	ids := make([]string, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+	vars, err := loadAgentVariables(r.db, ids)
+	if err != nil {
+		return nil
+	}
 	out := make([]*domain.Agent, 0, len(models))
 	for i := range models {
-		out = append(out, models[i].ToDomain())
+		out = append(out, models[i].ToDomain(vars[models[i].ID]))
 	}
 	return out
 }
 
 // Create creates an agent with generated id. Name, description, and prompt can be empty.
-func (r *AgentRepository) Create(name, description, prompt string) (*domain.Agent, error) {
+func (r *AgentRepository) Create(name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error) {
 	id, err := genID()
 	if err != nil {
 		return nil, err
 	}
 	m := &AgentModel{ID: id, Name: name, Description: description, Prompt: prompt}
-	if err := r.db.Create(m).Error; err != nil {
+	var a *domain.Agent
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if err := saveAgentVariables(tx, id, variables); err != nil {
+			return err
+		}
+		a, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-	return m.ToDomain(), nil
+	return a, nil
 }
 
 // Update updates an agent by id.
-func (r *AgentRepository) Update(id, name, description, prompt string) (*domain.Agent, error) {
-	var m AgentModel
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+func (r *AgentRepository) Update(id, name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error) {
+	var a *domain.Agent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := r.load(tx, id); err != nil {
+			return err
 		}
+		updates := map[string]interface{}{"name": name, "description": description, "prompt": prompt}
+		if err := tx.Model(&AgentModel{ID: id}).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := saveAgentVariables(tx, id, variables); err != nil {
+			return err
+		}
+		var err error
+		a, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-	updates := map[string]interface{}{"name": name, "description": description, "prompt": prompt}
-	if err := r.db.Model(&m).Updates(updates).Error; err != nil {
-		return nil, err
-	}
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		return nil, err
-	}
-	return m.ToDomain(), nil
+	return a, nil
 }
 
-// Delete removes an agent by id.
+// Delete removes an agent, its variables and its zone assignments by id in one transaction.
 func (r *AgentRepository) Delete(id string) error {
 	var m AgentModel
 	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
@@ -86,5 +107,29 @@ func (r *AgentRepository) Delete(id string) error {
 		}
 		return err
 	}
-	return r.db.Delete(&m).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("agent_id = ?", id).Delete(&ZoneAgentModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("agent_id = ?", id).Delete(&AgentVariableModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&m).Error
+	})
+}
+
+// load reads an agent with its declared variables.
+func (r *AgentRepository) load(db *gorm.DB, id string) (*domain.Agent, error) {
+	var m AgentModel
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+		}
+		return nil, err
+	}
+	vars, err := loadAgentVariables(db, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(vars[id]), nil
 }
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"time"
+
+	"operators-mcp/internal/application/ports"
+
+	"gorm.io/gorm"
+)
+
+// Ensure AgentTokens implements ports.AgentTokenStore at compile time.
+var _ ports.AgentTokenStore = (*AgentTokens)(nil)
+
+// AgentTokens persists agent token hashes in SQLite via GORM.
+type AgentTokens struct {
+	db *gorm.DB
+}
+
+// NewAgentTokens returns a new token store.
+func NewAgentTokens(db *gorm.DB) *AgentTokens {
+	return &AgentTokens{db: db}
+}
+
+// Set stores tokenHash as the agent's token, replacing any previous one.
+func (t *AgentTokens) Set(agentID, tokenHash string) error {
+	m := &AgentTokenModel{AgentID: agentID, TokenHash: tokenHash, CreatedAt: time.Now().UTC()}
+	return t.db.Save(m).Error
+}
+
+// AgentID returns the agent holding tokenHash, or "".
+func (t *AgentTokens) AgentID(tokenHash string) string {
+	var m AgentTokenModel
+	if err := t.db.Where("token_hash = ?", tokenHash).Limit(1).Find(&m).Error; err != nil {
+		return ""
+	}
+	return m.AgentID
+}
+
+// Delete removes the agent's token.
+func (t *AgentTokens) Delete(agentID string) error {
+	return t.db.Delete(&AgentTokenModel{}, "agent_id = ?", agentID).Error
+}
+
+// Any reports whether any agent holds a token.
+func (t *AgentTokens) Any() bool {
+	var n int64
+	if err := t.db.Model(&AgentTokenModel{}).Limit(1).Count(&n).Error; err != nil {
+		return false
+	}
+	return n > 0
+}
@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Ensure ChangeLog implements ports.ChangeLog at compile time.
+var _ ports.ChangeLog = (*ChangeLog)(nil)
+
+// ChangeLog persists recorded file changes in SQLite via GORM.
+type ChangeLog struct {
+	db *gorm.DB
+}
+
+// NewChangeLog returns a new change log.
+func NewChangeLog(db *gorm.DB) *ChangeLog {
+	return &ChangeLog{db: db}
+}
+
+// Record stores c with a generated id.
+func (l *ChangeLog) Record(c *domain.FileChange) (*domain.FileChange, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	m := &FileChangeModel{
+		ID:           id,
+		ProjectID:    c.ProjectID,
+		AgentID:      c.AgentID,
+		Path:         c.Path,
+		Operation:    c.Operation,
+		Source:       c.Source,
+		OldHash:      c.OldHash,
+		NewHash:      c.NewHash,
+		LinesAdded:   c.LinesAdded,
+		LinesRemoved: c.LinesRemoved,
+		CreatedAt:    c.CreatedAt.UTC(),
+	}
+	if err := l.db.Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// List returns the project's changes newest first, at most limit (0 means all).
+func (l *ChangeLog) List(projectID string, limit int) []*domain.FileChange {
+	q := l.db.Where("project_id = ?", projectID).Order("created_at DESC, rowid DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var models []FileChangeModel
+	if err := q.Find(&models).Error; err != nil {
+		return nil
+	}
+	out := make([]*domain.FileChange, len(models))
+	for i := range models {
+		out[i] = models[i].ToDomain()
+	}
+	return out
+}
@@ -7,15 +7,32 @@ import (
 	"gorm.io/gorm"
 )
 
-// Open opens a SQLite database at the given path (e.g. "file:data.db" or ":memory:").
-// It runs AutoMigrate for ProjectModel and ZoneModel.
+// Open opens a SQLite database at the given path (e.g. "file:data.db" or ":memory:")
+// and applies any pending migrations.
 func Open(path string) (*gorm.DB, error) {
+	db, err := Connect(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Migrate(db, 0); err != nil {
+		return nil, fmt.Errorf("sqlite migrate: %w", err)
+	}
+	return db, nil
+}
+
+// Connect opens a SQLite database at the given path without migrating it.
+func Connect(path string) (*gorm.DB, error) {
 	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("sqlite open: %w", err)
 	}
-	if err := db.AutoMigrate(&ProjectModel{}, &ZoneModel{}, &AgentModel{}); err != nil {
-		return nil, fmt.Errorf("sqlite migrate: %w", err)
+	if path == ":memory:" {
+		// Every pooled connection would otherwise get its own empty database.
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
 	}
 	return db, nil
 }
@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Ensure DecisionRepository implements ports.DecisionRepository at compile time.
+var _ ports.DecisionRepository = (*DecisionRepository)(nil)
+
+// DecisionRepository persists decision records in SQLite via GORM.
+type DecisionRepository struct {
+	db *gorm.DB
+}
+
+// NewDecisionRepository returns a new decision repository.
+func NewDecisionRepository(db *gorm.DB) *DecisionRepository {
+	return &DecisionRepository{db: db}
+}
+
+// Get returns the decision by id, or nil if not found.
+func (r *DecisionRepository) Get(id string) *domain.Decision {
+	d, err := r.load(r.db, id)
+	if err != nil {
+		return nil
+	}
+	return d
+}
+
+// List returns the project's decisions oldest first.
+func (r *DecisionRepository) List(projectID string) []*domain.Decision {
+	var models []DecisionModel
+	if err := r.db.Where("project_id = ?", projectID).Order("created_at, rowid").Find(&models).Error; err != nil {
+		return nil
+	}
+	ids := make([]string, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+	zones, err := loadDecisionZones(r.db, ids)
+	if err != nil {
+		return nil
+	}
+	out := make([]*domain.Decision, len(models))
+	for i := range models {
+		out[i] = models[i].ToDomain(zones[models[i].ID])
+	}
+	return out
+}
+
+// Create stores d with a generated id.
+func (r *DecisionRepository) Create(d *domain.Decision) (*domain.Decision, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	m := decisionModel(d)
+	m.ID = id
+	var out *domain.Decision
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if err := saveDecisionZones(tx, id, d.ZoneIDs); err != nil {
+			return err
+		}
+		out, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces every field of an existing decision.
+func (r *DecisionRepository) Update(d *domain.Decision) (*domain.Decision, error) {
+	var out *domain.Decision
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := r.load(tx, d.ID); err != nil {
+			return err
+		}
+		if err := tx.Save(decisionModel(d)).Error; err != nil {
+			return err
+		}
+		if err := saveDecisionZones(tx, d.ID, d.ZoneIDs); err != nil {
+			return err
+		}
+		var err error
+		out, err = r.load(tx, d.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteByProject removes all decisions of a project.
+func (r *DecisionRepository) DeleteByProject(projectID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		sub := tx.Model(&DecisionModel{}).Select("id").Where("project_id = ?", projectID)
+		if err := tx.Where("decision_id IN (?)", sub).Delete(&DecisionZoneModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("project_id = ?", projectID).Delete(&DecisionModel{}).Error
+	})
+}
+
+// load reads a decision with its zone ids.
+func (r *DecisionRepository) load(db *gorm.DB, id string) (*domain.Decision, error) {
+	var m DecisionModel
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &domain.StructuredError{Code: "DECISION_NOT_FOUND", Message: "decision not found"}
+		}
+		return nil, err
+	}
+	zones, err := loadDecisionZones(db, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(zones[id]), nil
+}
+
+func decisionModel(d *domain.Decision) *DecisionModel {
+	return &DecisionModel{
+		ID:           d.ID,
+		ProjectID:    d.ProjectID,
+		Number:       d.Number,
+		Title:        d.Title,
+		Context:      d.Context,
+		Decision:     d.Decision,
+		Consequences: d.Consequences,
+		Status:       d.Status,
+		Supersedes:   d.Supersedes,
+		CreatedBy:    d.CreatedBy,
+		CreatedAt:    d.CreatedAt.UTC(),
+		UpdatedAt:    d.UpdatedAt.UTC(),
+	}
+}
@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Ensure LeaseStore implements ports.LeaseStore at compile time.
+var _ ports.LeaseStore = (*LeaseStore)(nil)
+
+// LeaseStore persists zone leases in SQLite via GORM.
+type LeaseStore struct {
+	db *gorm.DB
+}
+
+// NewLeaseStore returns a new lease store.
+func NewLeaseStore(db *gorm.DB) *LeaseStore {
+	return &LeaseStore{db: db}
+}
+
+// Get returns the zone's lease, or nil if it has none.
+func (s *LeaseStore) Get(zoneID string) *domain.ZoneLease {
+	var ms []ZoneLeaseModel
+	if err := s.db.Where("zone_id = ?", zoneID).Limit(1).Find(&ms).Error; err != nil || len(ms) == 0 {
+		return nil
+	}
+	return ms[0].ToDomain()
+}
+
+// List returns the project's leases ordered by zone id.
+func (s *LeaseStore) List(projectID string) []*domain.ZoneLease {
+	var ms []ZoneLeaseModel
+	if err := s.db.Where("project_id = ?", projectID).Order("zone_id").Find(&ms).Error; err != nil {
+		return nil
+	}
+	out := make([]*domain.ZoneLease, len(ms))
+	for i := range ms {
+		out[i] = ms[i].ToDomain()
+	}
+	return out
+}
+
+// Put stores l as its zone's lease, replacing any previous one.
+func (s *LeaseStore) Put(l *domain.ZoneLease) error {
+	m := &ZoneLeaseModel{
+		ZoneID:     l.ZoneID,
+		ProjectID:  l.ProjectID,
+		AgentID:    l.AgentID,
+		AcquiredAt: l.AcquiredAt.UTC(),
+		ExpiresAt:  l.ExpiresAt.UTC(),
+	}
+	return s.db.Save(m).Error
+}
+
+// Delete removes the zone's lease, if any.
+func (s *LeaseStore) Delete(zoneID string) error {
+	return s.db.Delete(&ZoneLeaseModel{}, "zone_id = ?", zoneID).Error
+}
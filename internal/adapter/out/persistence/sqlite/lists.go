@@ -0,0 +1,289 @@
+package sqlite
+
+import (
+	"operators-mcp/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// loadIgnoredPaths returns the ignored paths of the given projects keyed by project id.
+func loadIgnoredPaths(db *gorm.DB, projectIDs []string) (map[string][]string, error) {
+	var rows []ProjectIgnoredPathModel
+	if err := db.Where("project_id IN ?", projectIDs).Order("project_id, position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string, len(projectIDs))
+	for _, r := range rows {
+		out[r.ProjectID] = append(out[r.ProjectID], r.Path)
+	}
+	return out, nil
+}
+
+// saveIgnoredPaths replaces a project's ignored paths.
+func saveIgnoredPaths(tx *gorm.DB, projectID string, paths []string) error {
+	if err := tx.Where("project_id = ?", projectID).Delete(&ProjectIgnoredPathModel{}).Error; err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	rows := make([]ProjectIgnoredPathModel, len(paths))
+	for i, p := range paths {
+		rows[i] = ProjectIgnoredPathModel{ProjectID: projectID, Position: i, Path: p}
+	}
+	return tx.Create(&rows).Error
+}
+
+// loadAgentVariables returns the declared variables of the given agents keyed by agent id.
+func loadAgentVariables(db *gorm.DB, agentIDs []string) (map[string][]AgentVariableModel, error) {
+	var rows []AgentVariableModel
+	if err := db.Where("agent_id IN ?", agentIDs).Order("agent_id, position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string][]AgentVariableModel, len(agentIDs))
+	for _, r := range rows {
+		out[r.AgentID] = append(out[r.AgentID], r)
+	}
+	return out, nil
+}
+
+// saveAgentVariables replaces an agent's declared variables.
+func saveAgentVariables(tx *gorm.DB, agentID string, vars []domain.PromptVariable) error {
+	if err := tx.Where("agent_id = ?", agentID).Delete(&AgentVariableModel{}).Error; err != nil {
+		return err
+	}
+	if len(vars) == 0 {
+		return nil
+	}
+	rows := make([]AgentVariableModel, len(vars))
+	for i, v := range vars {
+		rows[i] = AgentVariableModel{AgentID: agentID, Position: i, Name: v.Name, Description: v.Description, Required: v.Required, DefaultValue: v.Default}
+	}
+	return tx.Create(&rows).Error
+}
+
+// loadZoneLists returns the explicit paths, constraints and agents of the given zones keyed by zone id.
+func loadZoneLists(db *gorm.DB, zoneIDs []string) (map[string]zoneLists, error) {
+	out := make(map[string]zoneLists, len(zoneIDs))
+	if len(zoneIDs) == 0 {
+		return out, nil
+	}
+	var paths []ZonePathModel
+	if err := db.Where("zone_id IN ?", zoneIDs).Order("zone_id, position").Find(&paths).Error; err != nil {
+		return nil, err
+	}
+	var constraints []ZoneConstraintModel
+	if err := db.Where("zone_id IN ?", zoneIDs).Order("zone_id, position").Find(&constraints).Error; err != nil {
+		return nil, err
+	}
+	var agents []ZoneAgentModel
+	if err := db.Where("zone_id IN ?", zoneIDs).Order("zone_id, position").Find(&agents).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range paths {
+		l := out[r.ZoneID]
+		l.paths = append(l.paths, r.Path)
+		out[r.ZoneID] = l
+	}
+	for _, r := range constraints {
+		l := out[r.ZoneID]
+		l.constraints = append(l.constraints, r.Text)
+		out[r.ZoneID] = l
+	}
+	for _, r := range agents {
+		l := out[r.ZoneID]
+		l.agentIDs = append(l.agentIDs, r.AgentID)
+		out[r.ZoneID] = l
+	}
+	return out, nil
+}
+
+// saveZonePaths replaces a zone's explicit paths.
+func saveZonePaths(tx *gorm.DB, zoneID string, paths []string) error {
+	if err := tx.Where("zone_id = ?", zoneID).Delete(&ZonePathModel{}).Error; err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	rows := make([]ZonePathModel, len(paths))
+	for i, p := range paths {
+		rows[i] = ZonePathModel{ZoneID: zoneID, Position: i, Path: p}
+	}
+	return tx.Create(&rows).Error
+}
+
+// saveZoneConstraints replaces a zone's constraints.
+func saveZoneConstraints(tx *gorm.DB, zoneID string, constraints []string) error {
+	if err := tx.Where("zone_id = ?", zoneID).Delete(&ZoneConstraintModel{}).Error; err != nil {
+		return err
+	}
+	if len(constraints) == 0 {
+		return nil
+	}
+	rows := make([]ZoneConstraintModel, len(constraints))
+	for i, c := range constraints {
+		rows[i] = ZoneConstraintModel{ZoneID: zoneID, Position: i, Text: c}
+	}
+	return tx.Create(&rows).Error
+}
+
+// saveZoneAgents replaces a zone's agent references.
+func saveZoneAgents(tx *gorm.DB, zoneID string, agentIDs []string) error {
+	if err := tx.Where("zone_id = ?", zoneID).Delete(&ZoneAgentModel{}).Error; err != nil {
+		return err
+	}
+	if len(agentIDs) == 0 {
+		return nil
+	}
+	rows := make([]ZoneAgentModel, len(agentIDs))
+	for i, id := range agentIDs {
+		rows[i] = ZoneAgentModel{ZoneID: zoneID, AgentID: id, Position: i}
+	}
+	return tx.Create(&rows).Error
+}
+
+// deleteZoneLists removes the list rows of the given zones.
+func deleteZoneLists(tx *gorm.DB, zoneIDs []string) error {
+	for _, m := range []interface{}{&ZonePathModel{}, &ZoneConstraintModel{}, &ZoneAgentModel{}} {
+		if err := tx.Where("zone_id IN ?", zoneIDs).Delete(m).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTaskZones returns the zone ids of the given tasks keyed by task id.
+func loadTaskZones(db *gorm.DB, taskIDs []string) (map[string][]string, error) {
+	var rows []TaskZoneModel
+	if err := db.Where("task_id IN ?", taskIDs).Order("task_id, position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string, len(taskIDs))
+	for _, r := range rows {
+		out[r.TaskID] = append(out[r.TaskID], r.ZoneID)
+	}
+	return out, nil
+}
+
+// saveTaskZones replaces a task's zone ids.
+func saveTaskZones(tx *gorm.DB, taskID string, zoneIDs []string) error {
+	if err := tx.Where("task_id = ?", taskID).Delete(&TaskZoneModel{}).Error; err != nil {
+		return err
+	}
+	if len(zoneIDs) == 0 {
+		return nil
+	}
+	rows := make([]TaskZoneModel, len(zoneIDs))
+	for i, id := range zoneIDs {
+		rows[i] = TaskZoneModel{TaskID: taskID, ZoneID: id, Position: i}
+	}
+	return tx.Create(&rows).Error
+}
+
+// loadDecisionZones returns the zone ids of the given decisions keyed by decision id.
+func loadDecisionZones(db *gorm.DB, decisionIDs []string) (map[string][]string, error) {
+	var rows []DecisionZoneModel
+	if err := db.Where("decision_id IN ?", decisionIDs).Order("decision_id, position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string, len(decisionIDs))
+	for _, r := range rows {
+		out[r.DecisionID] = append(out[r.DecisionID], r.ZoneID)
+	}
+	return out, nil
+}
+
+// saveDecisionZones replaces a decision's zone ids.
+func saveDecisionZones(tx *gorm.DB, decisionID string, zoneIDs []string) error {
+	if err := tx.Where("decision_id = ?", decisionID).Delete(&DecisionZoneModel{}).Error; err != nil {
+		return err
+	}
+	if len(zoneIDs) == 0 {
+		return nil
+	}
+	rows := make([]DecisionZoneModel, len(zoneIDs))
+	for i, id := range zoneIDs {
+		rows[i] = DecisionZoneModel{DecisionID: decisionID, ZoneID: id, Position: i}
+	}
+	return tx.Create(&rows).Error
+}
+
+// loadNoteTags returns the tags of the given notes keyed by note id.
+func loadNoteTags(db *gorm.DB, noteIDs []string) (map[string][]string, error) {
+	var rows []ZoneNoteTagModel
+	if err := db.Where("note_id IN ?", noteIDs).Order("note_id, position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string, len(noteIDs))
+	for _, r := range rows {
+		out[r.NoteID] = append(out[r.NoteID], r.Tag)
+	}
+	return out, nil
+}
+
+// saveNoteTags stores a new note's tags.
+func saveNoteTags(tx *gorm.DB, noteID string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	rows := make([]ZoneNoteTagModel, len(tags))
+	for i, tag := range tags {
+		rows[i] = ZoneNoteTagModel{NoteID: noteID, Tag: tag, Position: i}
+	}
+	return tx.Create(&rows).Error
+}
+
+// loadRunTasks returns the task graphs of the given runs keyed by run id.
+func loadRunTasks(db *gorm.DB, runIDs []string) (map[string][]domain.RunTask, error) {
+	var tasks []RunTaskModel
+	if err := db.Where("run_id IN ?", runIDs).Order("run_id, position").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	var deps []RunTaskDepModel
+	if err := db.Where("run_id IN ?", runIDs).Order("run_id, task_id, position").Find(&deps).Error; err != nil {
+		return nil, err
+	}
+	depsOf := make(map[[2]string][]string)
+	for _, d := range deps {
+		key := [2]string{d.RunID, d.TaskID}
+		depsOf[key] = append(depsOf[key], d.DependsOn)
+	}
+	out := make(map[string][]domain.RunTask, len(runIDs))
+	for _, t := range tasks {
+		dependsOn := depsOf[[2]string{t.RunID, t.TaskID}]
+		if dependsOn == nil {
+			dependsOn = []string{}
+		}
+		out[t.RunID] = append(out[t.RunID], domain.RunTask{TaskID: t.TaskID, DependsOn: dependsOn})
+	}
+	return out, nil
+}
+
+// saveRunTasks replaces a run's task graph.
+func saveRunTasks(tx *gorm.DB, runID string, tasks []domain.RunTask) error {
+	if err := tx.Where("run_id = ?", runID).Delete(&RunTaskDepModel{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("run_id = ?", runID).Delete(&RunTaskModel{}).Error; err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	rows := make([]RunTaskModel, len(tasks))
+	var deps []RunTaskDepModel
+	for i, t := range tasks {
+		rows[i] = RunTaskModel{RunID: runID, TaskID: t.TaskID, Position: i}
+		for j, dep := range t.DependsOn {
+			deps = append(deps, RunTaskDepModel{RunID: runID, TaskID: t.TaskID, DependsOn: dep, Position: j})
+		}
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return err
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+	return tx.Create(&deps).Error
+}
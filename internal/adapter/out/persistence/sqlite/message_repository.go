@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Ensure MessageRepository implements ports.MessageRepository at compile time.
+var _ ports.MessageRepository = (*MessageRepository)(nil)
+
+// MessageRepository persists messages in SQLite via GORM.
+type MessageRepository struct {
+	db *gorm.DB
+}
+
+// NewMessageRepository returns a new message repository.
+func NewMessageRepository(db *gorm.DB) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+// Get returns the message by id, or nil if not found.
+func (r *MessageRepository) Get(id string) *domain.Message {
+	var ms []MessageModel
+	if err := r.db.Where("id = ?", id).Limit(1).Find(&ms).Error; err != nil || len(ms) == 0 {
+		return nil
+	}
+	return ms[0].ToDomain()
+}
+
+// List returns the project's messages oldest first.
+func (r *MessageRepository) List(projectID string) []*domain.Message {
+	var ms []MessageModel
+	if err := r.db.Where("project_id = ?", projectID).Order("created_at, rowid").Find(&ms).Error; err != nil {
+		return nil
+	}
+	out := make([]*domain.Message, len(ms))
+	for i := range ms {
+		out[i] = ms[i].ToDomain()
+	}
+	return out
+}
+
+// Create stores m with a generated id.
+func (r *MessageRepository) Create(m *domain.Message) (*domain.Message, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	model := messageModel(m)
+	model.ID = id
+	if err := r.db.Create(model).Error; err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// Update replaces every field of an existing message.
+func (r *MessageRepository) Update(m *domain.Message) (*domain.Message, error) {
+	if r.Get(m.ID) == nil {
+		return nil, &domain.StructuredError{Code: "MESSAGE_NOT_FOUND", Message: "message not found"}
+	}
+	model := messageModel(m)
+	if err := r.db.Save(model).Error; err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// DeleteByProject removes all messages of a project.
+func (r *MessageRepository) DeleteByProject(projectID string) error {
+	return r.db.Where("project_id = ?", projectID).Delete(&MessageModel{}).Error
+}
+
+func messageModel(m *domain.Message) *MessageModel {
+	model := &MessageModel{
+		ID:          m.ID,
+		ProjectID:   m.ProjectID,
+		ThreadID:    m.ThreadID,
+		ReplyTo:     m.ReplyTo,
+		FromAgentID: m.FromAgentID,
+		ToAgentID:   m.ToAgentID,
+		ZoneID:      m.ZoneID,
+		Subject:     m.Subject,
+		Body:        m.Body,
+		CreatedAt:   m.CreatedAt.UTC(),
+	}
+	if m.Acked() {
+		acked := m.AckedAt.UTC()
+		model.AckedAt = &acked
+	}
+	return model
+}
@@ -0,0 +1,219 @@
+package sqlite
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is one embedded up-migration. Files are named <version>_<name>.sql.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time // zero if pending
+}
+
+// Applied reports whether the migration has been applied.
+func (s MigrationStatus) Applied() bool { return !s.AppliedAt.IsZero() }
+
+// schemaVersion is a row of the schema_version table.
+type schemaVersion struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaVersion) TableName() string { return "schema_version" }
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	var out []Migration
+	for _, e := range entries {
+		versionStr, name, ok := strings.Cut(strings.TrimSuffix(e.Name(), ".sql"), "_")
+		version, err := strconv.Atoi(versionStr)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must be <version>_<name>.sql", e.Name())
+		}
+		b, err := migrationFiles.ReadFile(path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Migration{Version: version, Name: name, SQL: string(b)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	for i := 1; i < len(out); i++ {
+		if out[i].Version == out[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", out[i].Version)
+		}
+	}
+	return out, nil
+}
+
+// Status lists every embedded migration with the time it was applied, if it was.
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		out[i] = MigrationStatus{Version: m.Version, Name: m.Name, AppliedAt: applied[m.Version].AppliedAt}
+	}
+	return out, nil
+}
+
+// Migrate applies pending migrations up to and including target (0 means all) and returns
+// the migrations it applied. Each migration runs in its own transaction together with its
+// schema_version row. Databases created before versioning are adopted at the version their
+// schema corresponds to.
+func Migrate(db *gorm.DB, target int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := adoptLegacySchema(db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, m := range migrations {
+		if target > 0 && m.Version > target {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if check := migrationChecks[m.Version]; check != nil {
+				if err := check(tx); err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec(m.SQL).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaVersion{Version: m.Version, Name: m.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// appliedVersions returns the schema_version rows keyed by version; an unversioned database has none.
+func appliedVersions(db *gorm.DB) (map[int]schemaVersion, error) {
+	out := make(map[int]schemaVersion)
+	if !db.Migrator().HasTable(&schemaVersion{}) {
+		return out, nil
+	}
+	var rows []schemaVersion
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		out[r.Version] = r
+	}
+	return out, nil
+}
+
+// adoptLegacySchema creates schema_version and, for a database previously set up by GORM
+// AutoMigrate, records the migrations its schema already contains.
+func adoptLegacySchema(db *gorm.DB) error {
+	m := db.Migrator()
+	if m.HasTable(&schemaVersion{}) {
+		return nil
+	}
+	var legacy []schemaVersion
+	if m.HasTable("projects") {
+		now := time.Now().UTC()
+		legacy = append(legacy, schemaVersion{Version: 1, Name: "initial", AppliedAt: now})
+		if m.HasColumn(&ProjectModel{}, "blueprint_file") {
+			legacy = append(legacy, schemaVersion{Version: 2, Name: "blueprint_sync", AppliedAt: now})
+		}
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`CREATE TABLE schema_version (
+    version    INTEGER PRIMARY KEY,
+    name       TEXT NOT NULL,
+    applied_at DATETIME NOT NULL
+)`).Error; err != nil {
+			return err
+		}
+		if len(legacy) == 0 {
+			return nil
+		}
+		return tx.Create(&legacy).Error
+	})
+}
+
+// migrationChecks validate the data a migration is about to transform, by version. A migration
+// whose check fails is not applied, so the operator can fix the reported rows and run it again.
+var migrationChecks = map[int]func(tx *gorm.DB) error{
+	3: checkListColumns,
+}
+
+// listColumn is a legacy JSON list column and the JSON type its elements must have.
+type listColumn struct {
+	table, column, elem string
+}
+
+// checkListColumns rejects legacy list columns that 0003_normalize_lists cannot move as is:
+// values that are not JSON arrays and elements of the wrong type. Empty values are empty lists.
+func checkListColumns(tx *gorm.DB) error {
+	var problems []string
+	for _, c := range []listColumn{
+		{"projects", "ignored_paths", "text"},
+		{"zones", "explicit_paths", "text"},
+		{"zones", "constraints", "text"},
+		{"zones", "assigned_agents", "object"},
+	} {
+		var ids []string
+		err := tx.Raw(fmt.Sprintf(`SELECT id FROM %[1]s
+WHERE coalesce(%[2]s, '') <> '' AND CASE
+    WHEN NOT json_valid(%[2]s) THEN 1
+    WHEN json_type(%[2]s) <> 'array' THEN 1
+    ELSE EXISTS (SELECT 1 FROM json_each(%[2]s) WHERE type <> '%[3]s')
+END
+ORDER BY id`, c.table, c.column, c.elem)).Scan(&ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			problems = append(problems, fmt.Sprintf("%s.%s must be a JSON array of %s values (rows %s)", c.table, c.column, c.elem, strings.Join(ids, ", ")))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid list data, fix it and migrate again: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+-- Schema as created by the former GORM AutoMigrate: list-valued columns hold JSON arrays.
+CREATE TABLE projects (
+    id            TEXT PRIMARY KEY,
+    name          TEXT,
+    root_dir      TEXT,
+    ignored_paths TEXT
+);
+
+CREATE TABLE zones (
+    id              TEXT PRIMARY KEY,
+    project_id      TEXT,
+    name            TEXT,
+    pattern         TEXT,
+    purpose         TEXT,
+    constraints     TEXT,
+    assigned_agents TEXT,
+    explicit_paths  TEXT
+);
+CREATE INDEX idx_zones_project_id ON zones (project_id);
+
+CREATE TABLE agents (
+    id          TEXT PRIMARY KEY,
+    name        TEXT,
+    description TEXT,
+    prompt      TEXT
+);
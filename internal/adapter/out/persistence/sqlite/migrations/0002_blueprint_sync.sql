@@ -0,0 +1,4 @@
+-- Blueprint file binding and the content hashes recorded at the last sync.
+ALTER TABLE projects ADD COLUMN blueprint_file TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN blueprint_file_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE projects ADD COLUMN blueprint_store_hash TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,61 @@
+-- Move the JSON list columns into tables with one row per element.
+-- Empty values are empty lists. Values that are not JSON arrays, and elements of the wrong
+-- type, are rejected before this runs (see checkListColumns in migrate.go) instead of being
+-- coerced, so the operator can fix them.
+
+CREATE TABLE project_ignored_paths (
+    project_id TEXT NOT NULL,
+    position   INTEGER NOT NULL,
+    path       TEXT NOT NULL,
+    PRIMARY KEY (project_id, path)
+);
+
+CREATE TABLE zone_paths (
+    zone_id  TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    path     TEXT NOT NULL,
+    PRIMARY KEY (zone_id, path)
+);
+
+CREATE TABLE zone_constraints (
+    zone_id  TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    text     TEXT NOT NULL,
+    PRIMARY KEY (zone_id, position)
+);
+
+CREATE TABLE zone_agents (
+    zone_id     TEXT NOT NULL,
+    position    INTEGER NOT NULL,
+    agent_id    TEXT NOT NULL,
+    name        TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    prompt      TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (zone_id, position)
+);
+CREATE INDEX idx_zone_agents_agent_id ON zone_agents (agent_id);
+
+INSERT OR IGNORE INTO project_ignored_paths (project_id, position, path)
+SELECT p.id, j.key, j.value
+FROM projects p, json_each(nullif(p.ignored_paths, '')) j;
+
+INSERT OR IGNORE INTO zone_paths (zone_id, position, path)
+SELECT z.id, j.key, j.value
+FROM zones z, json_each(nullif(z.explicit_paths, '')) j;
+
+INSERT INTO zone_constraints (zone_id, position, text)
+SELECT z.id, j.key, j.value
+FROM zones z, json_each(nullif(z.constraints, '')) j;
+
+INSERT INTO zone_agents (zone_id, position, agent_id, name, description, prompt)
+SELECT z.id, j.key,
+       coalesce(json_extract(j.value, '$.ID'), ''),
+       coalesce(json_extract(j.value, '$.Name'), ''),
+       coalesce(json_extract(j.value, '$.Description'), ''),
+       coalesce(json_extract(j.value, '$.Prompt'), '')
+FROM zones z, json_each(nullif(z.assigned_agents, '')) j;
+
+ALTER TABLE projects DROP COLUMN ignored_paths;
+ALTER TABLE zones DROP COLUMN constraints;
+ALTER TABLE zones DROP COLUMN assigned_agents;
+ALTER TABLE zones DROP COLUMN explicit_paths;
@@ -0,0 +1,24 @@
+-- Zone assignments become references to agents instead of copies of them.
+-- Copies whose id no longer exists are matched to an agent by name; the rest are dropped.
+
+CREATE TABLE zone_agent_refs (
+    zone_id  TEXT NOT NULL,
+    agent_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (zone_id, agent_id)
+);
+
+INSERT OR IGNORE INTO zone_agent_refs (zone_id, agent_id, position)
+SELECT za.zone_id, a.id, za.position
+FROM zone_agents za JOIN agents a ON a.id = za.agent_id;
+
+INSERT OR IGNORE INTO zone_agent_refs (zone_id, agent_id, position)
+SELECT za.zone_id, (SELECT min(a.id) FROM agents a WHERE a.name = za.name), za.position
+FROM zone_agents za
+WHERE za.name <> ''
+  AND NOT EXISTS (SELECT 1 FROM agents a WHERE a.id = za.agent_id)
+  AND EXISTS (SELECT 1 FROM agents a WHERE a.name = za.name);
+
+DROP TABLE zone_agents;
+ALTER TABLE zone_agent_refs RENAME TO zone_agents;
+CREATE INDEX idx_zone_agents_agent_id ON zone_agents (agent_id);
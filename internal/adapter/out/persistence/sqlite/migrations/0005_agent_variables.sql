@@ -0,0 +1,10 @@
+-- Variables declared by an agent's prompt template.
+CREATE TABLE agent_variables (
+    agent_id      TEXT NOT NULL,
+    position      INTEGER NOT NULL,
+    name          TEXT NOT NULL,
+    description   TEXT NOT NULL DEFAULT '',
+    required      BOOLEAN NOT NULL DEFAULT 0,
+    default_value TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (agent_id, name)
+);
@@ -0,0 +1,16 @@
+-- File changes made through write_file and apply_patch.
+CREATE TABLE file_changes (
+    id            TEXT PRIMARY KEY,
+    project_id    TEXT NOT NULL,
+    agent_id      TEXT NOT NULL DEFAULT '',
+    path          TEXT NOT NULL,
+    operation     TEXT NOT NULL,
+    source        TEXT NOT NULL DEFAULT '',
+    old_hash      TEXT NOT NULL DEFAULT '',
+    new_hash      TEXT NOT NULL DEFAULT '',
+    lines_added   INTEGER NOT NULL DEFAULT 0,
+    lines_removed INTEGER NOT NULL DEFAULT 0,
+    created_at    DATETIME NOT NULL
+);
+
+CREATE INDEX idx_file_changes_project ON file_changes (project_id, created_at);
@@ -0,0 +1,6 @@
+-- Bearer tokens agents use to identify themselves on MCP sessions (sha256 hashes only).
+CREATE TABLE agent_tokens (
+    agent_id   TEXT PRIMARY KEY,
+    token_hash TEXT NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL
+);
@@ -0,0 +1,22 @@
+-- Tasks handed to agents, and the zones each task is routed to.
+CREATE TABLE tasks (
+    id          TEXT PRIMARY KEY,
+    project_id  TEXT NOT NULL,
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status      TEXT NOT NULL,
+    agent_id    TEXT NOT NULL DEFAULT '',
+    created_by  TEXT NOT NULL DEFAULT '',
+    result      TEXT NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL,
+    updated_at  DATETIME NOT NULL
+);
+
+CREATE INDEX idx_tasks_project ON tasks (project_id, created_at);
+
+CREATE TABLE task_zones (
+    task_id  TEXT NOT NULL,
+    zone_id  TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (task_id, zone_id)
+);
@@ -0,0 +1,10 @@
+-- Exclusive, expiring write leases on zones (at most one per zone).
+CREATE TABLE zone_leases (
+    zone_id     TEXT PRIMARY KEY,
+    project_id  TEXT NOT NULL,
+    agent_id    TEXT NOT NULL,
+    acquired_at DATETIME NOT NULL,
+    expires_at  DATETIME NOT NULL
+);
+
+CREATE INDEX idx_zone_leases_project ON zone_leases (project_id);
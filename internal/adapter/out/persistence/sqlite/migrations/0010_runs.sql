@@ -0,0 +1,27 @@
+-- Orchestration runs: tasks grouped into a dependency graph.
+CREATE TABLE runs (
+    id          TEXT PRIMARY KEY,
+    project_id  TEXT NOT NULL,
+    title       TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by  TEXT NOT NULL DEFAULT '',
+    created_at  DATETIME NOT NULL,
+    updated_at  DATETIME NOT NULL
+);
+
+CREATE INDEX idx_runs_project ON runs (project_id, created_at);
+
+CREATE TABLE run_tasks (
+    run_id   TEXT NOT NULL,
+    task_id  TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (run_id, task_id)
+);
+
+CREATE TABLE run_task_deps (
+    run_id     TEXT NOT NULL,
+    task_id    TEXT NOT NULL,
+    depends_on TEXT NOT NULL,
+    position   INTEGER NOT NULL,
+    PRIMARY KEY (run_id, task_id, depends_on)
+);
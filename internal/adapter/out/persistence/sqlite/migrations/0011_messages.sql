@@ -0,0 +1,17 @@
+-- Messages between agents, one row per recipient.
+CREATE TABLE messages (
+    id            TEXT PRIMARY KEY,
+    project_id    TEXT NOT NULL,
+    thread_id     TEXT NOT NULL DEFAULT '',
+    reply_to      TEXT NOT NULL DEFAULT '',
+    from_agent_id TEXT NOT NULL DEFAULT '',
+    to_agent_id   TEXT NOT NULL,
+    zone_id       TEXT NOT NULL DEFAULT '',
+    subject       TEXT NOT NULL DEFAULT '',
+    body          TEXT NOT NULL,
+    created_at    DATETIME NOT NULL,
+    acked_at      DATETIME
+);
+
+CREATE INDEX idx_messages_project ON messages (project_id, created_at);
+CREATE INDEX idx_messages_recipient ON messages (to_agent_id, created_at);
@@ -0,0 +1,24 @@
+-- Architecture decision records, and the zones each decision concerns.
+CREATE TABLE decisions (
+    id           TEXT PRIMARY KEY,
+    project_id   TEXT NOT NULL,
+    number       INTEGER NOT NULL,
+    title        TEXT NOT NULL,
+    context      TEXT NOT NULL DEFAULT '',
+    decision     TEXT NOT NULL DEFAULT '',
+    consequences TEXT NOT NULL DEFAULT '',
+    status       TEXT NOT NULL,
+    supersedes   TEXT NOT NULL DEFAULT '',
+    created_by   TEXT NOT NULL DEFAULT '',
+    created_at   DATETIME NOT NULL,
+    updated_at   DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_decisions_number ON decisions (project_id, number);
+
+CREATE TABLE decision_zones (
+    decision_id TEXT NOT NULL,
+    zone_id     TEXT NOT NULL,
+    position    INTEGER NOT NULL,
+    PRIMARY KEY (decision_id, zone_id)
+);
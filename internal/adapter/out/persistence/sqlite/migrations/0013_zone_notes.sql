@@ -0,0 +1,20 @@
+-- Informal notes agents keep about zones, and their tags.
+CREATE TABLE zone_notes (
+    id         TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    zone_id    TEXT NOT NULL,
+    agent_id   TEXT NOT NULL DEFAULT '',
+    text       TEXT NOT NULL,
+    pinned     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME
+);
+
+CREATE INDEX idx_zone_notes_project ON zone_notes (project_id, created_at);
+
+CREATE TABLE zone_note_tags (
+    note_id  TEXT NOT NULL,
+    tag      TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (note_id, tag)
+);
@@ -1,6 +1,10 @@
 package sqlite
 
-import "operators-mcp/internal/domain"
+import (
+	"time"
+
+	"operators-mcp/internal/domain"
+)
 
 // AgentModel is the GORM model for domain.Agent.
 type AgentModel struct {
@@ -13,89 +17,432 @@ type AgentModel struct {
 // TableName overrides the table name.
 func (AgentModel) TableName() string { return "agents" }
 
-// ToDomain converts the model to a domain.Agent.
-func (m *AgentModel) ToDomain() *domain.Agent {
+// ToDomain converts the model and its declared variables to a domain.Agent.
+func (m *AgentModel) ToDomain(vars []AgentVariableModel) *domain.Agent {
 	if m == nil {
 		return nil
 	}
-	return &domain.Agent{
+	a := &domain.Agent{
 		ID:          m.ID,
 		Name:        m.Name,
 		Description: m.Description,
 		Prompt:      m.Prompt,
 	}
+	for _, v := range vars {
+		a.Variables = append(a.Variables, domain.PromptVariable{Name: v.Name, Description: v.Description, Required: v.Required, Default: v.DefaultValue})
+	}
+	return a
+}
+
+// AgentVariableModel is one variable declared by an agent's prompt template.
+type AgentVariableModel struct {
+	AgentID      string `gorm:"column:agent_id;primaryKey"`
+	Position     int
+	Name         string `gorm:"primaryKey"`
+	Description  string
+	Required     bool
+	DefaultValue string `gorm:"column:default_value"`
 }
 
+// TableName overrides the table name.
+func (AgentVariableModel) TableName() string { return "agent_variables" }
+
 // ProjectModel is the GORM model for domain.Project.
 type ProjectModel struct {
-	ID           string `gorm:"primaryKey"`
-	Name         string
-	RootDir      string      `gorm:"column:root_dir"`
-	IgnoredPaths stringSlice `gorm:"column:ignored_paths"`
+	ID      string `gorm:"primaryKey"`
+	Name    string
+	RootDir string `gorm:"column:root_dir"`
+
+	BlueprintFile      string `gorm:"column:blueprint_file"`
+	BlueprintFileHash  string `gorm:"column:blueprint_file_hash"`
+	BlueprintStoreHash string `gorm:"column:blueprint_store_hash"`
 }
 
 // TableName overrides the table name.
 func (ProjectModel) TableName() string { return "projects" }
 
-// ToDomain converts the model to a domain.Project.
-func (m *ProjectModel) ToDomain() *domain.Project {
+// ToDomain converts the model and its ignored paths to a domain.Project.
+func (m *ProjectModel) ToDomain(ignored []string) *domain.Project {
 	if m == nil {
 		return nil
 	}
-	paths := []string(m.IgnoredPaths)
-	if paths == nil {
-		paths = []string{}
-	}
+	paths := append([]string{}, ignored...)
 	return &domain.Project{
-		ID:           m.ID,
-		Name:         m.Name,
-		RootDir:      m.RootDir,
-		IgnoredPaths: paths,
+		ID:                 m.ID,
+		Name:               m.Name,
+		RootDir:            m.RootDir,
+		IgnoredPaths:       paths,
+		BlueprintFile:      m.BlueprintFile,
+		BlueprintFileHash:  m.BlueprintFileHash,
+		BlueprintStoreHash: m.BlueprintStoreHash,
 	}
 }
 
 // ZoneModel is the GORM model for domain.Zone.
 type ZoneModel struct {
-	ID             string `gorm:"primaryKey"`
-	ProjectID      string `gorm:"column:project_id;index"`
-	Name           string
-	Pattern        string
-	Purpose        string
-	Constraints    stringSlice `gorm:"column:constraints"`
-	AssignedAgents agentSlice  `gorm:"column:assigned_agents"`
-	ExplicitPaths  stringSlice `gorm:"column:explicit_paths"`
+	ID        string `gorm:"primaryKey"`
+	ProjectID string `gorm:"column:project_id;index"`
+	Name      string
+	Pattern   string
+	Purpose   string
 }
 
 // TableName overrides the table name.
 func (ZoneModel) TableName() string { return "zones" }
 
-// ToDomain converts the model to a domain.Zone.
-func (m *ZoneModel) ToDomain() *domain.Zone {
+// ToDomain converts the model and its list rows to a domain.Zone.
+func (m *ZoneModel) ToDomain(l zoneLists) *domain.Zone {
 	if m == nil {
 		return nil
 	}
 	return &domain.Zone{
-		ID:             m.ID,
-		ProjectID:      m.ProjectID,
-		Name:           m.Name,
-		Pattern:        m.Pattern,
-		Purpose:        m.Purpose,
-		Constraints:    sliceOrNil([]string(m.Constraints)),
-		AssignedAgents: sliceAgentsOrNil([]domain.Agent(m.AssignedAgents)),
-		ExplicitPaths:  sliceOrNil([]string(m.ExplicitPaths)),
+		ID:            m.ID,
+		ProjectID:     m.ProjectID,
+		Name:          m.Name,
+		Pattern:       m.Pattern,
+		Purpose:       m.Purpose,
+		Constraints:   l.constraints,
+		AgentIDs:      l.agentIDs,
+		ExplicitPaths: l.paths,
 	}
 }
 
-func sliceOrNil(s []string) []string {
-	if s == nil {
-		return nil
+// ProjectIgnoredPathModel is one entry of a project's ignored paths.
+type ProjectIgnoredPathModel struct {
+	ProjectID string `gorm:"column:project_id;primaryKey"`
+	Position  int
+	Path      string `gorm:"primaryKey"`
+}
+
+// TableName overrides the table name.
+func (ProjectIgnoredPathModel) TableName() string { return "project_ignored_paths" }
+
+// ZonePathModel is one of a zone's explicit paths.
+type ZonePathModel struct {
+	ZoneID   string `gorm:"column:zone_id;primaryKey"`
+	Position int
+	Path     string `gorm:"primaryKey"`
+}
+
+// TableName overrides the table name.
+func (ZonePathModel) TableName() string { return "zone_paths" }
+
+// ZoneConstraintModel is one of a zone's constraints.
+type ZoneConstraintModel struct {
+	ZoneID   string `gorm:"column:zone_id;primaryKey"`
+	Position int    `gorm:"primaryKey;autoIncrement:false"`
+	Text     string
+}
+
+// TableName overrides the table name.
+func (ZoneConstraintModel) TableName() string { return "zone_constraints" }
+
+// ZoneAgentModel references an agent assigned to a zone.
+type ZoneAgentModel struct {
+	ZoneID   string `gorm:"column:zone_id;primaryKey"`
+	AgentID  string `gorm:"column:agent_id;primaryKey"`
+	Position int
+}
+
+// TableName overrides the table name.
+func (ZoneAgentModel) TableName() string { return "zone_agents" }
+
+// zoneLists holds a zone's list-valued fields loaded from their tables.
+type zoneLists struct {
+	paths       []string
+	constraints []string
+	agentIDs    []string
+}
+
+// FileChangeModel is the GORM model for domain.FileChange.
+type FileChangeModel struct {
+	ID           string `gorm:"primaryKey"`
+	ProjectID    string `gorm:"column:project_id"`
+	AgentID      string `gorm:"column:agent_id"`
+	Path         string
+	Operation    string
+	Source       string
+	OldHash      string `gorm:"column:old_hash"`
+	NewHash      string `gorm:"column:new_hash"`
+	LinesAdded   int    `gorm:"column:lines_added"`
+	LinesRemoved int    `gorm:"column:lines_removed"`
+	CreatedAt    time.Time
+}
+
+// TableName overrides the table name.
+func (FileChangeModel) TableName() string { return "file_changes" }
+
+// ToDomain converts the model to a domain.FileChange.
+func (m *FileChangeModel) ToDomain() *domain.FileChange {
+	return &domain.FileChange{
+		ID:           m.ID,
+		ProjectID:    m.ProjectID,
+		AgentID:      m.AgentID,
+		Path:         m.Path,
+		Operation:    m.Operation,
+		Source:       m.Source,
+		OldHash:      m.OldHash,
+		NewHash:      m.NewHash,
+		LinesAdded:   m.LinesAdded,
+		LinesRemoved: m.LinesRemoved,
+		CreatedAt:    m.CreatedAt,
 	}
-	return append([]string(nil), s...)
 }
 
-func sliceAgentsOrNil(a []domain.Agent) []domain.Agent {
-	if a == nil {
-		return nil
+// AgentTokenModel is the GORM model for an agent's token hash.
+type AgentTokenModel struct {
+	AgentID   string `gorm:"column:agent_id;primaryKey"`
+	TokenHash string `gorm:"column:token_hash"`
+	CreatedAt time.Time
+}
+
+// TableName overrides the table name.
+func (AgentTokenModel) TableName() string { return "agent_tokens" }
+
+// TaskModel is the GORM model for domain.Task.
+type TaskModel struct {
+	ID          string `gorm:"primaryKey"`
+	ProjectID   string `gorm:"column:project_id"`
+	Title       string
+	Description string
+	Status      string
+	AgentID     string `gorm:"column:agent_id"`
+	CreatedBy   string `gorm:"column:created_by"`
+	Result      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TableName overrides the table name.
+func (TaskModel) TableName() string { return "tasks" }
+
+// ToDomain converts the model and its zone ids to a domain.Task.
+func (m *TaskModel) ToDomain(zoneIDs []string) *domain.Task {
+	return &domain.Task{
+		ID:          m.ID,
+		ProjectID:   m.ProjectID,
+		Title:       m.Title,
+		Description: m.Description,
+		Status:      m.Status,
+		ZoneIDs:     append([]string{}, zoneIDs...),
+		AgentID:     m.AgentID,
+		CreatedBy:   m.CreatedBy,
+		Result:      m.Result,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// TaskZoneModel references a zone a task is routed to.
+type TaskZoneModel struct {
+	TaskID   string `gorm:"column:task_id;primaryKey"`
+	ZoneID   string `gorm:"column:zone_id;primaryKey"`
+	Position int
+}
+
+// TableName overrides the table name.
+func (TaskZoneModel) TableName() string { return "task_zones" }
+
+// ZoneLeaseModel is the GORM model for domain.ZoneLease.
+type ZoneLeaseModel struct {
+	ZoneID     string `gorm:"column:zone_id;primaryKey"`
+	ProjectID  string `gorm:"column:project_id"`
+	AgentID    string `gorm:"column:agent_id"`
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+// TableName overrides the table name.
+func (ZoneLeaseModel) TableName() string { return "zone_leases" }
+
+// ToDomain converts the model to a domain.ZoneLease.
+func (m *ZoneLeaseModel) ToDomain() *domain.ZoneLease {
+	return &domain.ZoneLease{
+		ZoneID:     m.ZoneID,
+		ProjectID:  m.ProjectID,
+		AgentID:    m.AgentID,
+		AcquiredAt: m.AcquiredAt,
+		ExpiresAt:  m.ExpiresAt,
 	}
-	return append([]domain.Agent(nil), a...)
 }
+
+// RunModel is the GORM model for domain.Run.
+type RunModel struct {
+	ID          string `gorm:"primaryKey"`
+	ProjectID   string `gorm:"column:project_id"`
+	Title       string
+	Description string
+	CreatedBy   string `gorm:"column:created_by"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TableName overrides the table name.
+func (RunModel) TableName() string { return "runs" }
+
+// ToDomain converts the model and its task graph to a domain.Run.
+func (m *RunModel) ToDomain(tasks []domain.RunTask) *domain.Run {
+	if tasks == nil {
+		tasks = []domain.RunTask{}
+	}
+	return &domain.Run{
+		ID:          m.ID,
+		ProjectID:   m.ProjectID,
+		Title:       m.Title,
+		Description: m.Description,
+		Tasks:       tasks,
+		CreatedBy:   m.CreatedBy,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// RunTaskModel references a task of a run.
+type RunTaskModel struct {
+	RunID    string `gorm:"column:run_id;primaryKey"`
+	TaskID   string `gorm:"column:task_id;primaryKey"`
+	Position int
+}
+
+// TableName overrides the table name.
+func (RunTaskModel) TableName() string { return "run_tasks" }
+
+// RunTaskDepModel records that a task of a run depends on another task of the run.
+type RunTaskDepModel struct {
+	RunID     string `gorm:"column:run_id;primaryKey"`
+	TaskID    string `gorm:"column:task_id;primaryKey"`
+	DependsOn string `gorm:"column:depends_on;primaryKey"`
+	Position  int
+}
+
+// TableName overrides the table name.
+func (RunTaskDepModel) TableName() string { return "run_task_deps" }
+
+// MessageModel is the GORM model for domain.Message. AckedAt is NULL until acknowledged.
+type MessageModel struct {
+	ID          string `gorm:"primaryKey"`
+	ProjectID   string `gorm:"column:project_id"`
+	ThreadID    string `gorm:"column:thread_id"`
+	ReplyTo     string `gorm:"column:reply_to"`
+	FromAgentID string `gorm:"column:from_agent_id"`
+	ToAgentID   string `gorm:"column:to_agent_id"`
+	ZoneID      string `gorm:"column:zone_id"`
+	Subject     string
+	Body        string
+	CreatedAt   time.Time
+	AckedAt     *time.Time
+}
+
+// TableName overrides the table name.
+func (MessageModel) TableName() string { return "messages" }
+
+// ToDomain converts the model to a domain.Message.
+func (m *MessageModel) ToDomain() *domain.Message {
+	msg := &domain.Message{
+		ID:          m.ID,
+		ProjectID:   m.ProjectID,
+		ThreadID:    m.ThreadID,
+		ReplyTo:     m.ReplyTo,
+		FromAgentID: m.FromAgentID,
+		ToAgentID:   m.ToAgentID,
+		ZoneID:      m.ZoneID,
+		Subject:     m.Subject,
+		Body:        m.Body,
+		CreatedAt:   m.CreatedAt,
+	}
+	if m.AckedAt != nil {
+		msg.AckedAt = *m.AckedAt
+	}
+	return msg
+}
+
+// DecisionModel is the GORM model for domain.Decision.
+type DecisionModel struct {
+	ID           string `gorm:"primaryKey"`
+	ProjectID    string `gorm:"column:project_id"`
+	Number       int
+	Title        string
+	Context      string
+	Decision     string
+	Consequences string
+	Status       string
+	Supersedes   string
+	CreatedBy    string `gorm:"column:created_by"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// TableName overrides the table name.
+func (DecisionModel) TableName() string { return "decisions" }
+
+// ToDomain converts the model and its zone ids to a domain.Decision.
+func (m *DecisionModel) ToDomain(zoneIDs []string) *domain.Decision {
+	return &domain.Decision{
+		ID:           m.ID,
+		ProjectID:    m.ProjectID,
+		Number:       m.Number,
+		Title:        m.Title,
+		Context:      m.Context,
+		Decision:     m.Decision,
+		Consequences: m.Consequences,
+		Status:       m.Status,
+		ZoneIDs:      append([]string{}, zoneIDs...),
+		Supersedes:   m.Supersedes,
+		CreatedBy:    m.CreatedBy,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+// DecisionZoneModel references a zone a decision concerns.
+type DecisionZoneModel struct {
+	DecisionID string `gorm:"column:decision_id;primaryKey"`
+	ZoneID     string `gorm:"column:zone_id;primaryKey"`
+	Position   int
+}
+
+// TableName overrides the table name.
+func (DecisionZoneModel) TableName() string { return "decision_zones" }
+
+// ZoneNoteModel is the GORM model for domain.ZoneNote. ExpiresAt is NULL for pinned notes.
+type ZoneNoteModel struct {
+	ID        string `gorm:"primaryKey"`
+	ProjectID string `gorm:"column:project_id"`
+	ZoneID    string `gorm:"column:zone_id"`
+	AgentID   string `gorm:"column:agent_id"`
+	Text      string
+	Pinned    bool
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+// TableName overrides the table name.
+func (ZoneNoteModel) TableName() string { return "zone_notes" }
+
+// ToDomain converts the model and its tags to a domain.ZoneNote.
+func (m *ZoneNoteModel) ToDomain(tags []string) *domain.ZoneNote {
+	n := &domain.ZoneNote{
+		ID:        m.ID,
+		ProjectID: m.ProjectID,
+		ZoneID:    m.ZoneID,
+		AgentID:   m.AgentID,
+		Text:      m.Text,
+		Tags:      append([]string{}, tags...),
+		Pinned:    m.Pinned,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.ExpiresAt != nil {
+		n.ExpiresAt = *m.ExpiresAt
+	}
+	return n
+}
+
+// ZoneNoteTagModel is a tag of a zone note.
+type ZoneNoteTagModel struct {
+	NoteID   string `gorm:"column:note_id;primaryKey"`
+	Tag      string `gorm:"primaryKey"`
+	Position int
+}
+
+// TableName overrides the table name.
+func (ZoneNoteTagModel) TableName() string { return "zone_note_tags" }
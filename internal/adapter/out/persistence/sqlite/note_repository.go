@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Ensure NoteRepository implements ports.NoteRepository at compile time.
+var _ ports.NoteRepository = (*NoteRepository)(nil)
+
+// NoteRepository persists zone notes in SQLite via GORM.
+type NoteRepository struct {
+	db *gorm.DB
+}
+
+// NewNoteRepository returns a new note repository.
+func NewNoteRepository(db *gorm.DB) *NoteRepository {
+	return &NoteRepository{db: db}
+}
+
+// Get returns the note by id, or nil if not found.
+func (r *NoteRepository) Get(id string) *domain.ZoneNote {
+	n, err := r.load(r.db, id)
+	if err != nil {
+		return nil
+	}
+	return n
+}
+
+// List returns the project's notes oldest first.
+func (r *NoteRepository) List(projectID string) []*domain.ZoneNote {
+	var models []ZoneNoteModel
+	if err := r.db.Where("project_id = ?", projectID).Order("created_at, rowid").Find(&models).Error; err != nil {
+		return nil
+	}
+	ids := make([]string, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+	tags, err := loadNoteTags(r.db, ids)
+	if err != nil {
+		return nil
+	}
+	out := make([]*domain.ZoneNote, len(models))
+	for i := range models {
+		out[i] = models[i].ToDomain(tags[models[i].ID])
+	}
+	return out
+}
+
+// Create stores n with a generated id.
+func (r *NoteRepository) Create(n *domain.ZoneNote) (*domain.ZoneNote, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	m := &ZoneNoteModel{
+		ID:        id,
+		ProjectID: n.ProjectID,
+		ZoneID:    n.ZoneID,
+		AgentID:   n.AgentID,
+		Text:      n.Text,
+		Pinned:    n.Pinned,
+		CreatedAt: n.CreatedAt.UTC(),
+	}
+	if !n.ExpiresAt.IsZero() {
+		expires := n.ExpiresAt.UTC()
+		m.ExpiresAt = &expires
+	}
+	var out *domain.ZoneNote
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if err := saveNoteTags(tx, id, n.Tags); err != nil {
+			return err
+		}
+		out, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete removes a note and its tags by id.
+func (r *NoteRepository) Delete(id string) error {
+	if _, err := r.load(r.db, id); err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("note_id = ?", id).Delete(&ZoneNoteTagModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&ZoneNoteModel{ID: id}).Error
+	})
+}
+
+// DeleteByProject removes all notes of a project.
+func (r *NoteRepository) DeleteByProject(projectID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		sub := tx.Model(&ZoneNoteModel{}).Select("id").Where("project_id = ?", projectID)
+		if err := tx.Where("note_id IN (?)", sub).Delete(&ZoneNoteTagModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("project_id = ?", projectID).Delete(&ZoneNoteModel{}).Error
+	})
+}
+
+// load reads a note with its tags.
+func (r *NoteRepository) load(db *gorm.DB, id string) (*domain.ZoneNote, error) {
+	var m ZoneNoteModel
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &domain.StructuredError{Code: "NOTE_NOT_FOUND", Message: "note not found"}
+		}
+		return nil, err
+	}
+	tags, err := loadNoteTags(db, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(tags[id]), nil
+}
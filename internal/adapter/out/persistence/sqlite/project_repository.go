@@ -22,14 +22,11 @@ func NewProjectRepository(db *gorm.DB) *ProjectRepository {
 
 // Get returns the project by id, or nil if not found.
 func (r *ProjectRepository) Get(id string) *domain.Project {
-	var m ProjectModel
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil
-		}
+	p, err := r.load(r.db, id)
+	if err != nil {
 		return nil
 	}
-	return m.ToDomain()
+	return p
 }
 
 // List returns all projects.
@@ -38,9 +35,17 @@ func (r *ProjectRepository) List() []*domain.Project {
 	if err := r.db.Find(&models).Error; err != nil {
 		return nil
 	}
+	ids := make([]string, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+	ignored, err := loadIgnoredPaths(r.db, ids)
+	if err != nil {
+		return nil
+	}
 	out := make([]*domain.Project, 0, len(models))
 	for i := range models {
-		out = append(out, models[i].ToDomain())
+		out = append(out, models[i].ToDomain(ignored[models[i].ID]))
 	}
 	return out
 }
@@ -55,26 +60,18 @@ func (r *ProjectRepository) Create(name, rootDir string) (*domain.Project, error
 		return nil, err
 	}
 	m := &ProjectModel{
-		ID:           id,
-		Name:         name,
-		RootDir:      rootDir,
-		IgnoredPaths: stringSlice{},
+		ID:      id,
+		Name:    name,
+		RootDir: rootDir,
 	}
 	if err := r.db.Create(m).Error; err != nil {
 		return nil, err
 	}
-	return m.ToDomain(), nil
+	return m.ToDomain(nil), nil
 }
 
 // Update updates a project by id.
 func (r *ProjectRepository) Update(id, name, rootDir string) (*domain.Project, error) {
-	var m ProjectModel
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
-		}
-		return nil, err
-	}
 	updates := map[string]interface{}{}
 	if name != "" {
 		updates["name"] = name
@@ -82,19 +79,10 @@ func (r *ProjectRepository) Update(id, name, rootDir string) (*domain.Project, e
 	if rootDir != "" {
 		updates["root_dir"] = rootDir
 	}
-	if len(updates) > 0 {
-		if err := r.db.Model(&m).Updates(updates).Error; err != nil {
-			return nil, err
-		}
-	}
-	// Reload to get updated row
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		return nil, err
-	}
-	return m.ToDomain(), nil
+	return r.update(id, updates)
 }
 
-// Delete removes a project by id. Caller should delete zones for the project first (e.g. via ZoneRepository.DeleteByProject).
+// Delete removes a project and its ignored paths by id. Caller should delete zones for the project first (e.g. via ZoneRepository.DeleteByProject).
 func (r *ProjectRepository) Delete(projectID string) error {
 	var m ProjectModel
 	if err := r.db.First(&m, "id = ?", projectID).Error; err != nil {
@@ -103,10 +91,12 @@ func (r *ProjectRepository) Delete(projectID string) error {
 		}
 		return err
 	}
-	if err := r.db.Delete(&m).Error; err != nil {
-		return err
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", projectID).Delete(&ProjectIgnoredPathModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&m).Error
+	})
 }
 
 // AddIgnoredPath adds path to the project's ignored list (no-op if already present).
@@ -115,44 +105,85 @@ func (r *ProjectRepository) AddIgnoredPath(projectID, path string) (*domain.Proj
 	if path == "" {
 		return nil, &domain.StructuredError{Code: "INVALID_PATH", Message: "path is required"}
 	}
-	var m ProjectModel
-	if err := r.db.First(&m, "id = ?", projectID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	return r.modifyIgnored(projectID, func(paths []string) []string {
+		for _, ig := range paths {
+			if ig == path {
+				return paths
+			}
 		}
-		return nil, err
-	}
-	for _, ig := range m.IgnoredPaths {
-		if ig == path {
-			return m.ToDomain(), nil
-		}
-	}
-	m.IgnoredPaths = append(m.IgnoredPaths, path)
-	if err := r.db.Model(&m).Update("ignored_paths", m.IgnoredPaths).Error; err != nil {
-		return nil, err
-	}
-	return m.ToDomain(), nil
+		return append(paths, path)
+	})
 }
 
 // RemoveIgnoredPath removes path from the project's ignored list.
 func (r *ProjectRepository) RemoveIgnoredPath(projectID, path string) (*domain.Project, error) {
 	path = domain.NormalizePath(path)
+	return r.modifyIgnored(projectID, func(paths []string) []string {
+		var filtered []string
+		for _, ig := range paths {
+			if ig != path {
+				filtered = append(filtered, ig)
+			}
+		}
+		return filtered
+	})
+}
+
+// SetBlueprintSync sets the project's blueprint file binding and last-sync hashes.
+// An empty file removes the binding.
+func (r *ProjectRepository) SetBlueprintSync(projectID, file, fileHash, storeHash string) (*domain.Project, error) {
+	return r.update(projectID, map[string]interface{}{
+		"blueprint_file":       file,
+		"blueprint_file_hash":  fileHash,
+		"blueprint_store_hash": storeHash,
+	})
+}
+
+// load reads a project with its ignored paths.
+func (r *ProjectRepository) load(db *gorm.DB, id string) (*domain.Project, error) {
 	var m ProjectModel
-	if err := r.db.First(&m, "id = ?", projectID).Error; err != nil {
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
 		}
 		return nil, err
 	}
-	var filtered []string
-	for _, ig := range m.IgnoredPaths {
-		if ig != path {
-			filtered = append(filtered, ig)
+	ignored, err := loadIgnoredPaths(db, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(ignored[id]), nil
+}
+
+// update applies column updates to a project and returns the reloaded project.
+func (r *ProjectRepository) update(id string, updates map[string]interface{}) (*domain.Project, error) {
+	if _, err := r.load(r.db, id); err != nil {
+		return nil, err
+	}
+	if len(updates) > 0 {
+		if err := r.db.Model(&ProjectModel{ID: id}).Updates(updates).Error; err != nil {
+			return nil, err
 		}
 	}
-	m.IgnoredPaths = stringSlice(filtered)
-	if err := r.db.Model(&m).Update("ignored_paths", m.IgnoredPaths).Error; err != nil {
+	return r.load(r.db, id)
+}
+
+// modifyIgnored rewrites a project's ignored paths with fn in one transaction.
+func (r *ProjectRepository) modifyIgnored(id string, fn func(paths []string) []string) (*domain.Project, error) {
+	var p *domain.Project
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		cur, err := r.load(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := saveIgnoredPaths(tx, id, fn(cur.IgnoredPaths)); err != nil {
+			return err
+		}
+		p, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-	return m.ToDomain(), nil
+	return p, nil
 }
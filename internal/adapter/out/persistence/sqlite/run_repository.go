@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Ensure RunRepository implements ports.RunRepository at compile time.
+var _ ports.RunRepository = (*RunRepository)(nil)
+
+// RunRepository persists runs in SQLite via GORM.
+type RunRepository struct {
+	db *gorm.DB
+}
+
+// NewRunRepository returns a new run repository.
+func NewRunRepository(db *gorm.DB) *RunRepository {
+	return &RunRepository{db: db}
+}
+
+// Get returns the run by id, or nil if not found.
+func (r *RunRepository) Get(id string) *domain.Run {
+	run, err := r.load(r.db, id)
+	if err != nil {
+		return nil
+	}
+	return run
+}
+
+// List returns the project's runs oldest first.
+func (r *RunRepository) List(projectID string) []*domain.Run {
+	var models []RunModel
+	if err := r.db.Where("project_id = ?", projectID).Order("created_at, rowid").Find(&models).Error; err != nil {
+		return nil
+	}
+	ids := make([]string, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+	tasks, err := loadRunTasks(r.db, ids)
+	if err != nil {
+		return nil
+	}
+	out := make([]*domain.Run, len(models))
+	for i := range models {
+		out[i] = models[i].ToDomain(tasks[models[i].ID])
+	}
+	return out
+}
+
+// Create stores run with a generated id.
+func (r *RunRepository) Create(run *domain.Run) (*domain.Run, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	m := runModel(run)
+	m.ID = id
+	var out *domain.Run
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if err := saveRunTasks(tx, id, run.Tasks); err != nil {
+			return err
+		}
+		out, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces every field of an existing run.
+func (r *RunRepository) Update(run *domain.Run) (*domain.Run, error) {
+	var out *domain.Run
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := r.load(tx, run.ID); err != nil {
+			return err
+		}
+		if err := tx.Save(runModel(run)).Error; err != nil {
+			return err
+		}
+		if err := saveRunTasks(tx, run.ID, run.Tasks); err != nil {
+			return err
+		}
+		var err error
+		out, err = r.load(tx, run.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete removes a run and its task graph by id. The tasks themselves are kept.
+func (r *RunRepository) Delete(id string) error {
+	if _, err := r.load(r.db, id); err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveRunTasks(tx, id, nil); err != nil {
+			return err
+		}
+		return tx.Delete(&RunModel{ID: id}).Error
+	})
+}
+
+// DeleteByProject removes all runs of a project.
+func (r *RunRepository) DeleteByProject(projectID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		sub := tx.Model(&RunModel{}).Select("id").Where("project_id = ?", projectID)
+		if err := tx.Where("run_id IN (?)", sub).Delete(&RunTaskDepModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("run_id IN (?)", sub).Delete(&RunTaskModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("project_id = ?", projectID).Delete(&RunModel{}).Error
+	})
+}
+
+// load reads a run with its task graph.
+func (r *RunRepository) load(db *gorm.DB, id string) (*domain.Run, error) {
+	var m RunModel
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &domain.StructuredError{Code: "RUN_NOT_FOUND", Message: "run not found"}
+		}
+		return nil, err
+	}
+	tasks, err := loadRunTasks(db, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(tasks[id]), nil
+}
+
+func runModel(run *domain.Run) *RunModel {
+	return &RunModel{
+		ID:          run.ID,
+		ProjectID:   run.ProjectID,
+		Title:       run.Title,
+		Description: run.Description,
+		CreatedBy:   run.CreatedBy,
+		CreatedAt:   run.CreatedAt.UTC(),
+		UpdatedAt:   run.UpdatedAt.UTC(),
+	}
+}
@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// Ensure TaskRepository implements ports.TaskRepository at compile time.
+var _ ports.TaskRepository = (*TaskRepository)(nil)
+
+// TaskRepository persists tasks in SQLite via GORM.
+type TaskRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskRepository returns a new task repository.
+func NewTaskRepository(db *gorm.DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+// Get returns the task by id, or nil if not found.
+func (r *TaskRepository) Get(id string) *domain.Task {
+	t, err := r.load(r.db, id)
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+// List returns the project's tasks oldest first.
+func (r *TaskRepository) List(projectID string) []*domain.Task {
+	var models []TaskModel
+	if err := r.db.Where("project_id = ?", projectID).Order("created_at, rowid").Find(&models).Error; err != nil {
+		return nil
+	}
+	ids := make([]string, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+	zones, err := loadTaskZones(r.db, ids)
+	if err != nil {
+		return nil
+	}
+	out := make([]*domain.Task, len(models))
+	for i := range models {
+		out[i] = models[i].ToDomain(zones[models[i].ID])
+	}
+	return out
+}
+
+// Create stores t with a generated id.
+func (r *TaskRepository) Create(t *domain.Task) (*domain.Task, error) {
+	id, err := genID()
+	if err != nil {
+		return nil, err
+	}
+	m := taskModel(t)
+	m.ID = id
+	var out *domain.Task
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if err := saveTaskZones(tx, id, t.ZoneIDs); err != nil {
+			return err
+		}
+		out, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update replaces every field of an existing task.
+func (r *TaskRepository) Update(t *domain.Task) (*domain.Task, error) {
+	var out *domain.Task
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := r.load(tx, t.ID); err != nil {
+			return err
+		}
+		if err := tx.Save(taskModel(t)).Error; err != nil {
+			return err
+		}
+		if err := saveTaskZones(tx, t.ID, t.ZoneIDs); err != nil {
+			return err
+		}
+		var err error
+		out, err = r.load(tx, t.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete removes a task and its zone references by id.
+func (r *TaskRepository) Delete(id string) error {
+	if _, err := r.load(r.db, id); err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("task_id = ?", id).Delete(&TaskZoneModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&TaskModel{ID: id}).Error
+	})
+}
+
+// DeleteByProject removes all tasks of a project.
+func (r *TaskRepository) DeleteByProject(projectID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		sub := tx.Model(&TaskModel{}).Select("id").Where("project_id = ?", projectID)
+		if err := tx.Where("task_id IN (?)", sub).Delete(&TaskZoneModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("project_id = ?", projectID).Delete(&TaskModel{}).Error
+	})
+}
+
+// load reads a task with its zone ids.
+func (r *TaskRepository) load(db *gorm.DB, id string) (*domain.Task, error) {
+	var m TaskModel
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &domain.StructuredError{Code: "TASK_NOT_FOUND", Message: "task not found"}
+		}
+		return nil, err
+	}
+	zones, err := loadTaskZones(db, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(zones[id]), nil
+}
+
+func taskModel(t *domain.Task) *TaskModel {
+	return &TaskModel{
+		ID:          t.ID,
+		ProjectID:   t.ProjectID,
+		Title:       t.Title,
+		Description: t.Description,
+		Status:      t.Status,
+		AgentID:     t.AgentID,
+		CreatedBy:   t.CreatedBy,
+		Result:      t.Result,
+		CreatedAt:   t.CreatedAt.UTC(),
+		UpdatedAt:   t.UpdatedAt.UTC(),
+	}
+}
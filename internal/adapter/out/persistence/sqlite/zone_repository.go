@@ -22,14 +22,11 @@ func NewZoneRepository(db *gorm.DB) *ZoneRepository {
 
 // Get returns the zone by id, or nil if not found.
 func (r *ZoneRepository) Get(id string) *domain.Zone {
-	var m ZoneModel
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil
-		}
+	z, err := r.load(r.db, id)
+	if err != nil {
 		return nil
 	}
-	return m.ToDomain()
+	return z
 }
 
 // ListByProject returns all zones for the given project.
@@ -38,15 +35,23 @@ func (r *ZoneRepository) ListByProject(projectID string) []*domain.Zone {
 	if err := r.db.Where("project_id = ?", projectID).Find(&models).Error; err != nil {
 		return nil
 	}
+	ids := make([]string, len(models))
+	for i := range models {
+		ids[i] = models[i].ID
+	}
+	lists, err := loadZoneLists(r.db, ids)
+	if err != nil {
+		return nil
+	}
 	out := make([]*domain.Zone, 0, len(models))
 	for i := range models {
-		out = append(out, models[i].ToDomain())
+		out = append(out, models[i].ToDomain(lists[models[i].ID]))
 	}
 	return out
 }
 
 // Create creates a zone in the given project and returns it with generated id.
-func (r *ZoneRepository) Create(projectID, name, pattern, purpose string, constraints []string, agents []domain.Agent) (*domain.Zone, error) {
+func (r *ZoneRepository) Create(projectID, name, pattern, purpose string, constraints []string, agentIDs []string) (*domain.Zone, error) {
 	if name == "" {
 		return nil, &domain.StructuredError{Code: "INVALID_NAME", Message: "zone name is required"}
 	}
@@ -54,82 +59,144 @@ func (r *ZoneRepository) Create(projectID, name, pattern, purpose string, constr
 	if err != nil {
 		return nil, err
 	}
-	agentsCopy := cloneAgents(agents)
-	if agentsCopy == nil {
-		agentsCopy = []domain.Agent{}
-	}
 	m := &ZoneModel{
-		ID:             id,
-		ProjectID:      projectID,
-		Name:           name,
-		Pattern:        pattern,
-		Purpose:        purpose,
-		Constraints:    stringSlice(append([]string(nil), constraints...)),
-		AssignedAgents: agentSlice(agentsCopy),
-		ExplicitPaths:  nil,
-	}
-	if err := r.db.Create(m).Error; err != nil {
+		ID:        id,
+		ProjectID: projectID,
+		Name:      name,
+		Pattern:   pattern,
+		Purpose:   purpose,
+	}
+	var z *domain.Zone
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if err := saveZoneConstraints(tx, id, constraints); err != nil {
+			return err
+		}
+		if err := saveZoneAgents(tx, id, agentIDs); err != nil {
+			return err
+		}
+		z, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-	return m.ToDomain(), nil
+	return z, nil
 }
 
 // Update updates a zone by id.
-func (r *ZoneRepository) Update(id, name, pattern, purpose string, constraints []string, agents []domain.Agent) (*domain.Zone, error) {
-	var m ZoneModel
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
-		}
-		return nil, err
-	}
+func (r *ZoneRepository) Update(id, name, pattern, purpose string, constraints []string, agentIDs []string) (*domain.Zone, error) {
 	updates := map[string]interface{}{
-		"pattern":         pattern,
-		"purpose":         purpose,
-		"constraints":     stringSlice(append([]string(nil), constraints...)),
-		"assigned_agents": agentSlice(cloneAgents(agents)),
+		"pattern": pattern,
+		"purpose": purpose,
 	}
 	if name != "" {
 		updates["name"] = name
 	}
-	if err := r.db.Model(&m).Updates(updates).Error; err != nil {
-		return nil, err
-	}
-	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
-		return nil, err
-	}
-	return m.ToDomain(), nil
+	return r.modify(id, func(tx *gorm.DB, _ *domain.Zone) error {
+		if err := tx.Model(&ZoneModel{ID: id}).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := saveZoneConstraints(tx, id, constraints); err != nil {
+			return err
+		}
+		return saveZoneAgents(tx, id, agentIDs)
+	})
 }
 
 // AssignPath adds path to zone's explicit paths.
 func (r *ZoneRepository) AssignPath(zoneID, path string) (*domain.Zone, error) {
+	return r.modify(zoneID, func(tx *gorm.DB, z *domain.Zone) error {
+		for _, p := range z.ExplicitPaths {
+			if p == path {
+				return nil
+			}
+		}
+		return saveZonePaths(tx, zoneID, append(z.ExplicitPaths, path))
+	})
+}
+
+// UnassignPath removes path from zone's explicit paths (no-op if absent).
+func (r *ZoneRepository) UnassignPath(zoneID, path string) (*domain.Zone, error) {
+	return r.modify(zoneID, func(tx *gorm.DB, z *domain.Zone) error {
+		var filtered []string
+		for _, p := range z.ExplicitPaths {
+			if p != path {
+				filtered = append(filtered, p)
+			}
+		}
+		return saveZonePaths(tx, zoneID, filtered)
+	})
+}
+
+// Delete removes a zone and its list rows by id.
+func (r *ZoneRepository) Delete(id string) error {
 	var m ZoneModel
-	if err := r.db.First(&m, "id = ?", zoneID).Error; err != nil {
+	if err := r.db.First(&m, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+			return &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
 		}
-		return nil, err
+		return err
 	}
-	for _, p := range m.ExplicitPaths {
-		if p == path {
-			return m.ToDomain(), nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteZoneLists(tx, []string{id}); err != nil {
+			return err
 		}
-	}
-	m.ExplicitPaths = append(m.ExplicitPaths, path)
-	if err := r.db.Model(&m).Update("explicit_paths", m.ExplicitPaths).Error; err != nil {
-		return nil, err
-	}
-	return m.ToDomain(), nil
+		return tx.Delete(&m).Error
+	})
 }
 
 // DeleteByProject deletes all zones for the given project.
 func (r *ZoneRepository) DeleteByProject(projectID string) error {
-	return r.db.Where("project_id = ?", projectID).Delete(&ZoneModel{}).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		if err := tx.Model(&ZoneModel{}).Where("project_id = ?", projectID).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := deleteZoneLists(tx, ids); err != nil {
+			return err
+		}
+		return tx.Where("project_id = ?", projectID).Delete(&ZoneModel{}).Error
+	})
 }
 
-func cloneAgents(a []domain.Agent) []domain.Agent {
-	if a == nil {
-		return nil
+// load reads a zone with its list rows.
+func (r *ZoneRepository) load(db *gorm.DB, id string) (*domain.Zone, error) {
+	var m ZoneModel
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+		}
+		return nil, err
+	}
+	lists, err := loadZoneLists(db, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(lists[id]), nil
+}
+
+// modify runs fn on the current zone inside a transaction and returns the reloaded zone.
+func (r *ZoneRepository) modify(id string, fn func(tx *gorm.DB, z *domain.Zone) error) (*domain.Zone, error) {
+	var z *domain.Zone
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		cur, err := r.load(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(tx, cur); err != nil {
+			return err
+		}
+		z, err = r.load(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return append([]domain.Agent(nil), a...)
+	return z, nil
 }
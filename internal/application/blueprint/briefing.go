@@ -0,0 +1,311 @@
+package blueprint
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"operators-mcp/internal/domain"
+)
+
+// charsPerToken is the ratio used to estimate token counts from text length.
+const charsPerToken = 4
+
+// Neighbour relations: a dependency is a zone a briefed zone imports from, a dependent is a zone
+// importing from a briefed zone.
+const (
+	RelationDependency = "dependency"
+	RelationDependent  = "dependent"
+)
+
+// BriefingOptions configures GetBriefing. ProjectID limits the briefed zones when no zone is
+// given. MaxChars and MaxTokens bound the briefing text (zero is unbounded; the tighter applies).
+type BriefingOptions struct {
+	ProjectID string
+	Task      string
+	Variables map[string]string
+	MaxChars  int
+	MaxTokens int
+}
+
+// Briefing is what an agent needs before starting work: its rendered prompt, the briefed zones
+// and the neighbouring zones with their owners, also rendered as one markdown Text. Tokens is the
+// estimated size of Text; Truncated reports that sections were shortened to fit the budget.
+type Briefing struct {
+	Agent      *domain.Agent
+	Prompt     string
+	Zones      []ZoneBriefing
+	Neighbours []NeighbourZone
+	Text       string
+	Tokens     int
+	Truncated  bool
+}
+
+// ZoneBriefing is a briefed zone with its project, accepted decisions, current notes and owned
+// paths. PathCount is the number of owned paths, including those left out of Paths.
+type ZoneBriefing struct {
+	Zone      *domain.Zone
+	Project   *domain.Project
+	Decisions []string
+	Notes     []*domain.ZoneNote
+	Paths     []string
+	PathCount int
+}
+
+// NeighbourZone is another zone in a briefed zone's project, with its relations to the briefed
+// zones (RelationDependency, RelationDependent) when a DependencyAnalyzer is configured.
+type NeighbourZone struct {
+	Zone      *domain.Zone
+	Relations []string
+}
+
+// briefingLimits caps the entries kept in each shrinkable briefing section.
+type briefingLimits struct {
+	paths, notes, neighbours, decisions int
+}
+
+// GetBriefing assembles the briefing of the acting agent (see ActingAgent) for zoneID, or for
+// all its zones (in opts.ProjectID, when set) when zoneID is empty. A session identified by a
+// token may only be briefed on zones assigned to its agent. To fit the budget, owned
+// paths are dropped first, then notes (unpinned before pinned), neighbours and decisions; the
+// prompt is cut only as a last resort.
+func (s *Service) GetBriefing(ctx context.Context, agentID, zoneID string, opts BriefingOptions) (*Briefing, error) {
+	agentID, err := s.ActingAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxChars < 0 || opts.MaxTokens < 0 {
+		return nil, &domain.StructuredError{Code: "INVALID_BUDGET", Message: "budget must not be negative"}
+	}
+	var zones []*domain.Zone
+	if zoneID != "" {
+		z := s.GetZone(zoneID)
+		if z == nil {
+			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+		}
+		if CallerID(ctx) != "" && !slices.Contains(z.AgentIDs, agentID) {
+			return nil, &domain.StructuredError{Code: "OUT_OF_ZONE", Message: "zone " + z.Name + " is not assigned to agent " + agentID}
+		}
+		zones = []*domain.Zone{z}
+	} else if zones, err = s.AgentZones(agentID, opts.ProjectID); err != nil {
+		return nil, err
+	}
+	p, err := s.renderAgentPrompt(agentID, zoneID, opts.Task, opts.Variables, false)
+	if err != nil {
+		return nil, err
+	}
+
+	full := &Briefing{Agent: p.Agent, Prompt: strings.TrimSpace(p.Text), Zones: []ZoneBriefing{}}
+	var projects []*domain.Project
+	for _, z := range zones {
+		pr := s.Projects.Get(z.ProjectID)
+		if pr == nil {
+			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		if !slices.ContainsFunc(projects, func(o *domain.Project) bool { return o.ID == pr.ID }) {
+			projects = append(projects, pr)
+		}
+		paths, err := s.zonePaths(pr, z)
+		if err != nil {
+			return nil, err
+		}
+		zb := ZoneBriefing{Zone: z, Project: pr, Decisions: s.decisionSummaries(z), Paths: paths, PathCount: len(paths)}
+		if s.Notes != nil {
+			if zb.Notes, err = s.ListZoneNotes(z.ID, ""); err != nil {
+				return nil, err
+			}
+		}
+		full.Zones = append(full.Zones, zb)
+	}
+	full.Neighbours = s.neighbourZones(zones, projects)
+	return fitBriefing(full, budgetChars(opts.MaxChars, opts.MaxTokens)), nil
+}
+
+// neighbourZones returns the other zones of the projects, those related to a briefed zone first.
+func (s *Service) neighbourZones(briefed []*domain.Zone, projects []*domain.Project) []NeighbourZone {
+	isBriefed := make(map[string]bool, len(briefed))
+	for _, z := range briefed {
+		isBriefed[z.ID] = true
+	}
+	out := []NeighbourZone{}
+	for _, p := range projects {
+		zones := s.ListZones(p.ID)
+		edges := s.zoneDependencies(p.RootDir, zones)
+		var related, others []NeighbourZone
+		for _, z := range zones {
+			if isBriefed[z.ID] {
+				continue
+			}
+			n := NeighbourZone{Zone: z, Relations: []string{}}
+			for _, e := range edges {
+				rel := ""
+				switch {
+				case isBriefed[e.from] && e.to == z.ID:
+					rel = RelationDependency
+				case e.from == z.ID && isBriefed[e.to]:
+					rel = RelationDependent
+				}
+				if rel != "" && !slices.Contains(n.Relations, rel) {
+					n.Relations = append(n.Relations, rel)
+				}
+			}
+			if len(n.Relations) > 0 {
+				related = append(related, n)
+			} else {
+				others = append(others, n)
+			}
+		}
+		out = append(append(out, related...), others...)
+	}
+	return out
+}
+
+// fitBriefing renders the briefing, shrinking its sections until the text is at most limit
+// characters (zero is unbounded). Owned paths are always capped at maxPromptPaths.
+func fitBriefing(full *Briefing, limit int) *Briefing {
+	l := briefingLimits{paths: maxPromptPaths, neighbours: len(full.Neighbours)}
+	for _, zb := range full.Zones {
+		l.notes = max(l.notes, len(zb.Notes))
+		l.decisions = max(l.decisions, len(zb.Decisions))
+	}
+	truncated := false
+	for {
+		b := full.trim(l)
+		b.Text = b.render()
+		if limit > 0 && utf8.RuneCountInString(b.Text) > limit && !l.shrink() {
+			// Nothing left to drop: cut the prompt, then the text itself.
+			over := utf8.RuneCountInString(b.Text) - limit
+			b.Prompt = cutText(b.Prompt, utf8.RuneCountInString(b.Prompt)-over-len(" [truncated]")) + " [truncated]"
+			if b.Text = b.render(); utf8.RuneCountInString(b.Text) > limit {
+				b.Text = cutText(b.Text, limit)
+			}
+			truncated = true
+		}
+		if limit == 0 || utf8.RuneCountInString(b.Text) <= limit {
+			b.Tokens = estimateTokens(b.Text)
+			b.Truncated = truncated
+			return b
+		}
+		truncated = true
+	}
+}
+
+// shrink halves the first non-empty section limit; it reports false when all are empty.
+func (l *briefingLimits) shrink() bool {
+	for _, n := range []*int{&l.paths, &l.notes, &l.neighbours, &l.decisions} {
+		if *n > 0 {
+			*n /= 2
+			return true
+		}
+	}
+	return false
+}
+
+// trim returns a copy of the briefing with its sections capped by l.
+func (b *Briefing) trim(l briefingLimits) *Briefing {
+	out := *b
+	out.Zones = make([]ZoneBriefing, len(b.Zones))
+	for i, zb := range b.Zones {
+		zb.Paths = zb.Paths[:min(len(zb.Paths), l.paths)]
+		zb.Notes = zb.Notes[:min(len(zb.Notes), l.notes)]
+		zb.Decisions = zb.Decisions[:min(len(zb.Decisions), l.decisions)]
+		out.Zones[i] = zb
+	}
+	out.Neighbours = b.Neighbours[:min(len(b.Neighbours), l.neighbours)]
+	return &out
+}
+
+// render returns the briefing as markdown.
+func (b *Briefing) render() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Briefing: %s\n\n## Prompt\n%s\n", agentLabel(b.Agent), b.Prompt)
+	for _, zb := range b.Zones {
+		fmt.Fprintf(&sb, "\n## Zone: %s (project %s)\n", zb.Zone.Name, zb.Project.Name)
+		if zb.Zone.Purpose != "" {
+			fmt.Fprintf(&sb, "Purpose: %s\n", zb.Zone.Purpose)
+		}
+		if len(zb.Zone.Constraints) > 0 {
+			sb.WriteString("Constraints:\n")
+			for _, c := range zb.Zone.Constraints {
+				fmt.Fprintf(&sb, "- %s\n", c)
+			}
+		}
+		if len(zb.Decisions) > 0 {
+			sb.WriteString("Decisions:\n")
+			for _, d := range zb.Decisions {
+				fmt.Fprintf(&sb, "- %s\n", d)
+			}
+		}
+		if len(zb.Notes) > 0 {
+			sb.WriteString("Notes:\n")
+			for _, n := range zb.Notes {
+				sb.WriteString("- ")
+				if n.Pinned {
+					sb.WriteString("[pinned] ")
+				}
+				sb.WriteString(strings.Join(strings.Fields(n.Text), " "))
+				if len(n.Tags) > 0 {
+					fmt.Fprintf(&sb, " (%s)", strings.Join(n.Tags, ", "))
+				}
+				sb.WriteString("\n")
+			}
+		}
+		fmt.Fprintf(&sb, "Paths (%d):\n", zb.PathCount)
+		for _, path := range zb.Paths {
+			fmt.Fprintf(&sb, "- %s\n", path)
+		}
+		if more := zb.PathCount - len(zb.Paths); more > 0 {
+			fmt.Fprintf(&sb, "- ... and %d more\n", more)
+		}
+	}
+	if len(b.Neighbours) > 0 {
+		sb.WriteString("\n## Neighbouring zones\nCoordinate with their owners before changing their files.\n")
+		for _, n := range b.Neighbours {
+			owners := make([]string, 0, len(n.Zone.AssignedAgents))
+			for i := range n.Zone.AssignedAgents {
+				owners = append(owners, agentLabel(&n.Zone.AssignedAgents[i]))
+			}
+			fmt.Fprintf(&sb, "- %s", n.Zone.Name)
+			if len(n.Relations) > 0 {
+				fmt.Fprintf(&sb, " [%s]", strings.Join(n.Relations, ", "))
+			}
+			if len(owners) == 0 {
+				owners = []string{"none"}
+			}
+			fmt.Fprintf(&sb, ": owners %s", strings.Join(owners, ", "))
+			if n.Zone.Purpose != "" {
+				fmt.Fprintf(&sb, "; %s", n.Zone.Purpose)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// budgetChars returns the tighter of a character and a token budget in characters (zero when
+// neither is set).
+func budgetChars(maxChars, maxTokens int) int {
+	limit := maxChars
+	if t := maxTokens * charsPerToken; t > 0 && (limit == 0 || t < limit) {
+		limit = t
+	}
+	return limit
+}
+
+// estimateTokens estimates the number of tokens in text.
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// cutText returns the first n characters of text.
+func cutText(text string, n int) string {
+	for i := range text {
+		if n <= 0 {
+			return text[:i]
+		}
+		n--
+	}
+	return text
+}
@@ -0,0 +1,267 @@
+package blueprint
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"operators-mcp/internal/domain"
+)
+
+// Change sources recorded in domain.FileChange.Source.
+const (
+	SourceWriteFile       = "write_file"
+	SourceApplyPatch      = "apply_patch"
+	SourceExportDecisions = "export_decisions"
+)
+
+// pendingChange is a validated change that has not been written yet.
+type pendingChange struct {
+	path       string
+	old, new   []byte
+	existed    bool
+	remove     bool
+	added, rem int
+}
+
+// WriteFile creates or replaces a file of the project on behalf of agentID. The path must be
+// inside the root, not ignored, and in a zone assigned to the agent; otherwise OUT_OF_ZONE lists
+// the zones that own it. Paths in a zone leased by another agent are refused with ZONE_LEASED.
+// The change is recorded in the change log when one is configured. Writing a file's current
+// content changes nothing: the result describes the write but is not recorded.
+func (s *Service) WriteFile(projectID, agentID, path, content string) (*domain.FileChange, error) {
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	p, err := s.writableProject(projectID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := projectPath(p, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAgentZone(p, agentID, rel); err != nil {
+		return nil, err
+	}
+	if err := s.checkLeases(p, agentID, rel); err != nil {
+		return nil, err
+	}
+	old, existed, err := s.readExisting(p.RootDir, rel)
+	if err != nil {
+		return nil, err
+	}
+	added, removed := lineDelta(old, []byte(content))
+	changes, err := s.commitChanges(p, agentID, SourceWriteFile, []*pendingChange{
+		{path: rel, old: old, new: []byte(content), existed: existed, added: added, rem: removed},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		h := hashContent(old)
+		return &domain.FileChange{ProjectID: p.ID, AgentID: agentID, Path: rel, Source: SourceWriteFile,
+			Operation: domain.ChangeWrite, OldHash: h, NewHash: h, CreatedAt: time.Now().UTC()}, nil
+	}
+	return changes[0], nil
+}
+
+// ApplyPatch applies a unified diff to the project on behalf of agentID. Every touched path is
+// checked before anything is written (OUT_OF_ZONE lists all offending paths, ZONE_LEASED those
+// in zones leased by another agent) and every hunk is applied in memory first, so a patch that
+// does not apply leaves the files untouched. A write that fails midway is rolled back (see
+// commitChanges).
+func (s *Service) ApplyPatch(projectID, agentID, patch string) ([]*domain.FileChange, error) {
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	p, err := s.writableProject(projectID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	patches, err := parsePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(patches))
+	seen := make(map[string]bool)
+	for i, fp := range patches {
+		rel, err := projectPath(p, fp.path())
+		if err != nil {
+			return nil, err
+		}
+		if seen[rel] {
+			return nil, invalidPatch("%s is patched more than once", rel)
+		}
+		seen[rel] = true
+		paths[i] = rel
+	}
+	if err := s.checkAgentZone(p, agentID, paths...); err != nil {
+		return nil, err
+	}
+	if err := s.checkLeases(p, agentID, paths...); err != nil {
+		return nil, err
+	}
+	pending := make([]*pendingChange, len(patches))
+	for i, fp := range patches {
+		old, existed, err := s.readExisting(p.RootDir, paths[i])
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case fp.oldPath == "" && existed:
+			return nil, &domain.StructuredError{Code: "PATCH_CONFLICT", Message: paths[i] + " already exists"}
+		case fp.oldPath != "" && !existed:
+			return nil, &domain.StructuredError{Code: "PATCH_CONFLICT", Message: paths[i] + " does not exist"}
+		}
+		updated, added, removed, err := fp.apply(old)
+		if err != nil {
+			return nil, err
+		}
+		if fp.newPath == "" && len(updated) > 0 {
+			return nil, &domain.StructuredError{Code: "PATCH_CONFLICT", Message: paths[i] + ": deletion does not remove every line"}
+		}
+		pending[i] = &pendingChange{path: paths[i], old: old, new: updated, existed: existed, remove: fp.newPath == "", added: added, rem: removed}
+	}
+	return s.commitChanges(p, agentID, SourceApplyPatch, pending)
+}
+
+// ListChanges returns the project's recorded file changes, newest first, at most limit (0 means all).
+func (s *Service) ListChanges(projectID string, limit int) ([]*domain.FileChange, error) {
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	if s.Changes == nil {
+		return []*domain.FileChange{}, nil
+	}
+	changes := s.Changes.List(projectID, limit)
+	if changes == nil {
+		changes = []*domain.FileChange{}
+	}
+	return changes, nil
+}
+
+// writableProject checks the preconditions shared by WriteFile, ApplyPatch and ExportDecisions.
+func (s *Service) writableProject(projectID, agentID string) (*domain.Project, error) {
+	if s.Files == nil {
+		return nil, errFilesUnavailable
+	}
+	if agentID == "" {
+		return nil, &domain.StructuredError{Code: "AGENT_REQUIRED", Message: "writes must be made on behalf of an agent"}
+	}
+	if s.Agents.Get(agentID) == nil {
+		return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	p := s.Projects.Get(projectID)
+	if p == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	return p, nil
+}
+
+// readExisting returns the current content of rel and whether it exists.
+func (s *Service) readExisting(root, rel string) ([]byte, bool, error) {
+	data, err := s.readText(root, rel)
+	if isCode(err, "FILE_NOT_FOUND") {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// commitChanges writes the pending changes, then records them. Unchanged files are skipped.
+// When a write fails, the files already written are restored to their previous content; paths
+// that cannot be restored are named in the error. Callers hold leaseMu, so no lease can be
+// claimed between their lease check and the writes.
+func (s *Service) commitChanges(p *domain.Project, agentID, source string, pending []*pendingChange) ([]*domain.FileChange, error) {
+	out := make([]*domain.FileChange, 0, len(pending))
+	var written []*pendingChange
+	for _, c := range pending {
+		if c.existed && !c.remove && bytes.Equal(c.old, c.new) {
+			continue
+		}
+		fc := &domain.FileChange{
+			ProjectID:    p.ID,
+			AgentID:      agentID,
+			Path:         c.path,
+			Source:       source,
+			LinesAdded:   c.added,
+			LinesRemoved: c.rem,
+			CreatedAt:    time.Now().UTC(),
+		}
+		if c.existed {
+			fc.OldHash = hashContent(c.old)
+		}
+		var err error
+		switch {
+		case c.remove:
+			fc.Operation = domain.ChangeDelete
+			err = s.Files.RemoveFile(p.RootDir, c.path)
+		case c.existed:
+			fc.Operation = domain.ChangeWrite
+			fc.NewHash = hashContent(c.new)
+			err = s.Files.WriteFile(p.RootDir, c.path, c.new)
+		default:
+			fc.Operation = domain.ChangeCreate
+			fc.NewHash = hashContent(c.new)
+			err = s.Files.WriteFile(p.RootDir, c.path, c.new)
+		}
+		if err != nil {
+			return nil, s.rollBack(p, written, err)
+		}
+		written = append(written, c)
+		out = append(out, fc)
+	}
+	if s.Changes != nil {
+		for i, fc := range out {
+			var err error
+			if out[i], err = s.Changes.Record(fc); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return out, nil
+}
+
+// rollBack restores written files to their content before commitChanges, newest first, and
+// returns the write error cause, naming the paths it could not restore.
+func (s *Service) rollBack(p *domain.Project, written []*pendingChange, cause error) error {
+	var stuck []string
+	for i := len(written) - 1; i >= 0; i-- {
+		c := written[i]
+		var err error
+		if c.existed {
+			err = s.Files.WriteFile(p.RootDir, c.path, c.old)
+		} else {
+			err = s.Files.RemoveFile(p.RootDir, c.path)
+		}
+		if err != nil {
+			stuck = append(stuck, c.path)
+		}
+	}
+	if len(stuck) > 0 {
+		return fmt.Errorf("%w (could not restore %s)", cause, strings.Join(stuck, ", "))
+	}
+	return cause
+}
+
+// lineDelta counts lines of new that are not in old (added) and lines of old that are not in
+// new (removed), comparing lines as multisets.
+func lineDelta(old, new []byte) (added, removed int) {
+	counts := make(map[string]int)
+	for _, l := range splitLines(string(old)) {
+		counts[l]++
+	}
+	for _, l := range splitLines(string(new)) {
+		if counts[l] > 0 {
+			counts[l]--
+		} else {
+			added++
+		}
+	}
+	for _, n := range counts {
+		removed += n
+	}
+	return added, removed
+}
@@ -0,0 +1,299 @@
+package blueprint
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// Context bundle limits, in estimated tokens. Files of at most SmallFileTokens are preferred
+// after entry points. A file that does not fit is included cut at a line boundary when at least
+// minPartialTokens of the budget are left.
+const (
+	DefaultContextTokens = 16000
+	MaxContextTokens     = 250000
+	SmallFileTokens      = 1000
+	minPartialTokens     = 200
+	recentChanges        = 100
+)
+
+// Reasons a file is selected for a context bundle, in priority order.
+const (
+	ContextEntryPoint = "entry_point"
+	ContextSmall      = "small"
+	ContextRecent     = "recent"
+	ContextOther      = "other"
+)
+
+// entryPointNames are file names (without extension, lowercased) that usually start a program or
+// describe a package.
+var entryPointNames = []string{"main", "index", "app", "server", "__main__", "__init__", "lib", "mod", "doc", "readme"}
+
+// ContextOptions selects the files for BuildContext: the files of ZoneID, or the files at or
+// under Paths. MaxTokens defaults to DefaultContextTokens. When AgentID is set every file must be
+// in a zone assigned to the agent.
+type ContextOptions struct {
+	ZoneID    string
+	Paths     []string
+	MaxTokens int
+	AgentID   string
+}
+
+// ContextFile is a file included in a context bundle: lines 1..EndLine of TotalLines.
+// Truncated is set when the file was cut to fit the budget.
+type ContextFile struct {
+	Path       string `json:"path"`
+	Reason     string `json:"reason"`
+	Tokens     int    `json:"tokens"`
+	TotalLines int    `json:"total_lines"`
+	EndLine    int    `json:"end_line"`
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
+// OmittedFile is a selected file left out of a context bundle, with why (budget, binary,
+// too_large, not_found or unreadable) and, when known, its estimated tokens.
+type OmittedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+	Tokens int    `json:"tokens,omitempty"`
+}
+
+// ContextBundle is the result of BuildContext: the included files concatenated in Text, each
+// under a "==> path <==" header, and the estimated Tokens used out of Budget.
+type ContextBundle struct {
+	ProjectID string         `json:"project_id"`
+	Budget    int            `json:"budget"`
+	Tokens    int            `json:"tokens"`
+	Files     []*ContextFile `json:"files"`
+	Omitted   []*OmittedFile `json:"omitted"`
+	Text      string         `json:"text"`
+}
+
+// contextCandidate is a file considered for a context bundle.
+type contextCandidate struct {
+	path   string
+	size   int64
+	reason string
+	recent int // position in the change log, newest first; -1 when not recently changed
+}
+
+// BuildContext concatenates the text files of a zone, or of paths in the project, into one bundle
+// of at most MaxTokens estimated tokens. Files are taken in priority order: entry points, small
+// files (smallest first), files recently changed through the service (newest first), then the
+// rest (smallest first). Files that do not fit, binary files and files over MaxReadFileSize are
+// reported in Omitted.
+func (s *Service) BuildContext(projectID string, opts ContextOptions) (*ContextBundle, error) {
+	if s.Files == nil {
+		return nil, errFilesUnavailable
+	}
+	budget := opts.MaxTokens
+	if budget == 0 {
+		budget = DefaultContextTokens
+	}
+	if budget < 0 || budget > MaxContextTokens {
+		return nil, &domain.StructuredError{Code: "INVALID_BUDGET", Message: fmt.Sprintf("max_tokens must be between 1 and %d", MaxContextTokens)}
+	}
+	var p *domain.Project
+	var paths []string
+	res := &ContextBundle{Budget: budget, Files: []*ContextFile{}, Omitted: []*OmittedFile{}}
+	if opts.ZoneID != "" {
+		z := s.Zones.Get(opts.ZoneID)
+		if z == nil {
+			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+		}
+		if p = s.Projects.Get(z.ProjectID); p == nil {
+			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		if opts.AgentID != "" && !slices.Contains(z.AgentIDs, opts.AgentID) {
+			return nil, &domain.StructuredError{Code: "OUT_OF_ZONE", Message: "zone " + z.Name + " is not assigned to agent " + opts.AgentID}
+		}
+		var err error
+		if paths, err = s.zonePaths(p, z); err != nil {
+			return nil, err
+		}
+	} else {
+		if len(opts.Paths) == 0 {
+			return nil, &domain.StructuredError{Code: "PATHS_REQUIRED", Message: "zone_id or paths is required"}
+		}
+		if p = s.Projects.Get(projectID); p == nil {
+			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		var missing []string
+		var err error
+		if paths, missing, err = s.expandPaths(p, opts.Paths); err != nil {
+			return nil, err
+		}
+		for _, m := range missing {
+			res.Omitted = append(res.Omitted, &OmittedFile{Path: m, Reason: "not_found"})
+		}
+	}
+	res.ProjectID = p.ID
+
+	recent := map[string]int{}
+	if s.Changes != nil {
+		for i, c := range s.Changes.List(p.ID, recentChanges) {
+			if _, ok := recent[c.Path]; !ok {
+				recent[c.Path] = i
+			}
+		}
+	}
+	var cands []contextCandidate
+	for _, rel := range paths {
+		size, err := s.Files.FileSize(p.RootDir, rel)
+		switch {
+		case isCode(err, "NOT_A_FILE"):
+			continue
+		case err != nil:
+			res.Omitted = append(res.Omitted, &OmittedFile{Path: rel, Reason: "unreadable"})
+			continue
+		}
+		c := contextCandidate{path: rel, size: size, recent: -1}
+		if i, ok := recent[rel]; ok {
+			c.recent = i
+		}
+		switch {
+		case isEntryPoint(rel):
+			c.reason = ContextEntryPoint
+		case estimateTokens64(size) <= SmallFileTokens:
+			c.reason = ContextSmall
+		case c.recent >= 0:
+			c.reason = ContextRecent
+		default:
+			c.reason = ContextOther
+		}
+		cands = append(cands, c)
+	}
+	if opts.AgentID != "" && len(cands) > 0 {
+		files := make([]string, len(cands))
+		for i, c := range cands {
+			files[i] = c.path
+		}
+		if err := s.checkAgentZone(p, opts.AgentID, files...); err != nil {
+			return nil, err
+		}
+	}
+	sortCandidates(cands)
+
+	var text strings.Builder
+	used := 0
+	for _, c := range cands {
+		if left := budget - used; left < minPartialTokens && estimateTokens64(c.size) > left {
+			res.Omitted = append(res.Omitted, &OmittedFile{Path: c.path, Reason: "budget", Tokens: estimateTokens64(c.size)})
+			continue
+		}
+		data, err := s.readText(p.RootDir, c.path)
+		if err != nil {
+			reason := "unreadable"
+			if isCode(err, "BINARY_FILE") {
+				reason = "binary"
+			} else if isCode(err, "FILE_TOO_LARGE") {
+				reason = "too_large"
+			}
+			res.Omitted = append(res.Omitted, &OmittedFile{Path: c.path, Reason: reason, Tokens: estimateTokens64(c.size)})
+			continue
+		}
+		full := sliceLines(c.path, data, 0, 0, len(data))
+		chunk := "==> " + c.path + " <==\n" + withNewline(full.Content) + "\n"
+		cost := estimateTokens(chunk)
+		if used+cost > budget {
+			left := budget - used
+			if left < minPartialTokens {
+				res.Omitted = append(res.Omitted, &OmittedFile{Path: c.path, Reason: "budget", Tokens: cost})
+				continue
+			}
+			// Reserve room for the header with the widest line range and the trailing newlines.
+			header := fmt.Sprintf("==> %s (lines 1-%d of %d) <==\n", c.path, full.TotalLines, full.TotalLines)
+			part := sliceLines(c.path, data, 0, 0, left*charsPerToken-len(header)-2)
+			if part.EndLine == 0 {
+				res.Omitted = append(res.Omitted, &OmittedFile{Path: c.path, Reason: "budget", Tokens: cost})
+				continue
+			}
+			chunk = fmt.Sprintf("==> %s (lines 1-%d of %d) <==\n", c.path, part.EndLine, part.TotalLines) + withNewline(part.Content) + "\n"
+			full, cost = part, estimateTokens(chunk)
+		}
+		used += cost
+		text.WriteString(chunk)
+		res.Files = append(res.Files, &ContextFile{Path: c.path, Reason: c.reason, Tokens: cost, TotalLines: full.TotalLines, EndLine: full.EndLine, Truncated: full.Truncated})
+	}
+	res.Text = text.String()
+	res.Tokens = estimateTokens(res.Text)
+	return res, nil
+}
+
+// expandPaths resolves paths (files or directories) to the sorted project paths at or under them,
+// excluding ignored paths. Paths matching nothing are returned in missing.
+func (s *Service) expandPaths(p *domain.Project, paths []string) (out, missing []string, err error) {
+	all, err := s.PathMatcher.ListMatchingPaths(p.RootDir, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, raw := range paths {
+		rel, err := projectPath(p, raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		found := false
+		for _, candidate := range all {
+			if (candidate != rel && !strings.HasPrefix(candidate, rel+"/")) || isIgnored(p, candidate) {
+				continue
+			}
+			found = true
+			if !slices.Contains(out, candidate) {
+				out = append(out, candidate)
+			}
+		}
+		if !found {
+			missing = append(missing, rel)
+		}
+	}
+	sort.Strings(out)
+	return out, missing, nil
+}
+
+// sortCandidates orders files by priority: entry points (shallowest first), small files
+// (smallest first), recently changed files (newest first), then the rest (smallest first).
+func sortCandidates(cands []contextCandidate) {
+	tier := map[string]int{ContextEntryPoint: 0, ContextSmall: 1, ContextRecent: 2, ContextOther: 3}
+	sort.SliceStable(cands, func(i, j int) bool {
+		a, b := cands[i], cands[j]
+		if tier[a.reason] != tier[b.reason] {
+			return tier[a.reason] < tier[b.reason]
+		}
+		switch a.reason {
+		case ContextEntryPoint:
+			if da, db := strings.Count(a.path, "/"), strings.Count(b.path, "/"); da != db {
+				return da < db
+			}
+		case ContextRecent:
+			return a.recent < b.recent
+		default:
+			if a.size != b.size {
+				return a.size < b.size
+			}
+		}
+		return a.path < b.path
+	})
+}
+
+// isEntryPoint reports whether the file's name marks it as a program entry point or package
+// overview (main.go, index.ts, __init__.py, README.md, ...).
+func isEntryPoint(rel string) bool {
+	base := strings.ToLower(path.Base(rel))
+	return slices.Contains(entryPointNames, strings.TrimSuffix(base, path.Ext(base)))
+}
+
+// estimateTokens64 estimates the tokens of size bytes of text.
+func estimateTokens64(size int64) int {
+	return int((size + charsPerToken - 1) / charsPerToken)
+}
+
+func withNewline(s string) string {
+	if s == "" || strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
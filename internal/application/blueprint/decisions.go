@@ -0,0 +1,416 @@
+package blueprint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"operators-mcp/internal/domain"
+)
+
+// DefaultDecisionDir is where ExportDecisions writes markdown, relative to the project root.
+const DefaultDecisionDir = "docs/adr"
+
+// DecisionDraft is a decision record to create with CreateDecision. Status is proposed (the
+// default) or accepted; Supersedes names a decision of the same project this one replaces.
+type DecisionDraft struct {
+	Title        string
+	Context      string
+	Decision     string
+	Consequences string
+	Status       string
+	ZoneIDs      []string
+	Supersedes   string
+}
+
+// DecisionFilter selects decisions in ListDecisions. Empty fields match every decision; Query
+// matches title, context, decision and consequences case-insensitively.
+type DecisionFilter struct {
+	ZoneID string
+	Status string
+	Query  string
+}
+
+// CreateDecision records a decision about zones of the project, numbered after the project's
+// last decision. The caller is recorded as its author. Accepting a decision that supersedes
+// another marks that one superseded.
+func (s *Service) CreateDecision(ctx context.Context, projectID string, d DecisionDraft) (*domain.Decision, error) {
+	if s.Decisions == nil {
+		return nil, errDecisionsUnavailable
+	}
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	title := strings.TrimSpace(d.Title)
+	if title == "" {
+		return nil, &domain.StructuredError{Code: "TITLE_REQUIRED", Message: "title is required"}
+	}
+	status := d.Status
+	if status == "" {
+		status = domain.DecisionProposed
+	}
+	if status != domain.DecisionProposed && status != domain.DecisionAccepted {
+		return nil, &domain.StructuredError{Code: "INVALID_STATUS", Message: "a new decision is proposed or accepted"}
+	}
+	zones, err := s.checkTaskZones(projectID, d.ZoneIDs)
+	if err != nil {
+		return nil, err
+	}
+	if d.Supersedes != "" {
+		old := s.Decisions.Get(d.Supersedes)
+		if old == nil || old.ProjectID != projectID {
+			return nil, &domain.StructuredError{Code: "DECISION_NOT_FOUND", Message: "superseded decision not found in project: " + d.Supersedes}
+		}
+	}
+
+	s.decisionMu.Lock()
+	defer s.decisionMu.Unlock()
+	number := 1
+	for _, other := range s.Decisions.List(projectID) {
+		number = max(number, other.Number+1)
+	}
+	now := time.Now().UTC()
+	created, err := s.Decisions.Create(&domain.Decision{
+		ProjectID:    projectID,
+		Number:       number,
+		Title:        title,
+		Context:      strings.TrimSpace(d.Context),
+		Decision:     strings.TrimSpace(d.Decision),
+		Consequences: strings.TrimSpace(d.Consequences),
+		Status:       status,
+		ZoneIDs:      zones,
+		Supersedes:   d.Supersedes,
+		CreatedBy:    CallerID(ctx),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if created, err = changed(s, created, err); err != nil {
+		return nil, err
+	}
+	if err := s.supersede(created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// SetDecisionStatus moves a decision to accepted or superseded. Superseded decisions are final.
+// Accepting a decision that supersedes another marks that one superseded.
+func (s *Service) SetDecisionStatus(decisionID, status string) (*domain.Decision, error) {
+	d, err := s.GetDecision(decisionID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.ValidDecisionStatus(status) {
+		return nil, &domain.StructuredError{Code: "INVALID_STATUS", Message: "status must be proposed, accepted or superseded"}
+	}
+	if d.Status == status {
+		return d, nil
+	}
+	if d.Status == domain.DecisionSuperseded || status == domain.DecisionProposed {
+		return nil, &domain.StructuredError{Code: "INVALID_STATUS", Message: fmt.Sprintf("a decision cannot move from %s to %s", d.Status, status)}
+	}
+	d.Status = status
+	d.UpdatedAt = time.Now().UTC()
+	d, err = s.Decisions.Update(d)
+	if d, err = changed(s, d, err); err != nil {
+		return nil, err
+	}
+	if err := s.supersede(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// GetDecision returns a decision by id.
+func (s *Service) GetDecision(decisionID string) (*domain.Decision, error) {
+	if s.Decisions == nil {
+		return nil, errDecisionsUnavailable
+	}
+	d := s.Decisions.Get(decisionID)
+	if d == nil {
+		return nil, &domain.StructuredError{Code: "DECISION_NOT_FOUND", Message: "decision not found"}
+	}
+	return d, nil
+}
+
+// ListDecisions returns the project's decisions matching f, oldest first.
+func (s *Service) ListDecisions(projectID string, f DecisionFilter) ([]*domain.Decision, error) {
+	if s.Decisions == nil {
+		return nil, errDecisionsUnavailable
+	}
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	if f.Status != "" && !domain.ValidDecisionStatus(f.Status) {
+		return nil, &domain.StructuredError{Code: "INVALID_STATUS", Message: "unknown decision status: " + f.Status}
+	}
+	query := strings.ToLower(strings.TrimSpace(f.Query))
+	out := []*domain.Decision{}
+	for _, d := range s.Decisions.List(projectID) {
+		if (f.Status == "" || d.Status == f.Status) && (f.ZoneID == "" || slices.Contains(d.ZoneIDs, f.ZoneID)) && (query == "" || decisionMatches(d, query)) {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// ZoneDecisions returns the accepted decisions about a zone, oldest first. It is empty when
+// decision records are not configured.
+func (s *Service) ZoneDecisions(z *domain.Zone) []*domain.Decision {
+	if s.Decisions == nil {
+		return nil
+	}
+	var out []*domain.Decision
+	for _, d := range s.Decisions.List(z.ProjectID) {
+		if d.Status == domain.DecisionAccepted && slices.Contains(d.ZoneIDs, z.ID) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// ExportDecisions writes every decision of the project as markdown under dir (relative to the
+// project root, DefaultDecisionDir when empty), one NNNN-title.md file per decision plus a
+// README.md index, and returns the written paths. Existing files are replaced, and other files
+// for the same decision number (left by an older title) are removed. The files are written on
+// behalf of the acting agent (see ActingAgent) like WriteFile: paths in a zone leased by another
+// agent are refused with ZONE_LEASED, and the changes are recorded and rolled back together.
+func (s *Service) ExportDecisions(ctx context.Context, projectID, agentID, dir string) ([]string, error) {
+	agentID, err := s.ActingAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	p, err := s.writableProject(projectID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := s.ListDecisions(projectID, DecisionFilter{})
+	if err != nil {
+		return nil, err
+	}
+	dir = strings.Trim(path.Clean("/"+strings.TrimSpace(dir)), "/")
+	if dir == "" {
+		dir = DefaultDecisionDir
+	}
+	if isIgnored(p, dir) {
+		return nil, &domain.StructuredError{Code: "PATH_IGNORED", Message: "path is ignored: " + dir}
+	}
+	byID := make(map[string]*domain.Decision, len(decisions))
+	supersededBy := make(map[string]*domain.Decision)
+	for _, d := range decisions {
+		byID[d.ID] = d
+		if d.Supersedes != "" && d.Status == domain.DecisionAccepted {
+			supersededBy[d.Supersedes] = d
+		}
+	}
+	files := make(map[string][]byte, len(decisions)+1)
+	written := make([]string, 0, len(decisions)+1)
+	var index strings.Builder
+	index.WriteString("# Architecture decision records\n\n| ADR | Title | Status | Zones |\n| --- | --- | --- | --- |\n")
+	for _, d := range decisions {
+		name := decisionFileName(d)
+		files[path.Join(dir, name)] = []byte(s.decisionMarkdown(d, byID, supersededBy[d.ID]))
+		written = append(written, path.Join(dir, name))
+		fmt.Fprintf(&index, "| [%s](%s) | %s | %s | %s |\n", d.Label(), name, tableCell(d.Title), d.Status, tableCell(strings.Join(s.zoneNames(d.ZoneIDs), ", ")))
+	}
+	files[path.Join(dir, "README.md")] = []byte(index.String())
+	written = append(written, path.Join(dir, "README.md"))
+	stale, err := s.staleDecisionFiles(p, dir, decisions, files)
+	if err != nil {
+		return nil, err
+	}
+	touched := append(slices.Clone(written), stale...)
+	if err := s.checkAgentZone(p, agentID, touched...); err != nil {
+		return nil, err
+	}
+	if err := s.checkLeases(p, agentID, touched...); err != nil {
+		return nil, err
+	}
+	pending := make([]*pendingChange, 0, len(written)+len(stale))
+	for _, rel := range written {
+		old, existed, err := s.readExisting(p.RootDir, rel)
+		if err != nil {
+			return nil, err
+		}
+		added, removed := lineDelta(old, files[rel])
+		pending = append(pending, &pendingChange{path: rel, old: old, new: files[rel], existed: existed, added: added, rem: removed})
+	}
+	for _, rel := range stale {
+		old, _, err := s.readExisting(p.RootDir, rel)
+		if err != nil {
+			return nil, err
+		}
+		_, removed := lineDelta(old, nil)
+		pending = append(pending, &pendingChange{path: rel, old: old, existed: true, remove: true, rem: removed})
+	}
+	if _, err := s.commitChanges(p, agentID, SourceExportDecisions, pending); err != nil {
+		return nil, err
+	}
+	return written, nil
+}
+
+// decisionFilePattern matches the markdown file names ExportDecisions gives decisions: the
+// zero-padded number, optionally followed by a slug of the title.
+var decisionFilePattern = regexp.MustCompile(`^(\d{4,})(-[a-z0-9]+(-[a-z0-9]+)*)?\.md$`)
+
+// staleDecisionFiles returns the files in dir that an earlier export wrote for one of the
+// decisions and that are not among files, the ones being written: their name follows
+// decisionFileName for the decision's number and they start with the decision's heading, so
+// hand-written files sharing a number prefix are kept.
+func (s *Service) staleDecisionFiles(p *domain.Project, dir string, decisions []*domain.Decision, files map[string][]byte) ([]string, error) {
+	byNumber := make(map[int]*domain.Decision, len(decisions))
+	for _, d := range decisions {
+		byNumber[d.Number] = d
+	}
+	paths, err := s.PathMatcher.ListMatchingPaths(p.RootDir, "^"+regexp.QuoteMeta(dir)+`/\d{4,}[^/]*\.md$`)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, rel := range paths {
+		if files[rel] != nil {
+			continue
+		}
+		m := decisionFilePattern.FindStringSubmatch(path.Base(rel))
+		if m == nil {
+			continue
+		}
+		n, err := strconv.Atoi(m[1])
+		d := byNumber[n]
+		if err != nil || d == nil || m[1] != fmt.Sprintf("%04d", n) {
+			continue
+		}
+		old, _, err := s.readExisting(p.RootDir, rel)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(old, []byte("# "+d.Label()+": ")) {
+			out = append(out, rel)
+		}
+	}
+	return out, nil
+}
+
+// tableCell escapes text for a markdown table cell.
+func tableCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// decisionMarkdown renders a decision record in the usual ADR layout.
+func (s *Service) decisionMarkdown(d *domain.Decision, byID map[string]*domain.Decision, supersededBy *domain.Decision) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: %s\n\n", d.Label(), d.Title)
+	fmt.Fprintf(&b, "- Status: %s\n", d.Status)
+	fmt.Fprintf(&b, "- Date: %s\n", d.CreatedAt.Format(time.DateOnly))
+	if names := s.zoneNames(d.ZoneIDs); len(names) > 0 {
+		fmt.Fprintf(&b, "- Zones: %s\n", strings.Join(names, ", "))
+	}
+	if old := byID[d.Supersedes]; old != nil {
+		fmt.Fprintf(&b, "- Supersedes: [%s](%s)\n", old.Label(), decisionFileName(old))
+	}
+	if supersededBy != nil {
+		fmt.Fprintf(&b, "- Superseded by: [%s](%s)\n", supersededBy.Label(), decisionFileName(supersededBy))
+	}
+	for _, section := range []struct{ heading, text string }{
+		{"Context", d.Context},
+		{"Decision", d.Decision},
+		{"Consequences", d.Consequences},
+	} {
+		if section.text != "" {
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", section.heading, section.text)
+		}
+	}
+	return b.String()
+}
+
+// decisionSummaries returns one line per accepted decision about the zone, for agent briefings.
+func (s *Service) decisionSummaries(z *domain.Zone) []string {
+	var out []string
+	for _, d := range s.ZoneDecisions(z) {
+		line := d.Label() + " " + d.Title
+		if d.Decision != "" {
+			line += ": " + strings.Join(strings.Fields(d.Decision), " ")
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// supersede marks the decision replaced by an accepted decision superseded.
+func (s *Service) supersede(d *domain.Decision) error {
+	if d.Status != domain.DecisionAccepted || d.Supersedes == "" {
+		return nil
+	}
+	old := s.Decisions.Get(d.Supersedes)
+	if old == nil || old.Status == domain.DecisionSuperseded {
+		return nil
+	}
+	old.Status = domain.DecisionSuperseded
+	old.UpdatedAt = time.Now().UTC()
+	if _, err := s.Decisions.Update(old); err != nil {
+		return err
+	}
+	s.notify(recordEvent(old))
+	return nil
+}
+
+// dropDecisionZone removes a deleted zone from the decisions about it. The decisions are kept.
+func (s *Service) dropDecisionZone(z *domain.Zone) error {
+	if s.Decisions == nil {
+		return nil
+	}
+	for _, d := range s.Decisions.List(z.ProjectID) {
+		if !slices.Contains(d.ZoneIDs, z.ID) {
+			continue
+		}
+		d.ZoneIDs = slices.DeleteFunc(d.ZoneIDs, func(id string) bool { return id == z.ID })
+		d.UpdatedAt = time.Now().UTC()
+		if _, err := s.Decisions.Update(d); err != nil {
+			return err
+		}
+		s.notify(recordEvent(d))
+	}
+	return nil
+}
+
+// zoneNames returns the names of the zones that still exist, in order.
+func (s *Service) zoneNames(zoneIDs []string) []string {
+	var out []string
+	for _, id := range zoneIDs {
+		if z := s.Zones.Get(id); z != nil {
+			out = append(out, z.Name)
+		}
+	}
+	return out
+}
+
+func decisionMatches(d *domain.Decision, query string) bool {
+	for _, text := range []string{d.Title, d.Context, d.Decision, d.Consequences} {
+		if strings.Contains(strings.ToLower(text), query) {
+			return true
+		}
+	}
+	return false
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// decisionFileName returns the markdown file name of a decision, e.g. 0003-use-sqlite.md.
+func decisionFileName(d *domain.Decision) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(d.Title), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		return fmt.Sprintf("%04d.md", d.Number)
+	}
+	return fmt.Sprintf("%04d-%s.md", d.Number, slug)
+}
@@ -0,0 +1,351 @@
+package blueprint
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// Diagram formats supported by ExportDiagram.
+const (
+	DiagramMermaid  = "mermaid"
+	DiagramDOT      = "dot"
+	DiagramPlantUML = "plantuml"
+)
+
+// DiagramOptions controls how ExportDiagram renders a project's zones.
+// Format defaults to mermaid. GroupNested draws zones whose paths lie inside another zone
+// as children of that zone. ZoneIDs restricts the diagram to a subset; empty means all zones.
+type DiagramOptions struct {
+	Format      string
+	GroupNested bool
+	ZoneIDs     []string
+}
+
+// diagramNode is a zone in the rendered hierarchy.
+type diagramNode struct {
+	zone     *domain.Zone
+	children []*diagramNode
+}
+
+// diagramEdge is a dependency between two zones (from imports to).
+type diagramEdge struct {
+	from, to string
+}
+
+// ExportDiagram renders the project's zones (purpose, assigned agents and, when a
+// DependencyAnalyzer is configured, inter-zone dependencies) as a diagram in the requested format.
+func (s *Service) ExportDiagram(projectID string, opts DiagramOptions) (string, error) {
+	p := s.Projects.Get(projectID)
+	if p == nil {
+		return "", &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	format := opts.Format
+	if format == "" {
+		format = DiagramMermaid
+	}
+	if format != DiagramMermaid && format != DiagramDOT && format != DiagramPlantUML {
+		return "", &domain.StructuredError{Code: "INVALID_FORMAT", Message: "format must be one of mermaid, dot, plantuml"}
+	}
+	zones, err := filterZones(s.ListZones(projectID), opts.ZoneIDs)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		if zones[i].Name != zones[j].Name {
+			return zones[i].Name < zones[j].Name
+		}
+		return zones[i].ID < zones[j].ID
+	})
+	var paths []string
+	if opts.GroupNested && len(zones) > 1 {
+		paths, err = s.PathMatcher.ListMatchingPaths(p.RootDir, "")
+		if err != nil {
+			return "", err
+		}
+	}
+	roots := nestZones(zones, paths)
+	edges := s.zoneDependencies(p.RootDir, zones)
+	title := p.Name
+	if title == "" {
+		title = p.ID
+	}
+	switch format {
+	case DiagramDOT:
+		return renderDOT(title, roots, edges), nil
+	case DiagramPlantUML:
+		return renderPlantUML(title, roots, edges), nil
+	default:
+		return renderMermaid(roots, edges), nil
+	}
+}
+
+// filterZones keeps only the zones listed in ids (all zones when ids is empty).
+func filterZones(zones []*domain.Zone, ids []string) ([]*domain.Zone, error) {
+	if len(ids) == 0 {
+		return zones, nil
+	}
+	byID := make(map[string]*domain.Zone, len(zones))
+	for _, z := range zones {
+		byID[z.ID] = z
+	}
+	out := make([]*domain.Zone, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		z, ok := byID[id]
+		if !ok {
+			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found: " + id}
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, z)
+		}
+	}
+	return out, nil
+}
+
+// nestZones arranges zones into a forest. A zone is nested under the smallest other zone whose
+// matched paths are a strict superset of its own. With no paths every zone is a root.
+func nestZones(zones []*domain.Zone, paths []string) []*diagramNode {
+	nodes := make([]*diagramNode, len(zones))
+	members := make([]map[string]bool, len(zones))
+	for i, m := range domain.NewZoneMatchers(zones) {
+		nodes[i] = &diagramNode{zone: m.Zone}
+		members[i] = make(map[string]bool)
+		for _, p := range paths {
+			if p != "" && m.Contains(p) {
+				members[i][p] = true
+			}
+		}
+	}
+	var roots []*diagramNode
+	for i := range zones {
+		parent := -1
+		for j := range zones {
+			if i == j || len(members[i]) == 0 || len(members[j]) <= len(members[i]) {
+				continue
+			}
+			if !isSubset(members[i], members[j]) {
+				continue
+			}
+			if parent == -1 || len(members[j]) < len(members[parent]) {
+				parent = j
+			}
+		}
+		if parent == -1 {
+			roots = append(roots, nodes[i])
+		} else {
+			nodes[parent].children = append(nodes[parent].children, nodes[i])
+		}
+	}
+	return roots
+}
+
+func isSubset(a, b map[string]bool) bool {
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// zoneDependencies maps file-level imports to zone-level edges. It returns nil when no
+// DependencyAnalyzer is configured or the project cannot be analyzed.
+func (s *Service) zoneDependencies(root string, zones []*domain.Zone) []diagramEdge {
+	if s.Dependencies == nil || len(zones) < 2 {
+		return nil
+	}
+	imports, err := s.Dependencies.ListImports(root)
+	if err != nil {
+		return nil
+	}
+	matchers := domain.NewZoneMatchers(zones)
+	// Zones are resolved once per file and once per imported directory.
+	fileZones := make(map[string][]*domain.Zone, len(imports))
+	dirFiles := make(map[string][]string)
+	for f := range imports {
+		for _, m := range matchers {
+			if m.Contains(f) {
+				fileZones[f] = append(fileZones[f], m.Zone)
+			}
+		}
+		dir := path.Dir(f)
+		dirFiles[dir] = append(dirFiles[dir], f)
+	}
+	dirZones := make(map[string][]*domain.Zone)
+	owners := func(dir string) []*domain.Zone {
+		if zs, ok := dirZones[dir]; ok {
+			return zs
+		}
+		zs := []*domain.Zone{}
+		for _, m := range matchers {
+			if m.Contains(dir) || slices.ContainsFunc(dirFiles[dir], func(f string) bool { return slices.Contains(fileZones[f], m.Zone) }) {
+				zs = append(zs, m.Zone)
+			}
+		}
+		dirZones[dir] = zs
+		return zs
+	}
+	seen := make(map[diagramEdge]bool)
+	var edges []diagramEdge
+	for file, dirs := range imports {
+		for _, from := range fileZones[file] {
+			for _, dir := range dirs {
+				for _, to := range owners(dir) {
+					e := diagramEdge{from: from.ID, to: to.ID}
+					if from.ID == to.ID || seen[e] {
+						continue
+					}
+					seen[e] = true
+					edges = append(edges, e)
+				}
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from != edges[j].from {
+			return edges[i].from < edges[j].from
+		}
+		return edges[i].to < edges[j].to
+	})
+	return edges
+}
+
+// zoneLabelLines returns the label lines for a zone: name, purpose and assigned agents.
+func zoneLabelLines(z *domain.Zone) []string {
+	lines := []string{z.Name}
+	if z.Purpose != "" {
+		lines = append(lines, z.Purpose)
+	}
+	if len(z.AssignedAgents) > 0 {
+		names := make([]string, 0, len(z.AssignedAgents))
+		for _, a := range z.AssignedAgents {
+			if a.Name != "" {
+				names = append(names, a.Name)
+			} else {
+				names = append(names, a.ID)
+			}
+		}
+		lines = append(lines, "agents: "+strings.Join(names, ", "))
+	}
+	return lines
+}
+
+// diagramID returns an identifier safe to use in every supported diagram language.
+func diagramID(prefix, id string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, r := range id {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func renderMermaid(roots []*diagramNode, edges []diagramEdge) string {
+	esc := strings.NewReplacer(`"`, "#quot;", "\n", " ")
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	var walk func(n *diagramNode, depth int)
+	walk = func(n *diagramNode, depth int) {
+		indent := strings.Repeat("    ", depth)
+		lines := zoneLabelLines(n.zone)
+		for i := range lines {
+			lines[i] = esc.Replace(lines[i])
+		}
+		node := diagramID("z_", n.zone.ID) + `["` + strings.Join(lines, "<br/>") + "\"]\n"
+		if len(n.children) == 0 {
+			b.WriteString(indent + node)
+			return
+		}
+		b.WriteString(indent + "subgraph " + diagramID("g_", n.zone.ID) + `["` + esc.Replace(n.zone.Name) + "\"]\n")
+		b.WriteString(indent + "    " + node)
+		for _, c := range n.children {
+			walk(c, depth+1)
+		}
+		b.WriteString(indent + "end\n")
+	}
+	for _, n := range roots {
+		walk(n, 1)
+	}
+	for _, e := range edges {
+		b.WriteString("    " + diagramID("z_", e.from) + " --> " + diagramID("z_", e.to) + "\n")
+	}
+	return b.String()
+}
+
+func renderDOT(title string, roots []*diagramNode, edges []diagramEdge) string {
+	esc := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ")
+	var b strings.Builder
+	b.WriteString(`digraph "` + esc.Replace(title) + "\" {\n")
+	b.WriteString("    rankdir=LR;\n    node [shape=box];\n")
+	var walk func(n *diagramNode, depth int)
+	walk = func(n *diagramNode, depth int) {
+		indent := strings.Repeat("    ", depth)
+		lines := zoneLabelLines(n.zone)
+		for i := range lines {
+			lines[i] = esc.Replace(lines[i])
+		}
+		node := `"` + diagramID("z_", n.zone.ID) + `" [label="` + strings.Join(lines, `\n`) + "\"];\n"
+		if len(n.children) == 0 {
+			b.WriteString(indent + node)
+			return
+		}
+		b.WriteString(indent + `subgraph "` + diagramID("cluster_", n.zone.ID) + "\" {\n")
+		b.WriteString(indent + `    label="` + esc.Replace(n.zone.Name) + "\";\n")
+		b.WriteString(indent + "    " + node)
+		for _, c := range n.children {
+			walk(c, depth+1)
+		}
+		b.WriteString(indent + "}\n")
+	}
+	for _, n := range roots {
+		walk(n, 1)
+	}
+	for _, e := range edges {
+		b.WriteString(`    "` + diagramID("z_", e.from) + `" -> "` + diagramID("z_", e.to) + "\";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func renderPlantUML(title string, roots []*diagramNode, edges []diagramEdge) string {
+	esc := strings.NewReplacer(`"`, "'", "\n", " ")
+	var b strings.Builder
+	b.WriteString("@startuml\n")
+	b.WriteString("title " + esc.Replace(title) + "\n")
+	var walk func(n *diagramNode, depth int)
+	walk = func(n *diagramNode, depth int) {
+		indent := strings.Repeat("    ", depth)
+		lines := zoneLabelLines(n.zone)
+		for i := range lines {
+			lines[i] = esc.Replace(lines[i])
+		}
+		node := `component "` + strings.Join(lines, `\n`) + `" as ` + diagramID("z_", n.zone.ID) + "\n"
+		if len(n.children) == 0 {
+			b.WriteString(indent + node)
+			return
+		}
+		b.WriteString(indent + `package "` + esc.Replace(n.zone.Name) + `" as ` + diagramID("g_", n.zone.ID) + " {\n")
+		b.WriteString(indent + "    " + node)
+		for _, c := range n.children {
+			walk(c, depth+1)
+		}
+		b.WriteString(indent + "}\n")
+	}
+	for _, n := range roots {
+		walk(n, 0)
+	}
+	for _, e := range edges {
+		b.WriteString(diagramID("z_", e.from) + " --> " + diagramID("z_", e.to) + "\n")
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
@@ -0,0 +1,548 @@
+package blueprint
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"operators-mcp/internal/domain"
+)
+
+// DocumentVersion is the blueprint document schema version written by ExportBlueprint.
+const DocumentVersion = 1
+
+// Blueprint document encodings.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Import modes. ImportCreate only adds what is missing, ImportUpdate also changes existing
+// entities, and ImportPrune additionally deletes zones and ignored paths absent from the document.
+// Agents are shared between projects and are never deleted by an import.
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+	ImportPrune  = "prune"
+)
+
+// Document is the serialized, diff-friendly form of a project blueprint.
+// Zones and agents are keyed by name so a document can be applied to any store;
+// the project root is deliberately omitted so the file can live inside the repository it describes.
+type Document struct {
+	Version int             `json:"version" yaml:"version"`
+	Project DocumentProject `json:"project" yaml:"project"`
+	Agents  []DocumentAgent `json:"agents,omitempty" yaml:"agents,omitempty"`
+	Zones   []DocumentZone  `json:"zones,omitempty" yaml:"zones,omitempty"`
+}
+
+// DocumentProject holds project-level settings.
+type DocumentProject struct {
+	Name         string   `json:"name" yaml:"name"`
+	IgnoredPaths []string `json:"ignored_paths,omitempty" yaml:"ignored_paths,omitempty"`
+}
+
+// DocumentAgent is an agent referenced by at least one zone of the project.
+type DocumentAgent struct {
+	Name        string             `json:"name" yaml:"name"`
+	Description string             `json:"description,omitempty" yaml:"description,omitempty"`
+	Prompt      string             `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	Variables   []DocumentVariable `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// DocumentVariable is a variable declared by an agent's prompt template.
+type DocumentVariable struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
+	Default     string `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+func documentVariables(vars []domain.PromptVariable) []DocumentVariable {
+	var out []DocumentVariable
+	for _, v := range vars {
+		out = append(out, DocumentVariable(v))
+	}
+	return out
+}
+
+func domainVariables(vars []DocumentVariable) []domain.PromptVariable {
+	var out []domain.PromptVariable
+	for _, v := range vars {
+		out = append(out, domain.PromptVariable(v))
+	}
+	return out
+}
+
+// DocumentZone is a zone; Agents lists agent names.
+type DocumentZone struct {
+	Name          string   `json:"name" yaml:"name"`
+	Pattern       string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Purpose       string   `json:"purpose,omitempty" yaml:"purpose,omitempty"`
+	Constraints   []string `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	ExplicitPaths []string `json:"explicit_paths,omitempty" yaml:"explicit_paths,omitempty"`
+	Agents        []string `json:"agents,omitempty" yaml:"agents,omitempty"`
+}
+
+// ImportOptions selects the target project and how a document is applied.
+// ProjectID imports into an existing project; otherwise the project whose RootDir equals
+// RootDir is used, or a new one is created there. Mode defaults to ImportUpdate.
+type ImportOptions struct {
+	ProjectID string
+	RootDir   string
+	Mode      string
+	DryRun    bool
+}
+
+// BlueprintChange is one entry of an import diff.
+type BlueprintChange struct {
+	Action string   `json:"action"`
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ImportResult reports the changes an import made (or would make when DryRun is set).
+// ProjectID is empty when a dry run would create a new project.
+type ImportResult struct {
+	ProjectID string            `json:"project_id,omitempty"`
+	Applied   bool              `json:"applied"`
+	Changes   []BlueprintChange `json:"changes"`
+}
+
+// MarshalDocument encodes doc as YAML (default) or JSON.
+func MarshalDocument(doc *Document, format string) ([]byte, error) {
+	switch format {
+	case "", FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}
+	return nil, &domain.StructuredError{Code: "INVALID_FORMAT", Message: "format must be yaml or json"}
+}
+
+// UnmarshalDocument decodes a YAML or JSON document. An empty format detects JSON by its
+// leading brace and falls back to YAML. Unknown fields are rejected.
+func UnmarshalDocument(data []byte, format string) (*Document, error) {
+	if format == "" {
+		format = FormatYAML
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			format = FormatJSON
+		}
+	}
+	var doc Document
+	switch format {
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&doc); err != nil {
+			return nil, &domain.StructuredError{Code: "INVALID_DOCUMENT", Message: err.Error()}
+		}
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, &domain.StructuredError{Code: "INVALID_DOCUMENT", Message: err.Error()}
+		}
+	default:
+		return nil, &domain.StructuredError{Code: "INVALID_FORMAT", Message: "format must be yaml or json"}
+	}
+	if doc.Version != DocumentVersion {
+		return nil, &domain.StructuredError{Code: "INVALID_DOCUMENT", Message: "unsupported blueprint version"}
+	}
+	return &doc, nil
+}
+
+// ExportBlueprint builds the document for a project: settings, zones sorted by name and the
+// agents they reference. Explicit and ignored paths are sorted; constraint order is preserved.
+// Documents reference agents by name, so a zone assigned to an unnamed agent fails the export
+// with UNNAMED_AGENT rather than losing the assignment.
+func (s *Service) ExportBlueprint(projectID string) (*Document, error) {
+	p := s.Projects.Get(projectID)
+	if p == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	doc := &Document{
+		Version: DocumentVersion,
+		Project: DocumentProject{Name: p.Name, IgnoredPaths: sortedCopy(p.IgnoredPaths)},
+	}
+	agents := make(map[string]DocumentAgent)
+	for _, z := range s.ListZones(projectID) {
+		dz := DocumentZone{
+			Name:          z.Name,
+			Pattern:       z.Pattern,
+			Purpose:       z.Purpose,
+			Constraints:   append([]string(nil), z.Constraints...),
+			ExplicitPaths: sortedCopy(z.ExplicitPaths),
+		}
+		for _, a := range z.AssignedAgents {
+			da := DocumentAgent{Name: a.Name, Description: a.Description, Prompt: a.Prompt, Variables: documentVariables(a.Variables)}
+			if da.Name == "" {
+				return nil, &domain.StructuredError{Code: "UNNAMED_AGENT", Message: "agent " + a.ID + " of zone " + z.Name + " has no name; name it to export the blueprint"}
+			}
+			agents[da.Name] = da
+			dz.Agents = append(dz.Agents, da.Name)
+		}
+		sort.Strings(dz.Agents)
+		doc.Zones = append(doc.Zones, dz)
+	}
+	sort.Slice(doc.Zones, func(i, j int) bool { return doc.Zones[i].Name < doc.Zones[j].Name })
+	for _, a := range agents {
+		doc.Agents = append(doc.Agents, a)
+	}
+	sort.Slice(doc.Agents, func(i, j int) bool { return doc.Agents[i].Name < doc.Agents[j].Name })
+	return doc, nil
+}
+
+// ImportBlueprint applies doc to the store according to opts and returns the resulting diff.
+// With DryRun set nothing is written.
+func (s *Service) ImportBlueprint(doc *Document, opts ImportOptions) (*ImportResult, error) {
+	res, err := s.importDocument(doc, opts)
+	if err != nil {
+		return nil, err
+	}
+	if res.Applied && len(res.Changes) > 0 {
+		s.publish(Event{Kind: EventProject, ID: res.ProjectID, ProjectID: res.ProjectID})
+	}
+	return res, nil
+}
+
+// importDocument does the work of ImportBlueprint without publishing the project event. Pruned
+// zones are deleted as by DeleteZone, and subscribers are notified of each.
+func (s *Service) importDocument(doc *Document, opts ImportOptions) (*ImportResult, error) {
+	mode := opts.Mode
+	if mode == "" {
+		mode = ImportUpdate
+	}
+	if mode != ImportCreate && mode != ImportUpdate && mode != ImportPrune {
+		return nil, &domain.StructuredError{Code: "INVALID_MODE", Message: "mode must be one of create, update, prune"}
+	}
+	if err := validateDocument(doc); err != nil {
+		return nil, err
+	}
+	if err := s.checkImportAgents(doc); err != nil {
+		return nil, err
+	}
+	apply := !opts.DryRun
+	update := mode != ImportCreate
+	res := &ImportResult{Applied: apply, Changes: []BlueprintChange{}}
+	record := func(action, kind, name string, fields ...string) {
+		res.Changes = append(res.Changes, BlueprintChange{Action: action, Kind: kind, Name: name, Fields: fields})
+	}
+
+	// Project
+	p, err := s.importTarget(opts)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		record("create", "project", doc.Project.Name)
+		if apply {
+			if p, err = s.Projects.Create(doc.Project.Name, opts.RootDir); err != nil {
+				return nil, err
+			}
+		} else {
+			p = &domain.Project{Name: doc.Project.Name, RootDir: opts.RootDir}
+		}
+	} else if update && doc.Project.Name != "" && p.Name != doc.Project.Name {
+		record("update", "project", doc.Project.Name, "name")
+		if apply {
+			if p, err = s.Projects.Update(p.ID, doc.Project.Name, ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+	res.ProjectID = p.ID
+
+	// Ignored paths
+	wantIgnored := make(map[string]bool)
+	for _, ig := range doc.Project.IgnoredPaths {
+		ig = domain.NormalizePath(ig)
+		wantIgnored[ig] = true
+		if !slices.Contains(p.IgnoredPaths, ig) {
+			record("create", "ignored_path", ig)
+			if apply {
+				if _, err := s.Projects.AddIgnoredPath(p.ID, ig); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if mode == ImportPrune {
+		for _, ig := range sortedCopy(p.IgnoredPaths) {
+			if !wantIgnored[ig] {
+				record("delete", "ignored_path", ig)
+				if apply {
+					if _, err := s.Projects.RemoveIgnoredPath(p.ID, ig); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+
+	// Agents
+	agentsByName := make(map[string]domain.Agent)
+	existing := s.Agents.List()
+	sort.Slice(existing, func(i, j int) bool { return existing[i].ID < existing[j].ID })
+	for _, a := range existing {
+		if _, ok := agentsByName[a.Name]; !ok && a.Name != "" {
+			agentsByName[a.Name] = *a
+		}
+	}
+	for _, da := range doc.Agents {
+		vars := domainVariables(da.Variables)
+		cur, ok := agentsByName[da.Name]
+		if !ok {
+			record("create", "agent", da.Name)
+			a := &domain.Agent{Name: da.Name, Description: da.Description, Prompt: da.Prompt, Variables: vars}
+			if apply {
+				if a, err = s.Agents.Create(da.Name, da.Description, da.Prompt, vars); err != nil {
+					return nil, err
+				}
+			}
+			agentsByName[da.Name] = *a
+			continue
+		}
+		var fields []string
+		if cur.Description != da.Description {
+			fields = append(fields, "description")
+		}
+		if cur.Prompt != da.Prompt {
+			fields = append(fields, "prompt")
+		}
+		if !slices.Equal(cur.Variables, vars) {
+			fields = append(fields, "variables")
+		}
+		if update && len(fields) > 0 {
+			record("update", "agent", da.Name, fields...)
+			if apply {
+				if _, err := s.Agents.Update(cur.ID, da.Name, da.Description, da.Prompt, vars); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// Zones
+	var zones []*domain.Zone
+	if p.ID != "" {
+		zones = s.ListZones(p.ID)
+	}
+	zonesByName := make(map[string]*domain.Zone, len(zones))
+	for _, z := range zones {
+		zonesByName[z.Name] = z
+	}
+	for _, dz := range doc.Zones {
+		agentIDs := make([]string, 0, len(dz.Agents))
+		for _, name := range dz.Agents {
+			a, ok := agentsByName[name]
+			if !ok {
+				return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found: " + name}
+			}
+			agentIDs = append(agentIDs, a.ID)
+		}
+		paths := make([]string, 0, len(dz.ExplicitPaths))
+		for _, ep := range dz.ExplicitPaths {
+			paths = append(paths, domain.NormalizePath(ep))
+		}
+		z, ok := zonesByName[dz.Name]
+		if !ok {
+			record("create", "zone", dz.Name)
+			if apply {
+				if z, err = s.Zones.Create(p.ID, dz.Name, dz.Pattern, dz.Purpose, dz.Constraints, agentIDs); err != nil {
+					return nil, err
+				}
+				for _, ep := range paths {
+					if _, err := s.Zones.AssignPath(z.ID, ep); err != nil {
+						return nil, err
+					}
+				}
+			}
+			continue
+		}
+		if !update {
+			continue
+		}
+		fields := zoneFieldChanges(z, dz, agentIDs, paths)
+		if len(fields) == 0 {
+			continue
+		}
+		record("update", "zone", dz.Name, fields...)
+		if !apply {
+			continue
+		}
+		if _, err := s.Zones.Update(z.ID, z.Name, dz.Pattern, dz.Purpose, dz.Constraints, agentIDs); err != nil {
+			return nil, err
+		}
+		for _, ep := range paths {
+			if !slices.Contains(z.ExplicitPaths, ep) {
+				if _, err := s.Zones.AssignPath(z.ID, ep); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, ep := range z.ExplicitPaths {
+			if !slices.Contains(paths, ep) {
+				if _, err := s.Zones.UnassignPath(z.ID, ep); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	if mode == ImportPrune {
+		wantZones := make(map[string]bool, len(doc.Zones))
+		for _, dz := range doc.Zones {
+			wantZones[dz.Name] = true
+		}
+		sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+		for _, z := range zones {
+			if !wantZones[z.Name] {
+				record("delete", "zone", z.Name)
+				if apply {
+					if err := s.deleteZone(z); err != nil {
+						return nil, err
+					}
+					s.notify(Event{Kind: EventZone, ID: z.ID, ProjectID: z.ProjectID, Deleted: true})
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// importTarget returns the project an import applies to, or nil when a new one must be created.
+func (s *Service) importTarget(opts ImportOptions) (*domain.Project, error) {
+	if opts.ProjectID != "" {
+		p := s.Projects.Get(opts.ProjectID)
+		if p == nil {
+			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		return p, nil
+	}
+	if opts.RootDir == "" {
+		return nil, &domain.StructuredError{Code: "INVALID_ROOT", Message: "project_id or root_dir is required"}
+	}
+	for _, p := range s.Projects.List() {
+		if p.RootDir == opts.RootDir {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkImportAgents rejects, before anything is written, agent prompts that do not parse (unless
+// unchanged from the stored agent of that name) and zones referencing an agent that is neither in the document nor in the store. Imports are not
+// transactional, so every check that can fail on the document's content is made up front.
+func (s *Service) checkImportAgents(doc *Document) error {
+	known := make(map[string]bool)
+	stored := make(map[string]*domain.Agent)
+	for _, a := range s.Agents.List() {
+		if a.Name != "" {
+			known[a.Name] = true
+			if _, ok := stored[a.Name]; !ok || a.ID < stored[a.Name].ID {
+				stored[a.Name] = a
+			}
+		}
+	}
+	for _, da := range doc.Agents {
+		vars := domainVariables(da.Variables)
+		if cur := stored[da.Name]; cur != nil && cur.Prompt == da.Prompt && slices.Equal(cur.Variables, vars) {
+			// Unchanged, as in UpdateAgent.
+			continue
+		}
+		if _, err := parsePrompt(da.Prompt, vars); err != nil {
+			if se, ok := err.(*domain.StructuredError); ok {
+				return &domain.StructuredError{Code: se.Code, Message: "agent " + da.Name + ": " + se.Message}
+			}
+			return err
+		}
+		known[da.Name] = true
+	}
+	for _, dz := range doc.Zones {
+		for _, name := range dz.Agents {
+			if !known[name] {
+				return &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found: " + name}
+			}
+		}
+	}
+	return nil
+}
+
+// validateDocument rejects documents with duplicate or empty zone and agent names.
+func validateDocument(doc *Document) error {
+	if doc == nil {
+		return &domain.StructuredError{Code: "INVALID_DOCUMENT", Message: "document is required"}
+	}
+	seen := make(map[string]bool)
+	for _, a := range doc.Agents {
+		if a.Name == "" || seen[a.Name] {
+			return &domain.StructuredError{Code: "INVALID_DOCUMENT", Message: "agent names must be unique and non-empty"}
+		}
+		seen[a.Name] = true
+	}
+	seen = make(map[string]bool)
+	for _, z := range doc.Zones {
+		if z.Name == "" || seen[z.Name] {
+			return &domain.StructuredError{Code: "INVALID_DOCUMENT", Message: "zone names must be unique and non-empty"}
+		}
+		seen[z.Name] = true
+	}
+	return nil
+}
+
+// zoneFieldChanges lists the zone fields that differ from the document.
+func zoneFieldChanges(z *domain.Zone, dz DocumentZone, agentIDs []string, paths []string) []string {
+	var fields []string
+	if z.Pattern != dz.Pattern {
+		fields = append(fields, "pattern")
+	}
+	if z.Purpose != dz.Purpose {
+		fields = append(fields, "purpose")
+	}
+	if !slices.Equal(z.Constraints, dz.Constraints) && (len(z.Constraints) > 0 || len(dz.Constraints) > 0) {
+		fields = append(fields, "constraints")
+	}
+	if !slices.Equal(sortedCopy(z.AgentIDs), sortedCopy(agentIDs)) {
+		fields = append(fields, "agents")
+	}
+	if !slices.Equal(sortedCopy(z.ExplicitPaths), sortedCopy(paths)) {
+		fields = append(fields, "explicit_paths")
+	}
+	return fields
+}
+
+func sortedCopy(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	out := append([]string(nil), s...)
+	sort.Strings(out)
+	return slices.Compact(out)
+}
+
+// String renders the change as a single diff line, e.g. "~ zone backend (pattern, purpose)".
+func (c BlueprintChange) String() string {
+	sign := map[string]string{"create": "+", "update": "~", "delete": "-"}[c.Action]
+	line := sign + " " + c.Kind + " " + c.Name
+	if len(c.Fields) > 0 {
+		line += " (" + strings.Join(c.Fields, ", ") + ")"
+	}
+	return line
+}
@@ -0,0 +1,91 @@
+package blueprint
+
+import (
+	"fmt"
+	"slices"
+
+	"operators-mcp/internal/domain"
+)
+
+// Event kinds published after a successful mutation.
+const (
+	EventProject  = "project"
+	EventZone     = "zone"
+	EventAgent    = "agent"
+	EventTask     = "task"
+	EventLease    = "lease"
+	EventRun      = "run"
+	EventMessage  = "message"
+	EventDecision = "decision"
+	EventNote     = "note"
+)
+
+// Event describes a change made through the service. ID is the id of the changed entity (the zone
+// for lease events); ProjectID is the owning project for every event but agent events, where it is
+// empty.
+type Event struct {
+	Kind      string
+	ID        string
+	ProjectID string
+	Deleted   bool
+}
+
+// Subscribe registers fn to be called synchronously after every change made through the service.
+func (s *Service) Subscribe(fn func(Event)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// publish writes bound blueprint files back and notifies subscribers.
+func (s *Service) publish(e Event) {
+	s.syncAfterChange(e)
+	s.notify(e)
+}
+
+// notify calls the subscribers only.
+func (s *Service) notify(e Event) {
+	s.mu.RLock()
+	subs := slices.Clone(s.subscribers)
+	s.mu.RUnlock()
+	for _, fn := range subs {
+		fn(e)
+	}
+}
+
+// recordEvent returns the event for a task, run, lease, message, decision or note. These records
+// are not part of blueprint documents, so their changes notify subscribers without syncing files.
+func recordEvent(v any) Event {
+	switch r := v.(type) {
+	case *domain.Task:
+		return Event{Kind: EventTask, ID: r.ID, ProjectID: r.ProjectID}
+	case *domain.Run:
+		return Event{Kind: EventRun, ID: r.ID, ProjectID: r.ProjectID}
+	case *domain.ZoneLease:
+		return Event{Kind: EventLease, ID: r.ZoneID, ProjectID: r.ProjectID}
+	case *domain.Message:
+		return Event{Kind: EventMessage, ID: r.ID, ProjectID: r.ProjectID}
+	case *domain.Decision:
+		return Event{Kind: EventDecision, ID: r.ID, ProjectID: r.ProjectID}
+	case *domain.ZoneNote:
+		return Event{Kind: EventNote, ID: r.ID, ProjectID: r.ProjectID}
+	}
+	panic(fmt.Sprintf("blueprint: no event for %T", v))
+}
+
+// changed notifies subscribers of a successful record mutation and passes its result through.
+func changed[T any](s *Service, v T, err error) (T, error) {
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	s.notify(recordEvent(v))
+	return v, nil
+}
+
+// removed notifies subscribers that a record was deleted.
+func (s *Service) removed(v any) {
+	e := recordEvent(v)
+	e.Deleted = true
+	s.notify(e)
+}
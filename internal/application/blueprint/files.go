@@ -0,0 +1,238 @@
+package blueprint
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// Read limits. MaxReadFileSize bounds the files ReadFile will open at all; MaxBytes in ReadOptions
+// bounds how much content is returned (per file for ReadFile, in total for ReadZoneFiles).
+const (
+	MaxReadFileSize      = 8 << 20
+	DefaultReadMaxBytes  = 256 << 10
+	DefaultZoneReadBytes = 512 << 10
+)
+
+// ReadOptions controls ReadFile and ReadZoneFiles. StartLine and EndLine select a 1-based,
+// inclusive line range (0 means the first or last line). MaxBytes caps the returned content.
+// When AgentID is set the path must belong to a zone the agent is assigned to.
+type ReadOptions struct {
+	AgentID   string
+	StartLine int
+	EndLine   int
+	MaxBytes  int
+}
+
+// FileContent is a file, or a line range of it, read through the service.
+// Truncated is set when the selected lines did not fit in MaxBytes; EndLine is then the last line returned.
+type FileContent struct {
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	TotalLines int    `json:"total_lines"`
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
+	Content    string `json:"content"`
+	Truncated  bool   `json:"truncated,omitempty"`
+}
+
+// ZoneFiles is the result of ReadZoneFiles. Skipped lists files that were not returned
+// (binary, too large, or over the byte budget).
+type ZoneFiles struct {
+	ZoneID    string         `json:"zone_id"`
+	Files     []*FileContent `json:"files"`
+	Skipped   []string       `json:"skipped,omitempty"`
+	Truncated bool           `json:"truncated,omitempty"`
+}
+
+// ReadFile reads a file of the project. The path is resolved against the project root; paths
+// outside it or under an ignored path are refused, as are binary files and files over MaxReadFileSize.
+func (s *Service) ReadFile(projectID, path string, opts ReadOptions) (*FileContent, error) {
+	if s.Files == nil {
+		return nil, errFilesUnavailable
+	}
+	p := s.Projects.Get(projectID)
+	if p == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	rel, err := projectPath(p, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAgentZone(p, opts.AgentID, rel); err != nil {
+		return nil, err
+	}
+	if opts.StartLine < 0 || opts.EndLine < 0 || (opts.EndLine > 0 && opts.EndLine < opts.StartLine) {
+		return nil, &domain.StructuredError{Code: "INVALID_RANGE", Message: "start_line and end_line must be positive and ordered"}
+	}
+	data, err := s.readText(p.RootDir, rel)
+	if err != nil {
+		return nil, err
+	}
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultReadMaxBytes
+	}
+	return sliceLines(rel, data, opts.StartLine, opts.EndLine, maxBytes), nil
+}
+
+// ReadZoneFiles reads every file that belongs to the zone, in path order, until MaxBytes
+// (DefaultZoneReadBytes when unset) of content has been returned. Line ranges are ignored.
+func (s *Service) ReadZoneFiles(zoneID string, opts ReadOptions) (*ZoneFiles, error) {
+	if s.Files == nil {
+		return nil, errFilesUnavailable
+	}
+	z := s.Zones.Get(zoneID)
+	if z == nil {
+		return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	p := s.Projects.Get(z.ProjectID)
+	if p == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	if opts.AgentID != "" && !slices.Contains(z.AgentIDs, opts.AgentID) {
+		return nil, &domain.StructuredError{Code: "OUT_OF_ZONE", Message: "zone " + z.Name + " is not assigned to agent " + opts.AgentID}
+	}
+	paths, err := s.zonePaths(p, z)
+	if err != nil {
+		return nil, err
+	}
+	budget := opts.MaxBytes
+	if budget <= 0 {
+		budget = DefaultZoneReadBytes
+	}
+	res := &ZoneFiles{ZoneID: z.ID, Files: []*FileContent{}}
+	for _, path := range paths {
+		data, err := s.readText(p.RootDir, path)
+		if isCode(err, "NOT_A_FILE") {
+			continue
+		}
+		if err != nil || len(data) > budget {
+			if err == nil {
+				res.Truncated = true
+			}
+			res.Skipped = append(res.Skipped, path)
+			continue
+		}
+		budget -= len(data)
+		res.Files = append(res.Files, sliceLines(path, data, 0, 0, len(data)))
+	}
+	return res, nil
+}
+
+// readText reads a text file, refusing files over MaxReadFileSize and files that look binary.
+func (s *Service) readText(root, rel string) ([]byte, error) {
+	size, err := s.Files.FileSize(root, rel)
+	if err != nil {
+		return nil, err
+	}
+	if size > MaxReadFileSize {
+		return nil, &domain.StructuredError{Code: "FILE_TOO_LARGE", Message: rel + " is larger than the read limit"}
+	}
+	data, err := s.Files.ReadFile(root, rel)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, &domain.StructuredError{Code: "BINARY_FILE", Message: rel + " is not a text file"}
+	}
+	return data, nil
+}
+
+// sliceLines returns lines start..end (1-based, inclusive, 0 for open ends) of data, cut at
+// the last whole line that fits in maxBytes.
+func sliceLines(path string, data []byte, start, end, maxBytes int) *FileContent {
+	lines := strings.SplitAfter(string(data), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	fc := &FileContent{Path: path, Size: int64(len(data)), TotalLines: len(lines)}
+	if start == 0 {
+		start = 1
+	}
+	if end == 0 || end > len(lines) {
+		end = len(lines)
+	}
+	fc.StartLine = start
+	var b strings.Builder
+	for i := start; i <= end; i++ {
+		line := lines[i-1]
+		if b.Len()+len(line) > maxBytes {
+			fc.Truncated = true
+			break
+		}
+		b.WriteString(line)
+		fc.EndLine = i
+	}
+	if fc.EndLine == 0 {
+		fc.EndLine = start - 1
+	}
+	fc.Content = b.String()
+	return fc
+}
+
+// projectPath normalizes path (absolute or relative to the project root) to a project-relative
+// path, refusing paths outside the root and paths under the project's ignored paths.
+func projectPath(p *domain.Project, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(p.RootDir, path)
+		if err != nil {
+			return "", &domain.StructuredError{Code: "INVALID_PATH", Message: err.Error()}
+		}
+		path = rel
+	}
+	rel := domain.NormalizePath(path)
+	if path == "" || rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", &domain.StructuredError{Code: "INVALID_PATH", Message: "path must be inside the project root: " + path}
+	}
+	if isIgnored(p, rel) {
+		return "", &domain.StructuredError{Code: "PATH_IGNORED", Message: rel + " is in an ignored path"}
+	}
+	return rel, nil
+}
+
+// checkAgentZone refuses paths unless agentID is empty or, for every path, one of the project's
+// zones containing it is assigned to the agent. The OutOfZoneError lists each refused path with
+// the zones that do contain it.
+func (s *Service) checkAgentZone(p *domain.Project, agentID string, paths ...string) error {
+	if agentID == "" {
+		return nil
+	}
+	zones := s.Zones.ListByProject(p.ID)
+	var violations []domain.ZoneViolation
+	for _, rel := range paths {
+		owned := false
+		var owners []string
+		for _, z := range zones {
+			if !z.Contains(rel) {
+				continue
+			}
+			if slices.Contains(z.AgentIDs, agentID) {
+				owned = true
+				break
+			}
+			owners = append(owners, z.Name)
+		}
+		if !owned {
+			violations = append(violations, domain.ZoneViolation{Path: rel, OwningZones: owners})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		if len(v.OwningZones) == 0 {
+			msgs[i] = v.Path + " is not in any zone"
+		} else {
+			msgs[i] = v.Path + " belongs to " + strings.Join(v.OwningZones, ", ")
+		}
+	}
+	return &domain.OutOfZoneError{
+		StructuredError: domain.StructuredError{Code: "OUT_OF_ZONE", Message: "agent " + agentID + " may not touch: " + strings.Join(msgs, "; ")},
+		Violations:      violations,
+	}
+}
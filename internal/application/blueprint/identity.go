@@ -0,0 +1,120 @@
+package blueprint
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"operators-mcp/internal/domain"
+)
+
+type callerKey struct{}
+
+// WithCaller returns a context identifying agentID as the caller. Inbound adapters set it once
+// the agent behind a session is known; an empty agentID leaves the caller anonymous.
+func WithCaller(ctx context.Context, agentID string) context.Context {
+	if agentID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, callerKey{}, agentID)
+}
+
+// CallerID returns the id of the agent making the call, or "" for anonymous callers.
+func CallerID(ctx context.Context) string {
+	id, _ := ctx.Value(callerKey{}).(string)
+	return id
+}
+
+// Caller returns the agent making the call, or nil for anonymous callers and deleted agents.
+func (s *Service) Caller(ctx context.Context) *domain.Agent {
+	if id := CallerID(ctx); id != "" {
+		return s.Agents.Get(id)
+	}
+	return nil
+}
+
+// ActingAgent returns the agent a call acts for: the caller when the session is identified,
+// otherwise agentID as given. An identified caller may not act for another agent. With
+// RequireIdentity set, anonymous sessions are refused once any agent token has been issued.
+func (s *Service) ActingAgent(ctx context.Context, agentID string) (string, error) {
+	caller := CallerID(ctx)
+	if caller == "" {
+		if s.RequireIdentity && s.Tokens != nil && s.Tokens.Any() {
+			return "", &domain.StructuredError{Code: "UNAUTHENTICATED", Message: "agent tokens are in use; identify the session with a bearer token"}
+		}
+		return agentID, nil
+	}
+	if agentID != "" && agentID != caller {
+		return "", &domain.StructuredError{Code: "IDENTITY_MISMATCH", Message: "session is identified as agent " + caller + " and cannot act for agent " + agentID}
+	}
+	return caller, nil
+}
+
+// IssueAgentToken generates a new bearer token for the agent, replacing any previous one.
+// Only a hash is stored, so the token cannot be retrieved again.
+func (s *Service) IssueAgentToken(agentID string) (string, error) {
+	if s.Tokens == nil {
+		return "", errTokensUnavailable
+	}
+	if s.Agents.Get(agentID) == nil {
+		return "", &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	if err := s.Tokens.Set(agentID, hashToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RevokeAgentToken removes the agent's token; sessions can no longer identify with it.
+func (s *Service) RevokeAgentToken(agentID string) error {
+	if s.Tokens == nil {
+		return errTokensUnavailable
+	}
+	if s.Agents.Get(agentID) == nil {
+		return &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	return s.Tokens.Delete(agentID)
+}
+
+// AgentForToken returns the agent holding token.
+func (s *Service) AgentForToken(token string) (*domain.Agent, error) {
+	if s.Tokens == nil {
+		return nil, errTokensUnavailable
+	}
+	if token != "" {
+		if id := s.Tokens.AgentID(hashToken(token)); id != "" {
+			if a := s.Agents.Get(id); a != nil {
+				return a, nil
+			}
+		}
+	}
+	return nil, &domain.StructuredError{Code: "INVALID_TOKEN", Message: "unknown agent token"}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type projectKey struct{}
+
+// WithActiveProject returns a context whose calls default to projectID when they name no project.
+// Inbound adapters set it from the session's active project or a project-scoped endpoint.
+func WithActiveProject(ctx context.Context, projectID string) context.Context {
+	if projectID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, projectKey{}, projectID)
+}
+
+// ActiveProject returns the project calls default to, or "" when none is bound.
+func ActiveProject(ctx context.Context) string {
+	id, _ := ctx.Value(projectKey{}).(string)
+	return id
+}
@@ -0,0 +1,122 @@
+package blueprint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// MaxInstructionsBytes caps the size of the server instructions built by ServerInstructions.
+const MaxInstructionsBytes = 8 << 10
+
+const instructionsIntro = "This server holds the blueprint of one or more projects: each project is a source tree divided into zones, " +
+	"regex-scoped areas with a purpose, constraints and the agents assigned to work in them. " +
+	"Respect a zone's constraints when changing its files, and only write inside zones assigned to you.\n"
+
+const instructionsTools = `
+Tools:
+- list_projects, list_zones, get_zone: read the blueprint. Tools default project_id to the session's active project; bind one with set_active_project.
+- list_tree, list_matching_paths: explore the project tree and test zone patterns.
+- read_file, read_zone_files: read project files (refused outside the project root and under ignored paths).
+- build_context: load a zone's (or some paths') files in one call, prioritized and fitted to a token budget.
+- write_file, apply_patch: change files in your zones; every change is recorded (list_changes).
+- claim_zone, renew_lease, release_zone, list_leases: lease a zone while you edit it so other agents cannot write there.
+- create_task, list_tasks, claim_task, update_task_status, complete_task: hand work to the agents of the zones it touches.
+- create_run, set_run_task, get_run, list_runs: order tasks into a dependency graph and follow its progress; a task is ready once its prerequisites are done.
+- list_decisions, get_decision, create_decision: read and record the architecture decisions behind a zone.
+- add_zone_note, list_zone_notes, search_zone_notes: share and find gotchas, entry points and working commands per zone.
+- send_message, list_inbox, acknowledge_message, get_thread: message other agents or a zone's agents and answer in threads.
+- get_briefing: everything to read before starting work in a zone (prompt, zone, decisions, notes, owned paths, neighbours), fitted to a budget.
+- whoami, render_agent_prompt: your identity and your prompt for a zone and task.
+`
+
+// ServerInstructions returns MCP server instructions built from the blueprint: an overview, the
+// zones of projectID with their purposes and constraints (every project's when projectID is empty),
+// the zones assigned to agentID when set, and how to use the tools. Unknown ids are ignored, and a
+// service without stores only gets the overview and the tools. The
+// blueprint part is cut at a line boundary so the result stays within MaxInstructionsBytes.
+func (s *Service) ServerInstructions(projectID, agentID string) string {
+	var b strings.Builder
+	b.WriteString(instructionsIntro)
+	if s.Projects == nil || s.Zones == nil || s.Agents == nil {
+		return b.String() + instructionsTools
+	}
+	a := s.Agents.Get(agentID)
+	if a != nil {
+		fmt.Fprintf(&b, "\nYou are agent %s (agent_id %s).", agentLabel(a), a.ID)
+		if a.Description != "" {
+			b.WriteString(" " + a.Description)
+		}
+		b.WriteString("\n")
+	}
+
+	projects := s.Projects.List()
+	active := s.Projects.Get(projectID)
+	if active != nil {
+		fmt.Fprintf(&b, "\nActive project: %s (project_id %s, root %s). Tools use it when project_id is omitted.\n", active.Name, active.ID, active.RootDir)
+		projects = []*domain.Project{active}
+	} else if len(projects) == 0 {
+		b.WriteString("\nNo projects exist yet; create one with create_project.\n")
+	} else {
+		b.WriteString("\nProjects (pass project_id, or bind one with set_active_project):\n")
+	}
+	for _, p := range projects {
+		zones := s.ListZones(p.ID)
+		if active == nil {
+			fmt.Fprintf(&b, "\n%s (project_id %s, root %s), %d zone(s):\n", p.Name, p.ID, p.RootDir, len(zones))
+		} else if len(zones) == 0 {
+			b.WriteString("It has no zones yet.\n")
+		} else {
+			b.WriteString("\nZones:\n")
+		}
+		for _, z := range zones {
+			writeZoneLine(&b, z, a != nil && slices.Contains(z.AgentIDs, a.ID))
+		}
+	}
+	if a != nil {
+		if zones, _ := s.AgentZones(a.ID, ""); len(zones) == 0 {
+			b.WriteString("\nNo zones are assigned to you yet.\n")
+		}
+	}
+	return truncateLines(b.String(), MaxInstructionsBytes-len(instructionsTools)) + instructionsTools
+}
+
+// writeZoneLine writes one zone of the instructions: name, id, pattern, purpose, constraints and
+// agents, marking the caller's own zones.
+func writeZoneLine(b *strings.Builder, z *domain.Zone, yours bool) {
+	fmt.Fprintf(b, "- %s (zone_id %s", z.Name, z.ID)
+	if z.Pattern != "" {
+		fmt.Fprintf(b, ", pattern %s", z.Pattern)
+	}
+	b.WriteString(")")
+	if yours {
+		b.WriteString(" [yours]")
+	}
+	if z.Purpose != "" {
+		b.WriteString(": " + z.Purpose)
+	}
+	b.WriteString("\n")
+	for _, c := range z.Constraints {
+		b.WriteString("  - constraint: " + c + "\n")
+	}
+	if len(z.AssignedAgents) > 0 {
+		names := make([]string, len(z.AssignedAgents))
+		for i := range z.AssignedAgents {
+			names[i] = agentLabel(&z.AssignedAgents[i])
+		}
+		b.WriteString("  - agents: " + strings.Join(names, ", ") + "\n")
+	}
+}
+
+// truncateLines returns s cut at the last line boundary that keeps it, with a closing note, within
+// max bytes.
+func truncateLines(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	const note = "… (truncated; use list_projects and list_zones for the full blueprint)\n"
+	cut := strings.LastIndex(s[:max-len(note)], "\n")
+	return s[:cut+1] + note
+}
@@ -0,0 +1,228 @@
+package blueprint
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"operators-mcp/internal/domain"
+)
+
+// DefaultLeaseTTL is the lease duration used when ClaimZone or RenewLease is given no TTL.
+const DefaultLeaseTTL = 10 * time.Minute
+
+// MaxLeaseTTL is the longest lease that can be claimed or renewed at once.
+const MaxLeaseTTL = 24 * time.Hour
+
+// ClaimZone gives the acting agent (see ActingAgent) an exclusive write lease on one of its zones
+// for ttl (DefaultLeaseTTL when zero). While the lease is active, WriteFile and ApplyPatch refuse
+// other agents' changes to the zone's paths with ZONE_LEASED. Claiming a zone one already holds
+// extends the lease; a zone leased by another agent cannot be claimed until it is released or
+// expires.
+func (s *Service) ClaimZone(ctx context.Context, zoneID, agentID string, ttl time.Duration) (*domain.ZoneLease, error) {
+	z, agentID, ttl, err := s.leaseArgs(ctx, zoneID, agentID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(z.AgentIDs, agentID) {
+		return nil, &domain.StructuredError{Code: "OUT_OF_ZONE", Message: "zone " + z.Name + " is not assigned to agent " + agentID}
+	}
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	now := time.Now().UTC()
+	l := s.Leases.Get(z.ID)
+	switch {
+	case l == nil || !l.Active(now):
+		l = &domain.ZoneLease{ZoneID: z.ID, ProjectID: z.ProjectID, AgentID: agentID, AcquiredAt: now}
+	case l.AgentID != agentID:
+		return nil, zoneLeased(z, l)
+	}
+	l.ExpiresAt = now.Add(ttl)
+	return s.putLease(l)
+}
+
+// RenewLease extends the acting agent's active lease on a zone to ttl from now (DefaultLeaseTTL
+// when zero). It fails with LEASE_NOT_HELD when the agent holds no active lease on the zone.
+func (s *Service) RenewLease(ctx context.Context, zoneID, agentID string, ttl time.Duration) (*domain.ZoneLease, error) {
+	z, agentID, ttl, err := s.leaseArgs(ctx, zoneID, agentID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	now := time.Now().UTC()
+	l := s.Leases.Get(z.ID)
+	if l == nil || !l.Active(now) || l.AgentID != agentID {
+		return nil, &domain.StructuredError{Code: "LEASE_NOT_HELD", Message: "agent " + agentID + " holds no active lease on zone " + z.Name}
+	}
+	l.ExpiresAt = now.Add(ttl)
+	return s.putLease(l)
+}
+
+// ReleaseZone ends the lease on a zone. An identified caller or an explicit agentID may only
+// release its own lease (LEASE_NOT_HELD otherwise); anonymous callers without agentID
+// (orchestrators, the UI) may release any lease. Releasing a zone without a lease is a no-op.
+func (s *Service) ReleaseZone(ctx context.Context, zoneID, agentID string) error {
+	if s.Leases == nil {
+		return errLeasesUnavailable
+	}
+	agentID, err := s.ActingAgent(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	z := s.Zones.Get(zoneID)
+	if z == nil {
+		return &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	l := s.Leases.Get(z.ID)
+	if l == nil {
+		return nil
+	}
+	if agentID != "" && l.AgentID != agentID && l.Active(time.Now()) {
+		return &domain.StructuredError{Code: "LEASE_NOT_HELD", Message: "zone " + z.Name + " is leased by agent " + l.AgentID}
+	}
+	return s.deleteLease(l)
+}
+
+// ListLeases returns the project's active leases ordered by zone id.
+func (s *Service) ListLeases(projectID string) ([]*domain.ZoneLease, error) {
+	if s.Leases == nil {
+		return nil, errLeasesUnavailable
+	}
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	now := time.Now()
+	out := []*domain.ZoneLease{}
+	for _, l := range s.Leases.List(projectID) {
+		if l.Active(now) {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+// leaseArgs resolves the zone, acting agent and TTL shared by ClaimZone and RenewLease.
+func (s *Service) leaseArgs(ctx context.Context, zoneID, agentID string, ttl time.Duration) (*domain.Zone, string, time.Duration, error) {
+	if s.Leases == nil {
+		return nil, "", 0, errLeasesUnavailable
+	}
+	agentID, err := s.ActingAgent(ctx, agentID)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if agentID == "" {
+		return nil, "", 0, &domain.StructuredError{Code: "AGENT_REQUIRED", Message: "a lease must be held by an agent"}
+	}
+	if s.Agents.Get(agentID) == nil {
+		return nil, "", 0, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	z := s.Zones.Get(zoneID)
+	if z == nil {
+		return nil, "", 0, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	if ttl == 0 {
+		ttl = DefaultLeaseTTL
+	}
+	if ttl < 0 || ttl > MaxLeaseTTL {
+		return nil, "", 0, &domain.StructuredError{Code: "INVALID_TTL", Message: "ttl must be positive and at most " + MaxLeaseTTL.String()}
+	}
+	return z, agentID, ttl, nil
+}
+
+// checkLeases refuses paths lying in a zone actively leased by an agent other than agentID.
+// Writers call it holding leaseMu until their write is done.
+func (s *Service) checkLeases(p *domain.Project, agentID string, paths ...string) error {
+	if s.Leases == nil {
+		return nil
+	}
+	now := time.Now()
+	var msgs []string
+	for _, l := range s.Leases.List(p.ID) {
+		if l.AgentID == agentID || !l.Active(now) {
+			continue
+		}
+		z := s.Zones.Get(l.ZoneID)
+		if z == nil {
+			continue
+		}
+		for _, rel := range paths {
+			if z.Contains(rel) {
+				msgs = append(msgs, rel+" is in zone "+z.Name+" leased by agent "+l.AgentID+" until "+l.ExpiresAt.UTC().Format(time.RFC3339))
+			}
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return &domain.StructuredError{Code: "ZONE_LEASED", Message: strings.Join(msgs, "; ")}
+}
+
+func zoneLeased(z *domain.Zone, l *domain.ZoneLease) error {
+	return &domain.StructuredError{Code: "ZONE_LEASED", Message: "zone " + z.Name + " is leased by agent " + l.AgentID + " until " + l.ExpiresAt.UTC().Format(time.RFC3339)}
+}
+
+// putLease stores l and notifies subscribers.
+func (s *Service) putLease(l *domain.ZoneLease) (*domain.ZoneLease, error) {
+	return changed(s, l, s.Leases.Put(l))
+}
+
+// deleteLease removes l and notifies subscribers.
+func (s *Service) deleteLease(l *domain.ZoneLease) error {
+	if err := s.Leases.Delete(l.ZoneID); err != nil {
+		return err
+	}
+	s.removed(l)
+	return nil
+}
+
+// dropZoneLease removes the lease of a deleted zone.
+func (s *Service) dropZoneLease(zoneID string) error {
+	if s.Leases == nil {
+		return nil
+	}
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	if l := s.Leases.Get(zoneID); l != nil {
+		return s.deleteLease(l)
+	}
+	return nil
+}
+
+// releaseAgentLeases removes the leases held by a deleted agent.
+func (s *Service) releaseAgentLeases(agentID string) error {
+	if s.Leases == nil {
+		return nil
+	}
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	for _, p := range s.Projects.List() {
+		for _, l := range s.Leases.List(p.ID) {
+			if l.AgentID != agentID {
+				continue
+			}
+			if err := s.deleteLease(l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dropProjectLeases removes every lease of a deleted project.
+func (s *Service) dropProjectLeases(projectID string) error {
+	if s.Leases == nil {
+		return nil
+	}
+	s.leaseMu.Lock()
+	defer s.leaseMu.Unlock()
+	for _, l := range s.Leases.List(projectID) {
+		if err := s.Leases.Delete(l.ZoneID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package blueprint
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"operators-mcp/internal/domain"
+)
+
+// MessageDraft is a message to send with SendMessage. A new message is addressed to an agent
+// (ToAgentID) or to every agent of a zone (ZoneID) of the project. A reply (ReplyTo) joins the
+// thread of the message it answers, in that message's project, and goes back to the other party
+// unless a recipient is given.
+type MessageDraft struct {
+	ProjectID string
+	ToAgentID string
+	ZoneID    string
+	ReplyTo   string
+	Subject   string
+	Body      string
+}
+
+// SendMessage stores a message from the acting agent (see ActingAgent; anonymous callers may
+// send too) and returns one copy per recipient. The sender is never a recipient of its own zone
+// message, and an agent cannot address itself. A reply joins the parent's thread, which an
+// identified sender must already take part in. Subscribers get a message event per copy, which
+// adapters use to deliver it.
+func (s *Service) SendMessage(ctx context.Context, fromAgentID string, d MessageDraft) ([]*domain.Message, error) {
+	if s.Messages == nil {
+		return nil, errMessagesUnavailable
+	}
+	fromAgentID, err := s.ActingAgent(ctx, fromAgentID)
+	if err != nil {
+		return nil, err
+	}
+	if fromAgentID != "" && s.Agents.Get(fromAgentID) == nil {
+		return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	if strings.TrimSpace(d.Body) == "" {
+		return nil, &domain.StructuredError{Code: "BODY_REQUIRED", Message: "body is required"}
+	}
+	msg := domain.Message{FromAgentID: fromAgentID, Subject: strings.TrimSpace(d.Subject), Body: d.Body, CreatedAt: time.Now().UTC()}
+	if d.ReplyTo != "" {
+		parent, err := s.GetMessage(d.ReplyTo)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.threadOf(parent, fromAgentID); fromAgentID != "" && err != nil {
+			return nil, err
+		}
+		msg.ProjectID, msg.ThreadID, msg.ReplyTo = parent.ProjectID, parent.Thread(), parent.ID
+		if msg.Subject == "" {
+			msg.Subject = parent.Subject
+		}
+		if d.ToAgentID == "" && d.ZoneID == "" {
+			d.ToAgentID = parent.FromAgentID
+			if fromAgentID != "" && fromAgentID == parent.FromAgentID {
+				d.ToAgentID = parent.ToAgentID
+			}
+		}
+	} else {
+		msg.ProjectID = d.ProjectID
+		if s.Projects.Get(msg.ProjectID) == nil {
+			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+	}
+	recipients, err := s.messageRecipients(msg.ProjectID, fromAgentID, d.ToAgentID, d.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+	msg.ZoneID = d.ZoneID
+	out := make([]*domain.Message, 0, len(recipients))
+	for _, to := range recipients {
+		m := msg
+		m.ToAgentID = to
+		stored, err := s.Messages.Create(&m)
+		if err != nil {
+			return nil, err
+		}
+		if msg.ThreadID == "" {
+			msg.ThreadID = stored.ID
+		}
+		out = append(out, stored)
+	}
+	for _, m := range out {
+		s.notify(recordEvent(m))
+	}
+	return out, nil
+}
+
+// GetMessage returns a message by id.
+func (s *Service) GetMessage(messageID string) (*domain.Message, error) {
+	if s.Messages == nil {
+		return nil, errMessagesUnavailable
+	}
+	m := s.Messages.Get(messageID)
+	if m == nil {
+		return nil, &domain.StructuredError{Code: "MESSAGE_NOT_FOUND", Message: "message not found"}
+	}
+	return m, nil
+}
+
+// ListInbox returns the messages sent to the acting agent, oldest first, in one project or in
+// all of them when projectID is empty. With unreadOnly, acknowledged messages are left out.
+func (s *Service) ListInbox(ctx context.Context, agentID, projectID string, unreadOnly bool) ([]*domain.Message, error) {
+	if s.Messages == nil {
+		return nil, errMessagesUnavailable
+	}
+	agentID, err := s.ActingAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if agentID == "" {
+		return nil, &domain.StructuredError{Code: "AGENT_REQUIRED", Message: "an inbox belongs to an agent"}
+	}
+	if s.Agents.Get(agentID) == nil {
+		return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	projects := s.Projects.List()
+	if projectID != "" {
+		p := s.Projects.Get(projectID)
+		if p == nil {
+			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		projects = []*domain.Project{p}
+	}
+	out := []*domain.Message{}
+	for _, p := range projects {
+		for _, m := range s.Messages.List(p.ID) {
+			if m.ToAgentID == agentID && (!unreadOnly || !m.Acked()) {
+				out = append(out, m)
+			}
+		}
+	}
+	slices.SortStableFunc(out, func(a, b *domain.Message) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return out, nil
+}
+
+// AcknowledgeMessage marks a message read by its recipient. Identified callers may only
+// acknowledge their own messages; acknowledging twice keeps the first time.
+func (s *Service) AcknowledgeMessage(ctx context.Context, messageID, agentID string) (*domain.Message, error) {
+	m, err := s.GetMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if agentID, err = s.ActingAgent(ctx, agentID); err != nil {
+		return nil, err
+	}
+	if agentID != "" && agentID != m.ToAgentID {
+		return nil, &domain.StructuredError{Code: "NOT_RECIPIENT", Message: "message is addressed to agent " + m.ToAgentID}
+	}
+	if m.Acked() {
+		return m, nil
+	}
+	m.AckedAt = time.Now().UTC()
+	m, err = s.Messages.Update(m)
+	return changed(s, m, err)
+}
+
+// MessageThread returns every message of the thread containing messageID, oldest first. The
+// acting agent must have sent or received a message of the thread (NOT_PARTICIPANT).
+func (s *Service) MessageThread(ctx context.Context, messageID, agentID string) ([]*domain.Message, error) {
+	m, err := s.GetMessage(messageID)
+	if err != nil {
+		return nil, err
+	}
+	if agentID, err = s.ActingAgent(ctx, agentID); err != nil {
+		return nil, err
+	}
+	if agentID == "" {
+		return nil, &domain.StructuredError{Code: "AGENT_REQUIRED", Message: "a thread is read by one of its agents"}
+	}
+	return s.threadOf(m, agentID)
+}
+
+// threadOf returns the messages of m's thread, or NOT_PARTICIPANT unless agentID sent or received
+// one of them.
+func (s *Service) threadOf(m *domain.Message, agentID string) ([]*domain.Message, error) {
+	thread := m.Thread()
+	out := []*domain.Message{}
+	participant := false
+	for _, other := range s.Messages.List(m.ProjectID) {
+		if other.Thread() == thread {
+			out = append(out, other)
+			participant = participant || other.FromAgentID == agentID || other.ToAgentID == agentID
+		}
+	}
+	if !participant {
+		return nil, &domain.StructuredError{Code: "NOT_PARTICIPANT", Message: "agent " + agentID + " has not sent or received a message of this thread"}
+	}
+	return out, nil
+}
+
+// messageRecipients resolves the agents a message goes to.
+func (s *Service) messageRecipients(projectID, fromAgentID, toAgentID, zoneID string) ([]string, error) {
+	switch {
+	case toAgentID != "" && zoneID != "":
+		return nil, &domain.StructuredError{Code: "INVALID_RECIPIENT", Message: "address an agent or a zone, not both"}
+	case toAgentID != "":
+		if toAgentID == fromAgentID {
+			return nil, &domain.StructuredError{Code: "INVALID_RECIPIENT", Message: "an agent cannot message itself"}
+		}
+		if s.Agents.Get(toAgentID) == nil {
+			return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+		}
+		return []string{toAgentID}, nil
+	case zoneID != "":
+		z := s.Zones.Get(zoneID)
+		if z == nil || z.ProjectID != projectID {
+			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found in project: " + zoneID}
+		}
+		recipients := slices.DeleteFunc(slices.Clone(z.AgentIDs), func(id string) bool { return id == fromAgentID })
+		if len(recipients) == 0 {
+			return nil, &domain.StructuredError{Code: "INVALID_RECIPIENT", Message: "zone " + z.Name + " has no other agents"}
+		}
+		return recipients, nil
+	}
+	return nil, &domain.StructuredError{Code: "INVALID_RECIPIENT", Message: "to_agent_id or zone_id is required"}
+}
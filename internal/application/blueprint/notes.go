@@ -0,0 +1,258 @@
+package blueprint
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"operators-mcp/internal/domain"
+)
+
+// Zone note limits. Unpinned notes expire after their TTL, and a zone keeps at most MaxZoneNotes
+// of them: adding one more evicts the oldest. Pinned notes never expire but are capped too.
+const (
+	DefaultNoteTTL  = 30 * 24 * time.Hour
+	MaxNoteTTL      = 365 * 24 * time.Hour
+	MaxNoteLength   = 2000
+	MaxNoteTags     = 10
+	MaxZoneNotes    = 100
+	MaxPinnedNotes  = 20
+	defaultNoteHits = 50
+)
+
+// NoteDraft is a note to add with AddZoneNote. TTL defaults to DefaultNoteTTL and is ignored for
+// pinned notes.
+type NoteDraft struct {
+	Text   string
+	Tags   []string
+	Pinned bool
+	TTL    time.Duration
+}
+
+// NoteQuery selects notes in SearchZoneNotes. Every word of Query must appear in the note's text
+// or tags (case-insensitively), and the note must carry every tag in Tags. Limit defaults to 50.
+type NoteQuery struct {
+	ZoneID string
+	Query  string
+	Tags   []string
+	Limit  int
+}
+
+// AddZoneNote adds a note to a zone on behalf of the acting agent (see ActingAgent; anonymous
+// callers may add notes too). Tags are lowercased and deduplicated. Expired notes of the zone are
+// removed, and the oldest unpinned notes are evicted beyond MaxZoneNotes.
+func (s *Service) AddZoneNote(ctx context.Context, zoneID, agentID string, d NoteDraft) (*domain.ZoneNote, error) {
+	if s.Notes == nil {
+		return nil, errNotesUnavailable
+	}
+	z := s.Zones.Get(zoneID)
+	if z == nil {
+		return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	agentID, err := s.ActingAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if agentID != "" && s.Agents.Get(agentID) == nil {
+		return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	text := strings.TrimSpace(d.Text)
+	if text == "" {
+		return nil, &domain.StructuredError{Code: "TEXT_REQUIRED", Message: "note text is required"}
+	}
+	if len(text) > MaxNoteLength {
+		return nil, &domain.StructuredError{Code: "NOTE_TOO_LONG", Message: fmt.Sprintf("note is %d bytes, at most %d are allowed", len(text), MaxNoteLength)}
+	}
+	tags := noteTags(d.Tags)
+	if len(tags) > MaxNoteTags {
+		return nil, &domain.StructuredError{Code: "TOO_MANY_TAGS", Message: fmt.Sprintf("at most %d tags are allowed", MaxNoteTags)}
+	}
+	ttl := d.TTL
+	if ttl == 0 {
+		ttl = DefaultNoteTTL
+	}
+	if !d.Pinned && (ttl < 0 || ttl > MaxNoteTTL) {
+		return nil, &domain.StructuredError{Code: "INVALID_TTL", Message: "ttl must be positive and at most one year"}
+	}
+
+	s.noteMu.Lock()
+	defer s.noteMu.Unlock()
+	now := time.Now().UTC()
+	notes, err := s.pruneZoneNotes(z, now)
+	if err != nil {
+		return nil, err
+	}
+	if d.Pinned && countNotes(notes, true) >= MaxPinnedNotes {
+		return nil, &domain.StructuredError{Code: "TOO_MANY_PINNED", Message: fmt.Sprintf("zone %s already has %d pinned notes; delete one first", z.Name, MaxPinnedNotes)}
+	}
+	n := &domain.ZoneNote{ProjectID: z.ProjectID, ZoneID: z.ID, AgentID: agentID, Text: text, Tags: tags, Pinned: d.Pinned, CreatedAt: now}
+	if !d.Pinned {
+		n.ExpiresAt = now.Add(ttl)
+	}
+	if n, err = s.Notes.Create(n); err != nil {
+		return nil, err
+	}
+	s.notify(recordEvent(n))
+	if !n.Pinned {
+		for excess := countNotes(notes, false) + 1 - MaxZoneNotes; excess > 0; excess-- {
+			i := slices.IndexFunc(notes, func(old *domain.ZoneNote) bool { return !old.Pinned })
+			if err := s.deleteNote(notes[i]); err != nil {
+				return nil, err
+			}
+			notes = slices.Delete(notes, i, i+1)
+		}
+	}
+	return n, nil
+}
+
+// ListZoneNotes returns the zone's current notes, pinned first and then newest first, optionally
+// only those tagged tag.
+func (s *Service) ListZoneNotes(zoneID, tag string) ([]*domain.ZoneNote, error) {
+	if s.Notes == nil {
+		return nil, errNotesUnavailable
+	}
+	z := s.Zones.Get(zoneID)
+	if z == nil {
+		return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	var tags []string
+	if tag != "" {
+		tags = []string{tag}
+	}
+	return s.SearchZoneNotes(z.ProjectID, NoteQuery{ZoneID: z.ID, Tags: tags, Limit: MaxZoneNotes + MaxPinnedNotes})
+}
+
+// SearchZoneNotes returns the project's current notes matching q, pinned first and then newest
+// first.
+func (s *Service) SearchZoneNotes(projectID string, q NoteQuery) ([]*domain.ZoneNote, error) {
+	if s.Notes == nil {
+		return nil, errNotesUnavailable
+	}
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultNoteHits
+	}
+	words := strings.Fields(strings.ToLower(q.Query))
+	tags := noteTags(q.Tags)
+	now := time.Now().UTC()
+	out := []*domain.ZoneNote{}
+	notes := s.Notes.List(projectID)
+	slices.Reverse(notes)
+	for _, n := range notes {
+		if n.Expired(now) || (q.ZoneID != "" && n.ZoneID != q.ZoneID) {
+			continue
+		}
+		if !slices.ContainsFunc(tags, func(tag string) bool { return !slices.Contains(n.Tags, tag) }) && noteMatches(n, words) {
+			out = append(out, n)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b *domain.ZoneNote) int {
+		switch {
+		case a.Pinned == b.Pinned:
+			return 0
+		case a.Pinned:
+			return -1
+		}
+		return 1
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// DeleteZoneNote deletes a note. Identified callers may only delete their own notes; anonymous
+// callers may delete any note.
+func (s *Service) DeleteZoneNote(ctx context.Context, noteID string) error {
+	if s.Notes == nil {
+		return errNotesUnavailable
+	}
+	n := s.Notes.Get(noteID)
+	if n == nil {
+		return &domain.StructuredError{Code: "NOTE_NOT_FOUND", Message: "note not found"}
+	}
+	if caller := CallerID(ctx); caller != "" && n.AgentID != caller {
+		return &domain.StructuredError{Code: "NOT_AUTHOR", Message: "note was written by agent " + n.AgentID}
+	}
+	return s.deleteNote(n)
+}
+
+// pruneZoneNotes deletes the zone's expired notes and returns the others, oldest first.
+func (s *Service) pruneZoneNotes(z *domain.Zone, now time.Time) ([]*domain.ZoneNote, error) {
+	var out []*domain.ZoneNote
+	for _, n := range s.Notes.List(z.ProjectID) {
+		if n.ZoneID != z.ID {
+			continue
+		}
+		if n.Expired(now) {
+			if err := s.deleteNote(n); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+// dropZoneNotes deletes the notes of a deleted zone.
+func (s *Service) dropZoneNotes(z *domain.Zone) error {
+	if s.Notes == nil {
+		return nil
+	}
+	for _, n := range s.Notes.List(z.ProjectID) {
+		if n.ZoneID != z.ID {
+			continue
+		}
+		if err := s.deleteNote(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteNote deletes a note and notifies subscribers.
+func (s *Service) deleteNote(n *domain.ZoneNote) error {
+	if err := s.Notes.Delete(n.ID); err != nil {
+		return err
+	}
+	s.removed(n)
+	return nil
+}
+
+// noteTags returns tags trimmed, lowercased and without empty or duplicate entries.
+func noteTags(tags []string) []string {
+	out := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+func noteMatches(n *domain.ZoneNote, words []string) bool {
+	text := strings.ToLower(n.Text + " " + strings.Join(n.Tags, " "))
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func countNotes(notes []*domain.ZoneNote, pinned bool) int {
+	count := 0
+	for _, n := range notes {
+		if n.Pinned == pinned {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,253 @@
+package blueprint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// filePatch is the part of a unified diff that touches one file. oldPath is empty for
+// created files (--- /dev/null) and newPath is empty for deleted files (+++ /dev/null).
+type filePatch struct {
+	oldPath, newPath string
+	hunks            []*hunk
+}
+
+// path returns the project-relative path the patch touches.
+func (fp *filePatch) path() string {
+	if fp.newPath != "" {
+		return fp.newPath
+	}
+	return fp.oldPath
+}
+
+// hunk is one @@ section. lines keep their ' ', '-' or '+' prefix.
+type hunk struct {
+	header   string
+	oldStart int
+	oldLines int
+	newLines int
+	lines    []string
+	noEOLOld bool
+	noEOLNew bool
+}
+
+func invalidPatch(format string, args ...any) error {
+	return &domain.StructuredError{Code: "INVALID_PATCH", Message: fmt.Sprintf(format, args...)}
+}
+
+// parsePatch parses a unified diff (plain or git style) into per-file patches.
+func parsePatch(text string) ([]*filePatch, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var out []*filePatch
+	for i := 0; i < len(lines); {
+		if !strings.HasPrefix(lines[i], "--- ") {
+			i++
+			continue
+		}
+		if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+			return nil, invalidPatch("line %d: --- header without +++ header", i+1)
+		}
+		fp := &filePatch{oldPath: patchPath(lines[i][4:], "a/"), newPath: patchPath(lines[i+1][4:], "b/")}
+		if fp.oldPath == "" && fp.newPath == "" {
+			return nil, invalidPatch("line %d: both sides are /dev/null", i+1)
+		}
+		if fp.oldPath != "" && fp.newPath != "" && fp.oldPath != fp.newPath {
+			return nil, invalidPatch("line %d: renames are not supported (%s -> %s)", i+1, fp.oldPath, fp.newPath)
+		}
+		i += 2
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@") {
+			h, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			fp.hunks = append(fp.hunks, h)
+			i = next
+		}
+		if len(fp.hunks) == 0 {
+			return nil, invalidPatch("%s: no hunks", fp.path())
+		}
+		out = append(out, fp)
+	}
+	if len(out) == 0 {
+		return nil, invalidPatch("no file headers (--- / +++) found")
+	}
+	return out, nil
+}
+
+// patchPath extracts the path from a ---/+++ header value: the timestamp after a tab is
+// dropped, git's a/ or b/ prefix is removed and /dev/null becomes "".
+func patchPath(s, gitPrefix string) string {
+	if i := strings.IndexByte(s, '\t'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(s, gitPrefix)
+}
+
+// parseHunk parses the hunk whose header is lines[i] and returns the index after it.
+func parseHunk(lines []string, i int) (*hunk, int, error) {
+	h := &hunk{header: lines[i]}
+	var oldRange, newRange string
+	if _, err := fmt.Sscanf(lines[i], "@@ -%s +%s @@", &oldRange, &newRange); err != nil {
+		return nil, 0, invalidPatch("line %d: malformed hunk header %q", i+1, lines[i])
+	}
+	var err error
+	if h.oldStart, h.oldLines, err = parseRange(oldRange); err != nil {
+		return nil, 0, invalidPatch("line %d: %v", i+1, err)
+	}
+	if _, h.newLines, err = parseRange(newRange); err != nil {
+		return nil, 0, invalidPatch("line %d: %v", i+1, err)
+	}
+	i++
+	oldSeen, newSeen := 0, 0
+	for i < len(lines) && (oldSeen < h.oldLines || newSeen < h.newLines) {
+		line := lines[i]
+		if line == "" {
+			line = " " // editors often strip the space of empty context lines
+		}
+		switch line[0] {
+		case ' ':
+			oldSeen++
+			newSeen++
+		case '-':
+			oldSeen++
+		case '+':
+			newSeen++
+		case '\\':
+			h.markNoEOL()
+			i++
+			continue
+		default:
+			return nil, 0, invalidPatch("line %d: unexpected %q in hunk", i+1, line)
+		}
+		h.lines = append(h.lines, line)
+		i++
+	}
+	if oldSeen != h.oldLines || newSeen != h.newLines {
+		return nil, 0, invalidPatch("hunk %q: expected %d old and %d new lines, got %d and %d", h.header, h.oldLines, h.newLines, oldSeen, newSeen)
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], `\`) {
+		h.markNoEOL()
+		i++
+	}
+	return h, i, nil
+}
+
+// markNoEOL records a "\ No newline at end of file" marker for the side of the last line.
+func (h *hunk) markNoEOL() {
+	if len(h.lines) == 0 {
+		return
+	}
+	switch h.lines[len(h.lines)-1][0] {
+	case '-':
+		h.noEOLOld = true
+	case '+':
+		h.noEOLNew = true
+	default:
+		h.noEOLOld, h.noEOLNew = true, true
+	}
+}
+
+func parseRange(s string) (start, count int, err error) {
+	count = 1
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	if start, err = strconv.Atoi(startStr); err != nil {
+		return 0, 0, fmt.Errorf("bad range %q", s)
+	}
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, fmt.Errorf("bad range %q", s)
+		}
+	}
+	return start, count, nil
+}
+
+// apply applies the patch to old and returns the new content and the number of lines added
+// and removed. Each hunk is tried at its stated position (shifted by earlier hunks) and then
+// at the nearest position where its context and removed lines match.
+func (fp *filePatch) apply(old []byte) ([]byte, int, int, error) {
+	src := splitLines(string(old))
+	eol := len(old) == 0 || strings.HasSuffix(string(old), "\n")
+	var out []string
+	pos, offset, added, removed := 0, 0, 0, 0
+	for _, h := range fp.hunks {
+		var before, after []string
+		for _, l := range h.lines {
+			if l[0] != '+' {
+				before = append(before, l[1:])
+			}
+			if l[0] != '-' {
+				after = append(after, l[1:])
+			}
+			switch l[0] {
+			case '+':
+				added++
+			case '-':
+				removed++
+			}
+		}
+		want := h.oldStart - 1 + offset
+		if h.oldLines == 0 {
+			want = h.oldStart + offset // an empty old range names the line after which to insert
+		}
+		at := findLines(src, before, pos, want)
+		if at < 0 {
+			return nil, 0, 0, &domain.StructuredError{Code: "PATCH_CONFLICT", Message: fmt.Sprintf("%s: hunk %s does not apply", fp.path(), h.header)}
+		}
+		out = append(out, src[pos:at]...)
+		out = append(out, after...)
+		pos = at + len(before)
+		offset += len(after) - len(before)
+		if pos == len(src) {
+			eol = !h.noEOLNew
+		}
+	}
+	out = append(out, src[pos:]...)
+	if len(out) == 0 {
+		return []byte{}, added, removed, nil
+	}
+	text := strings.Join(out, "\n")
+	if eol {
+		text += "\n"
+	}
+	return []byte(text), added, removed, nil
+}
+
+// splitLines splits s into lines without their terminating newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// findLines returns the index at or after from where want occurs in src, preferring the
+// position closest to near, or -1.
+func findLines(src, want []string, from, near int) int {
+	match := func(at int) bool {
+		if at < from || at+len(want) > len(src) {
+			return false
+		}
+		for i, l := range want {
+			if src[at+i] != l {
+				return false
+			}
+		}
+		return true
+	}
+	for d := 0; near-d >= from || near+d <= len(src); d++ {
+		if match(near - d) {
+			return near - d
+		}
+		if d > 0 && match(near+d) {
+			return near + d
+		}
+	}
+	return -1
+}
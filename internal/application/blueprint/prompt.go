@@ -0,0 +1,185 @@
+package blueprint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// maxPromptPaths caps the number of matched paths listed in a zone-aware agent prompt.
+const maxPromptPaths = 200
+
+// AgentPrompt is an agent's prompt rendered for a client, optionally in the context of a zone.
+type AgentPrompt struct {
+	Agent *domain.Agent
+	Zone  *domain.Zone // nil when rendered without a zone
+	Text  string
+}
+
+// RenderAgentPrompt executes the agent's prompt template with the agent, the zone and its project
+// (when zoneID is set), the task and the agent's declared variables. vars may only name declared
+// variables; omitted ones take their default and required ones must be non-empty.
+// Templates that do not reference .Zone or .Task get the zone summary (name, purpose, constraints,
+// accepted decisions and matched paths) and the task appended, so plain-text prompts stay useful.
+func (s *Service) RenderAgentPrompt(agentID, zoneID, task string, vars map[string]string) (*AgentPrompt, error) {
+	return s.renderAgentPrompt(agentID, zoneID, task, vars, true)
+}
+
+// renderAgentPrompt is RenderAgentPrompt; zoneSummary controls whether the zone summary is appended
+// for templates that do not reference .Zone.
+func (s *Service) renderAgentPrompt(agentID, zoneID, task string, vars map[string]string, zoneSummary bool) (*AgentPrompt, error) {
+	a := s.Agents.Get(agentID)
+	if a == nil {
+		return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	parsed, err := parsePrompt(a.Prompt, a.Variables)
+	if isCode(err, "INVALID_PROMPT") {
+		// Prompts stored before they became templates may contain a literal "{{".
+		parsed, err = &parsedPrompt{uses: map[string]bool{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	values, err := variableValues(a.Variables, vars)
+	if err != nil {
+		return nil, err
+	}
+	out := &AgentPrompt{Agent: a}
+	data := map[string]any{
+		"Agent":   map[string]any{"ID": a.ID, "Name": a.Name, "Description": a.Description},
+		"Project": map[string]any{"ID": "", "Name": "", "RootDir": ""},
+		"Zone":    map[string]any{"ID": "", "Name": "", "Purpose": "", "Constraints": []string(nil), "Paths": []string(nil), "Decisions": []string(nil)},
+		"Task":    strings.TrimSpace(task),
+		"Vars":    values,
+	}
+	var paths, decisions []string
+	if zoneID != "" {
+		z := s.GetZone(zoneID)
+		if z == nil {
+			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+		}
+		p := s.Projects.Get(z.ProjectID)
+		if p == nil {
+			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		if paths, err = s.zonePaths(p, z); err != nil {
+			return nil, err
+		}
+		decisions = s.decisionSummaries(z)
+		out.Zone = z
+		data["Project"] = map[string]any{"ID": p.ID, "Name": p.Name, "RootDir": p.RootDir}
+		data["Zone"] = map[string]any{"ID": z.ID, "Name": z.Name, "Purpose": z.Purpose, "Constraints": z.Constraints, "Paths": paths, "Decisions": decisions}
+	}
+
+	var body strings.Builder
+	if strings.TrimSpace(a.Prompt) == "" {
+		fmt.Fprintf(&body, "You are the %s agent.", agentLabel(a))
+		if a.Description != "" {
+			body.WriteString(" " + a.Description)
+		}
+	} else if parsed.tmpl == nil {
+		body.WriteString(a.Prompt)
+	} else if err := parsed.tmpl.Execute(&body, data); err != nil {
+		return nil, &domain.StructuredError{Code: "INVALID_PROMPT", Message: err.Error()}
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(body.String(), "\n") + "\n")
+
+	if out.Zone != nil && zoneSummary && !parsed.uses["Zone"] {
+		writeZoneSummary(&b, out.Zone, paths, decisions)
+	}
+	if t := data["Task"].(string); t != "" && !parsed.uses["Task"] {
+		fmt.Fprintf(&b, "\n## Task\n%s\n", t)
+	}
+	out.Text = b.String()
+	return out, nil
+}
+
+// variableValues resolves the declared variables from the supplied values and defaults.
+func variableValues(declared []domain.PromptVariable, supplied map[string]string) (map[string]string, error) {
+	out := make(map[string]string, len(declared))
+	known := make(map[string]bool, len(declared))
+	for _, v := range declared {
+		known[v.Name] = true
+		val, ok := supplied[v.Name]
+		if !ok || val == "" {
+			val = v.Default
+		}
+		if v.Required && val == "" {
+			return nil, &domain.StructuredError{Code: "MISSING_VARIABLE", Message: "missing required variable: " + v.Name}
+		}
+		out[v.Name] = val
+	}
+	for name := range supplied {
+		if !known[name] {
+			return nil, &domain.StructuredError{Code: "UNKNOWN_VARIABLE", Message: "unknown variable: " + name}
+		}
+	}
+	return out, nil
+}
+
+// writeZoneSummary appends the zone's name, purpose, constraints, decisions and matched paths.
+func writeZoneSummary(b *strings.Builder, z *domain.Zone, paths, decisions []string) {
+	fmt.Fprintf(b, "\n## Zone: %s\n", z.Name)
+	if z.Purpose != "" {
+		fmt.Fprintf(b, "Purpose: %s\n", z.Purpose)
+	}
+	if len(z.Constraints) > 0 {
+		b.WriteString("Constraints:\n")
+		for _, c := range z.Constraints {
+			fmt.Fprintf(b, "- %s\n", c)
+		}
+	}
+	if len(decisions) > 0 {
+		b.WriteString("Decisions:\n")
+		for _, d := range decisions {
+			fmt.Fprintf(b, "- %s\n", d)
+		}
+	}
+	fmt.Fprintf(b, "Paths (%d):\n", len(paths))
+	for i, path := range paths {
+		if i == maxPromptPaths {
+			fmt.Fprintf(b, "- ... and %d more\n", len(paths)-maxPromptPaths)
+			break
+		}
+		fmt.Fprintf(b, "- %s\n", path)
+	}
+	b.WriteString("Only change files in this zone.\n")
+}
+
+// zonePaths returns the project-relative paths (files and directories) that belong to the zone,
+// sorted, excluding the project's ignored paths.
+func (s *Service) zonePaths(p *domain.Project, z *domain.Zone) ([]string, error) {
+	paths, err := s.PathMatcher.ListMatchingPaths(p.RootDir, "")
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, path := range paths {
+		if path != "" && z.Contains(path) && !isIgnored(p, path) {
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// isIgnored reports whether path is, or is under, one of the project's ignored paths.
+func isIgnored(p *domain.Project, path string) bool {
+	for _, ig := range p.IgnoredPaths {
+		if path == ig || strings.HasPrefix(path, ig+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// agentLabel returns the agent's name, or its id when unnamed.
+func agentLabel(a *domain.Agent) string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.ID
+}
@@ -0,0 +1,182 @@
+package blueprint
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// TaskRoute is the routing proposal for a task: the paths it references, the zones owning them
+// with their agents, the paths no zone owns and, when the work spans several zones, one suggested
+// subtask per zone.
+type TaskRoute struct {
+	Paths    []RoutedPath
+	Zones    []ZoneRoute
+	AgentIDs []string
+	Unowned  []string
+	Split    []TaskSplit
+}
+
+// RoutedPath is a referenced path and the ids of the zones containing it.
+type RoutedPath struct {
+	Path    string
+	ZoneIDs []string
+}
+
+// ZoneRoute is a zone involved in a task (with its agents resolved) and the paths it owns.
+type ZoneRoute struct {
+	Zone  *domain.Zone
+	Paths []string
+}
+
+// TaskSplit is a suggested subtask for one zone. AgentID is the zone's first assigned agent, or
+// empty when the zone has none.
+type TaskSplit struct {
+	Title   string
+	ZoneID  string
+	AgentID string
+	Paths   []string
+}
+
+// pathRef matches path-like tokens in free text: words of path characters with a slash or a file
+// extension. Candidates are filtered further by pathCandidate.
+var pathRef = regexp.MustCompile(`[A-Za-z0-9_.@~+\-/]*[A-Za-z0-9_\-]\.[A-Za-z][A-Za-z0-9]{0,7}\b|[A-Za-z0-9_.@~+\-]*/[A-Za-z0-9_.@~+\-/]*`)
+
+// RouteTask proposes who should do a task in the project: it extracts path references from
+// text (a task's title and description) and files, resolves them against the zones' patterns and
+// explicit paths, and returns the zones and agents involved. Title names the suggested subtasks.
+// References found in text that no zone owns are kept only when they exist under the root, which
+// filters out words like "and/or"; files are always kept.
+func (s *Service) RouteTask(projectID, title, text string, files []string) (*TaskRoute, error) {
+	p := s.Projects.Get(projectID)
+	if p == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	zones := s.ListZones(projectID)
+	matchers := domain.NewZoneMatchers(zones)
+	var paths []string
+	add := func(ref string, explicit bool) {
+		path, ok := pathCandidate(p.RootDir, ref)
+		if ok && !slices.Contains(paths, path) && (explicit || s.pathExists(p, matchers, path)) {
+			paths = append(paths, path)
+		}
+	}
+	for _, f := range files {
+		add(f, true)
+	}
+	for _, ref := range pathRef.FindAllString(text, -1) {
+		add(ref, false)
+	}
+	route := &TaskRoute{Paths: []RoutedPath{}, Zones: []ZoneRoute{}, AgentIDs: []string{}, Unowned: []string{}, Split: []TaskSplit{}}
+	byZone := map[string]int{}
+	for _, path := range paths {
+		rp := RoutedPath{Path: path, ZoneIDs: []string{}}
+		for _, m := range matchers {
+			if !zoneTouches(m, path) {
+				continue
+			}
+			z := m.Zone
+			rp.ZoneIDs = append(rp.ZoneIDs, z.ID)
+			i, ok := byZone[z.ID]
+			if !ok {
+				i = len(route.Zones)
+				byZone[z.ID] = i
+				route.Zones = append(route.Zones, ZoneRoute{Zone: z})
+			}
+			route.Zones[i].Paths = append(route.Zones[i].Paths, path)
+		}
+		if len(rp.ZoneIDs) == 0 {
+			route.Unowned = append(route.Unowned, path)
+		}
+		route.Paths = append(route.Paths, rp)
+	}
+	for _, zr := range route.Zones {
+		for _, id := range zr.Zone.AgentIDs {
+			if !slices.Contains(route.AgentIDs, id) {
+				route.AgentIDs = append(route.AgentIDs, id)
+			}
+		}
+	}
+	if len(route.Zones) > 1 {
+		if title = strings.TrimSpace(title); title == "" {
+			title = "Task"
+		}
+		for _, zr := range route.Zones {
+			split := TaskSplit{Title: title + " (" + zr.Zone.Name + ")", ZoneID: zr.Zone.ID, Paths: zr.Paths}
+			if len(zr.Zone.AgentIDs) > 0 {
+				split.AgentID = zr.Zone.AgentIDs[0]
+			}
+			route.Split = append(route.Split, split)
+		}
+	}
+	return route, nil
+}
+
+// RouteExistingTask is RouteTask for a stored task, using its title and description.
+func (s *Service) RouteExistingTask(taskID string, files []string) (*TaskRoute, error) {
+	t, err := s.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	return s.RouteTask(t.ProjectID, t.Title, t.Title+"\n"+t.Description, files)
+}
+
+// zoneTouches reports whether the zone contains path or, for a directory reference ("api/"),
+// anything under it.
+func zoneTouches(m *domain.ZoneMatcher, path string) bool {
+	if dir, ok := strings.CutSuffix(path, "/"); ok {
+		return m.Touches(dir)
+	}
+	return m.Contains(path)
+}
+
+// pathExists reports whether a referenced path is owned by one of the zones or exists under the
+// project root.
+func (s *Service) pathExists(p *domain.Project, matchers []*domain.ZoneMatcher, path string) bool {
+	if slices.ContainsFunc(matchers, func(m *domain.ZoneMatcher) bool { return zoneTouches(m, path) }) {
+		return true
+	}
+	if s.Files == nil {
+		return false
+	}
+	_, err := s.Files.FileSize(p.RootDir, strings.TrimSuffix(path, "/"))
+	var se *domain.StructuredError
+	return err == nil || (errors.As(err, &se) && se.Code == "NOT_A_FILE")
+}
+
+// pathCandidate cleans a path reference into a project-relative path: quotes, trailing
+// punctuation and line suffixes (file.go:12) are dropped, absolute paths under root are made
+// relative (other leading slashes are dropped), and URLs, paths escaping the root and
+// single-letter names (e.g.) are rejected.
+// Directory references keep a trailing slash.
+func pathCandidate(root, ref string) (string, bool) {
+	ref = strings.Trim(ref, "`'\"()[]{}<>,;:!?")
+	if i := strings.Index(ref, ":"); i > 0 {
+		ref = ref[:i]
+	}
+	ref = strings.TrimRight(ref, ".")
+	if ref == "" || strings.Contains(ref, "//") {
+		return "", false
+	}
+	dir := strings.HasSuffix(ref, "/")
+	if rel, err := filepath.Rel(root, ref); filepath.IsAbs(ref) && err == nil && !strings.HasPrefix(rel, "..") {
+		ref = rel
+	}
+	path := domain.NormalizePath(ref)
+	if path == "." || path == ".." || strings.HasPrefix(path, "../") {
+		return "", false
+	}
+	if !strings.Contains(path, "/") {
+		if ext := filepath.Ext(path); len(path)-len(ext) < 2 {
+			return "", false
+		}
+	}
+	if dir {
+		path += "/"
+	}
+	return path, true
+}
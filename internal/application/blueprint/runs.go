@@ -0,0 +1,291 @@
+package blueprint
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"operators-mcp/internal/domain"
+)
+
+// RunProgress is the state of a run computed from its tasks. Ready lists the open tasks whose
+// prerequisites are all done; Waiting lists the unfinished tasks held back by prerequisites.
+// Percent is the share of tasks that are closed (done or cancelled).
+type RunProgress struct {
+	Status   string
+	Total    int
+	Done     int
+	Percent  int
+	ByStatus map[string]int
+	Ready    []string
+	Waiting  []WaitingTask
+	Zones    []ZoneProgress
+}
+
+// WaitingTask is a task of a run and the prerequisites it is waiting on.
+type WaitingTask struct {
+	TaskID    string
+	WaitingOn []string
+}
+
+// ZoneProgress counts the tasks of a run routed to one zone.
+type ZoneProgress struct {
+	ZoneID string
+	Total  int
+	Done   int
+	Ready  int
+}
+
+// CreateRun groups tasks of the project into a run. Each entry names a task and the tasks of the
+// same run it depends on. The graph must be acyclic (DEPENDENCY_CYCLE names a cycle), and a task
+// may belong to one run only (TASK_IN_RUN). The caller is recorded as the run's creator. Run
+// changes are serialized with task changes, so concurrent edits cannot break these rules.
+func (s *Service) CreateRun(ctx context.Context, projectID, title, description string, tasks []domain.RunTask) (*domain.Run, error) {
+	if s.Runs == nil || s.Tasks == nil {
+		return nil, errRunsUnavailable
+	}
+	s.taskMu.Lock()
+	defer s.taskMu.Unlock()
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, &domain.StructuredError{Code: "TITLE_REQUIRED", Message: "title is required"}
+	}
+	now := time.Now().UTC()
+	run := &domain.Run{
+		ProjectID:   projectID,
+		Title:       title,
+		Description: description,
+		Tasks:       []domain.RunTask{},
+		CreatedBy:   CallerID(ctx),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, t := range tasks {
+		if run.Task(t.TaskID) != nil {
+			return nil, &domain.StructuredError{Code: "INVALID_DEPENDENCY", Message: "task listed twice: " + t.TaskID}
+		}
+		run.Tasks = append(run.Tasks, domain.RunTask{TaskID: t.TaskID, DependsOn: dedupe(t.DependsOn)})
+	}
+	if err := s.checkRunGraph(run); err != nil {
+		return nil, err
+	}
+	run, err := s.Runs.Create(run)
+	return changed(s, run, err)
+}
+
+// SetRunTask adds a task of the run's project to the run with its prerequisites, or replaces the
+// prerequisites of a task already in it. The same graph rules as CreateRun apply.
+func (s *Service) SetRunTask(runID, taskID string, dependsOn []string) (*domain.Run, error) {
+	s.taskMu.Lock()
+	defer s.taskMu.Unlock()
+	run, err := s.GetRun(runID)
+	if err != nil {
+		return nil, err
+	}
+	if t := run.Task(taskID); t != nil {
+		t.DependsOn = dedupe(dependsOn)
+	} else {
+		run.Tasks = append(run.Tasks, domain.RunTask{TaskID: taskID, DependsOn: dedupe(dependsOn)})
+	}
+	if err := s.checkRunGraph(run); err != nil {
+		return nil, err
+	}
+	run.UpdatedAt = time.Now().UTC()
+	run, err = s.Runs.Update(run)
+	return changed(s, run, err)
+}
+
+// GetRun returns a run by id.
+func (s *Service) GetRun(runID string) (*domain.Run, error) {
+	if s.Runs == nil || s.Tasks == nil {
+		return nil, errRunsUnavailable
+	}
+	run := s.Runs.Get(runID)
+	if run == nil {
+		return nil, &domain.StructuredError{Code: "RUN_NOT_FOUND", Message: "run not found"}
+	}
+	return run, nil
+}
+
+// ListRuns returns the project's runs oldest first.
+func (s *Service) ListRuns(projectID string) ([]*domain.Run, error) {
+	if s.Runs == nil || s.Tasks == nil {
+		return nil, errRunsUnavailable
+	}
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	runs := s.Runs.List(projectID)
+	if runs == nil {
+		runs = []*domain.Run{}
+	}
+	return runs, nil
+}
+
+// DeleteRun deletes a run. Its tasks are kept and no longer wait on each other.
+func (s *Service) DeleteRun(runID string) error {
+	s.taskMu.Lock()
+	defer s.taskMu.Unlock()
+	run, err := s.GetRun(runID)
+	if err != nil {
+		return err
+	}
+	if err := s.Runs.Delete(run.ID); err != nil {
+		return err
+	}
+	s.removed(run)
+	return nil
+}
+
+// RunProgress computes the status of a run from the current state of its tasks, overall and per
+// zone. Tasks that no longer exist count as cancelled.
+func (s *Service) RunProgress(run *domain.Run) *RunProgress {
+	tasks := s.runTasks(run)
+	p := &RunProgress{Total: len(run.Tasks), ByStatus: map[string]int{}, Ready: []string{}, Waiting: []WaitingTask{}, Zones: []ZoneProgress{}}
+	closed, working := 0, 0
+	zones := map[string]int{}
+	for _, rt := range run.Tasks {
+		t := tasks[rt.TaskID]
+		p.ByStatus[t.Status]++
+		waiting := unfinished(rt.DependsOn, tasks)
+		ready := t.Status == domain.TaskOpen && len(waiting) == 0
+		switch {
+		case t.Closed():
+			closed++
+		case ready:
+			p.Ready = append(p.Ready, t.ID)
+		case len(waiting) > 0:
+			p.Waiting = append(p.Waiting, WaitingTask{TaskID: t.ID, WaitingOn: waiting})
+		}
+		if t.Status != domain.TaskOpen && !t.Closed() {
+			working++
+		}
+		if t.Status == domain.TaskDone {
+			p.Done++
+		}
+		for _, zoneID := range t.ZoneIDs {
+			i, ok := zones[zoneID]
+			if !ok {
+				i = len(p.Zones)
+				zones[zoneID] = i
+				p.Zones = append(p.Zones, ZoneProgress{ZoneID: zoneID})
+			}
+			p.Zones[i].Total++
+			if t.Status == domain.TaskDone {
+				p.Zones[i].Done++
+			}
+			if ready {
+				p.Zones[i].Ready++
+			}
+		}
+	}
+	if p.Total > 0 {
+		p.Percent = closed * 100 / p.Total
+	}
+	switch {
+	case p.Total > 0 && closed == p.Total:
+		p.Status = domain.RunComplete
+	case working > 0 || (len(p.Ready) > 0 && closed > 0):
+		p.Status = domain.RunActive
+	case len(p.Ready) > 0 || p.Total == 0:
+		p.Status = domain.RunPending
+	default:
+		p.Status = domain.RunStalled
+	}
+	return p
+}
+
+// checkTaskReady refuses to start a task of a run while any of its prerequisites is not done.
+func (s *Service) checkTaskReady(t *domain.Task) error {
+	if s.Runs == nil {
+		return nil
+	}
+	for _, run := range s.Runs.List(t.ProjectID) {
+		rt := run.Task(t.ID)
+		if rt == nil {
+			continue
+		}
+		tasks := s.runTasks(run)
+		if waiting := unfinished(rt.DependsOn, tasks); len(waiting) > 0 {
+			msgs := make([]string, len(waiting))
+			for i, id := range waiting {
+				msgs[i] = id + " (" + tasks[id].Status + ")"
+			}
+			return &domain.StructuredError{Code: "TASK_NOT_READY", Message: "task waits on unfinished prerequisites: " + strings.Join(msgs, ", ")}
+		}
+	}
+	return nil
+}
+
+// checkRunGraph validates a run's tasks and dependencies.
+func (s *Service) checkRunGraph(run *domain.Run) error {
+	others := map[string]string{}
+	for _, r := range s.Runs.List(run.ProjectID) {
+		if r.ID == run.ID {
+			continue
+		}
+		for _, t := range r.Tasks {
+			others[t.TaskID] = r.ID
+		}
+	}
+	for _, rt := range run.Tasks {
+		t := s.Tasks.Get(rt.TaskID)
+		if t == nil || t.ProjectID != run.ProjectID {
+			return &domain.StructuredError{Code: "TASK_NOT_FOUND", Message: "task not found in project: " + rt.TaskID}
+		}
+		if other, ok := others[rt.TaskID]; ok {
+			return &domain.StructuredError{Code: "TASK_IN_RUN", Message: "task " + rt.TaskID + " already belongs to run " + other}
+		}
+		for _, dep := range rt.DependsOn {
+			if dep == rt.TaskID {
+				return &domain.StructuredError{Code: "INVALID_DEPENDENCY", Message: "task " + dep + " depends on itself"}
+			}
+			if run.Task(dep) == nil {
+				return &domain.StructuredError{Code: "INVALID_DEPENDENCY", Message: "task " + rt.TaskID + " depends on " + dep + ", which is not in the run"}
+			}
+		}
+	}
+	if cycle := run.Cycle(); cycle != nil {
+		return &domain.StructuredError{Code: "DEPENDENCY_CYCLE", Message: "dependency cycle: " + strings.Join(cycle, " -> ")}
+	}
+	return nil
+}
+
+// runTasks returns the run's tasks keyed by id. Missing tasks are returned as cancelled.
+func (s *Service) runTasks(run *domain.Run) map[string]*domain.Task {
+	out := make(map[string]*domain.Task, len(run.Tasks))
+	for _, rt := range run.Tasks {
+		t := s.Tasks.Get(rt.TaskID)
+		if t == nil {
+			t = &domain.Task{ID: rt.TaskID, ProjectID: run.ProjectID, Status: domain.TaskCancelled}
+		}
+		out[rt.TaskID] = t
+	}
+	return out
+}
+
+// unfinished returns the ids in deps whose task is not done.
+func unfinished(deps []string, tasks map[string]*domain.Task) []string {
+	var out []string
+	for _, id := range deps {
+		if t := tasks[id]; t == nil || t.Status != domain.TaskDone {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// dedupe returns ids without duplicates, in order.
+func dedupe(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,268 @@
+package blueprint
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// AgentZones returns the zones assigned to the agent, with their agents resolved, in projectID
+// or in every project when projectID is empty.
+func (s *Service) AgentZones(agentID, projectID string) ([]*domain.Zone, error) {
+	if s.Agents.Get(agentID) == nil {
+		return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	var projects []*domain.Project
+	if projectID != "" {
+		p := s.Projects.Get(projectID)
+		if p == nil {
+			return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+		}
+		projects = []*domain.Project{p}
+	} else {
+		projects = s.Projects.List()
+	}
+	out := []*domain.Zone{}
+	for _, p := range projects {
+		for _, z := range s.ListZones(p.ID) {
+			if slices.Contains(z.AgentIDs, agentID) {
+				out = append(out, z)
+			}
+		}
+	}
+	return out, nil
+}
+
+// AgentProjects returns the projects in which the agent is assigned at least one zone.
+func (s *Service) AgentProjects(agentID string) []*domain.Project {
+	out := []*domain.Project{}
+	for _, p := range s.Projects.List() {
+		for _, z := range s.Zones.ListByProject(p.ID) {
+			if slices.Contains(z.AgentIDs, agentID) {
+				out = append(out, p)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// GetAgentZone returns a zone assigned to the agent; other zones are refused with OUT_OF_ZONE.
+func (s *Service) GetAgentZone(agentID, zoneID string) (*domain.Zone, error) {
+	z := s.GetZone(zoneID)
+	if z == nil {
+		return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	if !slices.Contains(z.AgentIDs, agentID) {
+		return nil, &domain.StructuredError{Code: "OUT_OF_ZONE", Message: "zone " + z.Name + " is not assigned to agent " + agentID}
+	}
+	return z, nil
+}
+
+// CheckAgentProject refuses, with OUT_OF_ZONE, a project in which the agent has no zone.
+func (s *Service) CheckAgentProject(agentID, projectID string) error {
+	zones, err := s.AgentZones(agentID, projectID)
+	if err != nil {
+		return err
+	}
+	if len(zones) == 0 {
+		return &domain.StructuredError{Code: "OUT_OF_ZONE", Message: "agent " + agentID + " has no zone in project " + projectID}
+	}
+	return nil
+}
+
+// GetAgentDecision returns a decision of a project in which the agent has a zone.
+func (s *Service) GetAgentDecision(agentID, decisionID string) (*domain.Decision, error) {
+	d, err := s.GetDecision(decisionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CheckAgentProject(agentID, d.ProjectID); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DeleteAgentZoneNote is DeleteZoneNote restricted to notes of projects in which the agent has
+// a zone.
+func (s *Service) DeleteAgentZoneNote(ctx context.Context, agentID, noteID string) error {
+	if s.Notes == nil {
+		return errNotesUnavailable
+	}
+	if n := s.Notes.Get(noteID); n != nil {
+		if err := s.CheckAgentProject(agentID, n.ProjectID); err != nil {
+			return err
+		}
+	}
+	return s.DeleteZoneNote(ctx, noteID)
+}
+
+// EntityProject returns the project of the entity of the given event kind (zone, task, run,
+// decision, message or note) with the id, or "" when there is none.
+func (s *Service) EntityProject(kind, id string) string {
+	switch kind {
+	case EventZone:
+		if z := s.Zones.Get(id); z != nil {
+			return z.ProjectID
+		}
+	case EventTask:
+		if t, err := s.GetTask(id); err == nil {
+			return t.ProjectID
+		}
+	case EventRun:
+		if r, err := s.GetRun(id); err == nil {
+			return r.ProjectID
+		}
+	case EventDecision:
+		if d, err := s.GetDecision(id); err == nil {
+			return d.ProjectID
+		}
+	case EventMessage:
+		if m, err := s.GetMessage(id); err == nil {
+			return m.ProjectID
+		}
+	case EventNote:
+		if s.Notes != nil {
+			if n := s.Notes.Get(id); n != nil {
+				return n.ProjectID
+			}
+		}
+	}
+	return ""
+}
+
+// ListAgentTree returns the project tree pruned to the files and directories inside the agent's
+// zones (and the directories leading to them). Ignored paths are left out.
+func (s *Service) ListAgentTree(agentID, projectID string) (*domain.TreeNode, error) {
+	p, zones, err := s.agentProjectZones(agentID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.TreeLister.ListTree(p.RootDir)
+	if err != nil {
+		return nil, err
+	}
+	keep := func(path string) bool { return inAnyZone(zones, path) && !isIgnored(p, path) }
+	if pruned := pruneTree(tree, keep); pruned != nil {
+		return pruned, nil
+	}
+	return &domain.TreeNode{Path: tree.Path, Name: tree.Name, IsDir: true}, nil
+}
+
+// ListAgentMatchingPaths is ListMatchingPaths restricted to paths inside the agent's zones.
+func (s *Service) ListAgentMatchingPaths(agentID, projectID, pattern string) ([]string, error) {
+	p, zones, err := s.agentProjectZones(agentID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := s.PathMatcher.ListMatchingPaths(p.RootDir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	out := []string{}
+	for _, path := range paths {
+		if path != "" && inAnyZone(zones, path) && !isIgnored(p, path) {
+			out = append(out, path)
+		}
+	}
+	return out, nil
+}
+
+// AgentInstructions returns MCP server instructions for a session scoped to the agent: its
+// prompt (rendered with default variables, or as written when it cannot be rendered) followed by
+// the zones it may work in.
+func (s *Service) AgentInstructions(agentID string) string {
+	a := s.Agents.Get(agentID)
+	if a == nil {
+		return ""
+	}
+	var b strings.Builder
+	if rendered, err := s.RenderAgentPrompt(agentID, "", "", nil); err == nil {
+		b.WriteString(rendered.Text)
+	} else {
+		b.WriteString(strings.TrimRight(a.Prompt, "\n") + "\n")
+	}
+	zones, _ := s.AgentZones(agentID, "")
+	fmt.Fprintf(&b, "\nThis endpoint is scoped to agent %s: tools only see and change the zones assigned to it.\n", agentLabel(a))
+	if len(zones) == 0 {
+		b.WriteString("No zones are assigned yet.\n")
+	}
+	for _, z := range zones {
+		fmt.Fprintf(&b, "- %s (zone_id %s, project_id %s)", z.Name, z.ID, z.ProjectID)
+		if z.Purpose != "" {
+			b.WriteString(": " + z.Purpose)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SearchAgentNotes is SearchZoneNotes restricted to the agent's zones in one project. An empty
+// projectID names the agent's only project.
+func (s *Service) SearchAgentNotes(agentID, projectID string, q NoteQuery) ([]*domain.ZoneNote, error) {
+	p, zones, err := s.agentProjectZones(agentID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultNoteHits
+	}
+	q.ZoneID, q.Limit = "", math.MaxInt
+	notes, err := s.SearchZoneNotes(p.ID, q)
+	if err != nil {
+		return nil, err
+	}
+	out := []*domain.ZoneNote{}
+	for _, n := range notes {
+		if len(out) < limit && slices.ContainsFunc(zones, func(z *domain.Zone) bool { return z.ID == n.ZoneID }) {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+// agentProjectZones returns the project and the agent's zones in it. An empty projectID names
+// the agent's only project.
+func (s *Service) agentProjectZones(agentID, projectID string) (*domain.Project, []*domain.Zone, error) {
+	if projectID == "" {
+		projects := s.AgentProjects(agentID)
+		if len(projects) != 1 {
+			return nil, nil, &domain.StructuredError{Code: "PROJECT_REQUIRED", Message: "project_id is required when the agent has zones in more than one project"}
+		}
+		projectID = projects[0].ID
+	}
+	zones, err := s.AgentZones(agentID, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.Projects.Get(projectID), zones, nil
+}
+
+func inAnyZone(zones []*domain.Zone, path string) bool {
+	for _, z := range zones {
+		if z.Contains(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneTree returns a copy of n with only the nodes for which keep is true and the directories
+// leading to them, or nil when nothing is kept. The root is always kept when anything is.
+func pruneTree(n *domain.TreeNode, keep func(path string) bool) *domain.TreeNode {
+	var children []*domain.TreeNode
+	for _, c := range n.Children {
+		if pc := pruneTree(c, keep); pc != nil {
+			children = append(children, pc)
+		}
+	}
+	if len(children) == 0 && (n.Path == "" || !keep(n.Path)) {
+		return nil
+	}
+	return &domain.TreeNode{Path: n.Path, Name: n.Name, IsDir: n.IsDir, Children: children}
+}
@@ -1,36 +1,78 @@
 package blueprint
 
 import (
+	"slices"
+	"sync"
+
 	"operators-mcp/internal/application/ports"
 	"operators-mcp/internal/domain"
 )
 
+// Errors returned by use cases whose optional port is not configured.
+var (
+	errFilesUnavailable     = errUnavailable("FILES", "file access is")
+	errTokensUnavailable    = errUnavailable("TOKENS", "agent tokens are")
+	errTasksUnavailable     = errUnavailable("TASKS", "tasks are")
+	errLeasesUnavailable    = errUnavailable("LEASES", "zone leases are")
+	errRunsUnavailable      = errUnavailable("RUNS", "runs are")
+	errMessagesUnavailable  = errUnavailable("MESSAGES", "messages are")
+	errDecisionsUnavailable = errUnavailable("DECISIONS", "decision records are")
+	errNotesUnavailable     = errUnavailable("NOTES", "zone notes are")
+)
+
+func errUnavailable(feature, subject string) error {
+	return &domain.StructuredError{Code: feature + "_UNAVAILABLE", Message: subject + " not configured"}
+}
+
 // Service implements blueprint use cases by delegating to the outbound ports.
 // It is the application (use-case) layer in hexagonal architecture.
+//
+// Projects, Zones, Agents, PathMatcher and TreeLister are required. The other ports are optional
+// and each enables the features noted next to it; use cases that need a port left nil fail with
+// its *_UNAVAILABLE error (see the errors above), and features that only enrich a result are skipped.
 type Service struct {
-	Projects    ports.ProjectRepository
-	Zones       ports.ZoneRepository
-	Agents      ports.AgentRepository
-	PathMatcher ports.PathMatcher
-	TreeLister  ports.TreeLister
-	DefaultRoot string
+	Projects     ports.ProjectRepository
+	Zones        ports.ZoneRepository
+	Agents       ports.AgentRepository
+	PathMatcher  ports.PathMatcher
+	TreeLister   ports.TreeLister
+	Dependencies ports.DependencyAnalyzer // inter-zone edges in diagrams and briefings
+	Files        ports.FileStore          // file tools and blueprint file sync
+	Changes      ports.ChangeLog          // recording of writes made through the service
+	Tokens       ports.AgentTokenStore    // bearer tokens identifying agents
+	Tasks        ports.TaskRepository     // tasks
+	Leases       ports.LeaseStore         // zone leases, enforced by the write tools
+	Runs         ports.RunRepository      // runs (task dependency graphs), with Tasks
+	Messages     ports.MessageRepository  // agent messages
+	Decisions    ports.DecisionRepository // architecture decision records
+	Notes        ports.NoteRepository     // zone notes
+
+	// RequireIdentity refuses anonymous sessions in ActingAgent once any agent token has been
+	// issued, instead of letting them act for the agent_id they name.
+	RequireIdentity bool
+
+	mu          sync.RWMutex
+	subscribers []func(Event)
+	syncMu      sync.Mutex
+	leaseMu     sync.Mutex
+	taskMu      sync.Mutex
+	decisionMu  sync.Mutex
+	noteMu      sync.Mutex
 }
 
 // NewService returns a blueprint application service with the given ports.
-// defaultRoot is used when no project_id is provided for list_tree/list_matching_paths.
-func NewService(projects ports.ProjectRepository, zones ports.ZoneRepository, agents ports.AgentRepository, pathMatcher ports.PathMatcher, treeLister ports.TreeLister, defaultRoot string) *Service {
+func NewService(projects ports.ProjectRepository, zones ports.ZoneRepository, agents ports.AgentRepository, pathMatcher ports.PathMatcher, treeLister ports.TreeLister) *Service {
 	return &Service{
 		Projects:    projects,
 		Zones:       zones,
 		Agents:      agents,
 		PathMatcher: pathMatcher,
 		TreeLister:  treeLister,
-		DefaultRoot: defaultRoot,
 	}
 }
 
 // resolveRoot returns the root path for tree/path operations. If root is non-empty it is used;
-// else if projectID is non-empty the project's RootDir is used; otherwise DefaultRoot.
+// else the RootDir of projectID is used. One of them is required.
 func (s *Service) resolveRoot(root, projectID string) (string, error) {
 	if root != "" {
 		return root, nil
@@ -42,7 +84,7 @@ func (s *Service) resolveRoot(root, projectID string) (string, error) {
 		}
 		return p.RootDir, nil
 	}
-	return s.DefaultRoot, nil
+	return "", &domain.StructuredError{Code: "PROJECT_REQUIRED", Message: "project_id or root is required"}
 }
 
 // ListProjects returns all projects.
@@ -62,7 +104,12 @@ func (s *Service) CreateProject(name, rootDir string) (*domain.Project, error) {
 
 // UpdateProject updates an existing project.
 func (s *Service) UpdateProject(projectID, name, rootDir string) (*domain.Project, error) {
-	return s.Projects.Update(projectID, name, rootDir)
+	p, err := s.Projects.Update(projectID, name, rootDir)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(Event{Kind: EventProject, ID: p.ID, ProjectID: p.ID})
+	return p, nil
 }
 
 // DeleteProject deletes a project and all its zones.
@@ -73,21 +120,63 @@ func (s *Service) DeleteProject(projectID string) error {
 	if err := s.Zones.DeleteByProject(projectID); err != nil {
 		return err
 	}
-	return s.Projects.Delete(projectID)
+	if s.Tasks != nil {
+		if err := s.Tasks.DeleteByProject(projectID); err != nil {
+			return err
+		}
+	}
+	if s.Runs != nil {
+		if err := s.Runs.DeleteByProject(projectID); err != nil {
+			return err
+		}
+	}
+	if s.Decisions != nil {
+		if err := s.Decisions.DeleteByProject(projectID); err != nil {
+			return err
+		}
+	}
+	if s.Notes != nil {
+		if err := s.Notes.DeleteByProject(projectID); err != nil {
+			return err
+		}
+	}
+	if s.Messages != nil {
+		if err := s.Messages.DeleteByProject(projectID); err != nil {
+			return err
+		}
+	}
+	if err := s.dropProjectLeases(projectID); err != nil {
+		return err
+	}
+	if err := s.Projects.Delete(projectID); err != nil {
+		return err
+	}
+	s.publish(Event{Kind: EventProject, ID: projectID, ProjectID: projectID, Deleted: true})
+	return nil
 }
 
 // AddIgnoredPath adds a path to the project's ignored list (hidden in tree view).
 func (s *Service) AddIgnoredPath(projectID, path string) (*domain.Project, error) {
-	return s.Projects.AddIgnoredPath(projectID, path)
+	p, err := s.Projects.AddIgnoredPath(projectID, path)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(Event{Kind: EventProject, ID: p.ID, ProjectID: p.ID})
+	return p, nil
 }
 
 // RemoveIgnoredPath removes a path from the project's ignored list.
 func (s *Service) RemoveIgnoredPath(projectID, path string) (*domain.Project, error) {
-	return s.Projects.RemoveIgnoredPath(projectID, path)
+	p, err := s.Projects.RemoveIgnoredPath(projectID, path)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(Event{Kind: EventProject, ID: p.ID, ProjectID: p.ID})
+	return p, nil
 }
 
 // ListMatchingPaths returns paths under root that match the regex pattern.
-// root and projectID are optional; if both empty, DefaultRoot is used.
+// One of root and projectID is required; root takes precedence.
 func (s *Service) ListMatchingPaths(root, projectID, pattern string) ([]string, error) {
 	r, err := s.resolveRoot(root, projectID)
 	if err != nil {
@@ -97,7 +186,7 @@ func (s *Service) ListMatchingPaths(root, projectID, pattern string) ([]string,
 }
 
 // ListTree returns the directory tree from root.
-// root and projectID are optional; if both empty, DefaultRoot is used.
+// One of root and projectID is required; root takes precedence.
 func (s *Service) ListTree(root, projectID string) (*domain.TreeNode, error) {
 	r, err := s.resolveRoot(root, projectID)
 	if err != nil {
@@ -106,29 +195,121 @@ func (s *Service) ListTree(root, projectID string) (*domain.TreeNode, error) {
 	return s.TreeLister.ListTree(r)
 }
 
-// ListZones returns all zones for the given project.
+// ListZones returns all zones for the given project with their assigned agents resolved.
 func (s *Service) ListZones(projectID string) []*domain.Zone {
-	return s.Zones.ListByProject(projectID)
+	zones := s.Zones.ListByProject(projectID)
+	for _, z := range zones {
+		s.resolveAgents(z)
+	}
+	return zones
 }
 
-// GetZone returns one zone by id, or nil if not found.
+// GetZone returns one zone by id with its assigned agents resolved, or nil if not found.
 func (s *Service) GetZone(zoneID string) *domain.Zone {
-	return s.Zones.Get(zoneID)
+	z := s.Zones.Get(zoneID)
+	if z != nil {
+		s.resolveAgents(z)
+	}
+	return z
 }
 
 // CreateZone creates a zone in the given project with the given metadata.
-func (s *Service) CreateZone(projectID, name, pattern, purpose string, constraints []string, agents []domain.Agent) (*domain.Zone, error) {
-	return s.Zones.Create(projectID, name, pattern, purpose, constraints, agents)
+// Every agent id must refer to an existing agent.
+func (s *Service) CreateZone(projectID, name, pattern, purpose string, constraints, agentIDs []string) (*domain.Zone, error) {
+	ids, err := s.checkAgentIDs(agentIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.zoneChanged(s.Zones.Create(projectID, name, pattern, purpose, constraints, ids))
 }
 
-// UpdateZone updates an existing zone.
-func (s *Service) UpdateZone(zoneID, name, pattern, purpose string, constraints []string, agents []domain.Agent) (*domain.Zone, error) {
-	return s.Zones.Update(zoneID, name, pattern, purpose, constraints, agents)
+// UpdateZone updates an existing zone. Every agent id must refer to an existing agent.
+func (s *Service) UpdateZone(zoneID, name, pattern, purpose string, constraints, agentIDs []string) (*domain.Zone, error) {
+	ids, err := s.checkAgentIDs(agentIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.zoneChanged(s.Zones.Update(zoneID, name, pattern, purpose, constraints, ids))
 }
 
 // AssignPathToZone adds a path to a zone's explicit paths (path is normalized).
 func (s *Service) AssignPathToZone(zoneID, path string) (*domain.Zone, error) {
-	return s.Zones.AssignPath(zoneID, domain.NormalizePath(path))
+	return s.zoneChanged(s.Zones.AssignPath(zoneID, domain.NormalizePath(path)))
+}
+
+// UnassignPathFromZone removes a path from a zone's explicit paths (path is normalized).
+func (s *Service) UnassignPathFromZone(zoneID, path string) (*domain.Zone, error) {
+	return s.zoneChanged(s.Zones.UnassignPath(zoneID, domain.NormalizePath(path)))
+}
+
+// DeleteZone deletes a zone by id.
+func (s *Service) DeleteZone(zoneID string) error {
+	z := s.Zones.Get(zoneID)
+	if z == nil {
+		return &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found"}
+	}
+	if err := s.deleteZone(z); err != nil {
+		return err
+	}
+	s.publish(Event{Kind: EventZone, ID: z.ID, ProjectID: z.ProjectID, Deleted: true})
+	return nil
+}
+
+// deleteZone deletes a zone and what refers to it (task and decision references, notes and its
+// lease) without publishing the zone event.
+func (s *Service) deleteZone(z *domain.Zone) error {
+	if err := s.Zones.Delete(z.ID); err != nil {
+		return err
+	}
+	if err := s.dropTaskZone(z); err != nil {
+		return err
+	}
+	if err := s.dropDecisionZone(z); err != nil {
+		return err
+	}
+	if err := s.dropZoneNotes(z); err != nil {
+		return err
+	}
+	return s.dropZoneLease(z.ID)
+}
+
+// zoneChanged publishes a zone event for a successful zone mutation and passes its result through.
+func (s *Service) zoneChanged(z *domain.Zone, err error) (*domain.Zone, error) {
+	if err != nil {
+		return nil, err
+	}
+	s.publish(Event{Kind: EventZone, ID: z.ID, ProjectID: z.ProjectID})
+	s.resolveAgents(z)
+	return z, nil
+}
+
+// checkAgentIDs returns agentIDs without duplicates, or AGENT_NOT_FOUND for the first unknown id.
+func (s *Service) checkAgentIDs(agentIDs []string) ([]string, error) {
+	out := make([]string, 0, len(agentIDs))
+	for _, id := range agentIDs {
+		if slices.Contains(out, id) {
+			continue
+		}
+		if s.Agents.Get(id) == nil {
+			return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found: " + id}
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// resolveAgents sets z.AssignedAgents to the current agents referenced by z.AgentIDs.
+// References to agents that no longer exist are dropped from both fields.
+func (s *Service) resolveAgents(z *domain.Zone) {
+	ids := z.AgentIDs[:0:0]
+	z.AssignedAgents = nil
+	for _, id := range z.AgentIDs {
+		if a := s.Agents.Get(id); a != nil {
+			ids = append(ids, id)
+			z.AssignedAgents = append(z.AssignedAgents, *a)
+		}
+	}
+	z.AgentIDs = ids
 }
 
 // ListAgents returns all agents.
@@ -142,41 +323,52 @@ func (s *Service) GetAgent(id string) *domain.Agent {
 }
 
 // CreateAgent creates an agent with the given name, description, and prompt.
-func (s *Service) CreateAgent(name, description, prompt string) (*domain.Agent, error) {
-	return s.Agents.Create(name, description, prompt)
+// The prompt is a template that may reference only known fields and the declared variables.
+func (s *Service) CreateAgent(name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error) {
+	if _, err := parsePrompt(prompt, variables); err != nil {
+		return nil, err
+	}
+	a, err := s.Agents.Create(name, description, prompt, variables)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(Event{Kind: EventAgent, ID: a.ID})
+	return a, nil
 }
 
-// UpdateAgent updates an existing agent.
-func (s *Service) UpdateAgent(id, name, description, prompt string) (*domain.Agent, error) {
-	return s.Agents.Update(id, name, description, prompt)
+// UpdateAgent updates an existing agent. The prompt is validated as in CreateAgent when it or the
+// variables change; an unchanged prompt stored before prompts were templates is kept as is (it
+// renders as plain text).
+func (s *Service) UpdateAgent(id, name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error) {
+	if cur := s.Agents.Get(id); cur == nil || cur.Prompt != prompt || !slices.Equal(cur.Variables, variables) {
+		if _, err := parsePrompt(prompt, variables); err != nil {
+			return nil, err
+		}
+	}
+	a, err := s.Agents.Update(id, name, description, prompt, variables)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(Event{Kind: EventAgent, ID: a.ID})
+	return a, nil
 }
 
-// DeleteAgent deletes an agent and removes it from all zones that reference it.
+// DeleteAgent deletes an agent. Its zone assignments go with it (see ports.AgentRepository).
 func (s *Service) DeleteAgent(id string) error {
-	if s.Agents.Get(id) == nil {
-		return &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
-	}
-	for _, p := range s.Projects.List() {
-		for _, z := range s.Zones.ListByProject(p.ID) {
-			var hasAgent bool
-			for _, a := range z.AssignedAgents {
-				if a.ID == id {
-					hasAgent = true
-					break
-				}
-			}
-			if hasAgent {
-				filtered := make([]domain.Agent, 0, len(z.AssignedAgents))
-				for _, a := range z.AssignedAgents {
-					if a.ID != id {
-						filtered = append(filtered, a)
-					}
-				}
-				if _, err := s.Zones.Update(z.ID, z.Name, z.Pattern, z.Purpose, z.Constraints, filtered); err != nil {
-					return err
-				}
-			}
+	if err := s.Agents.Delete(id); err != nil {
+		return err
+	}
+	if s.Tokens != nil {
+		if err := s.Tokens.Delete(id); err != nil {
+			return err
 		}
 	}
-	return s.Agents.Delete(id)
+	if err := s.releaseAgentTasks(id); err != nil {
+		return err
+	}
+	if err := s.releaseAgentLeases(id); err != nil {
+		return err
+	}
+	s.publish(Event{Kind: EventAgent, ID: id, Deleted: true})
+	return nil
 }
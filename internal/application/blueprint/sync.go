@@ -0,0 +1,269 @@
+package blueprint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// DefaultBlueprintFile is the project-relative file used when a project is bound without one.
+const DefaultBlueprintFile = ".operators/blueprint.yaml"
+
+// Sync statuses reported in SyncResult.
+const (
+	SyncInSync   = "in_sync"
+	SyncWritten  = "written"
+	SyncImported = "imported"
+	SyncConflict = "conflict"
+)
+
+// Conflict resolutions for SyncBlueprint: ResolveFile applies the file to the store,
+// ResolveStore overwrites the file with the store.
+const (
+	ResolveFile  = "file"
+	ResolveStore = "store"
+)
+
+// SyncResult reports what a blueprint sync did. For imports Changes is the applied diff;
+// for conflicts it is the diff the file would apply to the store.
+type SyncResult struct {
+	ProjectID string            `json:"project_id"`
+	File      string            `json:"file"`
+	Status    string            `json:"status"`
+	Changes   []BlueprintChange `json:"changes,omitempty"`
+}
+
+// BindBlueprintFile binds the project to a blueprint file inside its root and performs the first sync.
+// initial selects which side wins that sync; by default an existing file is applied to the store
+// and a missing one is created from the store.
+func (s *Service) BindBlueprintFile(projectID, file, initial string) (*SyncResult, error) {
+	if s.Files == nil {
+		return nil, errFilesUnavailable
+	}
+	p := s.Projects.Get(projectID)
+	if p == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	if file == "" {
+		file = DefaultBlueprintFile
+	}
+	file, err := blueprintFilePath(p.RootDir, file)
+	if err != nil {
+		return nil, err
+	}
+	if initial != "" && initial != ResolveFile && initial != ResolveStore {
+		return nil, &domain.StructuredError{Code: "INVALID_MODE", Message: "initial must be file or store"}
+	}
+	if initial == "" {
+		initial = ResolveStore
+		if _, err := s.Files.ReadFile(p.RootDir, file); err == nil {
+			initial = ResolveFile
+		}
+	}
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	if _, err := s.Projects.SetBlueprintSync(p.ID, file, "", ""); err != nil {
+		return nil, err
+	}
+	return s.syncProject(p.ID, initial)
+}
+
+// UnbindBlueprintFile removes the project's blueprint file binding. The file itself is kept.
+func (s *Service) UnbindBlueprintFile(projectID string) (*domain.Project, error) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	return s.Projects.SetBlueprintSync(projectID, "", "", "")
+}
+
+// SyncBlueprint reconciles a bound project with its blueprint file. When only the store changed
+// since the last sync the file is rewritten; when only the file changed it is imported in prune mode;
+// when both changed a conflict is reported unless resolve picks a side. A missing file is recreated.
+func (s *Service) SyncBlueprint(projectID, resolve string) (*SyncResult, error) {
+	if resolve != "" && resolve != ResolveFile && resolve != ResolveStore {
+		return nil, &domain.StructuredError{Code: "INVALID_MODE", Message: "resolve must be file or store"}
+	}
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	return s.syncProject(projectID, resolve)
+}
+
+// SyncBlueprints syncs every project bound to a blueprint file. Per-project failures are joined
+// into the returned error; results are returned for the projects that synced.
+func (s *Service) SyncBlueprints() ([]*SyncResult, error) {
+	var results []*SyncResult
+	var errs []error
+	for _, p := range s.Projects.List() {
+		if p.BlueprintFile == "" {
+			continue
+		}
+		res, err := s.SyncBlueprint(p.ID, "")
+		if err != nil {
+			errs = append(errs, errors.New(p.ID+": "+err.Error()))
+			continue
+		}
+		results = append(results, res)
+	}
+	return results, errors.Join(errs...)
+}
+
+// syncProject does the work of SyncBlueprint; the caller holds syncMu.
+func (s *Service) syncProject(projectID, resolve string) (*SyncResult, error) {
+	if s.Files == nil {
+		return nil, errFilesUnavailable
+	}
+	p := s.Projects.Get(projectID)
+	if p == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	if p.BlueprintFile == "" {
+		return nil, &domain.StructuredError{Code: "BLUEPRINT_NOT_BOUND", Message: "project is not bound to a blueprint file"}
+	}
+	format := formatForFile(p.BlueprintFile)
+	fileData, err := s.Files.ReadFile(p.RootDir, p.BlueprintFile)
+	fileExists := err == nil
+	if err != nil && !isCode(err, "FILE_NOT_FOUND") {
+		return nil, err
+	}
+	storeData, err := s.exportDocument(p.ID, format)
+	if err != nil {
+		return nil, err
+	}
+	res := &SyncResult{ProjectID: p.ID, File: p.BlueprintFile}
+	fileHash, storeHash := "", hashContent(storeData)
+	if fileExists {
+		fileHash = hashContent(fileData)
+	}
+	fileChanged := fileHash != p.BlueprintFileHash
+	storeChanged := storeHash != p.BlueprintStoreHash
+
+	action := resolve
+	if action == "" {
+		switch {
+		case fileExists && bytes.Equal(fileData, storeData), !fileChanged && !storeChanged:
+			res.Status = SyncInSync
+			_, err := s.Projects.SetBlueprintSync(p.ID, p.BlueprintFile, fileHash, storeHash)
+			return res, err
+		case !fileExists, !fileChanged:
+			action = ResolveStore
+		case !storeChanged:
+			action = ResolveFile
+		default:
+			doc, err := UnmarshalDocument(fileData, format)
+			if err != nil {
+				return nil, err
+			}
+			diff, err := s.importDocument(doc, ImportOptions{ProjectID: p.ID, Mode: ImportPrune, DryRun: true})
+			if err != nil {
+				return nil, err
+			}
+			res.Status = SyncConflict
+			res.Changes = diff.Changes
+			return res, nil
+		}
+	}
+
+	if action == ResolveStore {
+		if err := s.Files.WriteFile(p.RootDir, p.BlueprintFile, storeData); err != nil {
+			return nil, err
+		}
+		res.Status = SyncWritten
+		_, err := s.Projects.SetBlueprintSync(p.ID, p.BlueprintFile, storeHash, storeHash)
+		return res, err
+	}
+	if !fileExists {
+		return nil, &domain.StructuredError{Code: "FILE_NOT_FOUND", Message: "blueprint file not found: " + p.BlueprintFile}
+	}
+	doc, err := UnmarshalDocument(fileData, format)
+	if err != nil {
+		return nil, err
+	}
+	imported, err := s.importDocument(doc, ImportOptions{ProjectID: p.ID, Mode: ImportPrune})
+	if err != nil {
+		return nil, err
+	}
+	storeData, err = s.exportDocument(p.ID, format)
+	if err != nil {
+		return nil, err
+	}
+	res.Status = SyncImported
+	res.Changes = imported.Changes
+	if _, err := s.Projects.SetBlueprintSync(p.ID, p.BlueprintFile, fileHash, hashContent(storeData)); err != nil {
+		return nil, err
+	}
+	if len(imported.Changes) > 0 {
+		s.notify(Event{Kind: EventProject, ID: p.ID, ProjectID: p.ID})
+	}
+	return res, nil
+}
+
+// syncAfterChange writes changes made through the service back to bound blueprint files.
+// Failures and conflicts are logged; they surface again on the next explicit or periodic sync.
+func (s *Service) syncAfterChange(e Event) {
+	if s.Files == nil || (e.Kind == EventProject && e.Deleted) {
+		return
+	}
+	var projects []*domain.Project
+	if e.Kind == EventAgent {
+		projects = s.Projects.List()
+	} else if p := s.Projects.Get(e.ProjectID); p != nil {
+		projects = []*domain.Project{p}
+	}
+	for _, p := range projects {
+		if p.BlueprintFile == "" {
+			continue
+		}
+		res, err := s.SyncBlueprint(p.ID, "")
+		if err != nil {
+			slog.Warn("blueprint sync failed", "project", p.ID, "file", p.BlueprintFile, "error", err)
+		} else if res.Status == SyncConflict {
+			slog.Warn("blueprint sync conflict", "project", p.ID, "file", p.BlueprintFile, "changes", len(res.Changes))
+		}
+	}
+}
+
+func (s *Service) exportDocument(projectID, format string) ([]byte, error) {
+	doc, err := s.ExportBlueprint(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalDocument(doc, format)
+}
+
+// blueprintFilePath normalizes file to a path relative to root and rejects paths outside it.
+func blueprintFilePath(root, file string) (string, error) {
+	if filepath.IsAbs(file) {
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return "", &domain.StructuredError{Code: "INVALID_PATH", Message: err.Error()}
+		}
+		file = rel
+	}
+	rel := domain.NormalizePath(file)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", &domain.StructuredError{Code: "INVALID_PATH", Message: "blueprint file must be inside the project root"}
+	}
+	return rel, nil
+}
+
+func formatForFile(file string) string {
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+func hashContent(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func isCode(err error, code string) bool {
+	var se *domain.StructuredError
+	return errors.As(err, &se) && se.Code == code
+}
@@ -0,0 +1,267 @@
+package blueprint
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"operators-mcp/internal/domain"
+)
+
+// TaskFilter selects tasks in ListTasks. Empty fields match every task.
+type TaskFilter struct {
+	Status  string
+	AgentID string
+	ZoneID  string
+}
+
+// CreateTask adds an open task to the project, routed to zoneIDs (zones of that project) and
+// optionally assigned to agentID, who then claims it. The caller is recorded as its creator.
+func (s *Service) CreateTask(ctx context.Context, projectID, title, description string, zoneIDs []string, agentID string) (*domain.Task, error) {
+	if s.Tasks == nil {
+		return nil, errTasksUnavailable
+	}
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, &domain.StructuredError{Code: "TITLE_REQUIRED", Message: "title is required"}
+	}
+	zones, err := s.checkTaskZones(projectID, zoneIDs)
+	if err != nil {
+		return nil, err
+	}
+	if agentID != "" && s.Agents.Get(agentID) == nil {
+		return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	now := time.Now().UTC()
+	t, err := s.Tasks.Create(&domain.Task{
+		ProjectID:   projectID,
+		Title:       title,
+		Description: description,
+		Status:      domain.TaskOpen,
+		ZoneIDs:     zones,
+		AgentID:     agentID,
+		CreatedBy:   CallerID(ctx),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	return changed(s, t, err)
+}
+
+// GetTask returns a task by id.
+func (s *Service) GetTask(taskID string) (*domain.Task, error) {
+	if s.Tasks == nil {
+		return nil, errTasksUnavailable
+	}
+	t := s.Tasks.Get(taskID)
+	if t == nil {
+		return nil, &domain.StructuredError{Code: "TASK_NOT_FOUND", Message: "task not found"}
+	}
+	return t, nil
+}
+
+// ListTasks returns the project's tasks matching f, oldest first.
+func (s *Service) ListTasks(projectID string, f TaskFilter) ([]*domain.Task, error) {
+	if s.Tasks == nil {
+		return nil, errTasksUnavailable
+	}
+	if s.Projects.Get(projectID) == nil {
+		return nil, &domain.StructuredError{Code: "PROJECT_NOT_FOUND", Message: "project not found"}
+	}
+	if f.Status != "" && !domain.ValidTaskStatus(f.Status) {
+		return nil, &domain.StructuredError{Code: "INVALID_STATUS", Message: "unknown task status: " + f.Status}
+	}
+	out := []*domain.Task{}
+	for _, t := range s.Tasks.List(projectID) {
+		if (f.Status == "" || t.Status == f.Status) && (f.AgentID == "" || t.AgentID == f.AgentID) && (f.ZoneID == "" || slices.Contains(t.ZoneIDs, f.ZoneID)) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// ClaimTask assigns an open task to the acting agent (see ActingAgent) and marks it claimed.
+// A task routed to zones can only be claimed by an agent assigned to one of them, a task
+// assigned to another agent cannot be claimed, and a task of a run cannot be claimed until its
+// prerequisites are done (TASK_NOT_READY). Claiming one's own claimed task is a no-op.
+// Task changes are serialized, so of concurrent claims exactly one succeeds.
+func (s *Service) ClaimTask(ctx context.Context, taskID, agentID string) (*domain.Task, error) {
+	s.taskMu.Lock()
+	defer s.taskMu.Unlock()
+	t, err := s.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if agentID, err = s.ActingAgent(ctx, agentID); err != nil {
+		return nil, err
+	}
+	if agentID == "" {
+		return nil, &domain.StructuredError{Code: "AGENT_REQUIRED", Message: "a task must be claimed by an agent"}
+	}
+	if s.Agents.Get(agentID) == nil {
+		return nil, &domain.StructuredError{Code: "AGENT_NOT_FOUND", Message: "agent not found"}
+	}
+	if t.Closed() {
+		return nil, taskClosed(t)
+	}
+	if t.AgentID != "" && t.AgentID != agentID {
+		return nil, &domain.StructuredError{Code: "TASK_ASSIGNED", Message: "task is assigned to agent " + t.AgentID}
+	}
+	if t.AgentID == agentID && t.Status != domain.TaskOpen {
+		return t, nil
+	}
+	if len(t.ZoneIDs) > 0 && !slices.ContainsFunc(t.ZoneIDs, func(id string) bool {
+		z := s.Zones.Get(id)
+		return z != nil && slices.Contains(z.AgentIDs, agentID)
+	}) {
+		return nil, &domain.StructuredError{Code: "OUT_OF_ZONE", Message: "none of the task's zones is assigned to agent " + agentID}
+	}
+	if err := s.checkTaskReady(t); err != nil {
+		return nil, err
+	}
+	t.AgentID = agentID
+	t.Status = domain.TaskClaimed
+	return s.updateTask(t)
+}
+
+// UpdateTaskStatus moves a task to open (releasing its agent), in_progress, blocked or cancelled.
+// A task of a run cannot move to in_progress until its prerequisites are done. Tasks are
+// completed with CompleteTask. An identified caller may only update tasks assigned to
+// it; anonymous callers (orchestrators, the UI) may update any task.
+func (s *Service) UpdateTaskStatus(ctx context.Context, taskID, status string) (*domain.Task, error) {
+	s.taskMu.Lock()
+	defer s.taskMu.Unlock()
+	t, err := s.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTaskAgent(ctx, t); err != nil {
+		return nil, err
+	}
+	if t.Closed() {
+		return nil, taskClosed(t)
+	}
+	switch status {
+	case domain.TaskOpen:
+		t.AgentID = ""
+	case domain.TaskInProgress, domain.TaskBlocked:
+		if t.AgentID == "" {
+			return nil, &domain.StructuredError{Code: "TASK_NOT_CLAIMED", Message: "task must be claimed before work starts"}
+		}
+		if status == domain.TaskInProgress {
+			if err := s.checkTaskReady(t); err != nil {
+				return nil, err
+			}
+		}
+	case domain.TaskCancelled:
+	case domain.TaskDone:
+		return nil, &domain.StructuredError{Code: "INVALID_STATUS", Message: "use complete_task to finish a task"}
+	default:
+		return nil, &domain.StructuredError{Code: "INVALID_STATUS", Message: "status must be open, in_progress, blocked or cancelled"}
+	}
+	t.Status = status
+	return s.updateTask(t)
+}
+
+// CompleteTask marks a claimed task done with a result summary. The same caller rules as
+// UpdateTaskStatus apply.
+func (s *Service) CompleteTask(ctx context.Context, taskID, result string) (*domain.Task, error) {
+	s.taskMu.Lock()
+	defer s.taskMu.Unlock()
+	t, err := s.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTaskAgent(ctx, t); err != nil {
+		return nil, err
+	}
+	if t.Closed() {
+		return nil, taskClosed(t)
+	}
+	if t.AgentID == "" {
+		return nil, &domain.StructuredError{Code: "TASK_NOT_CLAIMED", Message: "task must be claimed before it is completed"}
+	}
+	t.Status = domain.TaskDone
+	t.Result = result
+	return s.updateTask(t)
+}
+
+// checkTaskZones returns zoneIDs without duplicates, or an error for ids that are not zones of
+// the project.
+func (s *Service) checkTaskZones(projectID string, zoneIDs []string) ([]string, error) {
+	out := make([]string, 0, len(zoneIDs))
+	for _, id := range zoneIDs {
+		if slices.Contains(out, id) {
+			continue
+		}
+		z := s.Zones.Get(id)
+		if z == nil || z.ProjectID != projectID {
+			return nil, &domain.StructuredError{Code: "ZONE_NOT_FOUND", Message: "zone not found in project: " + id}
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// checkTaskAgent refuses identified callers acting on a task assigned to another agent.
+func checkTaskAgent(ctx context.Context, t *domain.Task) error {
+	if caller := CallerID(ctx); caller != "" && t.AgentID != "" && t.AgentID != caller {
+		return &domain.StructuredError{Code: "TASK_ASSIGNED", Message: "task is assigned to agent " + t.AgentID}
+	}
+	return nil
+}
+
+func taskClosed(t *domain.Task) error {
+	return &domain.StructuredError{Code: "TASK_CLOSED", Message: "task is " + t.Status}
+}
+
+// updateTask stamps and stores t and publishes a task event.
+func (s *Service) updateTask(t *domain.Task) (*domain.Task, error) {
+	t.UpdatedAt = time.Now().UTC()
+	t, err := s.Tasks.Update(t)
+	return changed(s, t, err)
+}
+
+// releaseAgentTasks reopens the unfinished tasks assigned to a deleted agent.
+func (s *Service) releaseAgentTasks(agentID string) error {
+	if s.Tasks == nil {
+		return nil
+	}
+	s.taskMu.Lock()
+	defer s.taskMu.Unlock()
+	for _, p := range s.Projects.List() {
+		for _, t := range s.Tasks.List(p.ID) {
+			if t.AgentID != agentID || t.Closed() {
+				continue
+			}
+			t.AgentID = ""
+			t.Status = domain.TaskOpen
+			if _, err := s.updateTask(t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dropTaskZone removes a deleted zone from the tasks routed to it.
+func (s *Service) dropTaskZone(z *domain.Zone) error {
+	if s.Tasks == nil {
+		return nil
+	}
+	s.taskMu.Lock()
+	defer s.taskMu.Unlock()
+	for _, t := range s.Tasks.List(z.ProjectID) {
+		if !slices.Contains(t.ZoneIDs, z.ID) {
+			continue
+		}
+		t.ZoneIDs = slices.DeleteFunc(t.ZoneIDs, func(id string) bool { return id == z.ID })
+		if _, err := s.updateTask(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package blueprint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"operators-mcp/internal/domain"
+)
+
+// promptFields lists the fields agent prompt templates may reference, by top-level name.
+// Vars holds the agent's declared variables and is checked against them instead.
+var promptFields = map[string][]string{
+	"Agent":   {"ID", "Name", "Description"},
+	"Project": {"ID", "Name", "RootDir"},
+	"Zone":    {"ID", "Name", "Purpose", "Constraints", "Paths", "Decisions"},
+	"Task":    nil,
+	"Vars":    nil,
+}
+
+// promptFuncs are the functions available to agent prompt templates in addition to the text/template builtins.
+var promptFuncs = template.FuncMap{
+	"join": strings.Join,
+	"bullets": func(items []string) string {
+		var b strings.Builder
+		for _, it := range items {
+			b.WriteString("- " + it + "\n")
+		}
+		return b.String()
+	},
+}
+
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedVariables are prompt arguments with a fixed meaning that variables may not shadow.
+var reservedVariables = map[string]bool{"zone_id": true, "task": true}
+
+// parsedPrompt is a validated agent prompt template.
+type parsedPrompt struct {
+	tmpl *template.Template
+	uses map[string]bool // top-level fields referenced (Zone, Task, ...)
+}
+
+// parsePrompt parses an agent prompt as a text/template and checks that it references only
+// known fields and declared variables. Variable declarations are validated too.
+func parsePrompt(text string, vars []domain.PromptVariable) (*parsedPrompt, error) {
+	declared := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		switch {
+		case !variableName.MatchString(v.Name):
+			return nil, &domain.StructuredError{Code: "INVALID_VARIABLE", Message: fmt.Sprintf("invalid variable name %q: use letters, digits and _", v.Name)}
+		case reservedVariables[v.Name]:
+			return nil, &domain.StructuredError{Code: "INVALID_VARIABLE", Message: fmt.Sprintf("variable name %q is reserved", v.Name)}
+		case declared[v.Name]:
+			return nil, &domain.StructuredError{Code: "INVALID_VARIABLE", Message: fmt.Sprintf("variable %q declared twice", v.Name)}
+		}
+		declared[v.Name] = true
+	}
+	tmpl, err := template.New("prompt").Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, &domain.StructuredError{Code: "INVALID_PROMPT", Message: err.Error()}
+	}
+	p := &parsedPrompt{tmpl: tmpl, uses: make(map[string]bool)}
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		if err := p.check(t.Tree.Root, true, declared); err != nil {
+			return nil, &domain.StructuredError{Code: "INVALID_PROMPT", Message: err.Error()}
+		}
+	}
+	return p, nil
+}
+
+// check walks the template tree. rootDot reports whether dot is the template data at this point;
+// inside range and with bodies it is not, so only $-rooted references are checked there.
+func (p *parsedPrompt) check(node parse.Node, rootDot bool, declared map[string]bool) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := p.check(c, rootDot, declared); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return p.check(n.Pipe, rootDot, declared)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Cmds {
+			if err := p.check(c, rootDot, declared); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		if err := p.checkIndex(n, rootDot, declared); err != nil {
+			return err
+		}
+		for _, a := range n.Args {
+			if err := p.check(a, rootDot, declared); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return p.checkBranch(&n.BranchNode, rootDot, rootDot, declared)
+	case *parse.RangeNode:
+		return p.checkBranch(&n.BranchNode, rootDot, false, declared)
+	case *parse.WithNode:
+		return p.checkBranch(&n.BranchNode, rootDot, false, declared)
+	case *parse.TemplateNode:
+		return p.check(n.Pipe, rootDot, declared)
+	case *parse.FieldNode:
+		if rootDot {
+			return p.checkPath(n.Ident, declared)
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			return p.checkPath(n.Ident[1:], declared)
+		}
+	case *parse.ChainNode:
+		return p.check(n.Node, rootDot, declared)
+	}
+	return nil
+}
+
+// checkIndex validates `index <field> "key"...` as the field chain extended with its literal keys,
+// so {{index .Vars "x"}} must name a declared variable like {{.Vars.x}}. Keys of .Vars must be
+// literal strings.
+func (p *parsedPrompt) checkIndex(n *parse.CommandNode, rootDot bool, declared map[string]bool) error {
+	if id, ok := n.Args[0].(*parse.IdentifierNode); !ok || id.Ident != "index" || len(n.Args) < 3 {
+		return nil
+	}
+	var ident []string
+	switch x := n.Args[1].(type) {
+	case *parse.DotNode:
+		if !rootDot {
+			return nil
+		}
+	case *parse.FieldNode:
+		if !rootDot {
+			return nil
+		}
+		ident = x.Ident
+	case *parse.VariableNode:
+		if x.Ident[0] != "$" {
+			return nil
+		}
+		ident = x.Ident[1:]
+	default:
+		return nil
+	}
+	ident = slices.Clone(ident)
+	for _, a := range n.Args[2:] {
+		key, ok := a.(*parse.StringNode)
+		if !ok {
+			if len(ident) == 1 && ident[0] == "Vars" {
+				return fmt.Errorf("index of .Vars must name a variable with a literal string")
+			}
+			break
+		}
+		ident = append(ident, key.Text)
+	}
+	if len(ident) == 0 {
+		return nil
+	}
+	return p.checkPath(ident, declared)
+}
+
+func (p *parsedPrompt) checkBranch(b *parse.BranchNode, rootDot, bodyRootDot bool, declared map[string]bool) error {
+	if err := p.check(b.Pipe, rootDot, declared); err != nil {
+		return err
+	}
+	if err := p.check(b.List, bodyRootDot, declared); err != nil {
+		return err
+	}
+	return p.check(b.ElseList, rootDot, declared)
+}
+
+// checkPath validates a field chain rooted at the template data, e.g. [Zone Name] or [Vars lang].
+func (p *parsedPrompt) checkPath(ident []string, declared map[string]bool) error {
+	ref := "." + strings.Join(ident, ".")
+	fields, ok := promptFields[ident[0]]
+	if !ok {
+		return fmt.Errorf("unknown field %s", ref)
+	}
+	p.uses[ident[0]] = true
+	if len(ident) == 1 {
+		return nil
+	}
+	switch ident[0] {
+	case "Vars":
+		if !declared[ident[1]] {
+			return fmt.Errorf("unknown variable %s (declare it in the agent's variables)", ref)
+		}
+	case "Task":
+		return fmt.Errorf("unknown field %s", ref)
+	default:
+		found := false
+		for _, f := range fields {
+			found = found || f == ident[1]
+		}
+		if !found {
+			return fmt.Errorf("unknown field %s", ref)
+		}
+	}
+	if len(ident) > 2 {
+		return fmt.Errorf("unknown field %s", ref)
+	}
+	return nil
+}
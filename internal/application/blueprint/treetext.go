@@ -0,0 +1,149 @@
+package blueprint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"operators-mcp/internal/domain"
+)
+
+// Tree formats for list_tree and list_matching_paths. JSON is the structured default; text is an
+// indented tree and collapsed is text with large directories summarized.
+const (
+	TreeFormatJSON      = "json"
+	TreeFormatText      = "text"
+	TreeFormatCollapsed = "collapsed"
+)
+
+// DefaultCollapseEntries is the number of entries above which the collapsed format summarizes a
+// directory.
+const DefaultCollapseEntries = 20
+
+// TreeTextOptions controls RenderTree. With Collapse, directories with more than MaxEntries
+// (DefaultCollapseEntries when unset) entries are summarized as "name/ (N files)"; directories at
+// Depth (when set) are summarized too.
+type TreeTextOptions struct {
+	Collapse   bool
+	MaxEntries int
+	Depth      int
+}
+
+// ParseTreeFormat validates a tree format; empty means TreeFormatJSON.
+func ParseTreeFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return TreeFormatJSON, nil
+	case TreeFormatJSON, TreeFormatText, TreeFormatCollapsed:
+		return format, nil
+	}
+	return "", &domain.StructuredError{Code: "INVALID_FORMAT", Message: "format must be json, text or collapsed"}
+}
+
+// RenderTree renders a tree as indented text, one entry per line and directories ending in "/".
+// An entry is annotated with the names of the zones containing it, in brackets, when they differ
+// from its parent directory's ("[no zone]" when it leaves them).
+func RenderTree(root *domain.TreeNode, zones []*domain.Zone, opts TreeTextOptions) string {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultCollapseEntries
+	}
+	var b strings.Builder
+	renderNode(&b, root, domain.NewZoneMatchers(zones), opts, 0, nil)
+	return b.String()
+}
+
+// renderNode writes n and, unless it is summarized, its children. A directory belongs to the zones
+// containing it as a whole, or, when summarized, any file under it.
+func renderNode(b *strings.Builder, n *domain.TreeNode, matchers []*domain.ZoneMatcher, opts TreeTextOptions, depth int, parent []string) {
+	summarize := n.IsDir && depth > 0 && len(n.Children) > 0 &&
+		((opts.Collapse && len(n.Children) > opts.MaxEntries) || (opts.Depth > 0 && depth >= opts.Depth))
+	var names []string
+	for _, m := range matchers {
+		if n.Path == "" {
+			break
+		}
+		in := m.Contains(n.Path)
+		if n.IsDir {
+			in = m.ContainsDir(n.Path) || (summarize && anyFile(n, m.Contains))
+		}
+		if in {
+			names = append(names, m.Zone.Name)
+		}
+	}
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(n.Name)
+	if n.IsDir {
+		b.WriteString("/")
+	}
+	if summarize {
+		fmt.Fprintf(b, " (%d files)", countFiles(n))
+	}
+	if depth > 0 && !slices.Equal(names, parent) {
+		if len(names) == 0 {
+			b.WriteString(" [no zone]")
+		} else {
+			b.WriteString(" [" + strings.Join(names, ", ") + "]")
+		}
+	}
+	b.WriteString("\n")
+	if summarize {
+		return
+	}
+	for _, c := range n.Children {
+		renderNode(b, c, matchers, opts, depth+1, names)
+	}
+}
+
+// PathsTree arranges project-relative paths into a tree rooted at ".". Paths with entries under
+// them are directories.
+func PathsTree(paths []string) *domain.TreeNode {
+	root := &domain.TreeNode{Name: ".", IsDir: true}
+	nodes := map[string]*domain.TreeNode{"": root}
+	var add func(path string) *domain.TreeNode
+	add = func(path string) *domain.TreeNode {
+		if n, ok := nodes[path]; ok {
+			return n
+		}
+		parentPath, name := "", path
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			parentPath, name = path[:i], path[i+1:]
+		}
+		parent := add(parentPath)
+		parent.IsDir = true
+		n := &domain.TreeNode{Path: path, Name: name}
+		parent.Children = append(parent.Children, n)
+		nodes[path] = n
+		return n
+	}
+	for _, p := range paths {
+		if p = domain.NormalizePath(p); p != "" && p != "." {
+			add(p)
+		}
+	}
+	for _, n := range nodes {
+		sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	}
+	return root
+}
+
+// countFiles returns the number of files under n.
+func countFiles(n *domain.TreeNode) int {
+	count := 0
+	anyFile(n, func(string) bool { count++; return false })
+	return count
+}
+
+// anyFile reports whether f is true for a file under n, visiting files in order until it is.
+func anyFile(n *domain.TreeNode, f func(path string) bool) bool {
+	for _, c := range n.Children {
+		if c.IsDir {
+			if anyFile(c, f) {
+				return true
+			}
+		} else if f(c.Path) {
+			return true
+		}
+	}
+	return false
+}
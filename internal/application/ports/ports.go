@@ -12,16 +12,21 @@ type ProjectRepository interface {
 	Delete(projectID string) error
 	AddIgnoredPath(projectID, path string) (*domain.Project, error)
 	RemoveIgnoredPath(projectID, path string) (*domain.Project, error)
+	SetBlueprintSync(projectID, file, fileHash, storeHash string) (*domain.Project, error)
 }
 
 // ZoneRepository is the outbound port for persisting and retrieving zones.
 // Zones are scoped to a project. Implemented by adapters (e.g. in-memory store, future DB).
+// Agent assignments are stored as references (agent ids); zones returned by the repository
+// have AgentIDs set and AssignedAgents empty.
 type ZoneRepository interface {
 	Get(id string) *domain.Zone
 	ListByProject(projectID string) []*domain.Zone
-	Create(projectID, name, pattern, purpose string, constraints []string, agents []domain.Agent) (*domain.Zone, error)
-	Update(id, name, pattern, purpose string, constraints []string, agents []domain.Agent) (*domain.Zone, error)
+	Create(projectID, name, pattern, purpose string, constraints []string, agentIDs []string) (*domain.Zone, error)
+	Update(id, name, pattern, purpose string, constraints []string, agentIDs []string) (*domain.Zone, error)
 	AssignPath(zoneID, path string) (*domain.Zone, error)
+	UnassignPath(zoneID, path string) (*domain.Zone, error)
+	Delete(id string) error
 	DeleteByProject(projectID string) error
 }
 
@@ -38,11 +43,109 @@ type TreeLister interface {
 }
 
 // AgentRepository is the outbound port for persisting and retrieving agents.
-// Agents can be assigned to zones.
+// Agents can be assigned to zones. Delete also removes the agent's zone assignments where the
+// adapter shares storage with zones (in one transaction); otherwise dangling ids are dropped on read.
 type AgentRepository interface {
 	Get(id string) *domain.Agent
 	List() []*domain.Agent
-	Create(name, description, prompt string) (*domain.Agent, error)
-	Update(id, name, description, prompt string) (*domain.Agent, error)
+	Create(name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error)
+	Update(id, name, description, prompt string, variables []domain.PromptVariable) (*domain.Agent, error)
 	Delete(id string) error
 }
+
+// DependencyAnalyzer is the outbound port for discovering source-level dependencies under a root.
+// ListImports returns, for each project-relative file, the project-relative directories it imports.
+// Implemented by the filesystem adapter; projects it cannot analyze yield an empty map.
+type DependencyAnalyzer interface {
+	ListImports(root string) (map[string][]string, error)
+}
+
+// FileStore is the outbound port for reading and writing files inside a project root.
+// Paths are relative to root; paths that escape root (including through symlinks) are rejected
+// with INVALID_PATH and missing files are reported with FILE_NOT_FOUND. Writes are atomic.
+// FileSize reports NOT_A_FILE for directories.
+type FileStore interface {
+	FileSize(root, path string) (int64, error)
+	ReadFile(root, path string) ([]byte, error)
+	WriteFile(root, path string, data []byte) error
+	RemoveFile(root, path string) error
+}
+
+// ChangeLog is the outbound port for recording file changes made through the service.
+// Record assigns the id; List returns a project's changes newest first, at most limit (0 means all).
+type ChangeLog interface {
+	Record(c *domain.FileChange) (*domain.FileChange, error)
+	List(projectID string, limit int) []*domain.FileChange
+}
+
+// AgentTokenStore is the outbound port for the bearer tokens agents use to identify themselves
+// on MCP sessions. Only a hash of each token is stored and an agent has at most one token:
+// Set replaces the previous one. AgentID returns "" for an unknown hash. Any reports whether
+// any agent holds a token.
+type AgentTokenStore interface {
+	Set(agentID, tokenHash string) error
+	AgentID(tokenHash string) string
+	Delete(agentID string) error
+	Any() bool
+}
+
+// TaskRepository is the outbound port for persisting and retrieving tasks.
+// Create assigns the id; Update replaces every field of an existing task. List returns a
+// project's tasks oldest first.
+type TaskRepository interface {
+	Get(id string) *domain.Task
+	List(projectID string) []*domain.Task
+	Create(t *domain.Task) (*domain.Task, error)
+	Update(t *domain.Task) (*domain.Task, error)
+	Delete(id string) error
+	DeleteByProject(projectID string) error
+}
+
+// LeaseStore is the outbound port for zone leases, keyed by zone id. Put creates or replaces the
+// zone's lease. Expired leases are returned as stored; the service decides what is active.
+type LeaseStore interface {
+	Get(zoneID string) *domain.ZoneLease
+	List(projectID string) []*domain.ZoneLease
+	Put(l *domain.ZoneLease) error
+	Delete(zoneID string) error
+}
+
+// RunRepository is the outbound port for orchestration runs. List returns a project's runs oldest
+// first. Update replaces every field of an existing run, including its task graph.
+type RunRepository interface {
+	Get(id string) *domain.Run
+	List(projectID string) []*domain.Run
+	Create(r *domain.Run) (*domain.Run, error)
+	Update(r *domain.Run) (*domain.Run, error)
+	Delete(id string) error
+	DeleteByProject(projectID string) error
+}
+
+// DecisionRepository is the outbound port for architecture decision records. List returns a
+// project's decisions oldest first.
+type DecisionRepository interface {
+	Get(id string) *domain.Decision
+	List(projectID string) []*domain.Decision
+	Create(d *domain.Decision) (*domain.Decision, error)
+	Update(d *domain.Decision) (*domain.Decision, error)
+	DeleteByProject(projectID string) error
+}
+
+// NoteRepository is the outbound port for zone notes. List returns a project's notes oldest first.
+type NoteRepository interface {
+	Get(id string) *domain.ZoneNote
+	List(projectID string) []*domain.ZoneNote
+	Create(n *domain.ZoneNote) (*domain.ZoneNote, error)
+	Delete(id string) error
+	DeleteByProject(projectID string) error
+}
+
+// MessageRepository is the outbound port for agent messages. List returns a project's messages
+// oldest first.
+type MessageRepository interface {
+	Get(id string) *domain.Message
+	List(projectID string) []*domain.Message
+	Create(m *domain.Message) (*domain.Message, error)
+	Update(m *domain.Message) (*domain.Message, error)
+	DeleteByProject(projectID string) error
+}
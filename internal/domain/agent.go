@@ -1,9 +1,20 @@
 package domain
 
 // Agent represents an agent that can be assigned to a zone.
+// Prompt is a text/template; Variables declares the caller-supplied values it may reference.
 type Agent struct {
 	ID          string
 	Name        string
 	Description string
 	Prompt      string
+	Variables   []PromptVariable
+}
+
+// PromptVariable is a value supplied when an agent prompt is rendered.
+// Default is used when the caller omits a variable that is not Required.
+type PromptVariable struct {
+	Name        string
+	Description string
+	Required    bool
+	Default     string
 }
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// File change operations recorded in FileChange.Operation.
+const (
+	ChangeCreate = "create"
+	ChangeWrite  = "write"
+	ChangeDelete = "delete"
+)
+
+// FileChange records a file modification made through the service on behalf of an agent.
+// OldHash and NewHash are SHA-256 hex digests of the content before and after (empty when the
+// file did not exist before or was deleted). Source is the tool that made the change.
+type FileChange struct {
+	ID           string
+	ProjectID    string
+	AgentID      string
+	Path         string
+	Operation    string
+	Source       string
+	OldHash      string
+	NewHash      string
+	LinesAdded   int
+	LinesRemoved int
+	CreatedAt    time.Time
+}
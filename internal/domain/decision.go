@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Decision statuses. A decision is proposed, then accepted, and superseded once a later decision
+// replaces it.
+const (
+	DecisionProposed   = "proposed"
+	DecisionAccepted   = "accepted"
+	DecisionSuperseded = "superseded"
+)
+
+// Decision is an architecture decision record (ADR) explaining why one or more zones of a project
+// are shaped the way they are. Number is sequential within the project and names the record
+// (ADR-0001). Supersedes is the id of the decision this one replaces, if any; CreatedBy is the
+// agent that recorded it (empty for anonymous callers).
+type Decision struct {
+	ID           string
+	ProjectID    string
+	Number       int
+	Title        string
+	Context      string
+	Decision     string
+	Consequences string
+	Status       string
+	ZoneIDs      []string
+	Supersedes   string
+	CreatedBy    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Label returns the record's name, e.g. ADR-0003.
+func (d *Decision) Label() string {
+	return fmt.Sprintf("ADR-%04d", d.Number)
+}
+
+// ValidDecisionStatus reports whether status is one of the decision statuses.
+func ValidDecisionStatus(status string) bool {
+	return slices.Contains([]string{DecisionProposed, DecisionAccepted, DecisionSuperseded}, status)
+}
@@ -10,3 +10,21 @@ type StructuredError struct {
 func (e *StructuredError) Error() string {
 	return e.Code + ": " + e.Message
 }
+
+// ZoneViolation is a path the caller may not change, with the names of the zones that own it
+// (empty when no zone contains the path).
+type ZoneViolation struct {
+	Path        string
+	OwningZones []string
+}
+
+// OutOfZoneError is the OUT_OF_ZONE StructuredError, listing every offending path.
+type OutOfZoneError struct {
+	StructuredError
+	Violations []ZoneViolation
+}
+
+// Unwrap exposes the StructuredError so errors.As finds the code.
+func (e *OutOfZoneError) Unwrap() error {
+	return &e.StructuredError
+}
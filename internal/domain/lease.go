@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ZoneLease grants one agent exclusive write access to a zone until ExpiresAt. A zone has at most
+// one lease; an expired lease no longer restricts anyone and may be replaced.
+type ZoneLease struct {
+	ZoneID     string
+	ProjectID  string
+	AgentID    string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+// Active reports whether the lease has not expired at now.
+func (l *ZoneLease) Active(now time.Time) bool {
+	return now.Before(l.ExpiresAt)
+}
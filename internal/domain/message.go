@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Message is a note from one agent to another within a project, e.g. a request to change an
+// interface owned by another zone. A message sent to a zone is stored once per agent of the zone,
+// with ZoneID recording the addressed zone. FromAgentID is empty for anonymous senders.
+//
+// Messages form threads: ThreadID is the id of the thread's first message, and empty on that
+// first message itself (see Thread). ReplyTo is the message answered, if any. AckedAt is zero until
+// the recipient acknowledges the message.
+type Message struct {
+	ID          string
+	ProjectID   string
+	ThreadID    string
+	ReplyTo     string
+	FromAgentID string
+	ToAgentID   string
+	ZoneID      string
+	Subject     string
+	Body        string
+	CreatedAt   time.Time
+	AckedAt     time.Time
+}
+
+// Thread returns the id of the message's thread.
+func (m *Message) Thread() string {
+	if m.ThreadID != "" {
+		return m.ThreadID
+	}
+	return m.ID
+}
+
+// Acked reports whether the recipient has acknowledged the message.
+func (m *Message) Acked() bool {
+	return !m.AckedAt.IsZero()
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// ZoneNote is a short, informal note agents keep about a zone: a gotcha, an entry point, a command
+// that works. AgentID is the author (empty for anonymous callers). Pinned notes are kept until
+// deleted; other notes expire at ExpiresAt.
+type ZoneNote struct {
+	ID        string
+	ProjectID string
+	ZoneID    string
+	AgentID   string
+	Text      string
+	Tags      []string
+	Pinned    bool
+	CreatedAt time.Time
+	ExpiresAt time.Time // zero for pinned notes
+}
+
+// Expired reports whether the note has expired at now.
+func (n *ZoneNote) Expired(now time.Time) bool {
+	return !n.Pinned && !n.ExpiresAt.IsZero() && !now.Before(n.ExpiresAt)
+}
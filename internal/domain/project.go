@@ -3,9 +3,14 @@ package domain
 // Project defines the directory root that everything (tree, matching paths, zones) is based on.
 // All paths and operations are relative to the project's root.
 // IgnoredPaths are paths (files or directories) to hide from the tree view; children of ignored dirs are hidden too.
+// BlueprintFile optionally binds the project to a blueprint document inside RootDir (e.g. ".operators/blueprint.yaml");
+// BlueprintFileHash and BlueprintStoreHash record the file and store content at the last successful sync.
 type Project struct {
-	ID           string
-	Name         string
-	RootDir      string
-	IgnoredPaths []string
+	ID                 string
+	Name               string
+	RootDir            string
+	IgnoredPaths       []string
+	BlueprintFile      string
+	BlueprintFileHash  string
+	BlueprintStoreHash string
 }
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Run statuses, derived from the tasks of a run. A run is pending until one of its tasks is
+// claimed, active while work is possible, stalled when unfinished tasks remain but none is ready
+// or being worked on (a prerequisite was cancelled), and complete when every task is closed.
+const (
+	RunPending  = "pending"
+	RunActive   = "active"
+	RunStalled  = "stalled"
+	RunComplete = "complete"
+)
+
+// Run groups tasks of a project into a dependency graph driven by an orchestrator. Tasks are kept
+// in the order they were added; a task belongs to at most one run.
+type Run struct {
+	ID          string
+	ProjectID   string
+	Title       string
+	Description string
+	Tasks       []RunTask
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// RunTask is a task of a run and the ids of the tasks of the same run it depends on. A task is
+// ready once every prerequisite is done.
+type RunTask struct {
+	TaskID    string
+	DependsOn []string
+}
+
+// Task returns the run's entry for taskID, or nil.
+func (r *Run) Task(taskID string) *RunTask {
+	i := slices.IndexFunc(r.Tasks, func(t RunTask) bool { return t.TaskID == taskID })
+	if i < 0 {
+		return nil
+	}
+	return &r.Tasks[i]
+}
+
+// Cycle returns a dependency cycle of the run as task ids, the first repeated at the end
+// (a → b → a), or nil when the graph is acyclic.
+func (r *Run) Cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(r.Tasks))
+	var path []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case visiting:
+			i := slices.Index(path, id)
+			return append(slices.Clone(path[i:]), id)
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		path = append(path, id)
+		if t := r.Task(id); t != nil {
+			for _, dep := range t.DependsOn {
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+	for _, t := range r.Tasks {
+		if cycle := visit(t.TaskID); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Task statuses. A task starts open, is claimed by an agent, may move between in_progress and
+// blocked while it is worked on, and ends done or cancelled.
+const (
+	TaskOpen       = "open"
+	TaskClaimed    = "claimed"
+	TaskInProgress = "in_progress"
+	TaskBlocked    = "blocked"
+	TaskDone       = "done"
+	TaskCancelled  = "cancelled"
+)
+
+// Task is a unit of work in a project, routed to the zones it touches and handed to an agent.
+// AgentID is the assigned agent (empty while unassigned); CreatedBy is the agent that created
+// it (empty for anonymous callers). Result is the summary given when the task was completed.
+type Task struct {
+	ID          string
+	ProjectID   string
+	Title       string
+	Description string
+	Status      string
+	ZoneIDs     []string
+	AgentID     string
+	CreatedBy   string
+	Result      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Closed reports whether the task is done or cancelled.
+func (t *Task) Closed() bool {
+	return t.Status == TaskDone || t.Status == TaskCancelled
+}
+
+// ValidTaskStatus reports whether status is one of the task statuses.
+func ValidTaskStatus(status string) bool {
+	return slices.Contains([]string{TaskOpen, TaskClaimed, TaskInProgress, TaskBlocked, TaskDone, TaskCancelled}, status)
+}
@@ -1,8 +1,16 @@
 package domain
 
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
 // Zone holds zone state (pattern, metadata, explicit paths).
 // It is the core entity for the blueprint/pattern-management domain.
 // A zone belongs to a project and paths are relative to that project's root.
+// Assignments are stored as AgentIDs; AssignedAgents holds the current agents for those ids
+// and is filled in by the application service on read.
 type Zone struct {
 	ID             string
 	ProjectID      string
@@ -10,6 +18,62 @@ type Zone struct {
 	Pattern        string
 	Purpose        string
 	Constraints    []string
+	AgentIDs       []string
 	AssignedAgents []Agent
 	ExplicitPaths  []string
 }
+
+// Contains reports whether the project-relative path belongs to the zone, either because
+// it is (or is under) one of the explicit paths or because it matches the zone pattern.
+// An invalid pattern matches nothing. To test many paths, use a ZoneMatcher.
+func (z *Zone) Contains(path string) bool {
+	return NewZoneMatcher(z).Contains(path)
+}
+
+// ZoneMatcher tests paths against a zone with its pattern compiled once.
+type ZoneMatcher struct {
+	Zone *Zone
+	re   *regexp.Regexp
+}
+
+// NewZoneMatcher returns a matcher for the zone. An invalid pattern matches nothing.
+func NewZoneMatcher(z *Zone) *ZoneMatcher {
+	m := &ZoneMatcher{Zone: z}
+	if z.Pattern != "" {
+		m.re, _ = regexp.Compile(z.Pattern)
+	}
+	return m
+}
+
+// NewZoneMatchers returns a matcher per zone, in order.
+func NewZoneMatchers(zones []*Zone) []*ZoneMatcher {
+	out := make([]*ZoneMatcher, len(zones))
+	for i, z := range zones {
+		out[i] = NewZoneMatcher(z)
+	}
+	return out
+}
+
+// Contains is Zone.Contains.
+func (m *ZoneMatcher) Contains(path string) bool {
+	path = NormalizePath(path)
+	for _, p := range m.Zone.ExplicitPaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return m.re != nil && m.re.MatchString(path)
+}
+
+// ContainsDir reports whether the zone contains the directory as a whole: it contains the
+// directory's path or its pattern matches "dir/".
+func (m *ZoneMatcher) ContainsDir(dir string) bool {
+	dir = NormalizePath(dir)
+	return m.Contains(dir) || (m.re != nil && m.re.MatchString(dir+"/"))
+}
+
+// Touches reports whether the zone contains the directory or an explicit path under it.
+func (m *ZoneMatcher) Touches(dir string) bool {
+	dir = NormalizePath(dir)
+	return m.ContainsDir(dir) || slices.ContainsFunc(m.Zone.ExplicitPaths, func(e string) bool { return strings.HasPrefix(e, dir+"/") })
+}
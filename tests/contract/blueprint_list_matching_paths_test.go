@@ -25,7 +25,11 @@ func TestListMatchingPaths_ValidPattern_ReturnsPaths(t *testing.T) {
 	agentStore := memory.NewAgentStore()
 	pathMatcher := filesystem.NewMatcher()
 	treeLister := filesystem.NewLister()
-	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister, root)
+	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister)
+	p, err := svc.CreateProject("test", root)
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
 	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
 	defer cleanup()
 	c := testhelper.NewTestClient(t, baseURL)
@@ -34,7 +38,7 @@ func TestListMatchingPaths_ValidPattern_ReturnsPaths(t *testing.T) {
 	ctx := context.Background()
 	callReq := mcp.CallToolRequest{}
 	callReq.Params.Name = "list_matching_paths"
-	callReq.Params.Arguments = map[string]any{"pattern": "cmd"}
+	callReq.Params.Arguments = map[string]any{"pattern": "cmd", "project_id": p.ID}
 	res, err := c.CallTool(ctx, callReq)
 	if err != nil {
 		t.Fatalf("CallTool: %v", err)
@@ -71,7 +75,7 @@ func TestListMatchingPaths_InvalidPattern_StructuredError(t *testing.T) {
 	agentStore := memory.NewAgentStore()
 	pathMatcher := filesystem.NewMatcher()
 	treeLister := filesystem.NewLister()
-	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister, root)
+	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister)
 	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
 	defer cleanup()
 	c := testhelper.NewTestClient(t, baseURL)
@@ -80,7 +84,7 @@ func TestListMatchingPaths_InvalidPattern_StructuredError(t *testing.T) {
 	ctx := context.Background()
 	callReq := mcp.CallToolRequest{}
 	callReq.Params.Name = "list_matching_paths"
-	callReq.Params.Arguments = map[string]any{"pattern": "["}
+	callReq.Params.Arguments = map[string]any{"pattern": "[", "root": root}
 	res, err := c.CallTool(ctx, callReq)
 	if err != nil {
 		t.Fatalf("CallTool: %v", err)
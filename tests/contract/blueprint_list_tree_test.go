@@ -24,7 +24,11 @@ func TestListTree_ValidRoot_ReturnsTree(t *testing.T) {
 	agentStore := memory.NewAgentStore()
 	pathMatcher := filesystem.NewMatcher()
 	treeLister := filesystem.NewLister()
-	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister, root)
+	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister)
+	p, err := svc.CreateProject("test", root)
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
 	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
 	defer cleanup()
 	c := testhelper.NewTestClient(t, baseURL)
@@ -33,7 +37,7 @@ func TestListTree_ValidRoot_ReturnsTree(t *testing.T) {
 	ctx := context.Background()
 	callReq := mcp.CallToolRequest{}
 	callReq.Params.Name = "list_tree"
-	callReq.Params.Arguments = map[string]any{}
+	callReq.Params.Arguments = map[string]any{"project_id": p.ID}
 	res, err := c.CallTool(ctx, callReq)
 	if err != nil {
 		t.Fatalf("CallTool: %v", err)
@@ -73,7 +77,7 @@ func TestListTree_UnreadableRoot_StructuredError(t *testing.T) {
 	agentStore := memory.NewAgentStore()
 	pathMatcher := filesystem.NewMatcher()
 	treeLister := filesystem.NewLister()
-	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister, "/nonexistent/path/12345")
+	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister)
 	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
 	defer cleanup()
 	c := testhelper.NewTestClient(t, baseURL)
@@ -82,7 +86,7 @@ func TestListTree_UnreadableRoot_StructuredError(t *testing.T) {
 	ctx := context.Background()
 	callReq := mcp.CallToolRequest{}
 	callReq.Params.Name = "list_tree"
-	callReq.Params.Arguments = map[string]any{}
+	callReq.Params.Arguments = map[string]any{"root": "/nonexistent/path/12345"}
 	res, err := c.CallTool(ctx, callReq)
 	if err != nil {
 		t.Fatalf("CallTool: %v", err)
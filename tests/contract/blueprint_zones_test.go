@@ -19,7 +19,7 @@ func TestZones_ListCreateGetUpdateAssignPath(t *testing.T) {
 	agentStore := memory.NewAgentStore()
 	pathMatcher := filesystem.NewMatcher()
 	treeLister := filesystem.NewLister()
-	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister, root)
+	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister)
 	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
 	defer cleanup()
 	c := testhelper.NewTestClient(t, baseURL)
@@ -173,13 +173,12 @@ func TestZones_ListCreateGetUpdateAssignPath(t *testing.T) {
 }
 
 func TestGetZone_NotFound_StructuredError(t *testing.T) {
-	root := t.TempDir()
 	projectStore := memory.NewProjectStore()
 	zoneStore := memory.NewStore()
 	agentStore := memory.NewAgentStore()
 	pathMatcher := filesystem.NewMatcher()
 	treeLister := filesystem.NewLister()
-	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister, root)
+	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister)
 	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
 	defer cleanup()
 	c := testhelper.NewTestClient(t, baseURL)
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"operators-mcp/tests/testhelper"
+)
+
+// TestActiveProject_SessionAndEndpoint verifies project_id defaults to the project bound with
+// set_active_project or fixed by /projects/{id}/mcp, and is required otherwise. Tools called on
+// the project endpoint cannot reach another project's zones or tasks by id.
+func TestActiveProject_SessionAndEndpoint(t *testing.T) {
+	rootA, rootB := t.TempDir(), t.TempDir()
+	_ = os.WriteFile(filepath.Join(rootA, "a.txt"), []byte("a\n"), 0644)
+	_ = os.WriteFile(filepath.Join(rootB, "b.txt"), []byte("b\n"), 0644)
+	svc := testhelper.NewMemoryService()
+	pa, _ := svc.CreateProject("a", rootA)
+	pb, _ := svc.CreateProject("b", rootB)
+	bz, _ := svc.CreateZone(pb.ID, "bz", "", "", nil, nil)
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+	other := testhelper.NewTestClient(t, baseURL)
+	defer other.Close()
+
+	if text, isErr := callText(t, c, "list_zones", nil); !isErr || !strings.Contains(text, "project_id is required") {
+		t.Errorf("list_zones without a project = %s", text)
+	}
+	if text, isErr := callText(t, c, "list_tree", nil); !isErr || !strings.Contains(text, "project_id or root is required") {
+		t.Errorf("list_tree without a project = %s", text)
+	}
+	if text, isErr := callText(t, c, "set_active_project", map[string]any{"project_id": pa.ID}); isErr || !strings.Contains(text, `"name":"a"`) {
+		t.Fatalf("set_active_project = %s", text)
+	}
+	if text, isErr := callText(t, c, "create_zone", map[string]any{"name": "az"}); isErr {
+		t.Fatalf("create_zone in the active project = %s", text)
+	}
+	if zones := svc.ListZones(pa.ID); len(zones) != 1 || zones[0].Name != "az" {
+		t.Errorf("zones of the active project = %v", zones)
+	}
+	if text, _ := callText(t, c, "list_tree", nil); !strings.Contains(text, "a.txt") {
+		t.Errorf("list_tree in the active project = %s", text)
+	}
+	if text, _ := callText(t, c, "list_zones", map[string]any{"project_id": pb.ID}); !strings.Contains(text, `"name":"bz"`) {
+		t.Errorf("explicit project_id should win: %s", text)
+	}
+	if _, isErr := callText(t, other, "list_zones", nil); !isErr {
+		t.Error("another session should not see the binding")
+	}
+	if _, isErr := callText(t, c, "set_active_project", map[string]any{"project_id": "nope"}); !isErr {
+		t.Error("set_active_project with an unknown project: expected error")
+	}
+	_, _ = callText(t, c, "set_active_project", nil)
+	if _, isErr := callText(t, c, "list_zones", nil); !isErr {
+		t.Error("list_zones after clearing the binding: expected error")
+	}
+
+	pc := testhelper.NewTestClient(t, baseURL+"/projects/"+pb.ID+"/mcp")
+	defer pc.Close()
+	if text, _ := callText(t, pc, "list_zones", nil); !strings.Contains(text, `"name":"bz"`) {
+		t.Errorf("list_zones on the project endpoint = %s", text)
+	}
+	if text, isErr := callText(t, pc, "set_active_project", map[string]any{"project_id": pa.ID}); !isErr || !strings.Contains(text, "fixed") {
+		t.Errorf("set_active_project on the project endpoint = %s", text)
+	}
+	if text, isErr := callText(t, pc, "list_zones", map[string]any{"project_id": pa.ID}); !isErr || !strings.Contains(text, "not this endpoint's project") {
+		t.Errorf("list_zones of another project on the project endpoint = %s", text)
+	}
+	if text, isErr := callText(t, pc, "delete_project", map[string]any{"project_id": pa.ID}); !isErr || svc.GetProject(pa.ID) == nil {
+		t.Errorf("delete_project of another project on the project endpoint = %s", text)
+	}
+	az := svc.ListZones(pa.ID)[0]
+	task, _ := svc.CreateTask(context.Background(), pa.ID, "a task", "", nil, "")
+	for tool, args := range map[string]map[string]any{
+		"get_zone":            {"zone_id": az.ID},
+		"update_zone":         {"zone_id": az.ID, "name": "taken"},
+		"assign_path_to_zone": {"zone_id": az.ID, "path": "a.txt"},
+		"get_task":            {"task_id": task.ID},
+	} {
+		if text, isErr := callText(t, pc, tool, args); !isErr || !strings.Contains(text, "not in this endpoint's project") {
+			t.Errorf("%s of another project on the project endpoint = %s", tool, text)
+		}
+	}
+	if z := svc.GetZone(az.ID); z == nil || z.Name != "az" || len(z.ExplicitPaths) != 0 {
+		t.Errorf("zone of another project after project endpoint calls = %+v", z)
+	}
+	if text, isErr := callText(t, pc, "get_zone", map[string]any{"zone_id": bz.ID}); isErr {
+		t.Errorf("get_zone of the endpoint's project = %s", text)
+	}
+	for _, tool := range []string{"list_tree", "list_matching_paths"} {
+		if text, isErr := callText(t, pc, tool, map[string]any{"root": "/", "pattern": "."}); !isErr || !strings.Contains(text, "root is not accepted") {
+			t.Errorf("%s with root on the project endpoint = %s", tool, text)
+		}
+	}
+	if _, _, err := connectAgent(t, baseURL+"/projects/nope/mcp"); err == nil {
+		t.Error("unknown project endpoint: expected error")
+	}
+
+	_, _ = callText(t, c, "set_active_project", map[string]any{"project_id": pa.ID})
+	if err := svc.DeleteProject(pa.ID); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+	if text, isErr := callText(t, c, "list_zones", nil); !isErr || !strings.Contains(text, "project_id is required") {
+		t.Errorf("binding should be dropped with the project: %s", text)
+	}
+}
@@ -0,0 +1,180 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	adapter "operators-mcp/internal/adapter/in/mcp"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/tests/testhelper"
+)
+
+func connectAgent(t *testing.T, url string, opts ...transport.StreamableHTTPCOption) (*client.Client, *mcp.InitializeResult, error) {
+	t.Helper()
+	trans, err := transport.NewStreamableHTTP(url, opts...)
+	if err != nil {
+		t.Fatalf("transport: %v", err)
+	}
+	c := client.NewClient(trans)
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	initReq := mcp.InitializeRequest{}
+	initReq.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initReq.Params.ClientInfo = mcp.Implementation{Name: "test", Version: "0.0.1"}
+	res, err := c.Initialize(ctx, initReq)
+	if err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	return c, res, nil
+}
+
+// agentBearer issues a token for the agent and returns the header option that sends it.
+func agentBearer(t *testing.T, svc *blueprint.Service, agentID string) transport.StreamableHTTPCOption {
+	t.Helper()
+	token, err := svc.IssueAgentToken(agentID)
+	if err != nil {
+		t.Fatalf("IssueAgentToken: %v", err)
+	}
+	return transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token})
+}
+
+// TestAgentEndpoint_ScopedToAgentZones verifies /agents/{id}/mcp seeds instructions from the
+// agent's prompt, offers only the working tools and restricts listings and writes to its zones.
+func TestAgentEndpoint_ScopedToAgentZones(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"api/server.go", "api/routes.go", "db/store.go", "README.md"} {
+		_ = os.MkdirAll(filepath.Join(root, filepath.Dir(name)), 0755)
+		_ = os.WriteFile(filepath.Join(root, name), []byte("x\n"), 0644)
+	}
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "You review APIs.", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	_, _ = svc.CreateZone(p.ID, "api", "^api/", "HTTP handlers", nil, []string{ada.ID})
+	db, _ := svc.CreateZone(p.ID, "db", "^db/", "", nil, []string{bob.ID})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	c, init, err := connectAgent(t, baseURL+"/agents/"+ada.ID+"/mcp", agentBearer(t, svc, ada.ID))
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	defer c.Close()
+	if !strings.Contains(init.Instructions, "You review APIs.") || !strings.Contains(init.Instructions, "- api (zone_id") {
+		t.Errorf("instructions = %q", init.Instructions)
+	}
+
+	tools, err := c.ListTools(context.Background(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	names := map[string]bool{}
+	for _, tool := range tools.Tools {
+		names[tool.Name] = true
+	}
+	if !names["read_file"] || !names["apply_patch"] || names["create_project"] || names["delete_agent"] || names["issue_agent_token"] {
+		t.Errorf("scoped tools = %v", names)
+	}
+
+	if text, _ := callText(t, c, "list_tree", nil); !strings.Contains(text, "api/server.go") || strings.Contains(text, "db/store.go") || strings.Contains(text, "README.md") {
+		t.Errorf("list_tree = %s", text)
+	}
+	if text, _ := callText(t, c, "list_matching_paths", map[string]any{"pattern": "\\.go$"}); text != `{"paths":["api/routes.go","api/server.go"]}` {
+		t.Errorf("list_matching_paths = %s", text)
+	}
+	if text, isErr := callText(t, c, "get_zone", map[string]any{"zone_id": db.ID}); !isErr || !strings.Contains(text, "not assigned") {
+		t.Errorf("get_zone of another agent's zone = %s", text)
+	}
+	if text, isErr := callText(t, c, "write_file", map[string]any{"project_id": p.ID, "path": "db/store.go", "content": ""}); !isErr || !strings.Contains(text, "OUT_OF_ZONE") {
+		t.Errorf("write_file out of zone = %s", text)
+	}
+	if text, isErr := callText(t, c, "write_file", map[string]any{"project_id": p.ID, "path": "api/new.go", "content": "package api\n"}); isErr {
+		t.Errorf("write_file in zone = %s", text)
+	}
+
+	if _, _, err := connectAgent(t, baseURL+"/agents/"+ada.ID+"/mcp"); err == nil {
+		t.Error("endpoint without a token: expected error")
+	}
+	bobToken, _ := svc.IssueAgentToken(bob.ID)
+	if _, _, err := connectAgent(t, baseURL+"/agents/"+ada.ID+"/mcp", transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + bobToken})); err == nil {
+		t.Error("another agent's token on the endpoint: expected error")
+	}
+
+	// With AllowAnonymous the endpoint serves requests without a token.
+	agents := adapter.NewAgentServers(svc)
+	agents.AllowAnonymous = true
+	mux := http.NewServeMux()
+	mux.Handle(adapter.AgentEndpointPattern, agents)
+	anon := httptest.NewServer(mux)
+	defer anon.Close()
+	ac, _, err := connectAgent(t, anon.URL+"/agents/"+ada.ID+"/mcp")
+	if err != nil {
+		t.Fatalf("anonymous endpoint: %v", err)
+	}
+	defer ac.Close()
+	if text, _ := callText(t, ac, "whoami", nil); !strings.Contains(text, `"name":"Ada"`) {
+		t.Errorf("anonymous whoami = %s", text)
+	}
+	if _, _, err := connectAgent(t, anon.URL+"/agents/nope/mcp"); err == nil {
+		t.Error("unknown agent endpoint: expected error")
+	}
+}
+
+// TestAgentEndpoint_ScopedToAgentProjects verifies leases, decisions and note deletion on the
+// agent endpoint are refused for projects in which the agent has no zone.
+func TestAgentEndpoint_ScopedToAgentProjects(t *testing.T) {
+	svc := testhelper.NewMemoryService()
+	mine, _ := svc.CreateProject("mine", t.TempDir())
+	other, _ := svc.CreateProject("other", t.TempDir())
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	_, _ = svc.CreateZone(mine.ID, "api", "^api/", "", nil, []string{ada.ID})
+	web, _ := svc.CreateZone(other.ID, "web", "^web/", "", nil, []string{ada.ID})
+	ctx := context.Background()
+	note, err := svc.AddZoneNote(ctx, web.ID, ada.ID, blueprint.NoteDraft{Text: "run make web"})
+	if err != nil {
+		t.Fatalf("AddZoneNote: %v", err)
+	}
+	// Ada leaves the other project; the note she wrote there stays.
+	if _, err := svc.UpdateZone(web.ID, web.Name, web.Pattern, "", nil, nil); err != nil {
+		t.Fatalf("UpdateZone: %v", err)
+	}
+	dec, _ := svc.CreateDecision(ctx, other.ID, blueprint.DecisionDraft{Title: "Use SSR", Decision: "Render on the server."})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	c, _, err := connectAgent(t, baseURL+"/agents/"+ada.ID+"/mcp", agentBearer(t, svc, ada.ID))
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	defer c.Close()
+	for _, call := range []struct {
+		name string
+		args map[string]any
+	}{
+		{"list_leases", map[string]any{"project_id": other.ID}},
+		{"list_decisions", map[string]any{"project_id": other.ID}},
+		{"get_decision", map[string]any{"decision_id": dec.ID}},
+		{"delete_zone_note", map[string]any{"note_id": note.ID}},
+	} {
+		if text, isErr := callText(t, c, call.name, call.args); !isErr || !strings.Contains(text, "has no zone in project") {
+			t.Errorf("%s in another project = %s", call.name, text)
+		}
+	}
+	if text, isErr := callText(t, c, "list_decisions", map[string]any{"project_id": mine.ID}); isErr {
+		t.Errorf("list_decisions in own project = %s", text)
+	}
+	if text, isErr := callText(t, c, "list_leases", map[string]any{"project_id": mine.ID}); isErr {
+		t.Errorf("list_leases in own project = %s", text)
+	}
+}
@@ -0,0 +1,149 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"operators-mcp/tests/testhelper"
+)
+
+func callText(t *testing.T, c *client.Client, name string, args map[string]any) (string, bool) {
+	t.Helper()
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = args
+	res, err := c.CallTool(context.Background(), req)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return testhelper.ToolResultText(res.Content[0]), res.IsError
+}
+
+func postWhoAmI(t *testing.T, baseURL, sessionID, token string) (int, string) {
+	t.Helper()
+	body := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"whoami","arguments":{}}}`
+	req, _ := http.NewRequest(http.MethodPost, baseURL, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set(server.HeaderKeySessionID, sessionID)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+// TestAgentIdentity_BearerTokenSessions verifies a client identifies as an agent with a bearer
+// token, that the session stays bound to it, and that tools act for and are limited to that agent.
+func TestAgentIdentity_BearerTokenSessions(t *testing.T) {
+	root := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(root, "api"), 0755)
+	_ = os.MkdirAll(filepath.Join(root, "db"), 0755)
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	_, _ = svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID})
+	_, _ = svc.CreateZone(p.ID, "db", "^db/", "", nil, []string{bob.ID})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	anon := testhelper.NewTestClient(t, baseURL)
+	defer anon.Close()
+	if text, _ := callText(t, anon, "whoami", nil); text != `{"agent":null}` {
+		t.Errorf("anonymous whoami = %s", text)
+	}
+	text, isErr := callText(t, anon, "issue_agent_token", map[string]any{"agent_id": ada.ID})
+	if isErr {
+		t.Fatalf("issue_agent_token: %s", text)
+	}
+	var issued struct{ Token string }
+	_ = json.Unmarshal([]byte(text), &issued)
+
+	c := testhelper.NewTestClient(t, baseURL, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + issued.Token}))
+	defer c.Close()
+	if text, _ := callText(t, c, "whoami", nil); !strings.Contains(text, `"name":"Ada"`) {
+		t.Errorf("whoami = %s", text)
+	}
+	text, isErr = callText(t, c, "write_file", map[string]any{"project_id": p.ID, "path": "api/a.go", "content": "package api\n"})
+	if isErr || !strings.Contains(text, `"agent_id":"`+ada.ID+`"`) {
+		t.Errorf("write_file as session agent = %s", text)
+	}
+	if text, isErr = callText(t, c, "write_file", map[string]any{"project_id": p.ID, "path": "db/a.go", "content": ""}); !isErr || !strings.Contains(text, "OUT_OF_ZONE") {
+		t.Errorf("write_file out of zone = %s", text)
+	}
+	if text, isErr = callText(t, c, "write_file", map[string]any{"project_id": p.ID, "agent_id": bob.ID, "path": "db/a.go", "content": ""}); !isErr || !strings.Contains(text, "cannot act for agent") {
+		t.Errorf("write_file as another agent = %s", text)
+	}
+	if text, isErr = callText(t, c, "issue_agent_token", map[string]any{"agent_id": bob.ID}); !isErr || !strings.Contains(text, "cannot act for agent") {
+		t.Errorf("issue_agent_token for another agent = %s", text)
+	}
+
+	// The session stays bound without the header; a token for another agent is refused.
+	if status, body := postWhoAmI(t, baseURL, c.GetSessionId(), ""); status != http.StatusOK || !strings.Contains(body, `\"name\":\"Ada\"`) {
+		t.Errorf("bound session without token: %d %s", status, body)
+	}
+	bobToken, _ := svc.IssueAgentToken(bob.ID)
+	if status, _ := postWhoAmI(t, baseURL, c.GetSessionId(), bobToken); status != http.StatusUnauthorized {
+		t.Errorf("bound session with another agent's token: status %d", status)
+	}
+	if status, _ := postWhoAmI(t, baseURL, anon.GetSessionId(), "nope"); status != http.StatusUnauthorized {
+		t.Errorf("unknown token: status %d", status)
+	}
+
+	// Issuing a new token revokes the old one.
+	if _, err := svc.IssueAgentToken(ada.ID); err != nil {
+		t.Fatalf("IssueAgentToken: %v", err)
+	}
+	if _, err := svc.AgentForToken(issued.Token); err == nil {
+		t.Error("old token still valid after reissue")
+	}
+}
+
+// TestAgentIdentity_RequireIdentity verifies that with RequireIdentity anonymous sessions may
+// act for an agent_id only until the first token is issued, while identified sessions still work.
+func TestAgentIdentity_RequireIdentity(t *testing.T) {
+	root := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(root, "api"), 0755)
+	svc := testhelper.NewMemoryService()
+	svc.RequireIdentity = true
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	_, _ = svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	anon := testhelper.NewTestClient(t, baseURL)
+	defer anon.Close()
+	args := map[string]any{"project_id": p.ID, "agent_id": ada.ID, "path": "api/a.go", "content": "package api\n"}
+	if text, isErr := callText(t, anon, "write_file", args); isErr {
+		t.Errorf("anonymous write_file before any token = %s", text)
+	}
+	token, err := svc.IssueAgentToken(ada.ID)
+	if err != nil {
+		t.Fatalf("IssueAgentToken: %v", err)
+	}
+	if text, isErr := callText(t, anon, "write_file", args); !isErr || !strings.Contains(text, "identify the session") {
+		t.Errorf("anonymous write_file after a token was issued = %s", text)
+	}
+	c := testhelper.NewTestClient(t, baseURL, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+	defer c.Close()
+	if text, isErr := callText(t, c, "write_file", args); isErr {
+		t.Errorf("identified write_file = %s", text)
+	}
+}
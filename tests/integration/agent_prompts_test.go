@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestAgentPrompts_ListAndRenderWithZone verifies agents are listed as prompts, that
+// prompts/get injects the zone context and that new agents show up without a restart.
+func TestAgentPrompts_ListAndRenderWithZone(t *testing.T) {
+	root := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(root, "api"), 0755)
+	_ = os.WriteFile(filepath.Join(root, "api", "handler.go"), []byte("package api\n"), 0644)
+	_ = os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644)
+
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	a, _ := svc.CreateAgent("API Owner", "Maintains the HTTP API", "You maintain the HTTP API.", nil)
+	z, err := svc.CreateZone(p.ID, "api", "^api/", "HTTP handlers", []string{"no database access"}, []string{a.ID})
+	if err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+	ctx := context.Background()
+
+	list, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		t.Fatalf("ListPrompts: %v", err)
+	}
+	if len(list.Prompts) != 1 || list.Prompts[0].Name != "api-owner" {
+		t.Fatalf("ListPrompts: got %+v", list.Prompts)
+	}
+
+	req := mcp.GetPromptRequest{}
+	req.Params.Name = "api-owner"
+	req.Params.Arguments = map[string]string{"zone_id": z.ID, "task": "Add a health endpoint."}
+	res, err := c.GetPrompt(ctx, req)
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if len(res.Messages) != 1 {
+		t.Fatalf("GetPrompt: got %d messages", len(res.Messages))
+	}
+	text := testhelper.ToolResultText(res.Messages[0].Content)
+	for _, want := range []string{"You maintain the HTTP API.", "## Zone: api", "HTTP handlers", "- no database access", "- api/handler.go", "Add a health endpoint."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "main.go") {
+		t.Errorf("prompt lists a path outside the zone:\n%s", text)
+	}
+
+	req.Params.Arguments = map[string]string{"zone_id": "missing"}
+	if _, err := c.GetPrompt(ctx, req); err == nil {
+		t.Error("GetPrompt with unknown zone: expected error")
+	}
+
+	if _, err := svc.CreateAgent("Reviewer", "", "", nil); err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+	list, err = c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		t.Fatalf("ListPrompts: %v", err)
+	}
+	if len(list.Prompts) != 2 {
+		t.Errorf("expected new agent listed as prompt, got %+v", list.Prompts)
+	}
+}
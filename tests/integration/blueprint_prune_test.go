@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestImportBlueprint_PruneCascades verifies that pruning a zone on import removes it the way
+// delete_zone does: its tasks lose the reference, its lease and notes are dropped and subscribers
+// see the zone deleted.
+func TestImportBlueprint_PruneCascades(t *testing.T) {
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", t.TempDir())
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID})
+	db, _ := svc.CreateZone(p.ID, "db", "^db/", "", nil, nil)
+	ctx := context.Background()
+	task, err := svc.CreateTask(ctx, p.ID, "Add endpoint", "", []string{api.ID, db.ID}, "")
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if _, err := svc.ClaimZone(ctx, api.ID, ada.ID, 0); err != nil {
+		t.Fatalf("ClaimZone: %v", err)
+	}
+	if _, err := svc.AddZoneNote(ctx, api.ID, ada.ID, blueprint.NoteDraft{Text: "Handlers are thin."}); err != nil {
+		t.Fatalf("AddZoneNote: %v", err)
+	}
+	var deleted []string
+	svc.Subscribe(func(e blueprint.Event) {
+		if e.Kind == blueprint.EventZone && e.Deleted {
+			deleted = append(deleted, e.ID)
+		}
+	})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+
+	doc := `{"version": 1, "project": {"name": "app"}, "zones": [{"name": "db", "pattern": "^db/"}]}`
+	text, isErr := callText(t, c, "import_blueprint", map[string]any{"document": doc, "format": "json", "project_id": p.ID, "mode": "prune"})
+	if isErr || !strings.Contains(text, `"action":"delete","kind":"zone","name":"api"`) {
+		t.Fatalf("import_blueprint prune = %s", text)
+	}
+	if svc.GetZone(api.ID) != nil || len(deleted) != 1 || deleted[0] != api.ID {
+		t.Errorf("zone api still present or not announced: deleted events %v", deleted)
+	}
+	if got, _ := svc.GetTask(task.ID); len(got.ZoneIDs) != 1 || got.ZoneIDs[0] != db.ID {
+		t.Errorf("task zones after prune = %v", got.ZoneIDs)
+	}
+	if leases, _ := svc.ListLeases(p.ID); len(leases) != 0 {
+		t.Errorf("leases after prune = %+v", leases)
+	}
+	if notes := svc.Notes.List(p.ID); len(notes) != 0 {
+		t.Errorf("notes after prune = %+v", notes)
+	}
+}
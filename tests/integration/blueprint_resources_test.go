@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	adapter "operators-mcp/internal/adapter/in/mcp"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestBlueprintResources_ReadAndSubscribe verifies projects, zones and agents are readable through
+// resource templates and that subscribers are notified when the entity changes.
+func TestBlueprintResources_ReadAndSubscribe(t *testing.T) {
+	root := t.TempDir()
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	a, _ := svc.CreateAgent("Ada", "reviewer", "", nil)
+	z, err := svc.CreateZone(p.ID, "api", "^api/", "HTTP handlers", nil, []string{a.ID})
+	if err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL, transport.WithContinuousListening())
+	defer c.Close()
+	ctx := context.Background()
+
+	templates, err := c.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+	if err != nil {
+		t.Fatalf("ListResourceTemplates: %v", err)
+	}
+	if len(templates.ResourceTemplates) != 3 {
+		t.Errorf("expected 3 resource templates, got %d", len(templates.ResourceTemplates))
+	}
+
+	for uri, want := range map[string]string{
+		adapter.ProjectURI(p.ID): `"zones":[{"id":"` + z.ID,
+		adapter.ZoneURI(z.ID):    `"purpose":"HTTP handlers"`,
+		adapter.AgentURI(a.ID):   `"name":"Ada"`,
+	} {
+		req := mcp.ReadResourceRequest{}
+		req.Params.URI = uri
+		res, err := c.ReadResource(ctx, req)
+		if err != nil {
+			t.Fatalf("ReadResource %s: %v", uri, err)
+		}
+		if text := testhelper.ResourceResultText(res); !strings.Contains(text, want) {
+			t.Errorf("ReadResource %s: missing %q in %s", uri, want, text)
+		}
+	}
+
+	updated := make(chan string, 8)
+	c.OnNotification(func(n mcp.JSONRPCNotification) {
+		if n.Method == mcp.MethodNotificationResourceUpdated {
+			uri, _ := n.Params.AdditionalFields["uri"].(string)
+			updated <- uri
+		}
+	})
+	sub := mcp.SubscribeRequest{}
+	sub.Params.URI = adapter.ZoneURI(z.ID)
+	if err := c.Subscribe(ctx, sub); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	missing := mcp.SubscribeRequest{}
+	missing.Params.URI = adapter.ZoneURI("nope")
+	if err := c.Subscribe(ctx, missing); err == nil {
+		t.Error("Subscribe to a missing zone: expected error")
+	}
+
+	// An agent rename changes the zone's assigned agents.
+	if _, err := svc.UpdateAgent(a.ID, "Ada L.", "reviewer", "", nil); err != nil {
+		t.Fatalf("UpdateAgent: %v", err)
+	}
+	select {
+	case uri := <-updated:
+		if uri != adapter.ZoneURI(z.ID) {
+			t.Errorf("notification for %q, want %q", uri, adapter.ZoneURI(z.ID))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no resources/updated notification after agent rename")
+	}
+
+	unsub := mcp.UnsubscribeRequest{}
+	unsub.Params.URI = adapter.ZoneURI(z.ID)
+	if err := c.Unsubscribe(ctx, unsub); err != nil {
+		t.Fatalf("Unsubscribe: %v", err)
+	}
+	if _, err := svc.UpdateZone(z.ID, "api", "^api/", "Public API", nil, []string{a.ID}); err != nil {
+		t.Fatalf("UpdateZone: %v", err)
+	}
+	select {
+	case uri := <-updated:
+		t.Errorf("unexpected notification for %q after unsubscribe", uri)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestBlueprintResources_LargeRequestsPassThrough verifies that requests too large to be a
+// subscription reach the MCP server intact.
+func TestBlueprintResources_LargeRequestsPassThrough(t *testing.T) {
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", t.TempDir())
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+
+	purpose := strings.Repeat("a long purpose ", 10000)
+	if text, isErr := callText(t, c, "create_zone", map[string]any{"project_id": p.ID, "name": "big", "purpose": purpose}); isErr {
+		t.Fatalf("create_zone with a %d byte purpose: %s", len(purpose), text)
+	}
+	if zones := svc.ListZones(p.ID); len(zones) != 1 || zones[0].Purpose != purpose {
+		t.Errorf("large purpose not stored intact")
+	}
+}
@@ -26,7 +26,7 @@ func TestBlueprintTools_WithDesignerResource(t *testing.T) {
 	agentStore := memory.NewAgentStore()
 	pathMatcher := filesystem.NewMatcher()
 	treeLister := filesystem.NewLister()
-	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister, root)
+	svc := blueprint.NewService(projectStore, zoneStore, agentStore, pathMatcher, treeLister)
 	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
 	defer cleanup()
 	c := testhelper.NewTestClient(t, baseURL)
@@ -44,7 +44,17 @@ func TestBlueprintTools_WithDesignerResource(t *testing.T) {
 		"add_ignored_path": true, "remove_ignored_path": true,
 		"list_matching_paths": true, "list_tree": true, "list_zones": true,
 		"get_zone": true, "create_zone": true, "update_zone": true, "assign_path_to_zone": true,
-		"list_agents": true, "get_agent": true, "create_agent": true, "update_agent": true, "delete_agent": true,
+		"list_agents": true, "get_agent": true, "create_agent": true, "update_agent": true, "delete_agent": true, "render_agent_prompt": true,
+		"export_diagram": true, "read_file": true, "read_zone_files": true,
+		"write_file": true, "apply_patch": true, "list_changes": true,
+		"whoami": true, "issue_agent_token": true, "revoke_agent_token": true, "set_active_project": true,
+		"create_task": true, "list_tasks": true, "get_task": true, "claim_task": true, "update_task_status": true, "complete_task": true, "route_task": true,
+		"claim_zone": true, "renew_lease": true, "release_zone": true, "list_leases": true,
+		"create_run": true, "set_run_task": true, "get_run": true, "list_runs": true, "delete_run": true,
+		"create_decision": true, "set_decision_status": true, "get_decision": true, "list_decisions": true, "export_decisions": true,
+		"add_zone_note": true, "list_zone_notes": true, "search_zone_notes": true, "delete_zone_note": true,
+		"send_message": true, "list_inbox": true, "acknowledge_message": true, "get_thread": true,
+		"get_briefing": true, "build_context": true,
 	}
 	if len(listRes.Tools) < len(wantNames) {
 		t.Fatalf("ListTools: got %d tools, want at least %d", len(listRes.Tools), len(wantNames))
@@ -62,7 +72,7 @@ func TestBlueprintTools_WithDesignerResource(t *testing.T) {
 	// Call list_tree
 	callReq := mcp.CallToolRequest{}
 	callReq.Params.Name = "list_tree"
-	callReq.Params.Arguments = map[string]any{}
+	callReq.Params.Arguments = map[string]any{"root": root}
 	res, err := c.CallTool(ctx, callReq)
 	if err != nil {
 		t.Fatalf("list_tree: %v", err)
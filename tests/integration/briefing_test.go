@@ -0,0 +1,130 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	adapter "operators-mcp/internal/adapter/in/mcp"
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestGetBriefing verifies that a briefing gathers the agent's prompt, its zone's metadata,
+// decisions, notes and paths and the neighbouring zones with their owners, and fits a budget.
+func TestGetBriefing(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":                    "module example.com/app\n",
+		"cmd/server/main.go":        "package main\n\nimport _ \"example.com/app/internal/domain\"\n",
+		"cmd/server/flags.go":       "package main\n",
+		"internal/domain/zone.go":   "package domain\n",
+		"internal/adapter/store.go": "package adapter\n\nimport _ \"example.com/app/internal/domain\"\n",
+		"docs/guide.md":             "# Guide\n",
+	} {
+		p := filepath.Join(root, name)
+		_ = os.MkdirAll(filepath.Dir(p), 0755)
+		_ = os.WriteFile(p, []byte(content), 0644)
+	}
+	svc := testhelper.NewMemoryService()
+	svc.Dependencies = filesystem.NewImportAnalyzer()
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "You build {{.Vars.feature}}.", []domain.PromptVariable{{Name: "feature", Required: true}})
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	server, _ := svc.CreateZone(p.ID, "server", "^cmd/", "Entry point", []string{"No business logic"}, []string{ada.ID})
+	dom, _ := svc.CreateZone(p.ID, "domain", "^internal/domain", "Core model", nil, []string{bob.ID})
+	_, _ = svc.CreateZone(p.ID, "docs", "^docs/", "", nil, nil)
+	ctx := context.Background()
+	_, _ = svc.CreateDecision(ctx, p.ID, blueprint.DecisionDraft{Title: "Use flags", Decision: "Configure with flags.", Status: "accepted", ZoneIDs: []string{server.ID}})
+	_, _ = svc.AddZoneNote(ctx, server.ID, "", blueprint.NoteDraft{Text: "Run with -addr :0 in tests.", Tags: []string{"command"}, Pinned: true})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+
+	brief := func(args map[string]any) adapter.GetBriefingOut {
+		t.Helper()
+		text, isErr := callText(t, c, "get_briefing", args)
+		if isErr {
+			t.Fatalf("get_briefing: %s", text)
+		}
+		var out adapter.GetBriefingOut
+		_ = json.Unmarshal([]byte(text), &out)
+		return out
+	}
+	b := brief(map[string]any{"agent_id": ada.ID, "zone_id": server.ID, "variables": map[string]any{"feature": "the CLI"}})
+	if len(b.Zones) != 1 || b.Zones[0].Zone.ID != server.ID || b.Zones[0].PathCount != len(b.Zones[0].Paths) || b.Truncated {
+		t.Fatalf("get_briefing = %+v", b)
+	}
+	if zb := b.Zones[0]; len(zb.Decisions) != 1 || len(zb.Notes) != 1 || !strings.Contains(strings.Join(zb.Paths, ","), "cmd/server/main.go") {
+		t.Errorf("zone section = %+v", zb)
+	}
+	if len(b.Neighbours) != 2 || b.Neighbours[0].Zone.ID != dom.ID || len(b.Neighbours[0].Relations) != 1 || b.Neighbours[0].Relations[0] != blueprint.RelationDependency {
+		t.Errorf("neighbours = %+v", b.Neighbours)
+	}
+	for _, want := range []string{"You build the CLI.", "## Zone: server (project app)", "- No business logic", "ADR-0001 Use flags: Configure with flags.",
+		"- [pinned] Run with -addr :0 in tests. (command)", "- cmd/server/main.go", "- domain [dependency]: owners Bob; Core model", "- docs: owners none"} {
+		if !strings.Contains(b.Text, want) {
+			t.Errorf("briefing text lacks %q:\n%s", want, b.Text)
+		}
+	}
+	if strings.Contains(b.Prompt, "## Zone") || b.Chars != utf8.RuneCountInString(b.Text) || b.EstimatedTokens != (b.Chars+3)/4 {
+		t.Errorf("prompt %q, chars %d, tokens %d for %d characters", b.Prompt, b.Chars, b.EstimatedTokens, utf8.RuneCountInString(b.Text))
+	}
+
+	// Budgets drop paths first, then notes, neighbours and decisions.
+	limit := b.Chars - 20
+	small := brief(map[string]any{"agent_id": ada.ID, "zone_id": server.ID, "variables": map[string]any{"feature": "the CLI"}, "max_chars": limit})
+	if small.Chars > limit || !small.Truncated || small.Zones[0].PathCount != b.Zones[0].PathCount || len(small.Zones[0].Paths) >= len(b.Zones[0].Paths) || len(small.Zones[0].Notes) != 1 {
+		t.Errorf("briefing within %d chars = %+v", limit, small)
+	}
+	tiny := brief(map[string]any{"agent_id": ada.ID, "zone_id": server.ID, "variables": map[string]any{"feature": "the CLI"}, "max_tokens": 30})
+	if tiny.Chars > 120 || !tiny.Truncated || len(tiny.Zones[0].Paths) != 0 || len(tiny.Neighbours) != 0 || len(tiny.Zones[0].Decisions) != 0 {
+		t.Errorf("briefing within 30 tokens = %+v", tiny)
+	}
+	// Budgets count characters, and a cut prompt keeps whole characters.
+	wide := brief(map[string]any{"agent_id": ada.ID, "zone_id": server.ID, "variables": map[string]any{"feature": strings.Repeat("é", 300)}, "max_chars": 200})
+	if n := utf8.RuneCountInString(wide.Text); n > 200 || n < 150 || wide.Chars != n || !utf8.ValidString(wide.Text) || !wide.Truncated {
+		t.Errorf("briefing of multi-byte text within 200 chars: %d characters, valid %v: %q", n, utf8.ValidString(wide.Text), wide.Text)
+	}
+	if text, isErr := callText(t, c, "get_briefing", map[string]any{"agent_id": ada.ID, "max_chars": -1}); !isErr {
+		t.Errorf("get_briefing with a negative budget = %s", text)
+	}
+	if text, isErr := callText(t, c, "get_briefing", map[string]any{"agent_id": ada.ID}); !isErr || !strings.Contains(text, "missing required variable") {
+		t.Errorf("get_briefing without variables = %s", text)
+	}
+
+	// A session identified by its token is briefed on its own zones only.
+	gc, _, err := connectAgent(t, baseURL, agentBearer(t, svc, bob.ID))
+	if err != nil {
+		t.Fatalf("connect with token: %v", err)
+	}
+	defer gc.Close()
+	if text, isErr := callText(t, gc, "get_briefing", map[string]any{"zone_id": server.ID}); !isErr || !strings.Contains(text, "not assigned to agent "+bob.ID) {
+		t.Errorf("identified get_briefing outside its zones = %s", text)
+	}
+
+	// The per-agent endpoint briefs the agent on its own zones only.
+	ac, _, err := connectAgent(t, baseURL+"/agents/"+bob.ID+"/mcp", agentBearer(t, svc, bob.ID))
+	if err != nil {
+		t.Fatalf("connect agent endpoint: %v", err)
+	}
+	defer ac.Close()
+	text, isErr := callText(t, ac, "get_briefing", map[string]any{})
+	var own adapter.GetBriefingOut
+	if _ = json.Unmarshal([]byte(text), &own); isErr || own.AgentID != bob.ID || len(own.Zones) != 1 || own.Zones[0].Zone.ID != dom.ID {
+		t.Errorf("agent get_briefing = %s", text)
+	}
+	if len(own.Neighbours) == 0 || own.Neighbours[0].Zone.ID != server.ID || own.Neighbours[0].Relations[0] != blueprint.RelationDependent {
+		t.Errorf("agent neighbours = %+v", own.Neighbours)
+	}
+	if text, isErr := callText(t, ac, "get_briefing", map[string]any{"zone_id": server.ID}); !isErr || !strings.Contains(text, "not assigned to agent") {
+		t.Errorf("agent get_briefing outside its zones = %s", text)
+	}
+}
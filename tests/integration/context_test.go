@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestBuildContext verifies that build_context bundles a zone's files in priority order within
+// the token budget, cuts the file that does not fit and reports what was left out.
+func TestBuildContext(t *testing.T) {
+	root := t.TempDir()
+	long := func(word string, lines int) string { return strings.Repeat(word+" = value of some length\n", lines) }
+	for name, content := range map[string]string{
+		"api/main.go":     "package main\n",
+		"api/handler.go":  "package api\n\nfunc Handle() {}\n",
+		"api/tiny.go":     "package api\n",
+		"api/big.go":      long("big", 400),
+		"api/logo.png":    "\x89PNG\x00\x00binary",
+		"api/sub/util.go": "package sub\n",
+		"web/index.ts":    "export {}\n",
+	} {
+		p := filepath.Join(root, name)
+		_ = os.MkdirAll(filepath.Dir(p), 0755)
+		_ = os.WriteFile(p, []byte(content), 0644)
+	}
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID})
+	if _, err := svc.WriteFile(p.ID, ada.ID, "api/changed.go", long("changed", 200)); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+
+	build := func(args map[string]any) blueprint.ContextBundle {
+		t.Helper()
+		text, isErr := callText(t, c, "build_context", args)
+		if isErr {
+			t.Fatalf("build_context: %s", text)
+		}
+		var out blueprint.ContextBundle
+		_ = json.Unmarshal([]byte(text), &out)
+		return out
+	}
+	all := build(map[string]any{"zone_id": api.ID})
+	var order []string
+	for _, f := range all.Files {
+		order = append(order, f.Path+":"+f.Reason)
+	}
+	want := "api/main.go:entry_point api/sub/util.go:small api/tiny.go:small api/handler.go:small api/changed.go:recent api/big.go:other"
+	if strings.Join(order, " ") != want {
+		t.Errorf("file order = %v, want %s", order, want)
+	}
+	if len(all.Omitted) != 1 || all.Omitted[0].Path != "api/logo.png" || all.Omitted[0].Reason != "binary" {
+		t.Errorf("omitted = %+v", all.Omitted)
+	}
+	if all.Budget != blueprint.DefaultContextTokens || all.Tokens != (len(all.Text)+3)/4 || !strings.Contains(all.Text, "==> api/handler.go <==\npackage api\n\nfunc Handle() {}\n") {
+		t.Errorf("bundle of %d tokens (budget %d):\n%s", all.Tokens, all.Budget, all.Text)
+	}
+
+	// A budget that ends inside the recently changed file cuts it and leaves out the rest.
+	tight := build(map[string]any{"zone_id": api.ID, "max_tokens": 1000})
+	if tight.Tokens > 1000 || len(tight.Files) != 5 {
+		t.Fatalf("bundle within 1000 tokens: %d tokens, files %+v", tight.Tokens, tight.Files)
+	}
+	if cut := tight.Files[4]; cut.Path != "api/changed.go" || !cut.Truncated || cut.EndLine == 0 || cut.EndLine >= cut.TotalLines {
+		t.Errorf("cut file = %+v", cut)
+	}
+	if !strings.Contains(tight.Text, "==> api/changed.go (lines 1-") {
+		t.Errorf("cut file header missing:\n%s", tight.Text)
+	}
+	if o := tight.Omitted[len(tight.Omitted)-1]; o.Path != "api/big.go" || o.Reason != "budget" || o.Tokens == 0 {
+		t.Errorf("omitted = %+v", tight.Omitted)
+	}
+
+	byPaths := build(map[string]any{"project_id": p.ID, "paths": []any{"api/sub", "web/index.ts", "missing/"}})
+	if len(byPaths.Files) != 2 || byPaths.Files[0].Path != "web/index.ts" || byPaths.Files[1].Path != "api/sub/util.go" {
+		t.Errorf("bundle of paths = %+v", byPaths.Files)
+	}
+	if len(byPaths.Omitted) != 1 || byPaths.Omitted[0].Reason != "not_found" {
+		t.Errorf("missing path = %+v", byPaths.Omitted)
+	}
+
+	if text, isErr := callText(t, c, "build_context", map[string]any{"project_id": p.ID, "paths": []any{"web"}, "agent_id": ada.ID}); !isErr || !strings.Contains(text, "web/index.ts is not in any zone") {
+		t.Errorf("build_context outside the agent's zones = %s", text)
+	}
+	if text, isErr := callText(t, c, "build_context", map[string]any{"project_id": p.ID}); !isErr || !strings.Contains(text, "zone_id or paths is required") {
+		t.Errorf("build_context without files = %s", text)
+	}
+	if text, isErr := callText(t, c, "build_context", map[string]any{"zone_id": api.ID, "max_tokens": blueprint.MaxContextTokens + 1}); !isErr {
+		t.Errorf("build_context over the maximum budget = %s", text)
+	}
+}
@@ -0,0 +1,149 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	adapter "operators-mcp/internal/adapter/in/mcp"
+	"operators-mcp/internal/domain"
+	"operators-mcp/tests/testhelper"
+)
+
+type decisionResult struct {
+	Decision adapter.DecisionDTO `json:"decision"`
+}
+
+// TestDecisions verifies that decision records are numbered, superseded, queried, included in
+// the zone briefing once accepted and exported as markdown into the project.
+func TestDecisions(t *testing.T) {
+	root := t.TempDir()
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	cy, _ := svc.CreateAgent("Cy", "", "", nil)
+	api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID})
+	db, _ := svc.CreateZone(p.ID, "db", "^db/", "", nil, nil)
+	docs, _ := svc.CreateZone(p.ID, "docs", "^docs/", "", nil, []string{ada.ID, bob.ID})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+
+	create := func(args map[string]any) adapter.DecisionDTO {
+		t.Helper()
+		args["project_id"] = p.ID
+		text, isErr := callText(t, c, "create_decision", args)
+		if isErr {
+			t.Fatalf("create_decision: %s", text)
+		}
+		var out decisionResult
+		_ = json.Unmarshal([]byte(text), &out)
+		return out.Decision
+	}
+	rest := create(map[string]any{"title": "Use REST", "context": "Clients are browsers.", "decision": "Expose JSON over HTTP.", "status": "accepted", "zone_ids": []any{api.ID}})
+	store := create(map[string]any{"title": "Use SQLite", "decision": "One file per deployment.", "zone_ids": []any{db.ID}})
+	grpc := create(map[string]any{"title": "Use gRPC", "decision": "Expose protobuf\nservices.", "zone_ids": []any{api.ID}, "supersedes": rest.ID})
+	if rest.Label != "ADR-0001" || store.Number != 2 || grpc.Label != "ADR-0003" || store.Status != "proposed" {
+		t.Errorf("numbering = %s %d %s, status %s", rest.Label, store.Number, grpc.Label, store.Status)
+	}
+	if text, isErr := callText(t, c, "create_decision", map[string]any{"project_id": p.ID, "title": "x", "status": "superseded"}); !isErr {
+		t.Errorf("create_decision superseded = %s", text)
+	}
+
+	text, _ := callText(t, c, "render_agent_prompt", map[string]any{"agent_id": ada.ID, "zone_id": api.ID})
+	if !strings.Contains(text, "ADR-0001 Use REST: Expose JSON over HTTP.") || strings.Contains(text, "gRPC") {
+		t.Errorf("briefing before acceptance = %s", text)
+	}
+
+	text, isErr := callText(t, c, "set_decision_status", map[string]any{"decision_id": grpc.ID, "status": "accepted"})
+	if isErr {
+		t.Fatalf("set_decision_status: %s", text)
+	}
+	if d, _ := svc.GetDecision(rest.ID); d.Status != domain.DecisionSuperseded {
+		t.Errorf("superseded decision status = %s", d.Status)
+	}
+	if text, isErr = callText(t, c, "set_decision_status", map[string]any{"decision_id": rest.ID, "status": "accepted"}); !isErr {
+		t.Errorf("accepting a superseded decision = %s", text)
+	}
+	text, _ = callText(t, c, "render_agent_prompt", map[string]any{"agent_id": ada.ID, "zone_id": api.ID})
+	if !strings.Contains(text, "ADR-0003 Use gRPC: Expose protobuf services.") || strings.Contains(text, "REST") {
+		t.Errorf("briefing after supersede = %s", text)
+	}
+
+	var listed struct {
+		Decisions []adapter.DecisionDTO `json:"decisions"`
+	}
+	text, _ = callText(t, c, "list_decisions", map[string]any{"project_id": p.ID, "zone_id": api.ID})
+	if _ = json.Unmarshal([]byte(text), &listed); len(listed.Decisions) != 2 {
+		t.Errorf("list_decisions by zone = %s", text)
+	}
+	text, _ = callText(t, c, "list_decisions", map[string]any{"project_id": p.ID, "query": "browsers"})
+	if _ = json.Unmarshal([]byte(text), &listed); len(listed.Decisions) != 1 || listed.Decisions[0].ID != rest.ID {
+		t.Errorf("list_decisions by query = %s", text)
+	}
+	text, _ = callText(t, c, "list_decisions", map[string]any{"project_id": p.ID, "status": "proposed"})
+	if _ = json.Unmarshal([]byte(text), &listed); len(listed.Decisions) != 1 || listed.Decisions[0].ID != store.ID {
+		t.Errorf("list_decisions by status = %s", text)
+	}
+
+	pipes := create(map[string]any{"title": "Split reads | writes", "status": "accepted"})
+	// A file left by an older title of ADR-0002 is replaced by the current one; a hand-written
+	// file with the same number prefix is kept.
+	_ = os.MkdirAll(filepath.Join(root, "docs", "adr"), 0755)
+	_ = os.WriteFile(filepath.Join(root, "docs", "adr", "0002-old-title.md"), []byte("# ADR-0002: Old title\n"), 0644)
+	_ = os.WriteFile(filepath.Join(root, "docs", "adr", "0002-notes.md"), []byte("# Notes on ADR-0002\n"), 0644)
+	if text, isErr = callText(t, c, "export_decisions", map[string]any{"project_id": p.ID}); !isErr || !strings.Contains(text, "on behalf of an agent") {
+		t.Errorf("export_decisions without an agent = %s", text)
+	}
+	if text, isErr = callText(t, c, "export_decisions", map[string]any{"project_id": p.ID, "agent_id": cy.ID}); !isErr || !strings.Contains(text, "OUT_OF_ZONE") {
+		t.Errorf("export_decisions by an agent outside the docs zone = %s", text)
+	}
+	text, isErr = callText(t, c, "export_decisions", map[string]any{"project_id": p.ID, "agent_id": ada.ID})
+	if isErr {
+		t.Fatalf("export_decisions: %s", text)
+	}
+	if _, err := os.Stat(filepath.Join(root, "docs", "adr", "0002-old-title.md")); !os.IsNotExist(err) {
+		t.Errorf("stale ADR file after export: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "docs", "adr", "0002-notes.md")); err != nil {
+		t.Errorf("hand-written file after export: %v", err)
+	}
+	if changes, _ := svc.ListChanges(p.ID, 0); len(changes) != 6 {
+		t.Errorf("recorded export changes = %d, want 6", len(changes))
+	}
+	if !strings.Contains(text, "docs/adr/0001-use-rest.md") || !strings.Contains(text, "docs/adr/README.md") {
+		t.Errorf("export_decisions = %s", text)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "docs", "adr", "0001-use-rest.md"))
+	if err != nil {
+		t.Fatalf("read exported ADR: %v", err)
+	}
+	for _, want := range []string{"# ADR-0001: Use REST", "- Status: superseded", "- Zones: api", "- Superseded by: [ADR-0003](0003-use-grpc.md)", "## Context\n\nClients are browsers."} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("exported ADR lacks %q:\n%s", want, b)
+		}
+	}
+	index, _ := os.ReadFile(filepath.Join(root, "docs", "adr", "README.md"))
+	if !strings.Contains(string(index), "| [ADR-0002](0002-use-sqlite.md) | Use SQLite | proposed | db |") ||
+		!strings.Contains(string(index), "| ["+pipes.Label+"](0004-split-reads-writes.md) | Split reads \\| writes | accepted |  |") {
+		t.Errorf("exported index:\n%s", index)
+	}
+	if _, err := svc.ClaimZone(context.Background(), docs.ID, bob.ID, 0); err != nil {
+		t.Fatalf("ClaimZone: %v", err)
+	}
+	if text, isErr = callText(t, c, "export_decisions", map[string]any{"project_id": p.ID, "agent_id": ada.ID}); !isErr || !strings.Contains(text, "leased") {
+		t.Errorf("export_decisions into a zone leased by another agent = %s", text)
+	}
+
+	if err := svc.DeleteZone(db.ID); err != nil {
+		t.Fatalf("DeleteZone: %v", err)
+	}
+	if d, _ := svc.GetDecision(store.ID); len(d.ZoneIDs) != 0 {
+		t.Errorf("decision zones after zone deletion = %v", d.ZoneIDs)
+	}
+}
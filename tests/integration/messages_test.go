@@ -0,0 +1,165 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	adapter "operators-mcp/internal/adapter/in/mcp"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/tests/testhelper"
+)
+
+type messagesResult struct {
+	Messages []adapter.MessageDTO `json:"messages"`
+}
+
+// inboxNotifications returns a channel receiving the messages delivered to a client as
+// notifications/message log entries.
+func inboxNotifications(c interface {
+	OnNotification(func(mcp.JSONRPCNotification))
+}) chan adapter.MessageDTO {
+	ch := make(chan adapter.MessageDTO, 8)
+	c.OnNotification(func(n mcp.JSONRPCNotification) {
+		if n.Method != "notifications/message" || n.Params.AdditionalFields["logger"] != adapter.InboxLogger {
+			return
+		}
+		b, _ := json.Marshal(n.Params.AdditionalFields["data"])
+		var m adapter.MessageDTO
+		_ = json.Unmarshal(b, &m)
+		ch <- m
+	})
+	return ch
+}
+
+func receiveMessage(t *testing.T, ch chan adapter.MessageDTO, what string) adapter.MessageDTO {
+	t.Helper()
+	select {
+	case m := <-ch:
+		return m
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no inbox notification for %s", what)
+	}
+	return adapter.MessageDTO{}
+}
+
+// TestMessages verifies that agents message each other and zones, answer in threads, read and
+// acknowledge their inbox, and get new messages as notifications on their connected sessions.
+func TestMessages(t *testing.T) {
+	root := t.TempDir()
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	cy, _ := svc.CreateAgent("Cy", "", "", nil)
+	api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID, bob.ID, cy.ID})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	adaToken, _ := svc.IssueAgentToken(ada.ID)
+	bobToken, _ := svc.IssueAgentToken(bob.ID)
+	adaClient := testhelper.NewTestClient(t, baseURL, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + adaToken}))
+	defer adaClient.Close()
+	bobClient := testhelper.NewTestClient(t, baseURL, transport.WithContinuousListening(),
+		transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + bobToken}))
+	defer bobClient.Close()
+	bobInbox := inboxNotifications(bobClient)
+	cyClient, _, err := connectAgent(t, baseURL+"/agents/"+cy.ID+"/mcp", agentBearer(t, svc, cy.ID), transport.WithContinuousListening())
+	if err != nil {
+		t.Fatalf("connect agent endpoint: %v", err)
+	}
+	defer cyClient.Close()
+	cyInbox := inboxNotifications(cyClient)
+
+	text, isErr := callText(t, adaClient, "send_message", map[string]any{"project_id": p.ID, "zone_id": api.ID, "subject": "API", "body": "Please add a Version field."})
+	if isErr {
+		t.Fatalf("send_message to a zone: %s", text)
+	}
+	var sent messagesResult
+	_ = json.Unmarshal([]byte(text), &sent)
+	if len(sent.Messages) != 2 || sent.Messages[0].ToAgentID != bob.ID || sent.Messages[1].ToAgentID != cy.ID {
+		t.Fatalf("send_message to a zone = %s", text)
+	}
+	first := sent.Messages[0]
+	if first.FromAgentID != ada.ID || first.ZoneID != api.ID || sent.Messages[1].ThreadID != first.ThreadID {
+		t.Errorf("zone message copies = %s", text)
+	}
+	if m := receiveMessage(t, bobInbox, "bob on the global endpoint"); m.ID != first.ID || m.Body != "Please add a Version field." {
+		t.Errorf("bob notification = %+v", m)
+	}
+	if m := receiveMessage(t, cyInbox, "cy on the agent endpoint"); m.ID != sent.Messages[1].ID {
+		t.Errorf("cy notification = %+v", m)
+	}
+
+	text, _ = callText(t, bobClient, "list_inbox", map[string]any{"unread_only": true})
+	var inbox messagesResult
+	_ = json.Unmarshal([]byte(text), &inbox)
+	if len(inbox.Messages) != 1 || inbox.Messages[0].ID != first.ID {
+		t.Errorf("list_inbox = %s", text)
+	}
+
+	text, isErr = callText(t, bobClient, "send_message", map[string]any{"reply_to": first.ID, "body": "Done."})
+	if isErr {
+		t.Fatalf("reply: %s", text)
+	}
+	var reply messagesResult
+	_ = json.Unmarshal([]byte(text), &reply)
+	if len(reply.Messages) != 1 || reply.Messages[0].ToAgentID != ada.ID || reply.Messages[0].ThreadID != first.ThreadID || reply.Messages[0].ProjectID != p.ID {
+		t.Errorf("reply = %s", text)
+	}
+	text, _ = callText(t, adaClient, "get_thread", map[string]any{"message_id": reply.Messages[0].ID})
+	var thread messagesResult
+	_ = json.Unmarshal([]byte(text), &thread)
+	if len(thread.Messages) != 3 || thread.Messages[2].ReplyTo != first.ID {
+		t.Errorf("get_thread = %s", text)
+	}
+	dee, _ := svc.CreateAgent("Dee", "", "", nil)
+	if _, err := svc.MessageThread(context.Background(), first.ID, dee.ID); err == nil || !strings.Contains(err.Error(), "has not sent or received") {
+		t.Errorf("MessageThread by an agent outside the thread: %v", err)
+	}
+	if _, err := svc.SendMessage(context.Background(), dee.ID, blueprint.MessageDraft{ReplyTo: first.ID, ToAgentID: ada.ID, Body: "me too"}); err == nil || !strings.Contains(err.Error(), "NOT_PARTICIPANT") {
+		t.Errorf("reply by an agent outside the thread: %v", err)
+	}
+	if _, err := svc.SendMessage(context.Background(), bob.ID, blueprint.MessageDraft{ReplyTo: first.ID, ToAgentID: bob.ID, Body: "note to self"}); err == nil || !strings.Contains(err.Error(), "INVALID_RECIPIENT") {
+		t.Errorf("reply addressed to the sender: %v", err)
+	}
+	if _, err := svc.MessageThread(context.Background(), first.ID, ""); err == nil {
+		t.Error("MessageThread without an agent: expected error")
+	}
+
+	if text, isErr = callText(t, bobClient, "acknowledge_message", map[string]any{"message_id": sent.Messages[1].ID}); !isErr || !strings.Contains(text, "addressed to agent "+cy.ID) {
+		t.Errorf("acknowledge_message of another agent's message = %s", text)
+	}
+	if text, isErr = callText(t, bobClient, "acknowledge_message", map[string]any{"message_id": first.ID}); isErr || !strings.Contains(text, `"acked_at"`) {
+		t.Errorf("acknowledge_message = %s", text)
+	}
+	text, _ = callText(t, bobClient, "list_inbox", map[string]any{"unread_only": true})
+	if _ = json.Unmarshal([]byte(text), &inbox); len(inbox.Messages) != 0 {
+		t.Errorf("list_inbox unread_only after ack = %s", text)
+	}
+	text, _ = callText(t, bobClient, "list_inbox", map[string]any{})
+	if _ = json.Unmarshal([]byte(text), &inbox); len(inbox.Messages) != 1 || inbox.Messages[0].AckedAt == nil {
+		t.Errorf("list_inbox after ack = %s", text)
+	}
+
+	if text, isErr = callText(t, adaClient, "send_message", map[string]any{"project_id": p.ID, "body": "hello"}); !isErr || !strings.Contains(text, "to_agent_id or zone_id is required") {
+		t.Errorf("send_message without recipient = %s", text)
+	}
+	if text, isErr = callText(t, adaClient, "send_message", map[string]any{"project_id": p.ID, "to_agent_id": bob.ID}); !isErr {
+		t.Errorf("send_message without body = %s", text)
+	}
+	if text, isErr = callText(t, adaClient, "list_inbox", map[string]any{"agent_id": bob.ID}); !isErr || !strings.Contains(text, "cannot act for agent "+bob.ID) {
+		t.Errorf("list_inbox of another agent = %s", text)
+	}
+
+	if err := svc.DeleteProject(p.ID); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+	if _, err := svc.GetMessage(first.ID); err == nil {
+		t.Error("message kept after its project was deleted")
+	}
+}
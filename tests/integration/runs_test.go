@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/domain"
+	"operators-mcp/tests/testhelper"
+)
+
+type runJSON struct {
+	ID       string `json:"id"`
+	Progress struct {
+		Status   string         `json:"status"`
+		Total    int            `json:"total"`
+		Done     int            `json:"done"`
+		Percent  int            `json:"percent"`
+		ByStatus map[string]int `json:"by_status"`
+		Ready    []string       `json:"ready"`
+		Waiting  []struct {
+			TaskID    string   `json:"task_id"`
+			WaitingOn []string `json:"waiting_on"`
+		} `json:"waiting"`
+		Zones []struct {
+			ZoneID string `json:"zone_id"`
+			Total  int    `json:"total"`
+			Done   int    `json:"done"`
+			Ready  int    `json:"ready"`
+		} `json:"zones"`
+	} `json:"progress"`
+}
+
+type runResult struct {
+	Run runJSON `json:"run"`
+}
+
+// TestRuns drives a two-zone change through a run: the domain task must be done before the
+// adapter task can be claimed, cycles are refused, and progress follows the tasks.
+func TestRuns(t *testing.T) {
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", t.TempDir())
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	dom, _ := svc.CreateZone(p.ID, "domain", "^domain/", "", nil, []string{ada.ID})
+	adp, _ := svc.CreateZone(p.ID, "adapters", "^adapters/", "", nil, []string{bob.ID})
+	ctx := context.Background()
+	model, _ := svc.CreateTask(ctx, p.ID, "Add the model", "", []string{dom.ID}, "")
+	repo, _ := svc.CreateTask(ctx, p.ID, "Persist the model", "", []string{adp.ID}, "")
+	api, _ := svc.CreateTask(ctx, p.ID, "Expose the model", "", []string{adp.ID}, "")
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+
+	text, isErr := callText(t, c, "create_run", map[string]any{"project_id": p.ID, "title": "Add model", "tasks": []any{
+		map[string]any{"task_id": model.ID},
+		map[string]any{"task_id": repo.ID, "depends_on": []any{model.ID}},
+		map[string]any{"task_id": api.ID, "depends_on": []any{repo.ID}},
+	}})
+	if isErr {
+		t.Fatalf("create_run: %s", text)
+	}
+	var res runResult
+	_ = json.Unmarshal([]byte(text), &res)
+	runID := res.Run.ID
+	pr := res.Run.Progress
+	if pr.Status != "pending" || pr.Total != 3 || len(pr.Ready) != 1 || pr.Ready[0] != model.ID || len(pr.Waiting) != 2 {
+		t.Errorf("initial progress = %s", text)
+	}
+
+	if text, isErr = callText(t, c, "set_run_task", map[string]any{"run_id": runID, "task_id": model.ID, "depends_on": []any{api.ID}}); !isErr || !strings.Contains(text, "dependency cycle: ") {
+		t.Errorf("set_run_task closing a cycle = %s", text)
+	}
+	if text, isErr = callText(t, c, "create_run", map[string]any{"project_id": p.ID, "title": "Again", "tasks": []any{map[string]any{"task_id": repo.ID}}}); !isErr || !strings.Contains(text, "already belongs to run") {
+		t.Errorf("create_run with a task of another run = %s", text)
+	}
+	if text, isErr = callText(t, c, "claim_task", map[string]any{"task_id": repo.ID, "agent_id": bob.ID}); !isErr || !strings.Contains(text, "unfinished prerequisites: "+model.ID+" (open)") {
+		t.Errorf("claim_task before prerequisites = %s", text)
+	}
+
+	if _, err := svc.ClaimTask(ctx, model.ID, ada.ID); err != nil {
+		t.Fatalf("ClaimTask: %v", err)
+	}
+	text, _ = callText(t, c, "get_run", map[string]any{"run_id": runID})
+	_ = json.Unmarshal([]byte(text), &res)
+	if pr = res.Run.Progress; pr.Status != "active" || len(pr.Ready) != 0 || pr.ByStatus["claimed"] != 1 {
+		t.Errorf("progress while working = %s", text)
+	}
+	if _, err := svc.CompleteTask(ctx, model.ID, "added"); err != nil {
+		t.Fatalf("CompleteTask: %v", err)
+	}
+	if text, isErr = callText(t, c, "claim_task", map[string]any{"task_id": repo.ID, "agent_id": bob.ID}); isErr {
+		t.Errorf("claim_task once ready = %s", text)
+	}
+	text, _ = callText(t, c, "list_runs", map[string]any{"project_id": p.ID})
+	var list struct {
+		Runs []runJSON `json:"runs"`
+	}
+	_ = json.Unmarshal([]byte(text), &list)
+	if len(list.Runs) != 1 {
+		t.Fatalf("list_runs = %s", text)
+	}
+	pr = list.Runs[0].Progress
+	if pr.Done != 1 || pr.Percent != 33 || len(pr.Zones) != 2 || pr.Zones[0].ZoneID != dom.ID || pr.Zones[0].Done != 1 || pr.Zones[1].Total != 2 {
+		t.Errorf("progress after the domain task = %s", text)
+	}
+
+	// Cancelling a prerequisite stalls the run; deleting the run frees its tasks.
+	_, _ = svc.UpdateTaskStatus(ctx, repo.ID, domain.TaskCancelled)
+	run, _ := svc.GetRun(runID)
+	if got := svc.RunProgress(run).Status; got != domain.RunStalled {
+		t.Errorf("status with a cancelled prerequisite = %s", got)
+	}
+	if text, isErr = callText(t, c, "delete_run", map[string]any{"run_id": runID}); isErr {
+		t.Fatalf("delete_run: %s", text)
+	}
+	if _, err := svc.ClaimTask(ctx, api.ID, bob.ID); err != nil {
+		t.Errorf("ClaimTask after delete_run: %v", err)
+	}
+}
+
+// TestRuns_ConcurrentEdits verifies that concurrent run edits cannot put a task in two runs or
+// close a dependency cycle.
+func TestRuns_ConcurrentEdits(t *testing.T) {
+	svc := testhelper.NewMemoryService()
+	svc.Tasks = slowTasks{memory.NewTaskStore()}
+	ctx := context.Background()
+	p, _ := svc.CreateProject("app", t.TempDir())
+	a, _ := svc.CreateTask(ctx, p.ID, "a", "", nil, "")
+	b, _ := svc.CreateTask(ctx, p.ID, "b", "", nil, "")
+	c, _ := svc.CreateTask(ctx, p.ID, "c", "", nil, "")
+	r1, _ := svc.CreateRun(ctx, p.ID, "one", "", []domain.RunTask{{TaskID: a.ID}, {TaskID: b.ID}})
+	r2, _ := svc.CreateRun(ctx, p.ID, "two", "", nil)
+
+	concurrently := func(calls ...func() error) []error {
+		errs := make([]error, len(calls))
+		var wg sync.WaitGroup
+		for i, call := range calls {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = call()
+			}()
+		}
+		wg.Wait()
+		return errs
+	}
+	failed := func(errs []error, code string) int {
+		n := 0
+		for _, err := range errs {
+			if err != nil {
+				n++
+				if !isCode(err, code) {
+					t.Errorf("err = %v, want %s", err, code)
+				}
+			}
+		}
+		return n
+	}
+
+	errs := concurrently(
+		func() error { _, err := svc.SetRunTask(r1.ID, c.ID, nil); return err },
+		func() error { _, err := svc.SetRunTask(r2.ID, c.ID, nil); return err },
+	)
+	if n := failed(errs, "TASK_IN_RUN"); n != 1 {
+		t.Errorf("adding a task to two runs at once: %d failed, want 1", n)
+	}
+	errs = concurrently(
+		func() error { _, err := svc.SetRunTask(r1.ID, a.ID, []string{b.ID}); return err },
+		func() error { _, err := svc.SetRunTask(r1.ID, b.ID, []string{a.ID}); return err },
+	)
+	if n := failed(errs, "DEPENDENCY_CYCLE"); n != 1 {
+		t.Errorf("closing a cycle from both ends at once: %d failed, want 1", n)
+	}
+}
+
+func isCode(err error, code string) bool {
+	var se *domain.StructuredError
+	return errors.As(err, &se) && se.Code == code
+}
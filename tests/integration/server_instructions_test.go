@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestServerInstructions_FromBlueprint verifies initialize returns instructions describing the
+// blueprint, narrowed to the session's project and agent when they are known.
+func TestServerInstructions_FromBlueprint(t *testing.T) {
+	svc := testhelper.NewMemoryService()
+	shop, _ := svc.CreateProject("shop", t.TempDir())
+	blog, _ := svc.CreateProject("blog", t.TempDir())
+	ada, _ := svc.CreateAgent("Ada", "Backend developer.", "", nil)
+	_, _ = svc.CreateZone(shop.ID, "api", "^api/", "HTTP handlers", []string{"no SQL here"}, []string{ada.ID})
+	_, _ = svc.CreateZone(blog.ID, "posts", "^posts/", "Markdown posts", nil, nil)
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	c, res, err := connectAgent(t, baseURL)
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	defer c.Close()
+	for _, want := range []string{"shop (project_id " + shop.ID, "blog (project_id " + blog.ID, "api (zone_id", ": HTTP handlers", "constraint: no SQL here", "agents: Ada", "posts (zone_id", "set_active_project"} {
+		if !strings.Contains(res.Instructions, want) {
+			t.Errorf("global instructions missing %q:\n%s", want, res.Instructions)
+		}
+	}
+	text, _ := callText(t, c, "set_active_project", map[string]any{"project_id": blog.ID})
+	if !strings.Contains(text, "Active project: blog") || strings.Contains(text, "api (zone_id") {
+		t.Errorf("set_active_project instructions = %s", text)
+	}
+
+	token, _ := svc.IssueAgentToken(ada.ID)
+	pc, res, err := connectAgent(t, baseURL+"/projects/"+shop.ID+"/mcp", transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+	if err != nil {
+		t.Fatalf("initialize on the project endpoint: %v", err)
+	}
+	defer pc.Close()
+	for _, want := range []string{"You are agent Ada", "Backend developer.", "Active project: shop", "api (zone_id", "[yours]"} {
+		if !strings.Contains(res.Instructions, want) {
+			t.Errorf("project instructions missing %q:\n%s", want, res.Instructions)
+		}
+	}
+	if strings.Contains(res.Instructions, "posts") {
+		t.Errorf("project instructions list another project's zones:\n%s", res.Instructions)
+	}
+
+	for i := 0; i < 200; i++ {
+		_, _ = svc.CreateZone(blog.ID, fmt.Sprintf("zone-%03d", i), "", strings.Repeat("A long purpose. ", 8), nil, nil)
+	}
+	text = svc.ServerInstructions("", "")
+	if len(text) > blueprint.MaxInstructionsBytes || !strings.Contains(text, "truncated") || !strings.HasSuffix(text, "your prompt for a zone and task.\n") {
+		t.Errorf("capped instructions: %d bytes, ends %q", len(text), text[len(text)-80:])
+	}
+}
@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/adapter/out/persistence/sqlite"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestTasks_HandOffToZoneAgents verifies an orchestrator hands a task to a zone's agent, which
+// claims, works on and completes it through the MCP tools.
+func TestTasks_HandOffToZoneAgents(t *testing.T) {
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", t.TempDir())
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	orch := testhelper.NewTestClient(t, baseURL)
+	defer orch.Close()
+	text, isErr := callText(t, orch, "create_task", map[string]any{"project_id": p.ID, "title": "Add /health", "zone_ids": []any{api.ID}})
+	if isErr {
+		t.Fatalf("create_task: %s", text)
+	}
+	var out struct{ Task struct{ ID, Status string } }
+	_ = json.Unmarshal([]byte(text), &out)
+	taskID := out.Task.ID
+	if out.Task.Status != domain.TaskOpen {
+		t.Errorf("new task status = %q", out.Task.Status)
+	}
+	if text, isErr := callText(t, orch, "create_task", map[string]any{"project_id": p.ID, "title": " "}); !isErr || !strings.Contains(text, "title is required") {
+		t.Errorf("create_task without title = %s", text)
+	}
+
+	if text, isErr := callText(t, orch, "claim_task", map[string]any{"task_id": taskID, "agent_id": bob.ID}); !isErr || !strings.Contains(text, "none of the task's zones") {
+		t.Errorf("claim by an agent outside the zones = %s", text)
+	}
+	token, _ := svc.IssueAgentToken(ada.ID)
+	c := testhelper.NewTestClient(t, baseURL, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}))
+	defer c.Close()
+	if text, _ := callText(t, c, "claim_task", map[string]any{"task_id": taskID}); !strings.Contains(text, `"status":"claimed"`) || !strings.Contains(text, `"agent_id":"`+ada.ID+`"`) {
+		t.Errorf("claim_task = %s", text)
+	}
+	if text, isErr := callText(t, orch, "update_task_status", map[string]any{"task_id": taskID, "status": "done"}); !isErr || !strings.Contains(text, "complete_task") {
+		t.Errorf("update_task_status done = %s", text)
+	}
+	if text, _ := callText(t, c, "update_task_status", map[string]any{"task_id": taskID, "status": "in_progress"}); !strings.Contains(text, `"status":"in_progress"`) {
+		t.Errorf("update_task_status = %s", text)
+	}
+	if text, _ := callText(t, orch, "list_tasks", map[string]any{"project_id": p.ID, "agent_id": ada.ID, "status": "in_progress"}); !strings.Contains(text, taskID) {
+		t.Errorf("list_tasks by agent and status = %s", text)
+	}
+	if text, _ := callText(t, orch, "list_tasks", map[string]any{"project_id": p.ID, "status": "blocked"}); text != `{"tasks":[]}` {
+		t.Errorf("list_tasks blocked = %s", text)
+	}
+	if text, _ := callText(t, c, "complete_task", map[string]any{"task_id": taskID, "result": "added handler"}); !strings.Contains(text, `"status":"done"`) || !strings.Contains(text, `"result":"added handler"`) {
+		t.Errorf("complete_task = %s", text)
+	}
+	if text, isErr := callText(t, c, "update_task_status", map[string]any{"task_id": taskID, "status": "open"}); !isErr || !strings.Contains(text, "task is done") {
+		t.Errorf("update of a done task = %s", text)
+	}
+
+	// A task handed to Ada cannot be taken by Bob, and is released when Ada is deleted.
+	handed, _ := svc.CreateTask(blueprint.WithCaller(t.Context(), bob.ID), p.ID, "Review", "", nil, ada.ID)
+	if handed.CreatedBy != bob.ID {
+		t.Errorf("CreatedBy = %q", handed.CreatedBy)
+	}
+	if _, err := svc.ClaimTask(blueprint.WithCaller(t.Context(), bob.ID), handed.ID, ""); err == nil {
+		t.Error("claim of a task assigned to another agent: expected error")
+	}
+	if _, err := svc.ClaimTask(t.Context(), handed.ID, ada.ID); err != nil {
+		t.Fatalf("ClaimTask: %v", err)
+	}
+	if err := svc.DeleteAgent(ada.ID); err != nil {
+		t.Fatalf("DeleteAgent: %v", err)
+	}
+	if got, _ := svc.GetTask(handed.ID); got.AgentID != "" || got.Status != domain.TaskOpen {
+		t.Errorf("task of a deleted agent: agent %q status %q", got.AgentID, got.Status)
+	}
+	if err := svc.DeleteZone(api.ID); err != nil {
+		t.Fatalf("DeleteZone: %v", err)
+	}
+	if got, _ := svc.GetTask(taskID); len(got.ZoneIDs) != 0 {
+		t.Errorf("task zones after DeleteZone = %v", got.ZoneIDs)
+	}
+}
+
+// slowTasks delays returning reads so that concurrent read-modify-write sequences overlap.
+type slowTasks struct{ ports.TaskRepository }
+
+func (r slowTasks) Get(id string) *domain.Task {
+	t := r.TaskRepository.Get(id)
+	time.Sleep(5 * time.Millisecond)
+	return t
+}
+
+// TestTasks_ConcurrentClaims verifies that of many agents claiming one task at once, exactly one
+// gets it and the others are told it is assigned.
+func TestTasks_ConcurrentClaims(t *testing.T) {
+	db, _ := testhelper.OpenBackends(t)
+	for name, repo := range map[string]ports.TaskRepository{
+		"memory": memory.NewTaskStore(),
+		"sqlite": sqlite.NewTaskRepository(db),
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc := testhelper.NewMemoryService()
+			svc.Tasks = slowTasks{repo}
+			p, _ := svc.CreateProject("app", t.TempDir())
+			var agentIDs []string
+			for i := range 8 {
+				a, _ := svc.CreateAgent(fmt.Sprintf("agent-%d", i), "", "", nil)
+				agentIDs = append(agentIDs, a.ID)
+			}
+			api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, agentIDs)
+			task, err := svc.CreateTask(context.Background(), p.ID, "Add /health", "", []string{api.ID}, "")
+			if err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+
+			var wg sync.WaitGroup
+			errs := make([]error, len(agentIDs))
+			for i, id := range agentIDs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = svc.ClaimTask(context.Background(), task.ID, id)
+				}()
+			}
+			wg.Wait()
+			claimed := 0
+			for _, err := range errs {
+				var se *domain.StructuredError
+				switch {
+				case err == nil:
+					claimed++
+				case !errors.As(err, &se) || se.Code != "TASK_ASSIGNED":
+					t.Errorf("ClaimTask: %v", err)
+				}
+			}
+			if claimed != 1 {
+				t.Errorf("%d claims succeeded, want 1", claimed)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"operators-mcp/tests/testhelper"
+)
+
+// TestTreeFormats verifies the text and collapsed formats of list_tree and list_matching_paths:
+// an indented tree annotated with zones, large directories summarized, and JSON kept as default.
+func TestTreeFormats(t *testing.T) {
+	root := t.TempDir()
+	files := []string{"api/main.go", "api/handler.go", "web/index.ts", "README.md"}
+	for i := 0; i < 5; i++ {
+		files = append(files, fmt.Sprintf("vendor/lib%d.go", i))
+	}
+	for _, name := range files {
+		p := filepath.Join(root, name)
+		_ = os.MkdirAll(filepath.Dir(p), 0755)
+		_ = os.WriteFile(p, []byte("x\n"), 0644)
+	}
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	_, _ = svc.CreateZone(p.ID, "api", "^api/", "", nil, nil)
+	_, _ = svc.CreateZone(p.ID, "third-party", "^vendor/lib[0-2]", "", nil, nil)
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+	c := testhelper.NewTestClient(t, baseURL)
+	defer c.Close()
+
+	text, isErr := callText(t, c, "list_tree", map[string]any{"project_id": p.ID, "format": "text"})
+	for _, want := range []string{"  api/ [api]\n    handler.go\n    main.go\n", "  README.md\n", "  vendor/\n    lib0.go [third-party]\n", "    lib4.go\n", "  web/\n    index.ts\n"} {
+		if isErr || !strings.Contains(text, want) {
+			t.Errorf("text tree lacks %q:\n%s", want, text)
+		}
+	}
+	text, _ = callText(t, c, "list_tree", map[string]any{"project_id": p.ID, "format": "collapsed", "max_entries": 3})
+	if !strings.Contains(text, "  vendor/ (5 files) [third-party]\n") || strings.Contains(text, "lib0.go") || !strings.Contains(text, "    main.go\n") {
+		t.Errorf("collapsed tree:\n%s", text)
+	}
+	if text, _ = callText(t, c, "list_tree", map[string]any{"project_id": p.ID, "format": "text", "depth": 1}); !strings.Contains(text, "  api/ (2 files) [api]\n") || strings.Contains(text, "main.go") {
+		t.Errorf("tree to depth 1:\n%s", text)
+	}
+	text, _ = callText(t, c, "list_matching_paths", map[string]any{"project_id": p.ID, "pattern": `\.go$`, "format": "text"})
+	if want := "./\n  api/ [api]\n    handler.go\n    main.go\n  vendor/\n    lib0.go [third-party]\n"; !strings.HasPrefix(text, want) || strings.Contains(text, "index.ts") {
+		t.Errorf("matching paths as a tree:\n%s", text)
+	}
+	if text, isErr = callText(t, c, "list_tree", map[string]any{"project_id": p.ID, "format": "xml"}); !isErr || !strings.Contains(text, "format must be json, text or collapsed") {
+		t.Errorf("list_tree with an unknown format = %s", text)
+	}
+	text, _ = callText(t, c, "list_tree", map[string]any{"project_id": p.ID})
+	var out struct {
+		Tree struct {
+			Children []any `json:"children"`
+		} `json:"tree"`
+	}
+	if err := json.Unmarshal([]byte(text), &out); err != nil || len(out.Tree.Children) != 4 {
+		t.Errorf("default list_tree = %s", text)
+	}
+
+}
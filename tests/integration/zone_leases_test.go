@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestZoneLeases verifies that an agent leasing a shared zone keeps other agents' writes out of
+// it until the lease is released, and that leases are listed, renewed and refused as expected.
+func TestZoneLeases(t *testing.T) {
+	root := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(root, "api"), 0755)
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID, bob.ID})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	adaToken, _ := svc.IssueAgentToken(ada.ID)
+	bobToken, _ := svc.IssueAgentToken(bob.ID)
+	adaClient := testhelper.NewTestClient(t, baseURL, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + adaToken}))
+	defer adaClient.Close()
+	bobClient := testhelper.NewTestClient(t, baseURL, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + bobToken}))
+	defer bobClient.Close()
+
+	text, isErr := callText(t, adaClient, "claim_zone", map[string]any{"zone_id": api.ID, "ttl_seconds": 60})
+	if isErr {
+		t.Fatalf("claim_zone: %s", text)
+	}
+	var claimed struct {
+		Lease struct {
+			AgentID string `json:"agent_id"`
+			ZoneID  string `json:"zone_id"`
+		}
+	}
+	_ = json.Unmarshal([]byte(text), &claimed)
+	if claimed.Lease.AgentID != ada.ID || claimed.Lease.ZoneID != api.ID {
+		t.Errorf("claim_zone = %s", text)
+	}
+	if text, isErr = callText(t, bobClient, "claim_zone", map[string]any{"zone_id": api.ID}); !isErr || !strings.Contains(text, "leased by agent "+ada.ID) {
+		t.Errorf("claim_zone of a leased zone = %s", text)
+	}
+	if text, isErr = callText(t, bobClient, "write_file", map[string]any{"project_id": p.ID, "path": "api/b.go", "content": ""}); !isErr || !strings.Contains(text, "leased by agent "+ada.ID) {
+		t.Errorf("write_file in a zone leased by another agent = %s", text)
+	}
+	patch := "--- /dev/null\n+++ b/api/c.go\n@@ -0,0 +1 @@\n+package api\n"
+	if text, isErr = callText(t, bobClient, "apply_patch", map[string]any{"project_id": p.ID, "patch": patch}); !isErr || !strings.Contains(text, "api/c.go is in zone api leased") {
+		t.Errorf("apply_patch in a zone leased by another agent = %s", text)
+	}
+	if text, isErr = callText(t, adaClient, "write_file", map[string]any{"project_id": p.ID, "path": "api/a.go", "content": "package api\n"}); isErr {
+		t.Errorf("write_file by the lease holder = %s", text)
+	}
+	if text, isErr = callText(t, bobClient, "renew_lease", map[string]any{"zone_id": api.ID}); !isErr || !strings.Contains(text, "holds no active lease") {
+		t.Errorf("renew_lease by another agent = %s", text)
+	}
+	if text, isErr = callText(t, bobClient, "release_zone", map[string]any{"zone_id": api.ID}); !isErr || !strings.Contains(text, "is leased by agent "+ada.ID) {
+		t.Errorf("release_zone by another agent = %s", text)
+	}
+	if text, _ = callText(t, adaClient, "list_leases", map[string]any{"project_id": p.ID}); !strings.Contains(text, `"agent_id":"`+ada.ID+`"`) {
+		t.Errorf("list_leases = %s", text)
+	}
+
+	if text, isErr = callText(t, adaClient, "release_zone", map[string]any{"zone_id": api.ID}); isErr {
+		t.Fatalf("release_zone: %s", text)
+	}
+	if text, _ = callText(t, adaClient, "list_leases", map[string]any{"project_id": p.ID}); text != `{"leases":[]}` {
+		t.Errorf("list_leases after release = %s", text)
+	}
+	if text, isErr = callText(t, bobClient, "write_file", map[string]any{"project_id": p.ID, "path": "api/b.go", "content": ""}); isErr {
+		t.Errorf("write_file after release = %s", text)
+	}
+}
+
+func TestZoneLeases_ExpiryAndCleanup(t *testing.T) {
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", t.TempDir())
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID, bob.ID})
+	db, _ := svc.CreateZone(p.ID, "db", "^db/", "", nil, []string{ada.ID})
+	ctx := context.Background()
+
+	wantCode := func(err error, code string) {
+		t.Helper()
+		var se *domain.StructuredError
+		if !errors.As(err, &se) || se.Code != code {
+			t.Errorf("err = %v, want %s", err, code)
+		}
+	}
+	_, err := svc.ClaimZone(ctx, db.ID, bob.ID, 0)
+	wantCode(err, "OUT_OF_ZONE")
+	_, err = svc.ClaimZone(ctx, api.ID, "", 0)
+	wantCode(err, "AGENT_REQUIRED")
+	_, err = svc.ClaimZone(ctx, api.ID, ada.ID, 48*time.Hour)
+	wantCode(err, "INVALID_TTL")
+	_, err = svc.ClaimZone(blueprint.WithCaller(ctx, bob.ID), api.ID, ada.ID, 0)
+	wantCode(err, "IDENTITY_MISMATCH")
+
+	l, err := svc.ClaimZone(ctx, api.ID, ada.ID, 0)
+	if err != nil || l.ExpiresAt.Sub(l.AcquiredAt) != blueprint.DefaultLeaseTTL {
+		t.Fatalf("ClaimZone default TTL = %+v, %v", l, err)
+	}
+	// A short lease expires and no longer blocks other agents.
+	if _, err := svc.RenewLease(ctx, api.ID, ada.ID, 20*time.Millisecond); err != nil {
+		t.Fatalf("RenewLease: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if leases, _ := svc.ListLeases(p.ID); len(leases) != 0 {
+		t.Errorf("ListLeases after expiry = %d leases", len(leases))
+	}
+	_, err = svc.RenewLease(ctx, api.ID, ada.ID, 0)
+	wantCode(err, "LEASE_NOT_HELD")
+	if l, err = svc.ClaimZone(ctx, api.ID, bob.ID, time.Minute); err != nil || l.AgentID != bob.ID {
+		t.Fatalf("ClaimZone after expiry = %+v, %v", l, err)
+	}
+
+	// Anonymous callers may release any lease; deleting zones and agents drops theirs.
+	if err := svc.ReleaseZone(ctx, api.ID, ""); err != nil {
+		t.Fatalf("anonymous ReleaseZone: %v", err)
+	}
+	_, _ = svc.ClaimZone(ctx, api.ID, ada.ID, time.Minute)
+	_, _ = svc.ClaimZone(ctx, db.ID, ada.ID, time.Minute)
+	_ = svc.DeleteZone(db.ID)
+	if leases, _ := svc.ListLeases(p.ID); len(leases) != 1 || leases[0].ZoneID != api.ID {
+		t.Errorf("ListLeases after DeleteZone = %+v", leases)
+	}
+	_ = svc.DeleteAgent(ada.ID)
+	if leases, _ := svc.ListLeases(p.ID); len(leases) != 0 {
+		t.Errorf("ListLeases after DeleteAgent = %+v", leases)
+	}
+}
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client/transport"
+	adapter "operators-mcp/internal/adapter/in/mcp"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+	"operators-mcp/tests/testhelper"
+)
+
+type notesResult struct {
+	Notes []adapter.NoteDTO `json:"notes"`
+}
+
+// TestZoneNotes verifies that agents add, list, search and delete zone notes, and that expiry,
+// eviction and the pinned cap keep the notes bounded.
+func TestZoneNotes(t *testing.T) {
+	root := t.TempDir()
+	svc := testhelper.NewMemoryService()
+	p, _ := svc.CreateProject("app", root)
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	api, _ := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID, bob.ID})
+	db, _ := svc.CreateZone(p.ID, "db", "^db/", "", nil, []string{bob.ID})
+	baseURL, cleanup := testhelper.StartMCPServer(t, svc, false)
+	defer cleanup()
+
+	adaToken, _ := svc.IssueAgentToken(ada.ID)
+	adaClient := testhelper.NewTestClient(t, baseURL, transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + adaToken}))
+	defer adaClient.Close()
+
+	add := func(args map[string]any) adapter.NoteDTO {
+		t.Helper()
+		text, isErr := callText(t, adaClient, "add_zone_note", args)
+		if isErr {
+			t.Fatalf("add_zone_note: %s", text)
+		}
+		var out struct {
+			Note adapter.NoteDTO `json:"note"`
+		}
+		_ = json.Unmarshal([]byte(text), &out)
+		return out.Note
+	}
+	gotcha := add(map[string]any{"zone_id": api.ID, "text": "Handlers must not import the sqlite adapter.", "tags": []any{"Gotcha", "imports", "gotcha"}})
+	if gotcha.AgentID != ada.ID || len(gotcha.Tags) != 2 || gotcha.Tags[0] != "gotcha" || gotcha.ExpiresAt == nil {
+		t.Errorf("add_zone_note = %+v", gotcha)
+	}
+	if d := gotcha.ExpiresAt.Sub(gotcha.CreatedAt); d != blueprint.DefaultNoteTTL {
+		t.Errorf("default expiry = %v", d)
+	}
+	entry := add(map[string]any{"zone_id": api.ID, "text": "Entry point: cmd/server/main.go", "tags": []any{"entry-point"}, "pinned": true})
+	if entry.ExpiresAt != nil || !entry.Pinned {
+		t.Errorf("pinned note = %+v", entry)
+	}
+	cmd := add(map[string]any{"zone_id": api.ID, "text": "Run go test ./tests/integration/ for the API.", "tags": []any{"command"}, "ttl_days": 7})
+	_, _ = svc.AddZoneNote(context.Background(), db.ID, bob.ID, blueprint.NoteDraft{Text: "Migrations run at startup.", Tags: []string{"gotcha"}})
+
+	text, _ := callText(t, adaClient, "list_zone_notes", map[string]any{"zone_id": api.ID})
+	var listed notesResult
+	_ = json.Unmarshal([]byte(text), &listed)
+	if len(listed.Notes) != 3 || listed.Notes[0].ID != entry.ID || listed.Notes[1].ID != cmd.ID || listed.Notes[2].ID != gotcha.ID {
+		t.Errorf("list_zone_notes order = %s", text)
+	}
+	text, _ = callText(t, adaClient, "list_zone_notes", map[string]any{"zone_id": api.ID, "tag": "command"})
+	if _ = json.Unmarshal([]byte(text), &listed); len(listed.Notes) != 1 || listed.Notes[0].ID != cmd.ID {
+		t.Errorf("list_zone_notes by tag = %s", text)
+	}
+	text, _ = callText(t, adaClient, "search_zone_notes", map[string]any{"project_id": p.ID, "tags": []any{"gotcha"}})
+	if _ = json.Unmarshal([]byte(text), &listed); len(listed.Notes) != 2 {
+		t.Errorf("search_zone_notes by tag = %s", text)
+	}
+	text, _ = callText(t, adaClient, "search_zone_notes", map[string]any{"project_id": p.ID, "query": "SQLITE handlers"})
+	if _ = json.Unmarshal([]byte(text), &listed); len(listed.Notes) != 1 || listed.Notes[0].ID != gotcha.ID {
+		t.Errorf("search_zone_notes by words = %s", text)
+	}
+
+	if text, isErr := callText(t, adaClient, "add_zone_note", map[string]any{"zone_id": api.ID, "text": strings.Repeat("x", blueprint.MaxNoteLength+1)}); !isErr {
+		t.Errorf("add_zone_note too long = %s", text)
+	}
+	if text, isErr := callText(t, adaClient, "add_zone_note", map[string]any{"zone_id": api.ID, "text": "x", "ttl_days": 400}); !isErr {
+		t.Errorf("add_zone_note with a ttl over a year = %s", text)
+	}
+
+	bobNotes, _ := svc.ListZoneNotes(db.ID, "")
+	if text, isErr := callText(t, adaClient, "delete_zone_note", map[string]any{"note_id": bobNotes[0].ID}); !isErr || !strings.Contains(text, "written by agent "+bob.ID) {
+		t.Errorf("delete_zone_note of another agent's note = %s", text)
+	}
+	if text, isErr := callText(t, adaClient, "delete_zone_note", map[string]any{"note_id": cmd.ID}); isErr {
+		t.Errorf("delete_zone_note = %s", text)
+	}
+
+	// An expired note is hidden, then removed when the next note is added.
+	expired, _ := svc.Notes.Create(&domain.ZoneNote{ProjectID: p.ID, ZoneID: api.ID, Text: "stale", CreatedAt: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(-time.Minute)})
+	if notes, _ := svc.ListZoneNotes(api.ID, ""); len(notes) != 2 {
+		t.Errorf("ListZoneNotes shows expired notes: %d notes", len(notes))
+	}
+	ctx := context.Background()
+	for i := range blueprint.MaxZoneNotes {
+		if _, err := svc.AddZoneNote(ctx, api.ID, "", blueprint.NoteDraft{Text: fmt.Sprintf("note %d", i)}); err != nil {
+			t.Fatalf("AddZoneNote %d: %v", i, err)
+		}
+	}
+	if svc.Notes.Get(expired.ID) != nil {
+		t.Error("expired note kept after adding a note")
+	}
+	if svc.Notes.Get(gotcha.ID) != nil {
+		t.Error("oldest unpinned note not evicted beyond the limit")
+	}
+	if notes, _ := svc.ListZoneNotes(api.ID, ""); len(notes) != blueprint.MaxZoneNotes+1 || notes[0].ID != entry.ID {
+		t.Errorf("ListZoneNotes after eviction: %d notes", len(notes))
+	}
+	for i := 1; i < blueprint.MaxPinnedNotes; i++ {
+		_, _ = svc.AddZoneNote(ctx, api.ID, "", blueprint.NoteDraft{Text: "pinned", Pinned: true})
+	}
+	if _, err := svc.AddZoneNote(ctx, api.ID, "", blueprint.NoteDraft{Text: "one too many", Pinned: true}); err == nil || !strings.Contains(err.Error(), "pinned notes") {
+		t.Errorf("pinned note beyond the cap: %v", err)
+	}
+
+	// The per-agent endpoint only offers notes of the agent's zones.
+	c, _, err := connectAgent(t, baseURL+"/agents/"+ada.ID+"/mcp", agentBearer(t, svc, ada.ID))
+	if err != nil {
+		t.Fatalf("connect agent endpoint: %v", err)
+	}
+	defer c.Close()
+	if text, isErr := callText(t, c, "add_zone_note", map[string]any{"zone_id": db.ID, "text": "not mine"}); !isErr || !strings.Contains(text, "not assigned to agent") {
+		t.Errorf("add_zone_note outside the agent's zones = %s", text)
+	}
+	text, _ = callText(t, c, "search_zone_notes", map[string]any{"tags": []any{"gotcha"}})
+	if _ = json.Unmarshal([]byte(text), &listed); len(listed.Notes) != 0 {
+		t.Errorf("agent search_zone_notes shows other zones' notes = %s", text)
+	}
+
+	if err := svc.DeleteZone(db.ID); err != nil {
+		t.Fatalf("DeleteZone: %v", err)
+	}
+	if svc.Notes.Get(bobNotes[0].ID) != nil {
+		t.Error("note kept after its zone was deleted")
+	}
+}
@@ -0,0 +1,26 @@
+package testhelper
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+	"operators-mcp/internal/adapter/out/persistence/file"
+	"operators-mcp/internal/adapter/out/persistence/sqlite"
+)
+
+// OpenBackends opens a migrated SQLite database and a file store in temporary directories, for
+// tests that run a repository against every backend. The file store is closed when the test ends.
+func OpenBackends(t *testing.T) (*gorm.DB, *file.Dir) {
+	t.Helper()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	dir, err := file.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("file.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = dir.Close() })
+	return db, dir
+}
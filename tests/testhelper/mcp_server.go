@@ -28,8 +28,18 @@ func StartMCPServer(t *testing.T, svc *blueprint.Service, devMode bool) (baseURL
 // When devMode is true and devServerURL is "", ui.DefaultDevServerURL is used.
 func StartMCPServerWithDesigner(t *testing.T, svc *blueprint.Service, devMode bool, embedFS fs.FS, devServerURL string) (baseURL string, cleanup func()) {
 	t.Helper()
-	s := server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true))
+	ids := mcp.NewIdentities(svc)
+	projects := mcp.NewActiveProjects(svc)
+	hooks := &server.Hooks{}
+	ids.AddHooks(hooks)
+	projects.AddHooks(hooks)
+	s := server.NewMCPServer("test", "0.0.1", server.WithToolCapabilities(true), server.WithPromptCapabilities(true),
+		server.WithResourceCapabilities(true, false), server.WithLogging(), server.WithHooks(hooks), server.WithToolHandlerMiddleware(projects.ToolMiddleware))
 	mcp.RegisterTools(s, svc)
+	mcp.RegisterPrompts(s, svc)
+	subs := mcp.RegisterResources(s, svc)
+	projects.RegisterTools(s)
+	ids.DeliverMessages(s)
 
 	designerResource := mcplib.NewResource(ui.DesignerURI, "Designer",
 		mcplib.WithResourceDescription("Designer UI"),
@@ -63,20 +73,30 @@ func StartMCPServerWithDesigner(t *testing.T, svc *blueprint.Service, devMode bo
 	}
 	port := listener.Addr().(*net.TCPAddr).Port
 	baseURL = "http://127.0.0.1:" + strconv.Itoa(port)
-	srv := &http.Server{Handler: httpSrv}
+	mux := http.NewServeMux()
+	global := ids.Handler(projects.Handler(subs.Handler(httpSrv)))
+	mux.Handle("/", global)
+	mux.Handle(mcp.ProjectEndpointPattern, projects.ProjectEndpoint(global))
+	mux.Handle(mcp.AgentEndpointPattern, mcp.NewAgentServers(svc))
+	srv := &http.Server{Handler: mux}
 	go srv.Serve(listener)
 	return baseURL, func() { _ = srv.Shutdown(context.Background()) }
 }
 
 // NewTestClient creates an mcp-go client and initializes it against the server at baseURL.
-func NewTestClient(t *testing.T, baseURL string) *client.Client {
+// Pass transport.WithContinuousListening() to receive notifications outside of requests.
+func NewTestClient(t *testing.T, baseURL string, opts ...transport.StreamableHTTPCOption) *client.Client {
 	t.Helper()
-	trans, err := transport.NewStreamableHTTP(baseURL)
+	trans, err := transport.NewStreamableHTTP(baseURL, opts...)
 	if err != nil {
 		t.Fatalf("transport: %v", err)
 	}
 	c := client.NewClient(trans)
 	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		trans.Close()
+		t.Fatalf("start: %v", err)
+	}
 	initReq := mcplib.InitializeRequest{}
 	initReq.Params.ProtocolVersion = mcplib.LATEST_PROTOCOL_VERSION
 	initReq.Params.ClientInfo = mcplib.Implementation{Name: "test", Version: "0.0.1"}
@@ -0,0 +1,23 @@
+package testhelper
+
+import (
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/application/blueprint"
+)
+
+// NewMemoryService returns a service backed by in-memory stores and the real filesystem, with
+// every optional port set except Dependencies. Tests replace ports they need to observe or slow down.
+func NewMemoryService() *blueprint.Service {
+	svc := blueprint.NewService(memory.NewProjectStore(), memory.NewStore(), memory.NewAgentStore(), filesystem.NewMatcher(), filesystem.NewLister())
+	svc.Files = filesystem.NewFiles()
+	svc.Changes = memory.NewChangeLog()
+	svc.Tokens = memory.NewAgentTokens()
+	svc.Tasks = memory.NewTaskStore()
+	svc.Leases = memory.NewLeaseStore()
+	svc.Runs = memory.NewRunStore()
+	svc.Messages = memory.NewMessageStore()
+	svc.Decisions = memory.NewDecisionStore()
+	svc.Notes = memory.NewNoteStore()
+	return svc
+}
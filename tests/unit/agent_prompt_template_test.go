@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"operators-mcp/internal/domain"
+)
+
+func TestAgentPromptTemplate_RendersContextAndVariables(t *testing.T) {
+	for name, svc := range refBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			_ = os.MkdirAll(filepath.Join(root, "api"), 0755)
+			_ = os.WriteFile(filepath.Join(root, "api", "server.go"), []byte("package api\n"), 0644)
+			p, err := svc.CreateProject("shop", root)
+			if err != nil {
+				t.Fatalf("CreateProject: %v", err)
+			}
+			z, err := svc.CreateZone(p.ID, "api", "^api/", "HTTP layer", []string{"no sql"}, nil)
+			if err != nil {
+				t.Fatalf("CreateZone: %v", err)
+			}
+			prompt := "You own {{.Zone.Name}} in {{.Project.Name}} ({{.Zone.Purpose}}).\n" +
+				"Constraints:\n{{bullets .Zone.Constraints}}\nFiles: {{join .Zone.Paths \", \"}}\n" +
+				"Style: {{.Vars.style}}. Ticket: {{.Vars.ticket}}.\nTask: {{.Task}}"
+			vars := []domain.PromptVariable{
+				{Name: "style", Default: "terse"},
+				{Name: "ticket", Required: true, Description: "Issue key"},
+			}
+			a, err := svc.CreateAgent("Owner", "", prompt, vars)
+			if err != nil {
+				t.Fatalf("CreateAgent: %v", err)
+			}
+			if got := svc.GetAgent(a.ID); got == nil || len(got.Variables) != 2 || !got.Variables[1].Required {
+				t.Fatalf("stored variables = %+v", got)
+			}
+
+			out, err := svc.RenderAgentPrompt(a.ID, z.ID, "add pagination", map[string]string{"ticket": "SHOP-7"})
+			if err != nil {
+				t.Fatalf("RenderAgentPrompt: %v", err)
+			}
+			for _, want := range []string{"You own api in shop (HTTP layer).", "- no sql", "api/server.go", "Style: terse. Ticket: SHOP-7.", "Task: add pagination"} {
+				if !strings.Contains(out.Text, want) {
+					t.Errorf("rendered prompt missing %q:\n%s", want, out.Text)
+				}
+			}
+
+			_, err = svc.RenderAgentPrompt(a.ID, z.ID, "", nil)
+			if se, ok := err.(*domain.StructuredError); !ok || se.Code != "MISSING_VARIABLE" {
+				t.Errorf("expected MISSING_VARIABLE, got %v", err)
+			}
+			_, err = svc.RenderAgentPrompt(a.ID, z.ID, "", map[string]string{"ticket": "x", "mood": "calm"})
+			if se, ok := err.(*domain.StructuredError); !ok || se.Code != "UNKNOWN_VARIABLE" {
+				t.Errorf("expected UNKNOWN_VARIABLE, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAgentPromptTemplate_ValidatesOnCreateAndUpdate(t *testing.T) {
+	svc := refBackends(t)["memory"]
+	cases := []struct {
+		name   string
+		prompt string
+		vars   []domain.PromptVariable
+		code   string
+	}{
+		{"undeclared variable", "Hi {{.Vars.missing}}", nil, "INVALID_PROMPT"},
+		{"unknown field", "Hi {{.Zone.Owner}}", nil, "INVALID_PROMPT"},
+		{"syntax error", "Hi {{.Zone.Name", nil, "INVALID_PROMPT"},
+		{"reserved variable", "Hi", []domain.PromptVariable{{Name: "task"}}, "INVALID_VARIABLE"},
+		{"duplicate variable", "Hi", []domain.PromptVariable{{Name: "a"}, {Name: "a"}}, "INVALID_VARIABLE"},
+	}
+	for _, c := range cases {
+		_, err := svc.CreateAgent("bad", "", c.prompt, c.vars)
+		if se, ok := err.(*domain.StructuredError); !ok || se.Code != c.code {
+			t.Errorf("%s: expected %s, got %v", c.name, c.code, err)
+		}
+	}
+
+	a, err := svc.CreateAgent("plain", "", "Review {{.Vars.scope}}", []domain.PromptVariable{{Name: "scope", Default: "everything"}})
+	if err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+	_, err = svc.UpdateAgent(a.ID, "plain", "", "Review {{.Vars.scope}}", nil)
+	if se, ok := err.(*domain.StructuredError); !ok || se.Code != "INVALID_PROMPT" {
+		t.Errorf("expected INVALID_PROMPT when dropping a used variable, got %v", err)
+	}
+	if got := svc.GetAgent(a.ID); got.Prompt != "Review {{.Vars.scope}}" || len(got.Variables) != 1 {
+		t.Errorf("rejected update changed the agent: %+v", got)
+	}
+}
+
+func TestAgentPromptTemplate_IndexAndLegacyPrompts(t *testing.T) {
+	svc := refBackends(t)["memory"]
+	vars := []domain.PromptVariable{{Name: "lang", Default: "Go"}}
+	if _, err := svc.CreateAgent("bad", "", `Write {{index .Vars "missing"}}`, vars); err == nil || !strings.Contains(err.Error(), "unknown variable .Vars.missing") {
+		t.Errorf("index of an undeclared variable: %v", err)
+	}
+	if _, err := svc.CreateAgent("bad", "", `Write {{index .Vars .Task}}`, vars); err == nil {
+		t.Error("index of .Vars with a computed key was accepted")
+	}
+	a, err := svc.CreateAgent("ok", "", `Write {{index .Vars "lang"}} and {{index $.Zone "Name"}}.`, vars)
+	if err != nil {
+		t.Fatalf("CreateAgent with index: %v", err)
+	}
+	if p, err := svc.RenderAgentPrompt(a.ID, "", "", nil); err != nil || !strings.HasPrefix(p.Text, "Write Go and .") {
+		t.Errorf("rendered = %+v, %v", p, err)
+	}
+
+	// A prompt stored before prompts were templates renders as plain text and stays editable.
+	legacy, _ := svc.Agents.Create("legacy", "", "Use {{double braces}} in docs.", nil)
+	if p, err := svc.RenderAgentPrompt(legacy.ID, "", "", nil); err != nil || p.Text != "Use {{double braces}} in docs.\n" {
+		t.Errorf("legacy prompt rendered = %+v, %v", p, err)
+	}
+	if _, err := svc.UpdateAgent(legacy.ID, "legacy", "Writes docs", legacy.Prompt, nil); err != nil {
+		t.Errorf("updating a legacy agent's description: %v", err)
+	}
+	if _, err := svc.UpdateAgent(legacy.ID, "legacy", "Writes docs", "Use {{more braces}}.", nil); err == nil {
+		t.Error("a new invalid prompt was accepted")
+	}
+}
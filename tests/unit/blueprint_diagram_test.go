@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+)
+
+func newDiagramFixture(t *testing.T) (*blueprint.Service, *domain.Project, map[string]*domain.Zone) {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                     "module example.com/app\n",
+		"cmd/server/main.go":         "package main\n\nimport _ \"example.com/app/internal/domain\"\n",
+		"internal/domain/zone.go":    "package domain\n",
+		"internal/adapter/store.go":  "package adapter\n\nimport _ \"example.com/app/internal/domain\"\n",
+		"internal/adapter/extra.txt": "notes\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		_ = os.MkdirAll(filepath.Dir(p), 0755)
+		_ = os.WriteFile(p, []byte(content), 0644)
+	}
+	svc := blueprint.NewService(memory.NewProjectStore(), memory.NewStore(), memory.NewAgentStore(), filesystem.NewMatcher(), filesystem.NewLister())
+	svc.Dependencies = filesystem.NewImportAnalyzer()
+	p, err := svc.CreateProject("app", root)
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	ada, err := svc.CreateAgent("Ada", "", "", nil)
+	if err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+	zones := map[string]*domain.Zone{}
+	for _, spec := range []struct{ name, pattern, purpose string }{
+		{"server", "^cmd/", "Entry point"},
+		{"internal", "^internal/", "Libraries"},
+		{"domain", "^internal/domain", "Core \"model\""},
+	} {
+		z, err := svc.CreateZone(p.ID, spec.name, spec.pattern, spec.purpose, nil, []string{ada.ID})
+		if err != nil {
+			t.Fatalf("CreateZone: %v", err)
+		}
+		zones[spec.name] = z
+	}
+	return svc, p, zones
+}
+
+func TestExportDiagram_Mermaid_NodesAndDependencies(t *testing.T) {
+	svc, p, zones := newDiagramFixture(t)
+	out, err := svc.ExportDiagram(p.ID, blueprint.DiagramOptions{})
+	if err != nil {
+		t.Fatalf("ExportDiagram: %v", err)
+	}
+	if !strings.HasPrefix(out, "flowchart LR\n") {
+		t.Errorf("expected mermaid flowchart, got %q", out)
+	}
+	if !strings.Contains(out, "Core #quot;model#quot;") {
+		t.Errorf("expected escaped purpose in %q", out)
+	}
+	if !strings.Contains(out, "agents: Ada") {
+		t.Errorf("expected agent names in %q", out)
+	}
+	edge := "z_" + zones["server"].ID + " --> z_" + zones["domain"].ID
+	if !strings.Contains(out, edge) {
+		t.Errorf("expected edge %q in %q", edge, out)
+	}
+	if strings.Contains(out, "subgraph") {
+		t.Errorf("expected no subgraph without group_nested, got %q", out)
+	}
+}
+
+func TestExportDiagram_GroupNested_DOTAndPlantUML(t *testing.T) {
+	svc, p, zones := newDiagramFixture(t)
+	dot, err := svc.ExportDiagram(p.ID, blueprint.DiagramOptions{Format: blueprint.DiagramDOT, GroupNested: true})
+	if err != nil {
+		t.Fatalf("ExportDiagram dot: %v", err)
+	}
+	if !strings.Contains(dot, `subgraph "cluster_`+zones["internal"].ID+`"`) {
+		t.Errorf("expected internal cluster in %q", dot)
+	}
+	puml, err := svc.ExportDiagram(p.ID, blueprint.DiagramOptions{Format: blueprint.DiagramPlantUML, GroupNested: true})
+	if err != nil {
+		t.Fatalf("ExportDiagram plantuml: %v", err)
+	}
+	if !strings.HasPrefix(puml, "@startuml") || !strings.Contains(puml, `package "internal"`) {
+		t.Errorf("unexpected plantuml output %q", puml)
+	}
+}
+
+func TestExportDiagram_FilterAndErrors(t *testing.T) {
+	svc, p, zones := newDiagramFixture(t)
+	out, err := svc.ExportDiagram(p.ID, blueprint.DiagramOptions{ZoneIDs: []string{zones["server"].ID}})
+	if err != nil {
+		t.Fatalf("ExportDiagram: %v", err)
+	}
+	if strings.Contains(out, zones["domain"].ID) {
+		t.Errorf("filtered diagram should not contain domain zone: %q", out)
+	}
+	_, err = svc.ExportDiagram(p.ID, blueprint.DiagramOptions{Format: "svg"})
+	if se, ok := err.(*domain.StructuredError); !ok || se.Code != "INVALID_FORMAT" {
+		t.Errorf("expected INVALID_FORMAT, got %v", err)
+	}
+	_, err = svc.ExportDiagram(p.ID, blueprint.DiagramOptions{ZoneIDs: []string{"missing"}})
+	if se, ok := err.(*domain.StructuredError); !ok || se.Code != "ZONE_NOT_FOUND" {
+		t.Errorf("expected ZONE_NOT_FOUND, got %v", err)
+	}
+}
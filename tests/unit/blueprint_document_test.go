@@ -0,0 +1,152 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+)
+
+func newMemoryService(root string) *blueprint.Service {
+	return blueprint.NewService(memory.NewProjectStore(), memory.NewStore(), memory.NewAgentStore(), filesystem.NewMatcher(), filesystem.NewLister())
+}
+
+func TestBlueprintDocument_ExportImportRoundTrip(t *testing.T) {
+	src := newMemoryService("")
+	p, _ := src.CreateProject("app", "/src/app")
+	_, _ = src.AddIgnoredPath(p.ID, "node_modules")
+	_, _ = src.AddIgnoredPath(p.ID, ".git")
+	a, _ := src.CreateAgent("backend-dev", "Owns the server", "You write Go.", nil)
+	z, _ := src.CreateZone(p.ID, "server", "^cmd/", "Entry points", []string{"no UI code", "keep main small"}, []string{a.ID})
+	_, _ = src.AssignPathToZone(z.ID, "internal/app")
+	_, _ = src.CreateZone(p.ID, "docs", "^docs/", "", nil, nil)
+
+	doc, err := src.ExportBlueprint(p.ID)
+	if err != nil {
+		t.Fatalf("ExportBlueprint: %v", err)
+	}
+	yamlDoc, err := blueprint.MarshalDocument(doc, blueprint.FormatYAML)
+	if err != nil {
+		t.Fatalf("MarshalDocument: %v", err)
+	}
+	text := string(yamlDoc)
+	if strings.Index(text, "name: docs") > strings.Index(text, "name: server") {
+		t.Errorf("zones should be sorted by name:\n%s", text)
+	}
+	if !strings.Contains(text, "- .git\n    - node_modules") {
+		t.Errorf("ignored paths should be sorted:\n%s", text)
+	}
+
+	parsed, err := blueprint.UnmarshalDocument(yamlDoc, "")
+	if err != nil {
+		t.Fatalf("UnmarshalDocument: %v", err)
+	}
+	dst := newMemoryService("")
+	res, err := dst.ImportBlueprint(parsed, blueprint.ImportOptions{RootDir: "/dst/app"})
+	if err != nil {
+		t.Fatalf("ImportBlueprint: %v", err)
+	}
+	if !res.Applied || res.ProjectID == "" {
+		t.Fatalf("expected applied import with project id, got %+v", res)
+	}
+	again, err := dst.ExportBlueprint(res.ProjectID)
+	if err != nil {
+		t.Fatalf("ExportBlueprint dst: %v", err)
+	}
+	round, _ := blueprint.MarshalDocument(again, blueprint.FormatYAML)
+	if string(round) != text {
+		t.Errorf("round trip mismatch:\n%s\n---\n%s", text, round)
+	}
+
+	// Re-importing the same document is a no-op.
+	res, err = dst.ImportBlueprint(parsed, blueprint.ImportOptions{ProjectID: res.ProjectID, Mode: blueprint.ImportPrune})
+	if err != nil {
+		t.Fatalf("ImportBlueprint again: %v", err)
+	}
+	if len(res.Changes) != 0 {
+		t.Errorf("expected no changes, got %v", res.Changes)
+	}
+}
+
+func TestBlueprintDocument_DryRunPruneDiff(t *testing.T) {
+	svc := newMemoryService("")
+	p, _ := svc.CreateProject("app", "/app")
+	_, _ = svc.CreateZone(p.ID, "legacy", "^old/", "", nil, nil)
+	_, _ = svc.CreateZone(p.ID, "server", "^cmd/", "old purpose", nil, nil)
+
+	doc, err := blueprint.UnmarshalDocument([]byte(`{"version":1,"project":{"name":"app"},"zones":[{"name":"server","pattern":"^cmd/","purpose":"new purpose"},{"name":"web","pattern":"^web/"}]}`), "")
+	if err != nil {
+		t.Fatalf("UnmarshalDocument: %v", err)
+	}
+	res, err := svc.ImportBlueprint(doc, blueprint.ImportOptions{ProjectID: p.ID, Mode: blueprint.ImportPrune, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportBlueprint: %v", err)
+	}
+	var lines []string
+	for _, c := range res.Changes {
+		lines = append(lines, c.String())
+	}
+	want := "~ zone server (purpose)|+ zone web|- zone legacy"
+	if got := strings.Join(lines, "|"); got != want {
+		t.Errorf("diff: got %q, want %q", got, want)
+	}
+	if res.Applied || len(svc.ListZones(p.ID)) != 2 {
+		t.Error("dry run must not modify the store")
+	}
+
+	res, err = svc.ImportBlueprint(doc, blueprint.ImportOptions{ProjectID: p.ID, Mode: blueprint.ImportCreate})
+	if err != nil {
+		t.Fatalf("ImportBlueprint create: %v", err)
+	}
+	if len(res.Changes) != 1 || res.Changes[0].Name != "web" {
+		t.Errorf("create mode should only add missing zones, got %v", res.Changes)
+	}
+}
+
+func TestBlueprintDocument_RejectsUnknownFieldsAndAgents(t *testing.T) {
+	if _, err := blueprint.UnmarshalDocument([]byte("version: 1\nproject:\n  nam: x\n"), ""); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	svc := newMemoryService("")
+	p, _ := svc.CreateProject("app", "/app")
+	doc := &blueprint.Document{Version: 1, Zones: []blueprint.DocumentZone{{Name: "z", Agents: []string{"ghost"}}}}
+	_, err := svc.ImportBlueprint(doc, blueprint.ImportOptions{ProjectID: p.ID})
+	if se, ok := err.(*domain.StructuredError); !ok || se.Code != "AGENT_NOT_FOUND" {
+		t.Errorf("expected AGENT_NOT_FOUND, got %v", err)
+	}
+}
+
+func TestBlueprintDocument_UnnamedAgentFailsExport(t *testing.T) {
+	svc := newMemoryService("")
+	p, _ := svc.CreateProject("app", "/app")
+	a, _ := svc.CreateAgent("", "", "", nil)
+	_, _ = svc.CreateZone(p.ID, "z", "^z/", "", nil, []string{a.ID})
+	_, err := svc.ExportBlueprint(p.ID)
+	if se, ok := err.(*domain.StructuredError); !ok || se.Code != "UNNAMED_AGENT" || !strings.Contains(se.Message, a.ID) {
+		t.Errorf("expected UNNAMED_AGENT naming %s, got %v", a.ID, err)
+	}
+}
+
+func TestBlueprintDocument_InvalidDocumentWritesNothing(t *testing.T) {
+	svc := newMemoryService("")
+	doc := &blueprint.Document{
+		Version: 1,
+		Project: blueprint.DocumentProject{Name: "app", IgnoredPaths: []string{"vendor"}},
+		Agents:  []blueprint.DocumentAgent{{Name: "dev", Prompt: "You work on {{.Vars.area}}."}},
+		Zones:   []blueprint.DocumentZone{{Name: "z", Agents: []string{"dev"}}},
+	}
+	if _, err := svc.ImportBlueprint(doc, blueprint.ImportOptions{RootDir: "/app"}); err == nil {
+		t.Fatal("expected an error for an undeclared prompt variable")
+	}
+	doc.Agents[0].Prompt = ""
+	doc.Zones = append(doc.Zones, blueprint.DocumentZone{Name: "y", Agents: []string{"ghost"}})
+	if _, err := svc.ImportBlueprint(doc, blueprint.ImportOptions{RootDir: "/app"}); err == nil {
+		t.Fatal("expected an error for an unknown agent")
+	}
+	if len(svc.ListProjects()) != 0 || len(svc.ListAgents()) != 0 {
+		t.Errorf("failed imports wrote %d projects and %d agents", len(svc.ListProjects()), len(svc.ListAgents()))
+	}
+}
@@ -61,3 +61,20 @@ func TestListMatchingPaths_NonexistentRoot_StructuredError(t *testing.T) {
 		t.Errorf("expected ROOT_UNREADABLE, got %v", err)
 	}
 }
+
+func TestZoneMatcher_ContainsDirAndTouches(t *testing.T) {
+	z := &domain.Zone{Pattern: `^api/.*\.go$`, ExplicitPaths: []string{"docs/api.md"}}
+	m := domain.NewZoneMatcher(z)
+	if !m.Contains("api/main.go") || m.Contains("api/README.md") || !m.Contains("docs/api.md") {
+		t.Errorf("Contains does not match Zone.Contains")
+	}
+	if m.ContainsDir("api") || !m.ContainsDir("docs/api.md") {
+		t.Errorf("ContainsDir: a pattern on files does not contain its directory")
+	}
+	if !m.Touches("docs") || m.Touches("web") {
+		t.Errorf("Touches: docs holds an explicit path, web nothing")
+	}
+	if bad := domain.NewZoneMatcher(&domain.Zone{Pattern: "["}); bad.Contains("[") {
+		t.Errorf("an invalid pattern matched")
+	}
+}
@@ -15,8 +15,7 @@ func TestStore_CreateListGetUpdateAssignPath(t *testing.T) {
 	}
 	s := memory.NewStore()
 
-	agents1 := []domain.Agent{{ID: "agent-1", Name: "Agent 1"}}
-	z, err := s.Create(p.ID, "backend", "cmd/.*", "Server code", []string{"no UI"}, agents1)
+	z, err := s.Create(p.ID, "backend", "cmd/.*", "Server code", []string{"no UI"}, []string{"agent-1"})
 	if err != nil {
 		t.Fatalf("Create: %v", err)
 	}
@@ -29,8 +28,8 @@ func TestStore_CreateListGetUpdateAssignPath(t *testing.T) {
 	if z.Name != "backend" {
 		t.Errorf("name: got %q", z.Name)
 	}
-	if len(z.AssignedAgents) != 1 || z.AssignedAgents[0].ID != "agent-1" {
-		t.Errorf("AssignedAgents: got %v", z.AssignedAgents)
+	if len(z.AgentIDs) != 1 || z.AgentIDs[0] != "agent-1" {
+		t.Errorf("AgentIDs: got %v", z.AgentIDs)
 	}
 
 	list := s.ListByProject(p.ID)
@@ -49,16 +48,15 @@ func TestStore_CreateListGetUpdateAssignPath(t *testing.T) {
 		t.Errorf("Get.Name: got %q", got.Name)
 	}
 
-	agents2 := []domain.Agent{{ID: "agent-2", Name: "Agent 2"}}
-	updated, err := s.Update(z.ID, "backend-updated", "internal/.*", "Internal pkgs", nil, agents2)
+	updated, err := s.Update(z.ID, "backend-updated", "internal/.*", "Internal pkgs", nil, []string{"agent-2"})
 	if err != nil {
 		t.Fatalf("Update: %v", err)
 	}
 	if updated.Name != "backend-updated" {
 		t.Errorf("Update name: got %q", updated.Name)
 	}
-	if len(updated.AssignedAgents) != 1 || updated.AssignedAgents[0].ID != "agent-2" {
-		t.Errorf("AssignedAgents after Update: got %v", updated.AssignedAgents)
+	if len(updated.AgentIDs) != 1 || updated.AgentIDs[0] != "agent-2" {
+		t.Errorf("AgentIDs after Update: got %v", updated.AgentIDs)
 	}
 
 	assigned, err := s.AssignPath(z.ID, "internal/blueprint")
@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/application/blueprint"
+)
+
+func TestBlueprintSync_WriteBackImportAndConflict(t *testing.T) {
+	root := t.TempDir()
+	svc := newMemoryService(root)
+	svc.Files = filesystem.NewFiles()
+	p, _ := svc.CreateProject("app", root)
+	z, _ := svc.CreateZone(p.ID, "server", "^cmd/", "Entry points", nil, nil)
+
+	res, err := svc.BindBlueprintFile(p.ID, "", "")
+	if err != nil {
+		t.Fatalf("BindBlueprintFile: %v", err)
+	}
+	if res.Status != blueprint.SyncWritten || res.File != blueprint.DefaultBlueprintFile {
+		t.Fatalf("bind: got %+v", res)
+	}
+	file := filepath.Join(root, ".operators", "blueprint.yaml")
+	readFile := func() string {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read blueprint: %v", err)
+		}
+		return string(b)
+	}
+	if !strings.Contains(readFile(), "name: server") {
+		t.Fatalf("expected zone in file:\n%s", readFile())
+	}
+
+	// Changes through the service are written back.
+	if _, err := svc.UpdateZone(z.ID, "", "^cmd/", "Binaries", nil, nil); err != nil {
+		t.Fatalf("UpdateZone: %v", err)
+	}
+	if !strings.Contains(readFile(), "purpose: Binaries") {
+		t.Errorf("expected write-back of purpose:\n%s", readFile())
+	}
+
+	// Edits to the file are applied to the store.
+	edited := strings.Replace(readFile(), "purpose: Binaries", "purpose: From git", 1)
+	_ = os.WriteFile(file, []byte(edited), 0644)
+	res, err = svc.SyncBlueprint(p.ID, "")
+	if err != nil {
+		t.Fatalf("SyncBlueprint: %v", err)
+	}
+	if res.Status != blueprint.SyncImported {
+		t.Fatalf("expected imported, got %+v", res)
+	}
+	if got := svc.GetZone(z.ID).Purpose; got != "From git" {
+		t.Errorf("purpose after import: got %q", got)
+	}
+	if res, _ = svc.SyncBlueprint(p.ID, ""); res.Status != blueprint.SyncInSync {
+		t.Errorf("expected in_sync after import, got %+v", res)
+	}
+
+	// Both sides changed: conflict, nothing written until resolved.
+	_ = os.WriteFile(file, []byte(strings.Replace(edited, "purpose: From git", "purpose: File side", 1)), 0644)
+	if _, err := svc.UpdateZone(z.ID, "", "^cmd/", "Store side", nil, nil); err != nil {
+		t.Fatalf("UpdateZone: %v", err)
+	}
+	res, err = svc.SyncBlueprint(p.ID, "")
+	if err != nil {
+		t.Fatalf("SyncBlueprint: %v", err)
+	}
+	if res.Status != blueprint.SyncConflict || len(res.Changes) != 1 || res.Changes[0].Name != "server" {
+		t.Fatalf("expected conflict on server, got %+v", res)
+	}
+	if !strings.Contains(readFile(), "purpose: File side") {
+		t.Error("conflict must not overwrite the file")
+	}
+	if res, err = svc.SyncBlueprint(p.ID, blueprint.ResolveStore); err != nil || res.Status != blueprint.SyncWritten {
+		t.Fatalf("resolve store: %+v %v", res, err)
+	}
+	if !strings.Contains(readFile(), "purpose: Store side") {
+		t.Errorf("expected store to win:\n%s", readFile())
+	}
+}
+
+func TestBlueprintSync_RejectsFileOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	svc := newMemoryService(root)
+	svc.Files = filesystem.NewFiles()
+	p, _ := svc.CreateProject("app", root)
+	if _, err := svc.BindBlueprintFile(p.ID, "../outside.yaml", ""); err == nil {
+		t.Fatal("expected error for file outside the project root")
+	}
+}
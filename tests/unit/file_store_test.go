@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/adapter/out/persistence/file"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+)
+
+func newFileService(t *testing.T, dataDir string) *blueprint.Service {
+	t.Helper()
+	dir, err := file.Open(dataDir)
+	if err != nil {
+		t.Fatalf("file.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = dir.Close() })
+	return blueprint.NewService(file.NewProjectRepository(dir), file.NewZoneRepository(dir), file.NewAgentRepository(dir),
+		filesystem.NewMatcher(), filesystem.NewLister())
+}
+
+func TestFileStore_PersistsAcrossReopen(t *testing.T) {
+	dataDir := t.TempDir()
+	root := t.TempDir()
+	svc := newFileService(t, dataDir)
+	p, err := svc.CreateProject("app", root)
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	if _, err := svc.AddIgnoredPath(p.ID, "vendor"); err != nil {
+		t.Fatalf("AddIgnoredPath: %v", err)
+	}
+	a, err := svc.CreateAgent("Ada", "reviewer", "Review changes.", nil)
+	if err != nil {
+		t.Fatalf("CreateAgent: %v", err)
+	}
+	z, err := svc.CreateZone(p.ID, "api", "^api/", "HTTP layer", []string{"no sql"}, []string{a.ID})
+	if err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	if _, err := svc.AssignPathToZone(z.ID, "cmd/api"); err != nil {
+		t.Fatalf("AssignPathToZone: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "zones", z.ID+".json")); err != nil {
+		t.Errorf("expected zone record on disk: %v", err)
+	}
+
+	reopened := newFileService(t, dataDir)
+	gotP := reopened.GetProject(p.ID)
+	if gotP == nil || gotP.Name != "app" || len(gotP.IgnoredPaths) != 1 || gotP.IgnoredPaths[0] != "vendor" {
+		t.Fatalf("reopened project = %+v", gotP)
+	}
+	gotZ := reopened.GetZone(z.ID)
+	if gotZ == nil {
+		t.Fatal("GetZone: zone not found after reopen")
+	}
+	if gotZ.Pattern != "^api/" || len(gotZ.Constraints) != 1 || len(gotZ.ExplicitPaths) != 1 || len(gotZ.AssignedAgents) != 1 || gotZ.AssignedAgents[0].Name != "Ada" {
+		t.Errorf("reopened zone = %+v", gotZ)
+	}
+	if agents := reopened.ListAgents(); len(agents) != 1 || agents[0].Prompt != "Review changes." {
+		t.Errorf("reopened agents = %+v", agents)
+	}
+}
+
+func TestFileStore_DeleteAndNotFound(t *testing.T) {
+	svc := newFileService(t, t.TempDir())
+	p, err := svc.CreateProject("app", t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	if _, err := svc.CreateZone(p.ID, "api", "", "", nil, nil); err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	if err := svc.DeleteProject(p.ID); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+	if zones := svc.ListZones(p.ID); len(zones) != 0 {
+		t.Errorf("expected zones removed with project, got %d", len(zones))
+	}
+	_, err = svc.UpdateAgent("missing", "x", "", "", nil)
+	if se, ok := err.(*domain.StructuredError); !ok || se.Code != "AGENT_NOT_FOUND" {
+		t.Errorf("expected AGENT_NOT_FOUND, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package unit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+)
+
+func readBack(t *testing.T, root, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, rel))
+	if err != nil {
+		t.Fatalf("read %s: %v", rel, err)
+	}
+	return string(data)
+}
+
+func TestWriteFile_OwnershipAndChangeLog(t *testing.T) {
+	svc, p, _, ada, _ := newReadFixture(t)
+	svc.Changes = memory.NewChangeLog()
+
+	c, err := svc.WriteFile(p.ID, ada.ID, "api/health.go", "package api\n")
+	if err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if c.Operation != domain.ChangeCreate || c.AgentID != ada.ID || c.LinesAdded != 1 || c.ID == "" {
+		t.Errorf("WriteFile change = %+v", c)
+	}
+	if got := readBack(t, p.RootDir, "api/health.go"); got != "package api\n" {
+		t.Errorf("written content = %q", got)
+	}
+	// Rewriting the same content succeeds without recording a change.
+	c, err = svc.WriteFile(p.ID, ada.ID, "api/health.go", "package api\n")
+	if err != nil || c.ID != "" || c.OldHash != c.NewHash || c.LinesAdded != 0 {
+		t.Errorf("unchanged WriteFile = %+v, %v", c, err)
+	}
+
+	_, err = svc.WriteFile(p.ID, ada.ID, "db/store.go", "package hacked\n")
+	var oz *domain.OutOfZoneError
+	if !errors.As(err, &oz) || oz.Code != "OUT_OF_ZONE" {
+		t.Fatalf("expected OUT_OF_ZONE, got %v", err)
+	}
+	if len(oz.Violations) != 1 || oz.Violations[0].Path != "db/store.go" || len(oz.Violations[0].OwningZones) != 1 || oz.Violations[0].OwningZones[0] != "db" {
+		t.Errorf("violations = %+v", oz.Violations)
+	}
+	if got := readBack(t, p.RootDir, "db/store.go"); got != "package db\n" {
+		t.Errorf("out-of-zone file was modified: %q", got)
+	}
+
+	_, err = svc.WriteFile(p.ID, "", "api/x.go", "")
+	wantCode(t, err, "AGENT_REQUIRED")
+	_, err = svc.WriteFile(p.ID, ada.ID, "vendor/x/x.go", "")
+	wantCode(t, err, "PATH_IGNORED")
+
+	changes, err := svc.ListChanges(p.ID, 0)
+	if err != nil {
+		t.Fatalf("ListChanges: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Path != "api/health.go" {
+		t.Errorf("ListChanges = %+v", changes)
+	}
+}
+
+func TestApplyPatch_AllOrNothing(t *testing.T) {
+	svc, p, _, ada, _ := newReadFixture(t)
+	svc.Changes = memory.NewChangeLog()
+
+	crossZone := "--- a/api/routes.go\n+++ b/api/routes.go\n@@ -1 +1,2 @@\n package api\n+// routes\n" +
+		"--- a/db/store.go\n+++ b/db/store.go\n@@ -1 +1 @@\n-package db\n+package store\n" +
+		"--- /dev/null\n+++ b/docs/new.md\n@@ -0,0 +1 @@\n+hi\n"
+	_, err := svc.ApplyPatch(p.ID, ada.ID, crossZone)
+	var oz *domain.OutOfZoneError
+	if !errors.As(err, &oz) {
+		t.Fatalf("expected OUT_OF_ZONE, got %v", err)
+	}
+	if len(oz.Violations) != 2 || oz.Violations[0].Path != "db/store.go" || oz.Violations[1].Path != "docs/new.md" || len(oz.Violations[1].OwningZones) != 0 {
+		t.Errorf("violations = %+v", oz.Violations)
+	}
+	if got := readBack(t, p.RootDir, "api/routes.go"); got != "package api\n" {
+		t.Errorf("in-zone file was written despite the violation: %q", got)
+	}
+
+	patch := "diff --git a/api/server.go b/api/server.go\n--- a/api/server.go\n+++ b/api/server.go\n" +
+		"@@ -1,3 +1,3 @@\n package api\n \n-func Serve() {}\n+func Serve() error { return nil }\n" +
+		"--- /dev/null\n+++ b/api/doc.go\n@@ -0,0 +1 @@\n+// Package api serves HTTP.\n" +
+		"--- a/api/routes.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-package api\n"
+	changes, err := svc.ApplyPatch(p.ID, ada.ID, patch)
+	if err != nil {
+		t.Fatalf("ApplyPatch: %v", err)
+	}
+	if len(changes) != 3 || changes[0].Operation != domain.ChangeWrite || changes[1].Operation != domain.ChangeCreate || changes[2].Operation != domain.ChangeDelete {
+		t.Fatalf("ApplyPatch changes = %+v", changes)
+	}
+	if changes[0].LinesAdded != 1 || changes[0].LinesRemoved != 1 || changes[0].OldHash == changes[0].NewHash {
+		t.Errorf("modify change = %+v", changes[0])
+	}
+	if got := readBack(t, p.RootDir, "api/server.go"); got != "package api\n\nfunc Serve() error { return nil }\n" {
+		t.Errorf("patched content = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(p.RootDir, "api/routes.go")); !os.IsNotExist(err) {
+		t.Errorf("api/routes.go should be deleted, stat err = %v", err)
+	}
+
+	conflict := "--- a/api/server.go\n+++ b/api/server.go\n@@ -1,2 +1,2 @@\n-package apiz\n+package api2\n \n" +
+		"--- /dev/null\n+++ b/api/other.go\n@@ -0,0 +1 @@\n+package api\n"
+	_, err = svc.ApplyPatch(p.ID, ada.ID, conflict)
+	wantCode(t, err, "PATCH_CONFLICT")
+	if _, err := os.Stat(filepath.Join(p.RootDir, "api/other.go")); !os.IsNotExist(err) {
+		t.Error("api/other.go should not be created by a conflicting patch")
+	}
+	_, err = svc.ApplyPatch(p.ID, ada.ID, "not a diff")
+	wantCode(t, err, "INVALID_PATCH")
+
+	if all, _ := svc.ListChanges(p.ID, 2); len(all) != 2 || all[0].Path != "api/routes.go" {
+		t.Errorf("ListChanges limit 2 = %+v", all)
+	}
+}
+
+// failingFiles is a FileStore whose writes of one path fail.
+type failingFiles struct {
+	ports.FileStore
+	path string
+}
+
+func (f failingFiles) WriteFile(root, path string, data []byte) error {
+	if path == f.path {
+		return errors.New("disk full")
+	}
+	return f.FileStore.WriteFile(root, path, data)
+}
+
+func TestApplyPatch_RollsBackFailedWrites(t *testing.T) {
+	svc, p, _, ada, _ := newReadFixture(t)
+	svc.Changes = memory.NewChangeLog()
+	svc.Files = failingFiles{FileStore: svc.Files, path: "api/z.go"}
+
+	patch := "--- a/api/server.go\n+++ b/api/server.go\n@@ -1 +1 @@\n-package api\n+package server\n" +
+		"--- /dev/null\n+++ b/api/new.go\n@@ -0,0 +1 @@\n+package api\n" +
+		"--- /dev/null\n+++ b/api/z.go\n@@ -0,0 +1 @@\n+package api\n"
+	if _, err := svc.ApplyPatch(p.ID, ada.ID, patch); err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected the write error, got %v", err)
+	}
+	if got := readBack(t, p.RootDir, "api/server.go"); got != "package api\n\nfunc Serve() {}\n" {
+		t.Errorf("api/server.go not restored: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(p.RootDir, "api/new.go")); !os.IsNotExist(err) {
+		t.Errorf("api/new.go not removed, stat err = %v", err)
+	}
+	if all, _ := svc.ListChanges(p.ID, 0); len(all) != 0 {
+		t.Errorf("failed patch recorded changes: %+v", all)
+	}
+}
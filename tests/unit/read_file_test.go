@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+)
+
+func newReadFixture(t *testing.T) (*blueprint.Service, *domain.Project, *domain.Zone, *domain.Agent, *domain.Agent) {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"api/server.go":  "package api\n\nfunc Serve() {}\n",
+		"api/routes.go":  "package api\n",
+		"db/store.go":    "package db\n",
+		"vendor/x/x.go":  "package x\n",
+		"api/logo.png":   "\x89PNG\x00\x00",
+		"docs/notes.txt": "one\ntwo\nthree\nfour\n",
+	} {
+		p := filepath.Join(root, name)
+		_ = os.MkdirAll(filepath.Dir(p), 0755)
+		_ = os.WriteFile(p, []byte(content), 0644)
+	}
+	svc := blueprint.NewService(memory.NewProjectStore(), memory.NewStore(), memory.NewAgentStore(), filesystem.NewMatcher(), filesystem.NewLister())
+	svc.Files = filesystem.NewFiles()
+	p, _ := svc.CreateProject("app", root)
+	if _, err := svc.AddIgnoredPath(p.ID, "vendor"); err != nil {
+		t.Fatalf("AddIgnoredPath: %v", err)
+	}
+	ada, _ := svc.CreateAgent("Ada", "", "", nil)
+	bob, _ := svc.CreateAgent("Bob", "", "", nil)
+	api, err := svc.CreateZone(p.ID, "api", "^api/", "", nil, []string{ada.ID})
+	if err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	if _, err := svc.CreateZone(p.ID, "db", "^db/", "", nil, []string{bob.ID}); err != nil {
+		t.Fatalf("CreateZone: %v", err)
+	}
+	return svc, p, api, ada, bob
+}
+
+func wantCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var se *domain.StructuredError
+	if !errors.As(err, &se) || se.Code != code {
+		t.Errorf("expected %s, got %v", code, err)
+	}
+}
+
+func TestReadFile_RangesLimitsAndRefusals(t *testing.T) {
+	svc, p, _, _, _ := newReadFixture(t)
+
+	fc, err := svc.ReadFile(p.ID, "docs/notes.txt", blueprint.ReadOptions{StartLine: 2, EndLine: 3})
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if fc.Content != "two\nthree\n" || fc.TotalLines != 4 || fc.StartLine != 2 || fc.EndLine != 3 {
+		t.Errorf("ReadFile range = %+v", fc)
+	}
+	fc, err = svc.ReadFile(p.ID, filepath.Join(p.RootDir, "docs/notes.txt"), blueprint.ReadOptions{MaxBytes: 9})
+	if err != nil {
+		t.Fatalf("ReadFile absolute: %v", err)
+	}
+	if fc.Content != "one\ntwo\n" || !fc.Truncated || fc.EndLine != 2 {
+		t.Errorf("ReadFile max_bytes = %+v", fc)
+	}
+
+	_, err = svc.ReadFile(p.ID, "../outside.txt", blueprint.ReadOptions{})
+	wantCode(t, err, "INVALID_PATH")
+	_, err = svc.ReadFile(p.ID, "vendor/x/x.go", blueprint.ReadOptions{})
+	wantCode(t, err, "PATH_IGNORED")
+	_, err = svc.ReadFile(p.ID, "api/logo.png", blueprint.ReadOptions{})
+	wantCode(t, err, "BINARY_FILE")
+	_, err = svc.ReadFile(p.ID, "api", blueprint.ReadOptions{})
+	wantCode(t, err, "NOT_A_FILE")
+	_, err = svc.ReadFile(p.ID, "missing.go", blueprint.ReadOptions{})
+	wantCode(t, err, "FILE_NOT_FOUND")
+
+	outside := t.TempDir()
+	_ = os.WriteFile(filepath.Join(outside, "secret"), []byte("x"), 0644)
+	if err := os.Symlink(outside, filepath.Join(p.RootDir, "link")); err == nil {
+		_, err = svc.ReadFile(p.ID, "link/secret", blueprint.ReadOptions{})
+		wantCode(t, err, "INVALID_PATH")
+	}
+}
+
+func TestReadFile_AgentZoneOwnership(t *testing.T) {
+	svc, p, api, ada, bob := newReadFixture(t)
+
+	if _, err := svc.ReadFile(p.ID, "api/server.go", blueprint.ReadOptions{AgentID: ada.ID}); err != nil {
+		t.Errorf("owner read: %v", err)
+	}
+	_, err := svc.ReadFile(p.ID, "api/server.go", blueprint.ReadOptions{AgentID: bob.ID})
+	wantCode(t, err, "OUT_OF_ZONE")
+	if err != nil && !strings.Contains(err.Error(), "api") {
+		t.Errorf("OUT_OF_ZONE should name the owning zone: %v", err)
+	}
+	_, err = svc.ReadFile(p.ID, "docs/notes.txt", blueprint.ReadOptions{AgentID: ada.ID})
+	wantCode(t, err, "OUT_OF_ZONE")
+
+	zf, err := svc.ReadZoneFiles(api.ID, blueprint.ReadOptions{AgentID: ada.ID})
+	if err != nil {
+		t.Fatalf("ReadZoneFiles: %v", err)
+	}
+	var got []string
+	for _, f := range zf.Files {
+		got = append(got, f.Path)
+	}
+	if strings.Join(got, ",") != "api/routes.go,api/server.go" || len(zf.Skipped) != 1 || zf.Skipped[0] != "api/logo.png" {
+		t.Errorf("ReadZoneFiles = files %v skipped %v", got, zf.Skipped)
+	}
+	_, err = svc.ReadZoneFiles(api.ID, blueprint.ReadOptions{AgentID: bob.ID})
+	wantCode(t, err, "OUT_OF_ZONE")
+
+	zf, err = svc.ReadZoneFiles(api.ID, blueprint.ReadOptions{MaxBytes: 20})
+	if err != nil {
+		t.Fatalf("ReadZoneFiles budget: %v", err)
+	}
+	if len(zf.Files) != 1 || !zf.Truncated {
+		t.Errorf("ReadZoneFiles with budget = %+v", zf)
+	}
+}
@@ -0,0 +1,148 @@
+package unit
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/persistence/sqlite"
+)
+
+func TestSQLiteMigrate_FreshDatabase(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	status, err := sqlite.Status(db)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, s := range status {
+		if !s.Applied() {
+			t.Errorf("migration %d_%s not applied", s.Version, s.Name)
+		}
+	}
+	if applied, err := sqlite.Migrate(db, 0); err != nil || len(applied) != 0 {
+		t.Errorf("second Migrate = %v, %v; want nothing applied", applied, err)
+	}
+
+	projects := sqlite.NewProjectRepository(db)
+	zones := sqlite.NewZoneRepository(db)
+	p, err := projects.Create("app", t.TempDir())
+	if err != nil {
+		t.Fatalf("Create project: %v", err)
+	}
+	if _, err := projects.AddIgnoredPath(p.ID, ".git"); err != nil {
+		t.Fatalf("AddIgnoredPath: %v", err)
+	}
+	z, err := zones.Create(p.ID, "api", "^api/", "", []string{"b", "a"}, []string{"a1"})
+	if err != nil {
+		t.Fatalf("Create zone: %v", err)
+	}
+	if _, err := zones.AssignPath(z.ID, "cmd/api"); err != nil {
+		t.Fatalf("AssignPath: %v", err)
+	}
+	got := zones.Get(z.ID)
+	if !reflect.DeepEqual(got.Constraints, []string{"b", "a"}) || !reflect.DeepEqual(got.ExplicitPaths, []string{"cmd/api"}) || len(got.AgentIDs) != 1 {
+		t.Errorf("zone lists = %+v", got)
+	}
+	if err := zones.DeleteByProject(p.ID); err != nil {
+		t.Fatalf("DeleteByProject: %v", err)
+	}
+	var n int64
+	db.Table("zone_constraints").Count(&n)
+	if n != 0 {
+		t.Errorf("expected zone_constraints rows removed with zones, got %d", n)
+	}
+}
+
+func TestSQLiteMigrate_AdoptsLegacyJSONColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := sqlite.Connect(path)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE projects (id TEXT PRIMARY KEY, name TEXT, root_dir TEXT, ignored_paths TEXT)`,
+		`CREATE TABLE zones (id TEXT PRIMARY KEY, project_id TEXT, name TEXT, pattern TEXT, purpose TEXT, constraints TEXT, assigned_agents TEXT, explicit_paths TEXT)`,
+		`CREATE TABLE agents (id TEXT PRIMARY KEY, name TEXT, description TEXT, prompt TEXT)`,
+		`INSERT INTO projects VALUES ('p1', 'app', '/src/app', '[".git"]')`,
+		`INSERT INTO agents VALUES ('a1', 'Ada', '', '')`,
+		`INSERT INTO agents VALUES ('a2', 'Bob', '', '')`,
+		`INSERT INTO zones VALUES ('z1', 'p1', 'api', '^api/', 'HTTP', '["no sql"]', '[{"ID":"a1","Name":"Ada"},{"ID":"gone","Name":"Bob"},{"ID":"x","Name":"Nobody"}]', '["cmd/api","cmd/cli"]')`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	applied, err := sqlite.Migrate(db, 0)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if len(applied) == 0 || applied[0].Version != 2 {
+		t.Errorf("expected legacy database adopted at version 1, applied %+v", applied)
+	}
+
+	p := sqlite.NewProjectRepository(db).Get("p1")
+	if p == nil || !reflect.DeepEqual(p.IgnoredPaths, []string{".git"}) {
+		t.Errorf("migrated project = %+v", p)
+	}
+	z := sqlite.NewZoneRepository(db).Get("z1")
+	if z == nil {
+		t.Fatal("migrated zone not found")
+	}
+	if !reflect.DeepEqual(z.ExplicitPaths, []string{"cmd/api", "cmd/cli"}) || !reflect.DeepEqual(z.Constraints, []string{"no sql"}) {
+		t.Errorf("migrated zone lists = %+v", z)
+	}
+	if !reflect.DeepEqual(z.AgentIDs, []string{"a1", "a2"}) {
+		t.Errorf("migrated zone agent ids = %v, want a1 and a2 (matched by name)", z.AgentIDs)
+	}
+}
+
+func TestSQLiteMigrate_RejectsInvalidLegacyLists(t *testing.T) {
+	db, err := sqlite.Connect(filepath.Join(t.TempDir(), "legacy.db"))
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE projects (id TEXT PRIMARY KEY, name TEXT, root_dir TEXT, ignored_paths TEXT)`,
+		`CREATE TABLE zones (id TEXT PRIMARY KEY, project_id TEXT, name TEXT, pattern TEXT, purpose TEXT, constraints TEXT, assigned_agents TEXT, explicit_paths TEXT)`,
+		`CREATE TABLE agents (id TEXT PRIMARY KEY, name TEXT, description TEXT, prompt TEXT)`,
+		`INSERT INTO projects VALUES ('p1', 'app', '/src/app', '.git')`,
+		`INSERT INTO projects VALUES ('p2', 'web', '/src/web', '')`,
+		`INSERT INTO zones VALUES ('z1', 'p1', 'api', '', '', '["ok", 3]', '', '["cmd"]')`,
+		`INSERT INTO zones VALUES ('z2', 'p1', 'db', '', '', '', '["Ada"]', NULL)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	_, err = sqlite.Migrate(db, 0)
+	if err == nil {
+		t.Fatal("expected Migrate to fail on invalid list data")
+	}
+	for _, want := range []string{"projects.ignored_paths must be a JSON array of text values (rows p1)", "zones.constraints must be a JSON array of text values (rows z1)", "zones.assigned_agents must be a JSON array of object values (rows z2)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q lacks %q", err, want)
+		}
+	}
+	if !db.Migrator().HasColumn("projects", "ignored_paths") || db.Migrator().HasTable("zone_paths") {
+		t.Error("the failed migration was partly applied")
+	}
+
+	// Once the data is fixed the migration applies.
+	for _, stmt := range []string{
+		`UPDATE projects SET ignored_paths = '[".git"]' WHERE id = 'p1'`,
+		`UPDATE zones SET constraints = '["ok"]' WHERE id = 'z1'`,
+		`UPDATE zones SET assigned_agents = '[]' WHERE id = 'z2'`,
+	} {
+		_ = db.Exec(stmt).Error
+	}
+	if _, err := sqlite.Migrate(db, 0); err != nil {
+		t.Fatalf("Migrate after the fix: %v", err)
+	}
+	if z := sqlite.NewZoneRepository(db).Get("z1"); z == nil || len(z.ExplicitPaths) != 1 || len(z.Constraints) != 1 {
+		t.Errorf("migrated zone = %+v", z)
+	}
+}
@@ -0,0 +1,330 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"operators-mcp/internal/adapter/out/persistence/file"
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/adapter/out/persistence/sqlite"
+	"operators-mcp/internal/application/ports"
+	"operators-mcp/internal/domain"
+	"operators-mcp/tests/testhelper"
+)
+
+// TestTaskRepository_Backends verifies that every store keeps tasks per project in creation
+// order and updates, deletes and drops them by project.
+func TestTaskRepository_Backends(t *testing.T) {
+	db, dir := testhelper.OpenBackends(t)
+	for name, repo := range map[string]ports.TaskRepository{
+		"memory": memory.NewTaskStore(),
+		"sqlite": sqlite.NewTaskRepository(db),
+		"file":   file.NewTaskRepository(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now().UTC().Truncate(time.Second)
+			first, err := repo.Create(&domain.Task{ProjectID: "p1", Title: "first", Status: domain.TaskOpen, ZoneIDs: []string{"z2", "z1"}, CreatedAt: now, UpdatedAt: now})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			_, _ = repo.Create(&domain.Task{ProjectID: "p1", Title: "second", Status: domain.TaskOpen, CreatedAt: now, UpdatedAt: now})
+			_, _ = repo.Create(&domain.Task{ProjectID: "p2", Title: "other", Status: domain.TaskOpen, CreatedAt: now, UpdatedAt: now})
+
+			first.Status = domain.TaskClaimed
+			first.AgentID = "a1"
+			first.ZoneIDs = []string{"z1"}
+			if _, err := repo.Update(first); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			got := repo.Get(first.ID)
+			if got == nil || got.Status != domain.TaskClaimed || got.AgentID != "a1" || len(got.ZoneIDs) != 1 || !got.CreatedAt.Equal(now) {
+				t.Errorf("Get after Update = %+v", got)
+			}
+			tasks := repo.List("p1")
+			if len(tasks) != 2 || tasks[0].Title != "first" || tasks[1].Title != "second" {
+				t.Errorf("List = %+v", tasks)
+			}
+			if _, err := repo.Update(&domain.Task{ID: "nope"}); err == nil {
+				t.Error("Update of a missing task: expected error")
+			}
+			if err := repo.Delete(first.ID); err != nil || repo.Get(first.ID) != nil {
+				t.Errorf("Delete: %v", err)
+			}
+			if err := repo.DeleteByProject("p1"); err != nil || len(repo.List("p1")) != 0 || len(repo.List("p2")) != 1 {
+				t.Errorf("DeleteByProject: %v", err)
+			}
+		})
+	}
+}
+
+// TestTaskRepository_Backends verifies that every store keeps tasks per project in creation
+// order and updates, deletes and drops them by project.
+func TestRunRepository_Backends(t *testing.T) {
+	db, dir := testhelper.OpenBackends(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	for name, store := range map[string]ports.RunRepository{
+		"memory": memory.NewRunStore(),
+		"sqlite": sqlite.NewRunRepository(db),
+		"file":   file.NewRunRepository(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			run, err := store.Create(&domain.Run{ProjectID: "p1", Title: "first", CreatedAt: now, UpdatedAt: now, Tasks: []domain.RunTask{
+				{TaskID: "t1", DependsOn: []string{}},
+				{TaskID: "t2", DependsOn: []string{"t1"}},
+			}})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			second, _ := store.Create(&domain.Run{ProjectID: "p1", Title: "second", CreatedAt: now, UpdatedAt: now})
+			_, _ = store.Create(&domain.Run{ProjectID: "p2", Title: "other", CreatedAt: now, UpdatedAt: now})
+			run.Tasks = append(run.Tasks, domain.RunTask{TaskID: "t3", DependsOn: []string{"t2", "t1"}})
+			if _, err := store.Update(run); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			got := store.Get(run.ID)
+			if got == nil || len(got.Tasks) != 3 || got.Tasks[1].DependsOn[0] != "t1" || strings.Join(got.Tasks[2].DependsOn, ",") != "t2,t1" {
+				t.Errorf("Get after Update = %+v", got)
+			}
+			runs := store.List("p1")
+			if len(runs) != 2 || runs[0].ID != run.ID || runs[1].ID != second.ID {
+				t.Errorf("List = %+v", runs)
+			}
+			if err := store.Delete(run.ID); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if err := store.DeleteByProject("p1"); err != nil {
+				t.Fatalf("DeleteByProject: %v", err)
+			}
+			if len(store.List("p1")) != 0 || len(store.List("p2")) != 1 {
+				t.Error("runs left after DeleteByProject")
+			}
+		})
+	}
+}
+
+// TestTaskRepository_Backends verifies that every store keeps tasks per project in creation
+// order and updates, deletes and drops them by project.
+func TestLeaseStore_Backends(t *testing.T) {
+	db, dir := testhelper.OpenBackends(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	for name, store := range map[string]ports.LeaseStore{
+		"memory": memory.NewLeaseStore(),
+		"sqlite": sqlite.NewLeaseStore(db),
+		"file":   file.NewLeaseStore(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_ = store.Put(&domain.ZoneLease{ZoneID: "z2", ProjectID: "p1", AgentID: "a1", AcquiredAt: now, ExpiresAt: now.Add(time.Minute)})
+			_ = store.Put(&domain.ZoneLease{ZoneID: "z1", ProjectID: "p1", AgentID: "a1", AcquiredAt: now, ExpiresAt: now.Add(time.Minute)})
+			_ = store.Put(&domain.ZoneLease{ZoneID: "z3", ProjectID: "p2", AgentID: "a1", AcquiredAt: now, ExpiresAt: now.Add(time.Minute)})
+			if err := store.Put(&domain.ZoneLease{ZoneID: "z1", ProjectID: "p1", AgentID: "a2", AcquiredAt: now, ExpiresAt: now.Add(time.Hour)}); err != nil {
+				t.Fatalf("Put replace: %v", err)
+			}
+			l := store.Get("z1")
+			if l == nil || l.AgentID != "a2" || !l.ExpiresAt.Equal(now.Add(time.Hour)) {
+				t.Errorf("Get after replace = %+v", l)
+			}
+			leases := store.List("p1")
+			if len(leases) != 2 || leases[0].ZoneID != "z1" || leases[1].ZoneID != "z2" {
+				t.Errorf("List = %+v", leases)
+			}
+			if err := store.Delete("z1"); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if store.Get("z1") != nil || len(store.List("p1")) != 1 {
+				t.Error("lease still stored after Delete")
+			}
+		})
+	}
+}
+
+// TestMessageRepository_Backends verifies that every store keeps messages per project in
+// creation order and persists acknowledgements.
+func TestMessageRepository_Backends(t *testing.T) {
+	db, dir := testhelper.OpenBackends(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	for name, repo := range map[string]ports.MessageRepository{
+		"memory": memory.NewMessageStore(),
+		"sqlite": sqlite.NewMessageRepository(db),
+		"file":   file.NewMessageRepository(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			root, err := repo.Create(&domain.Message{ProjectID: "p1", FromAgentID: "a1", ToAgentID: "a2", Subject: "s", Body: "first", CreatedAt: now})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			_, _ = repo.Create(&domain.Message{ProjectID: "p2", ToAgentID: "a2", Body: "other", CreatedAt: now})
+			reply, _ := repo.Create(&domain.Message{ProjectID: "p1", ThreadID: root.ID, ReplyTo: root.ID, FromAgentID: "a2", ToAgentID: "a1", ZoneID: "z1", Body: "second", CreatedAt: now.Add(time.Second)})
+			messages := repo.List("p1")
+			if len(messages) != 2 || messages[0].ID != root.ID || messages[1].Thread() != root.ID || messages[1].ZoneID != "z1" {
+				t.Errorf("List = %+v", messages)
+			}
+			reply.AckedAt = now.Add(time.Minute)
+			if _, err := repo.Update(reply); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if m := repo.Get(reply.ID); m == nil || !m.AckedAt.Equal(now.Add(time.Minute)) || repo.Get(root.ID).Acked() {
+				t.Errorf("Get after ack = %+v", m)
+			}
+			if err := repo.DeleteByProject("p1"); err != nil {
+				t.Fatalf("DeleteByProject: %v", err)
+			}
+			if repo.Get(root.ID) != nil || len(repo.List("p1")) != 0 || len(repo.List("p2")) != 1 {
+				t.Error("DeleteByProject removed the wrong messages")
+			}
+		})
+	}
+}
+
+// TestDecisionRepository_Backends verifies that every store keeps decisions per project in
+// creation order with their zones.
+func TestDecisionRepository_Backends(t *testing.T) {
+	db, dir := testhelper.OpenBackends(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	for name, repo := range map[string]ports.DecisionRepository{
+		"memory": memory.NewDecisionStore(),
+		"sqlite": sqlite.NewDecisionRepository(db),
+		"file":   file.NewDecisionRepository(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			first, err := repo.Create(&domain.Decision{ProjectID: "p1", Number: 1, Title: "a", Decision: "d", Status: domain.DecisionAccepted, ZoneIDs: []string{"z2", "z1"}, CreatedAt: now, UpdatedAt: now})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			_, _ = repo.Create(&domain.Decision{ProjectID: "p2", Number: 1, Title: "other", Status: domain.DecisionProposed, CreatedAt: now, UpdatedAt: now})
+			second, _ := repo.Create(&domain.Decision{ProjectID: "p1", Number: 2, Title: "b", Status: domain.DecisionProposed, Supersedes: first.ID, CreatedAt: now.Add(time.Second), UpdatedAt: now})
+			decisions := repo.List("p1")
+			if len(decisions) != 2 || decisions[0].ID != first.ID || decisions[1].Supersedes != first.ID {
+				t.Fatalf("List = %+v", decisions)
+			}
+			if got := decisions[0].ZoneIDs; len(got) != 2 || got[0] != "z2" || got[1] != "z1" {
+				t.Errorf("zone ids = %v", got)
+			}
+			first.Status = domain.DecisionSuperseded
+			first.ZoneIDs = []string{"z1"}
+			if _, err := repo.Update(first); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if d := repo.Get(first.ID); d == nil || d.Status != domain.DecisionSuperseded || len(d.ZoneIDs) != 1 {
+				t.Errorf("Get after update = %+v", d)
+			}
+			if err := repo.DeleteByProject("p1"); err != nil {
+				t.Fatalf("DeleteByProject: %v", err)
+			}
+			if repo.Get(second.ID) != nil || len(repo.List("p1")) != 0 || len(repo.List("p2")) != 1 {
+				t.Error("DeleteByProject removed the wrong decisions")
+			}
+		})
+	}
+}
+
+// TestNoteRepository_Backends verifies that every store keeps notes per project in creation order
+// with their tags and expiry.
+func TestNoteRepository_Backends(t *testing.T) {
+	db, dir := testhelper.OpenBackends(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	for name, repo := range map[string]ports.NoteRepository{
+		"memory": memory.NewNoteStore(),
+		"sqlite": sqlite.NewNoteRepository(db),
+		"file":   file.NewNoteRepository(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			first, err := repo.Create(&domain.ZoneNote{ProjectID: "p1", ZoneID: "z1", AgentID: "a1", Text: "one", Tags: []string{"b", "a"}, CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			pinned, _ := repo.Create(&domain.ZoneNote{ProjectID: "p1", ZoneID: "z2", Text: "two", Pinned: true, CreatedAt: now.Add(time.Second)})
+			_, _ = repo.Create(&domain.ZoneNote{ProjectID: "p2", ZoneID: "z3", Text: "other", CreatedAt: now})
+			notes := repo.List("p1")
+			if len(notes) != 2 || notes[0].ID != first.ID || notes[1].ID != pinned.ID {
+				t.Fatalf("List = %+v", notes)
+			}
+			if n := notes[0]; len(n.Tags) != 2 || n.Tags[0] != "b" || !n.ExpiresAt.Equal(now.Add(time.Hour)) || n.AgentID != "a1" {
+				t.Errorf("first note = %+v", n)
+			}
+			if n := repo.Get(pinned.ID); n == nil || !n.Pinned || !n.ExpiresAt.IsZero() {
+				t.Errorf("pinned note = %+v", n)
+			}
+			if err := repo.Delete(first.ID); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if repo.Get(first.ID) != nil || repo.Delete(first.ID) == nil {
+				t.Error("note still stored after Delete")
+			}
+			if err := repo.DeleteByProject("p1"); err != nil {
+				t.Fatalf("DeleteByProject: %v", err)
+			}
+			if len(repo.List("p1")) != 0 || len(repo.List("p2")) != 1 {
+				t.Error("DeleteByProject removed the wrong notes")
+			}
+		})
+	}
+}
+
+// TestTaskRepository_Backends verifies that every store keeps tasks per project in creation
+// order and updates, deletes and drops them by project.
+func TestAgentTokenStore_Backends(t *testing.T) {
+	db, dir := testhelper.OpenBackends(t)
+	for name, store := range map[string]ports.AgentTokenStore{
+		"memory": memory.NewAgentTokens(),
+		"sqlite": sqlite.NewAgentTokens(db),
+		"file":   file.NewAgentTokens(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if store.Any() {
+				t.Error("Any on an empty store")
+			}
+			if err := store.Set("a1", "h1"); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			_ = store.Set("a2", "h2")
+			if err := store.Set("a1", "h3"); err != nil {
+				t.Fatalf("Set replace: %v", err)
+			}
+			if store.AgentID("h1") != "" || store.AgentID("h3") != "a1" || store.AgentID("h2") != "a2" {
+				t.Errorf("lookups after replace: h1=%q h3=%q h2=%q", store.AgentID("h1"), store.AgentID("h3"), store.AgentID("h2"))
+			}
+			if err := store.Delete("a1"); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if store.AgentID("h3") != "" {
+				t.Error("token still resolves after Delete")
+			}
+			if !store.Any() {
+				t.Error("Any = false with a2's token stored")
+			}
+			_ = store.Delete("a2")
+			if store.Any() {
+				t.Error("Any = true after every token was deleted")
+			}
+		})
+	}
+}
+
+// TestTaskRepository_Backends verifies that every store keeps tasks per project in creation
+// order and updates, deletes and drops them by project.
+func TestChangeLog_Backends(t *testing.T) {
+	db, dir := testhelper.OpenBackends(t)
+	for name, log := range map[string]ports.ChangeLog{
+		"memory": memory.NewChangeLog(),
+		"sqlite": sqlite.NewChangeLog(db),
+		"file":   file.NewChangeLog(dir),
+	} {
+		t.Run(name, func(t *testing.T) {
+			at := time.Now().UTC().Truncate(time.Second)
+			for i, path := range []string{"a.go", "b.go", "c.go"} {
+				if _, err := log.Record(&domain.FileChange{ProjectID: "p1", Path: path, Operation: domain.ChangeCreate, LinesAdded: i, CreatedAt: at}); err != nil {
+					t.Fatalf("Record: %v", err)
+				}
+			}
+			_, _ = log.Record(&domain.FileChange{ProjectID: "p2", Path: "x.go", Operation: domain.ChangeWrite, CreatedAt: at})
+			got := log.List("p1", 0)
+			if len(got) != 3 || got[0].Path != "c.go" || got[2].Path != "a.go" || got[0].ID == "" || got[0].LinesAdded != 2 {
+				t.Fatalf("List = %+v", got)
+			}
+			if got := log.List("p1", 1); len(got) != 1 || got[0].Path != "c.go" {
+				t.Errorf("List limit 1 = %+v", got)
+			}
+		})
+	}
+}
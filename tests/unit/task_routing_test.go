@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/persistence/memory"
+)
+
+func TestRouteTask_ResolvesPathsToZones(t *testing.T) {
+	svc, p, api, ada, _ := newReadFixture(t)
+
+	route, err := svc.RouteTask(p.ID, "Health", "Add a handler in `api/server.go:3`, e.g. next to api/routes.go. Works with and/or without docs/notes.txt.", nil)
+	if err != nil {
+		t.Fatalf("RouteTask: %v", err)
+	}
+	var paths []string
+	for _, rp := range route.Paths {
+		paths = append(paths, rp.Path)
+	}
+	if !slices.Equal(paths, []string{"api/server.go", "api/routes.go", "docs/notes.txt"}) {
+		t.Errorf("paths = %v", paths)
+	}
+	if len(route.Zones) != 1 || route.Zones[0].Zone.ID != api.ID || len(route.Zones[0].Paths) != 2 {
+		t.Errorf("zones = %+v", route.Zones)
+	}
+	if !slices.Equal(route.AgentIDs, []string{ada.ID}) || !slices.Equal(route.Unowned, []string{"docs/notes.txt"}) || len(route.Split) != 0 {
+		t.Errorf("agents %v unowned %v split %v", route.AgentIDs, route.Unowned, route.Split)
+	}
+}
+
+func TestRouteTask_SplitsAcrossZones(t *testing.T) {
+	svc, p, _, ada, bob := newReadFixture(t)
+	svc.Tasks = memory.NewTaskStore()
+	task, err := svc.CreateTask(context.Background(), p.ID, "Rename user", "Touches the db/ layer.", nil, "")
+	if err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	route, err := svc.RouteExistingTask(task.ID, []string{filepath.Join(p.RootDir, "api", "users.go"), "README.md"})
+	if err != nil {
+		t.Fatalf("RouteExistingTask: %v", err)
+	}
+	if len(route.Zones) != 2 || route.Zones[0].Zone.Name != "api" || route.Zones[1].Zone.Name != "db" {
+		t.Fatalf("zones = %+v", route.Zones)
+	}
+	if route.Zones[0].Paths[0] != "api/users.go" || route.Zones[1].Paths[0] != "db/" {
+		t.Errorf("zone paths = %v, %v", route.Zones[0].Paths, route.Zones[1].Paths)
+	}
+	if !slices.Equal(route.Unowned, []string{"README.md"}) {
+		t.Errorf("unowned = %v", route.Unowned)
+	}
+	if len(route.Split) != 2 || route.Split[0].Title != "Rename user (api)" || route.Split[0].AgentID != ada.ID || route.Split[1].AgentID != bob.ID {
+		t.Errorf("split = %+v", route.Split)
+	}
+
+	_, err = svc.RouteTask("nope", "", "", nil)
+	wantCode(t, err, "PROJECT_NOT_FOUND")
+}
@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"operators-mcp/internal/adapter/out/filesystem"
+	"operators-mcp/internal/adapter/out/persistence/file"
+	"operators-mcp/internal/adapter/out/persistence/memory"
+	"operators-mcp/internal/adapter/out/persistence/sqlite"
+	"operators-mcp/internal/application/blueprint"
+	"operators-mcp/internal/domain"
+)
+
+func refBackends(t *testing.T) map[string]*blueprint.Service {
+	t.Helper()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	dir, err := file.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("file.Open: %v", err)
+	}
+	t.Cleanup(func() { _ = dir.Close() })
+	matcher, lister := filesystem.NewMatcher(), filesystem.NewLister()
+	return map[string]*blueprint.Service{
+		"memory": blueprint.NewService(memory.NewProjectStore(), memory.NewStore(), memory.NewAgentStore(), matcher, lister),
+		"sqlite": blueprint.NewService(sqlite.NewProjectRepository(db), sqlite.NewZoneRepository(db), sqlite.NewAgentRepository(db), matcher, lister),
+		"file":   blueprint.NewService(file.NewProjectRepository(dir), file.NewZoneRepository(dir), file.NewAgentRepository(dir), matcher, lister),
+	}
+}
+
+func TestZoneAgentRefs_ResolveRejectAndCascade(t *testing.T) {
+	for name, svc := range refBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			p, err := svc.CreateProject("app", t.TempDir())
+			if err != nil {
+				t.Fatalf("CreateProject: %v", err)
+			}
+			a, _ := svc.CreateAgent("Ada", "reviewer", "v1", nil)
+			b, _ := svc.CreateAgent("Bob", "", "", nil)
+			z, err := svc.CreateZone(p.ID, "api", "", "", nil, []string{a.ID, b.ID, a.ID})
+			if err != nil {
+				t.Fatalf("CreateZone: %v", err)
+			}
+			if len(z.AgentIDs) != 2 {
+				t.Errorf("expected duplicate ids collapsed, got %v", z.AgentIDs)
+			}
+
+			if _, err := svc.UpdateAgent(a.ID, "Ada Lovelace", "reviewer", "v2", nil); err != nil {
+				t.Fatalf("UpdateAgent: %v", err)
+			}
+			got := svc.GetZone(z.ID)
+			if len(got.AssignedAgents) != 2 || got.AssignedAgents[0].Name != "Ada Lovelace" || got.AssignedAgents[0].Prompt != "v2" {
+				t.Errorf("expected current agent resolved on read, got %+v", got.AssignedAgents)
+			}
+
+			_, err = svc.UpdateZone(z.ID, "", "", "", nil, []string{"missing"})
+			if se, ok := err.(*domain.StructuredError); !ok || se.Code != "AGENT_NOT_FOUND" {
+				t.Errorf("expected AGENT_NOT_FOUND, got %v", err)
+			}
+
+			if err := svc.DeleteAgent(a.ID); err != nil {
+				t.Fatalf("DeleteAgent: %v", err)
+			}
+			got = svc.GetZone(z.ID)
+			if len(got.AgentIDs) != 1 || got.AgentIDs[0] != b.ID || len(got.AssignedAgents) != 1 {
+				t.Errorf("expected deleted agent unassigned, got ids %v agents %+v", got.AgentIDs, got.AssignedAgents)
+			}
+		})
+	}
+}